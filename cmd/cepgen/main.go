@@ -0,0 +1,62 @@
+// Command cepgen generates typed NAG client methods and their tests from a
+// JSON endpoint spec. See package internal/gen for the spec format.
+//
+// Usage:
+//
+//	cepgen -spec endpoints.json -out endpoints_gen.go
+//
+// writes endpoints_gen.go and endpoints_gen_test.go. It is meant to be run
+// with go:generate whenever the gateway adds or changes endpoints:
+//
+//	//go:generate go run github.com/lessuselesss/CEP-Go-APIs/cmd/cepgen -spec endpoints.json -out endpoints_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/gen"
+)
+
+func main() {
+	specPath := flag.String("spec", "", "path of the JSON endpoint spec")
+	out := flag.String("out", "", "path of the Go file to write; tests go next to it")
+	pkg := flag.String("package", "", "package name, overriding the spec's")
+	noTests := flag.Bool("notests", false, "do not generate tests")
+	flag.Parse()
+
+	if err := run(*specPath, *out, *pkg, !*noTests); err != nil {
+		fmt.Fprintln(os.Stderr, "cepgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, out, pkg string, tests bool) error {
+	if specPath == "" || out == "" || !strings.HasSuffix(out, ".go") {
+		return fmt.Errorf("-spec and an -out file ending in .go are required")
+	}
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := gen.Parse(data)
+	if err != nil {
+		return err
+	}
+	if pkg != "" {
+		spec.Package = pkg
+	}
+	code, testCode, err := gen.Generate(spec)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, code, 0o644); err != nil {
+		return err
+	}
+	if tests {
+		return os.WriteFile(strings.TrimSuffix(out, ".go")+"_test.go", testCode, 0o644)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Command cepload measures certification throughput by submitting
+// certificates at a configurable rate and printing throughput, latency
+// percentiles and a breakdown of errors. See package loadtest.
+//
+// Usage:
+//
+//	cepload -config circular.yaml -rate 20 -duration 1m -confirm
+//
+// loads the NAG, account and key source from the configuration file and the
+// CIRCULAR_ environment variables, as package config does. With -sim the load
+// is applied to an in-process simnag gateway instead, which needs neither a
+// network nor a key:
+//
+//	cepload -sim -sim-confirm 2s -rate 500 -count 10000 -confirm
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/config"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/loadtest"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/simnag"
+)
+
+func main() {
+	var lt loadtest.Config
+	var sim simnag.Config
+	configPath := flag.String("config", "", "configuration file of the account to load the NAG with")
+	useSim := flag.Bool("sim", false, "load an in-process simulated gateway instead of a NAG")
+	flag.Float64Var(&lt.Rate, "rate", 10, "submissions started per second; 0 for as fast as possible")
+	flag.DurationVar(&lt.Duration, "duration", 0, "how long to submit for")
+	flag.IntVar(&lt.Count, "count", 0, "how many certificates to submit")
+	flag.IntVar(&lt.Concurrency, "concurrency", loadtest.DefaultConcurrency, "submissions in flight at most")
+	flag.IntVar(&lt.PayloadSize, "size", loadtest.DefaultPayloadSize, "size in bytes of each certificate's data")
+	flag.BoolVar(&lt.Confirm, "confirm", false, "also wait for every certificate to be confirmed")
+	flag.IntVar(&lt.ConfirmTimeoutSec, "confirm-timeout", loadtest.DefaultConfirmTimeoutSec, "seconds to wait for each confirmation")
+	flag.DurationVar(&sim.Latency, "sim-latency", 0, "latency of the simulated gateway")
+	flag.DurationVar(&sim.ConfirmAfter, "sim-confirm", 0, "time the simulated gateway takes to confirm a transaction")
+	flag.Float64Var(&sim.ErrorRate, "sim-error-rate", 0, "share of requests the simulated gateway fails with 503")
+	flag.Float64Var(&sim.RejectRate, "sim-reject-rate", 0, "share of submissions the simulated gateway rejects")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, *configPath, *useSim, sim, lt, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, "cepload:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, configPath string, useSim bool, sim simnag.Config, lt loadtest.Config, asJSON bool) error {
+	if lt.Duration <= 0 && lt.Count <= 0 {
+		return fmt.Errorf("-duration or -count is required")
+	}
+	// Polling progress would drown the report.
+	quiet := cep.WithLogger(func(context.Context, string) {})
+
+	var account *cep.CEPAccount
+	var privateKey string
+	if useSim {
+		// The simulator answers in-process, so it is polled closely.
+		account = cep.NewCEPAccount(simnag.NAGURL, cep.DefaultChain, cep.LibVersion, simnag.New(sim).Option(), quiet,
+			cep.WithDefaultPollStrategy(cep.FixedPoll{Interval: 10 * time.Millisecond}))
+		if err := account.Open("0x" + strings.Repeat("a", 64)); err != nil {
+			return err
+		}
+		privateKey = strings.Repeat("1", 64)
+	} else {
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if cfg.Address == "" {
+			return fmt.Errorf("an account address is required; set address or CIRCULAR_ADDRESS")
+		}
+		if privateKey, err = cfg.PrivateKey(); err != nil {
+			return err
+		}
+		if account, err = cfg.NewAccount(ctx, quiet); err != nil {
+			return err
+		}
+		if _, err := account.UpdateAccountContext(ctx); err != nil {
+			return err
+		}
+	}
+
+	report, err := loadtest.Run(ctx, account, privateKey, lt)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	fmt.Print(report)
+	return nil
+}
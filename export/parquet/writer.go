@@ -0,0 +1,90 @@
+// Package parquetexport writes certificate exports as Parquet files. It lives
+// in its own module so the core SDK does not depend on a Parquet library.
+//
+//	w, err := parquetexport.NewWriter(file, cep.ColumnTxID, cep.ColumnBlock)
+//	n, err := client.ExportCertificates(ctx, address, start, end, w)
+//	err = w.Close()
+package parquetexport
+
+import (
+	"fmt"
+	"io"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/parquet-go/parquet-go"
+)
+
+// Writer is a cep.RowWriter producing a Parquet file with one column per
+// selected export column. Block and size are stored as INT64, every other
+// column as a UTF-8 string.
+type Writer struct {
+	writer *parquet.Writer
+	// columns lists the export column stored in each leaf column of the
+	// schema, in schema order.
+	columns []cep.ExportColumn
+	row     parquet.Row
+}
+
+// NewWriter returns a Writer for the given columns, or
+// cep.DefaultExportColumns if none are given. Close must be called to write
+// the file footer.
+func NewWriter(w io.Writer, columns ...cep.ExportColumn) (*Writer, error) {
+	columns, err := cep.ValidateExportColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+	group := parquet.Group{}
+	for _, column := range columns {
+		group[string(column)] = columnNode(column)
+	}
+	schema := parquet.NewSchema("certificate", group)
+
+	// A group orders its fields by name, so map leaf columns back to export
+	// columns through the schema rather than the order given.
+	paths := schema.Columns()
+	ordered := make([]cep.ExportColumn, len(paths))
+	for i, path := range paths {
+		ordered[i] = cep.ExportColumn(path[0])
+	}
+	return &Writer{
+		writer:  parquet.NewWriter(w, schema),
+		columns: ordered,
+		row:     make(parquet.Row, len(ordered)),
+	}, nil
+}
+
+// columnNode returns the Parquet type of an export column.
+func columnNode(column cep.ExportColumn) parquet.Node {
+	switch column {
+	case cep.ColumnBlock, cep.ColumnSize:
+		return parquet.Int(64)
+	default:
+		return parquet.String()
+	}
+}
+
+// WriteRow implements cep.RowWriter.
+func (w *Writer) WriteRow(row cep.CertificateRow) error {
+	for i, column := range w.columns {
+		var value interface{}
+		switch column {
+		case cep.ColumnBlock:
+			value = row.Block
+		case cep.ColumnSize:
+			value = int64(row.Size)
+		default:
+			value = row.Value(column)
+		}
+		w.row[i] = parquet.ValueOf(value).Level(0, 0, i)
+	}
+	if _, err := w.writer.WriteRows([]parquet.Row{w.row}); err != nil {
+		return fmt.Errorf("failed to write parquet row: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered rows and writes the file footer. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	return w.writer.Close()
+}
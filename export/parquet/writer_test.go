@@ -0,0 +1,46 @@
+package parquetexport
+
+import (
+	"bytes"
+	"testing"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, cep.ColumnTxID, cep.ColumnBlock, cep.ColumnSize)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	rows := []cep.CertificateRow{
+		{TxID: "aa01", Block: 7, Size: 5},
+		{TxID: "aa02", Block: 8, Size: 12},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected a readable parquet file, but got: %v", err)
+	}
+	if file.NumRows() != int64(len(rows)) {
+		t.Errorf("Expected %d rows, but got %d", len(rows), file.NumRows())
+	}
+	if got := len(file.Schema().Columns()); got != 3 {
+		t.Errorf("Expected 3 columns, but got %d", got)
+	}
+}
+
+func TestWriterUnknownColumn(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "nonce"); err == nil {
+		t.Error("Expected an error but got nil")
+	}
+}
@@ -5,11 +5,13 @@ go 1.24.4
 require (
 	github.com/circular-protocol/circular-go v0.0.0-20241027102342-f2ff57add44b
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0
+	github.com/joho/godotenv v1.5.1
+	golang.org/x/crypto v0.39.0
 )
 
 require (
 	github.com/btcsuite/btcd/chaincfg/chainhash v1.0.1 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1 v1.0.4 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v2 v2.0.0 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
+	golang.org/x/sys v0.33.0 // indirect
 )
@@ -0,0 +1,256 @@
+// Package blake3 implements the BLAKE3 hash function in its default hashing
+// mode with a 256-bit output. It follows the reference implementation of the
+// BLAKE3 specification and favours clarity over speed: it does not use SIMD
+// or hash chunks in parallel.
+package blake3
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Size is the length in bytes of a BLAKE3 digest.
+const Size = 32
+
+// BlockSize is the block size of BLAKE3 in bytes.
+const BlockSize = 64
+
+const chunkLen = 1024
+
+// Domain separation flags.
+const (
+	chunkStart = 1 << 0
+	chunkEnd   = 1 << 1
+	parent     = 1 << 2
+	root       = 1 << 3
+)
+
+var iv = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+var msgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+// g is the quarter-round function, mixing m0 and m1 into four state words.
+func g(s *[16]uint32, a, b, c, d int, m0, m1 uint32) {
+	s[a] = s[a] + s[b] + m0
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] = s[c] + s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] = s[a] + s[b] + m1
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] = s[c] + s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
+
+func round(s *[16]uint32, m *[16]uint32) {
+	// Columns.
+	g(s, 0, 4, 8, 12, m[0], m[1])
+	g(s, 1, 5, 9, 13, m[2], m[3])
+	g(s, 2, 6, 10, 14, m[4], m[5])
+	g(s, 3, 7, 11, 15, m[6], m[7])
+	// Diagonals.
+	g(s, 0, 5, 10, 15, m[8], m[9])
+	g(s, 1, 6, 11, 12, m[10], m[11])
+	g(s, 2, 7, 8, 13, m[12], m[13])
+	g(s, 3, 4, 9, 14, m[14], m[15])
+}
+
+func permute(m *[16]uint32) {
+	var permuted [16]uint32
+	for i, j := range msgPermutation {
+		permuted[i] = m[j]
+	}
+	*m = permuted
+}
+
+// compress runs the compression function on one block and returns the full
+// 16-word state; its first 8 words are the new chaining value.
+func compress(cv *[8]uint32, block [16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	for i := 0; i < 7; i++ {
+		round(&s, &block)
+		if i < 6 {
+			permute(&block)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		s[i] ^= s[i+8]
+		s[i+8] ^= cv[i]
+	}
+	return s
+}
+
+func first8(s [16]uint32) [8]uint32 {
+	var cv [8]uint32
+	copy(cv[:], s[:8])
+	return cv
+}
+
+// wordsOf reads a zero-padded block as little-endian words.
+func wordsOf(block *[BlockSize]byte) [16]uint32 {
+	var words [16]uint32
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	return words
+}
+
+// output is the state needed to produce either a chaining value or the
+// root digest of a node.
+type output struct {
+	inputCV  [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o *output) chainingValue() [8]uint32 {
+	return first8(compress(&o.inputCV, o.block, o.counter, o.blockLen, o.flags))
+}
+
+func (o *output) rootBytes(out []byte) []byte {
+	s := compress(&o.inputCV, o.block, 0, o.blockLen, o.flags|root)
+	for _, word := range s[:Size/4] {
+		out = binary.LittleEndian.AppendUint32(out, word)
+	}
+	return out
+}
+
+func parentOutput(left, right [8]uint32) output {
+	var block [16]uint32
+	copy(block[:8], left[:])
+	copy(block[8:], right[:])
+	return output{inputCV: iv, block: block, blockLen: BlockSize, flags: parent}
+}
+
+// chunkState hashes the blocks of one chunk.
+type chunkState struct {
+	cv               [8]uint32
+	counter          uint64
+	block            [BlockSize]byte
+	blockLen         int
+	blocksCompressed int
+}
+
+func newChunkState(counter uint64) chunkState {
+	return chunkState{cv: iv, counter: counter}
+}
+
+func (c *chunkState) len() int {
+	return BlockSize*c.blocksCompressed + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.blocksCompressed == 0 {
+		return chunkStart
+	}
+	return 0
+}
+
+func (c *chunkState) update(p []byte) {
+	for len(p) > 0 {
+		// The last block of a chunk is compressed by output, with the
+		// chunkEnd flag, so a full block is only compressed once more input
+		// arrives.
+		if c.blockLen == BlockSize {
+			c.cv = first8(compress(&c.cv, wordsOf(&c.block), c.counter, BlockSize, c.startFlag()))
+			c.blocksCompressed++
+			c.block = [BlockSize]byte{}
+			c.blockLen = 0
+		}
+		n := copy(c.block[c.blockLen:], p)
+		c.blockLen += n
+		p = p[n:]
+	}
+}
+
+func (c *chunkState) output() output {
+	return output{
+		inputCV:  c.cv,
+		block:    wordsOf(&c.block),
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.startFlag() | chunkEnd,
+	}
+}
+
+// digest is an incremental BLAKE3 hash.
+type digest struct {
+	chunk chunkState
+	// stack holds the chaining values of the complete subtrees to the left
+	// of the current chunk, one per set bit of the number of chunks done.
+	stack [][8]uint32
+}
+
+// New returns a new hash.Hash computing the 256-bit BLAKE3 digest.
+func New() hash.Hash {
+	return &digest{chunk: newChunkState(0)}
+}
+
+// Sum256 returns the BLAKE3 digest of data.
+func Sum256(data []byte) [Size]byte {
+	d := New()
+	d.Write(data)
+	var sum [Size]byte
+	d.Sum(sum[:0])
+	return sum
+}
+
+func (d *digest) Size() int      { return Size }
+func (d *digest) BlockSize() int { return BlockSize }
+
+func (d *digest) Reset() {
+	d.chunk = newChunkState(0)
+	d.stack = d.stack[:0]
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// A full chunk is only finished once more input arrives, since the
+		// last chunk is finalized differently.
+		if d.chunk.len() == chunkLen {
+			out := d.chunk.output()
+			d.pushChunk(out.chainingValue(), d.chunk.counter+1)
+			d.chunk = newChunkState(d.chunk.counter + 1)
+		}
+		want := chunkLen - d.chunk.len()
+		if want > len(p) {
+			want = len(p)
+		}
+		d.chunk.update(p[:want])
+		p = p[want:]
+	}
+	return n, nil
+}
+
+// pushChunk adds the chaining value of a finished chunk, merging every
+// subtree it completes: one per trailing zero bit of totalChunks.
+func (d *digest) pushChunk(cv [8]uint32, totalChunks uint64) {
+	for totalChunks&1 == 0 {
+		top := d.stack[len(d.stack)-1]
+		d.stack = d.stack[:len(d.stack)-1]
+		out := parentOutput(top, cv)
+		cv = out.chainingValue()
+		totalChunks >>= 1
+	}
+	d.stack = append(d.stack, cv)
+}
+
+// Sum appends the digest of the data written so far to b. It does not change
+// the state of the hash.
+func (d *digest) Sum(b []byte) []byte {
+	out := d.chunk.output()
+	for i := len(d.stack) - 1; i >= 0; i-- {
+		out = parentOutput(d.stack[i], out.chainingValue())
+	}
+	return out.rootBytes(b)
+}
@@ -0,0 +1,62 @@
+package blake3
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// testInput returns the input of the official BLAKE3 test vectors: the
+// repeating byte sequence 0, 1, ..., 250.
+func testInput(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestSum256(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"Empty", nil, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{"abc", []byte("abc"), "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"},
+		{"One Chunk", testInput(1024), "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{"Two Chunks", testInput(1025), "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+		{"Full Two Chunks", testInput(2048), "e776b6028c7cd22a4d0ba182a8bf62205d2ef576467e838ed6f2529b85fba24a"},
+		{"Hundred Chunks", testInput(102400), "bc3e3d41a1146b069abffad3c0d44860cf664390afce4d9661f7902e7943e085"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sum := Sum256(tc.input)
+			if got := hex.EncodeToString(sum[:]); got != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, got)
+			}
+
+			// Writing in uneven pieces must give the same digest.
+			h := New()
+			for data := tc.input; len(data) > 0; {
+				n := min(len(data), 7)
+				h.Write(data[:n])
+				data = data[n:]
+			}
+			if got := hex.EncodeToString(h.Sum(nil)); got != tc.expected {
+				t.Errorf("Expected %s from incremental writes, but got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	h := New()
+	h.Write(testInput(5000))
+	h.Sum(nil)
+	h.Reset()
+	h.Write([]byte("abc"))
+	if got := hex.EncodeToString(h.Sum(nil)); got != "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85" {
+		t.Errorf("Expected Reset to start a new digest, but got %s", got)
+	}
+}
@@ -0,0 +1,407 @@
+// Package gen generates typed NAG client methods, and tests for them, from a
+// JSON description of the gateway's endpoints. It backs the cepgen command.
+//
+// A spec lists the response types and the endpoints:
+//
+//	{
+//	  "package": "circular_enterprise_apis",
+//	  "types": [
+//	    {"name": "AssetInfo", "fields": [
+//	      {"name": "Name", "type": "string"},
+//	      {"name": "Supply", "json": "TotalSupply", "type": "float64"}
+//	    ]}
+//	  ],
+//	  "endpoints": [
+//	    {"name": "GetAssetInfo", "doc": "returns the description of an asset.",
+//	     "params": [{"name": "assetName", "json": "AssetName", "type": "string"}],
+//	     "response": "AssetInfo",
+//	     "example": {"Name": "CIRX", "TotalSupply": 1000}}
+//	  ]
+//	}
+//
+// Endpoints become methods on *Client when the package is the library's own,
+// and functions taking a *cep.Client otherwise. Requests carry the client's
+// Blockchain and Version unless "noDefaults" is set, like the built-in
+// methods, and every call goes through cep.Call.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// LibraryPackage is the name of the library's package; code generated into
+// it defines methods instead of functions.
+const LibraryPackage = "circular_enterprise_apis"
+
+// LibraryImport is the import path of the library.
+const LibraryImport = "github.com/lessuselesss/CEP-Go-APIs/pkg"
+
+// Spec describes the endpoints to generate.
+type Spec struct {
+	Package   string     `json:"package"`
+	Types     []Type     `json:"types"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// Type is a response struct.
+type Type struct {
+	Name   string  `json:"name"`
+	Doc    string  `json:"doc"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is a field of a response struct or a parameter of an endpoint. JSON
+// is the key on the wire and defaults to Name.
+type Field struct {
+	Name string `json:"name"`
+	JSON string `json:"json"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
+}
+
+// Endpoint is a NAG method. Method is the Go name and defaults to Name;
+// Response names a type of the spec, or is empty for a raw map.
+type Endpoint struct {
+	Name       string          `json:"name"`
+	Method     string          `json:"method"`
+	Doc        string          `json:"doc"`
+	Params     []Field         `json:"params"`
+	Response   string          `json:"response"`
+	NoDefaults bool            `json:"noDefaults"`
+	Example    json.RawMessage `json:"example"`
+}
+
+// Parse decodes a spec, rejecting unknown fields.
+func Parse(data []byte) (*Spec, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var spec Spec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// builtinTypes are the Go types fields and parameters may use besides the
+// types of the spec, with the zero value used for them in generated tests.
+var builtinTypes = map[string]string{
+	"string":                 `""`,
+	"bool":                   "false",
+	"int":                    "0",
+	"int64":                  "0",
+	"float64":                "0",
+	"json.RawMessage":        "nil",
+	"interface{}":            "nil",
+	"map[string]interface{}": "nil",
+	"[]string":               "nil",
+	"[]interface{}":          "nil",
+}
+
+// endpointNamePattern restricts endpoint names to what the NAG URL accepts.
+var endpointNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Validate checks that names are valid, unique Go identifiers and that every
+// type is known.
+func (s *Spec) Validate() error {
+	if !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("invalid package name %q", s.Package)
+	}
+	types := make(map[string]bool)
+	for _, t := range s.Types {
+		if !token.IsExported(t.Name) || !token.IsIdentifier(t.Name) {
+			return fmt.Errorf("type %q: name must be an exported identifier", t.Name)
+		}
+		if types[t.Name] {
+			return fmt.Errorf("type %q is defined twice", t.Name)
+		}
+		types[t.Name] = true
+	}
+	known := func(typ string) bool {
+		_, builtin := builtinTypes[typ]
+		return builtin || types[typ] || types[strings.TrimPrefix(typ, "[]")]
+	}
+
+	for _, t := range s.Types {
+		seen := make(map[string]bool)
+		for _, f := range t.Fields {
+			if !token.IsExported(f.Name) || !token.IsIdentifier(f.Name) || seen[f.Name] {
+				return fmt.Errorf("type %s: invalid or duplicate field %q", t.Name, f.Name)
+			}
+			seen[f.Name] = true
+			if !known(f.Type) {
+				return fmt.Errorf("type %s: field %s has unknown type %q", t.Name, f.Name, f.Type)
+			}
+		}
+	}
+
+	methods := make(map[string]bool)
+	for _, e := range s.Endpoints {
+		if !endpointNamePattern.MatchString(e.Name) {
+			return fmt.Errorf("invalid endpoint name %q", e.Name)
+		}
+		method := e.method()
+		if !token.IsExported(method) || !token.IsIdentifier(method) || methods[method] {
+			return fmt.Errorf("endpoint %s: invalid or duplicate method name %q", e.Name, method)
+		}
+		methods[method] = true
+		if e.Response != "" && !types[e.Response] {
+			return fmt.Errorf("endpoint %s: unknown response type %q", e.Name, e.Response)
+		}
+		seen := map[string]bool{"ctx": true, "c": true}
+		for _, p := range e.Params {
+			if !token.IsIdentifier(p.Name) || token.IsKeyword(p.Name) || seen[p.Name] {
+				return fmt.Errorf("endpoint %s: invalid or duplicate parameter %q", e.Name, p.Name)
+			}
+			seen[p.Name] = true
+			if !known(p.Type) {
+				return fmt.Errorf("endpoint %s: parameter %s has unknown type %q", e.Name, p.Name, p.Type)
+			}
+		}
+		if len(e.Example) > 0 && !json.Valid(e.Example) {
+			return fmt.Errorf("endpoint %s: example is not valid JSON", e.Name)
+		}
+	}
+	return nil
+}
+
+func (e Endpoint) method() string {
+	if e.Method != "" {
+		return e.Method
+	}
+	return e.Name
+}
+
+func (f Field) key() string {
+	if f.JSON != "" {
+		return f.JSON
+	}
+	return f.Name
+}
+
+// Generate returns the formatted source of the client code and of its tests.
+func Generate(spec *Spec) (code, tests []byte, err error) {
+	if err := spec.Validate(); err != nil {
+		return nil, nil, err
+	}
+	data := newTemplateData(spec)
+	if code, err = render(codeTemplate, data); err != nil {
+		return nil, nil, err
+	}
+	if tests, err = render(testTemplate, data); err != nil {
+		return nil, nil, err
+	}
+	return code, tests, nil
+}
+
+// render executes tmpl and formats the result.
+func render(tmpl *template.Template, data templateData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to generate %s: %w", tmpl.Name(), err)
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", tmpl.Name(), err)
+	}
+	return source, nil
+}
+
+// templateData is the view of a spec used by the templates.
+type templateData struct {
+	Package string
+	// Internal is set when generating into the library's own package.
+	Internal bool
+	// Q qualifies the library's identifiers: "" or "cep.".
+	Q         string
+	JSON      bool
+	Types     []Type
+	Endpoints []endpointData
+}
+
+type endpointData struct {
+	Endpoint
+	Method   string
+	Doc      string
+	Result   string
+	Defaults bool
+	Args     string
+	ZeroArgs string
+	Keys     []string
+	Params   []paramData
+	Example  string
+}
+
+type paramData struct {
+	Field
+	Key string
+}
+
+func newTemplateData(spec *Spec) templateData {
+	data := templateData{Package: spec.Package, Internal: spec.Package == LibraryPackage}
+	if !data.Internal {
+		data.Q = "cep."
+	}
+	data.Types = make([]Type, len(spec.Types))
+	for i, t := range spec.Types {
+		t.Fields = append([]Field(nil), t.Fields...)
+		for j := range t.Fields {
+			t.Fields[j].JSON = t.Fields[j].key()
+			data.JSON = data.JSON || strings.Contains(t.Fields[j].Type, "json.")
+		}
+		data.Types[i] = t
+	}
+
+	for _, e := range spec.Endpoints {
+		d := endpointData{Endpoint: e, Method: e.method(), Defaults: !e.NoDefaults}
+		d.Doc = e.Doc
+		if d.Doc == "" {
+			d.Doc = fmt.Sprintf("calls the %s endpoint.", e.Name)
+		}
+		d.Result = "map[string]interface{}"
+		if e.Response != "" {
+			d.Result = "*" + e.Response
+		}
+		var args, zeros []string
+		for _, p := range e.Params {
+			d.Params = append(d.Params, paramData{Field: p, Key: p.key()})
+			args = append(args, p.Name+" "+p.Type)
+			data.JSON = data.JSON || strings.Contains(p.Type, "json.")
+			zero, ok := builtinTypes[p.Type]
+			if !ok {
+				zero = p.Type + "{}"
+				if strings.HasPrefix(p.Type, "[]") {
+					zero = "nil"
+				}
+			}
+			zeros = append(zeros, zero)
+			d.Keys = append(d.Keys, p.key())
+		}
+		if d.Defaults {
+			d.Keys = append(d.Keys, "Blockchain", "Version")
+		}
+		sort.Strings(d.Keys)
+		if len(args) > 0 {
+			d.Args = ", " + strings.Join(args, ", ")
+			d.ZeroArgs = ", " + strings.Join(zeros, ", ")
+		}
+		example := string(e.Example)
+		if example == "" {
+			example = "{}"
+		}
+		d.Example = strconv.Quote(`{"Result":200,"Response":` + example + `}`)
+		data.Endpoints = append(data.Endpoints, d)
+	}
+	return data
+}
+
+var codeTemplate = template.Must(template.New("code").Parse(`// Code generated by cepgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+{{- if .JSON}}
+	"encoding/json"
+{{- end}}
+{{- if not .Internal}}
+
+	cep "` + LibraryImport + `"
+{{- end}}
+)
+{{range .Types}}
+{{if .Doc}}// {{.Name}} {{.Doc}}{{else}}// {{.Name}} is a response of the NAG.{{end}}
+type {{.Name}} struct {
+{{- range .Fields}}
+{{- if .Doc}}
+	// {{.Doc}}
+{{- end}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSON}}"` + "`" + `
+{{- end}}
+}
+{{end}}
+{{- range .Endpoints}}
+{{- $q := $.Q}}
+// {{.Method}} {{.Doc}}
+{{- if $.Internal}}
+func (c *Client) {{.Method}}(ctx context.Context{{.Args}}) ({{.Result}}, error) {
+{{- else}}
+func {{.Method}}(ctx context.Context, c *cep.Client{{.Args}}) ({{.Result}}, error) {
+{{- end}}
+	request := map[string]interface{}{
+{{- if .Defaults}}
+		"Blockchain": c.Blockchain,
+		"Version":    c.CodeVersion,
+{{- end}}
+{{- range .Params}}
+		"{{.Key}}": {{.Name}},
+{{- end}}
+	}
+{{- if .Response}}
+	response, err := {{$q}}Call[{{$q}}Envelope[{{.Response}}]](ctx, c, "{{.Name}}", request)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Response, nil
+{{- else}}
+	return {{$q}}Call[map[string]interface{}](ctx, c, "{{.Name}}", request)
+{{- end}}
+}
+{{end}}`))
+
+var testTemplate = template.Must(template.New("tests").Parse(`// Code generated by cepgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+{{- if not .Internal}}
+
+	cep "` + LibraryImport + `"
+{{- end}}
+)
+{{range .Endpoints}}
+{{- $q := $.Q}}
+func TestGenerated{{.Method}}(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Circular_{{.Name}}_" {
+			t.Errorf("Expected a request to {{.Name}}, but got %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte({{.Example}}))
+	}))
+	defer server.Close()
+
+	c := {{$q}}NewClient(server.URL, {{$q}}DefaultChain, {{$q}}LibVersion)
+{{- if $.Internal}}
+	response, err := c.{{.Method}}(context.Background(){{.ZeroArgs}})
+{{- else}}
+	response, err := {{.Method}}(context.Background(), c{{.ZeroArgs}})
+{{- end}}
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if response == nil {
+		t.Error("Expected a response")
+	}
+	for _, key := range []string{ {{- range $i, $k := .Keys}}{{if $i}}, {{end}}"{{$k}}"{{end -}} } {
+		if _, ok := request[key]; !ok {
+			t.Errorf("Expected the request to carry %s, but got %v", key, request)
+		}
+	}
+}
+{{end}}`))
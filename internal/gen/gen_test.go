@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := os.ReadFile("testdata/endpoints.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		pkg      string
+		expected []string
+	}{
+		{
+			name: "Library Package",
+			pkg:  LibraryPackage,
+			expected: []string{
+				"func (c *Client) GetAssetInfo(ctx context.Context, assetName string) (*AssetInfo, error)",
+				`Call[Envelope[AssetInfo]](ctx, c, "GetAssetInfo", request)`,
+				"func (c *Client) NodeStatus(ctx context.Context, verbose bool) (map[string]interface{}, error)",
+				"Supply  float64         `json:\"TotalSupply\"`",
+				"Holders []Holder",
+			},
+		},
+		{
+			name: "External Package",
+			pkg:  "gateway",
+			expected: []string{
+				`cep "github.com/lessuselesss/CEP-Go-APIs/pkg"`,
+				"func GetAssetInfo(ctx context.Context, c *cep.Client, assetName string) (*AssetInfo, error)",
+				`cep.Call[cep.Envelope[AssetInfo]](ctx, c, "GetAssetInfo", request)`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec.Package = tc.pkg
+			code, tests, err := Generate(spec)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			for name, source := range map[string][]byte{"code": code, "tests": tests} {
+				if _, err := parser.ParseFile(token.NewFileSet(), name+".go", source, 0); err != nil {
+					t.Errorf("Expected valid Go %s, but got: %v\n%s", name, err, source)
+				}
+				if !strings.HasPrefix(string(source), "// Code generated by cepgen. DO NOT EDIT.") {
+					t.Errorf("Expected the generated %s to be marked as such", name)
+				}
+			}
+			for _, want := range tc.expected {
+				if !strings.Contains(string(code), want) {
+					t.Errorf("Expected the code to contain %q, but got:\n%s", want, code)
+				}
+			}
+			if !strings.Contains(string(tests), "func TestGeneratedNodeStatus(t *testing.T)") ||
+				!strings.Contains(string(tests), `[]string{"Verbose"}`) {
+				t.Errorf("Expected a test per endpoint, but got:\n%s", tests)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec string
+	}{
+		{"Unknown Field", `{"package":"p","endpoint":[]}`},
+		{"Bad Package", `{"package":"my-pkg"}`},
+		{"Bad Endpoint Name", `{"package":"p","endpoints":[{"name":"Get/../Admin"}]}`},
+		{"Duplicate Method", `{"package":"p","endpoints":[{"name":"A"},{"name":"B","method":"A"}]}`},
+		{"Unknown Response", `{"package":"p","endpoints":[{"name":"A","response":"Missing"}]}`},
+		{"Unknown Param Type", `{"package":"p","endpoints":[{"name":"A","params":[{"name":"x","type":"chan int"}]}]}`},
+		{"Keyword Param", `{"package":"p","endpoints":[{"name":"A","params":[{"name":"func","type":"string"}]}]}`},
+		{"Unexported Field", `{"package":"p","types":[{"name":"T","fields":[{"name":"x","type":"string"}]}]}`},
+		{"Unknown Field Type", `{"package":"p","types":[{"name":"T","fields":[{"name":"X","type":"Other"}]}]}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := Parse([]byte(tc.spec))
+			if err == nil {
+				_, _, err = Generate(spec)
+			}
+			if err == nil {
+				t.Error("Expected the spec to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// benchmarkPayload is roughly the size of a large certificate document.
+var benchmarkPayload = strings.Repeat("circular enterprise payload ", 1<<15)
+
+func BenchmarkPadNumber(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PadNumber(i % 60)
+	}
+}
+
+func BenchmarkGetFormattedTimestamp(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		GetFormattedTimestamp()
+	}
+}
+
+func BenchmarkHexFix(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		HexFix("0x8a20baa40c45dc5055aeb26197c203e576ef389d9acb171bd62da11dc5ad72b2")
+	}
+}
+
+func BenchmarkStringToHex(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkPayload)))
+	for i := 0; i < b.N; i++ {
+		StringToHex(benchmarkPayload)
+	}
+}
+
+func BenchmarkHexToString(b *testing.B) {
+	encoded := StringToHex(benchmarkPayload)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(encoded)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HexToString(encoded)
+	}
+}
+
+func BenchmarkHexEncodeTo(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkPayload)))
+	for i := 0; i < b.N; i++ {
+		HexEncodeTo(io.Discard, strings.NewReader(benchmarkPayload))
+	}
+}
+
+func BenchmarkHexDecodeFrom(b *testing.B) {
+	encoded := StringToHex(benchmarkPayload)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(encoded)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HexDecodeFrom(io.Discard, strings.NewReader(encoded))
+	}
+}
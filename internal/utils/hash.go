@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/sha3"
+	"encoding/hex"
+
+	xsha3 "golang.org/x/crypto/sha3"
+)
+
+// The helpers below mirror the hashing helpers of the JS and Java Circular
+// SDKs. The Hex variants hash the UTF-8 bytes of a string and return the
+// lowercase hex digest without a 0x prefix, which is the form those SDKs use
+// in payloads.
+
+// SHA256 returns the SHA-256 digest of data.
+func SHA256(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// SHA256Hex returns the hex-encoded SHA-256 digest of str.
+func SHA256Hex(str string) string {
+	return hex.EncodeToString(SHA256([]byte(str)))
+}
+
+// SHA3_256 returns the FIPS 202 SHA3-256 digest of data.
+func SHA3_256(data []byte) []byte {
+	sum := sha3.Sum256(data)
+	return sum[:]
+}
+
+// SHA3_256Hex returns the hex-encoded SHA3-256 digest of str.
+func SHA3_256Hex(str string) string {
+	return hex.EncodeToString(SHA3_256([]byte(str)))
+}
+
+// Keccak256 returns the legacy Keccak-256 digest of data, the pre-standard
+// variant used by Ethereum-style tooling. It differs from SHA3_256 only in
+// padding, so the two must not be used interchangeably.
+func Keccak256(data []byte) []byte {
+	h := xsha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// Keccak256Hex returns the hex-encoded Keccak-256 digest of str.
+func Keccak256Hex(str string) string {
+	return hex.EncodeToString(Keccak256([]byte(str)))
+}
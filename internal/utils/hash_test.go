@@ -0,0 +1,27 @@
+package utils
+
+import "testing"
+
+func TestHashHelpers(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hash     func(string) string
+		input    string
+		expected string
+	}{
+		{"SHA256 Empty", SHA256Hex, "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"SHA256 abc", SHA256Hex, "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"SHA3-256 Empty", SHA3_256Hex, "", "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"},
+		{"SHA3-256 abc", SHA3_256Hex, "abc", "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"},
+		{"Keccak256 Empty", Keccak256Hex, "", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"Keccak256 abc", Keccak256Hex, "abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.hash(tc.input); got != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, got)
+			}
+		})
+	}
+}
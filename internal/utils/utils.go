@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"bufio"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 )
@@ -17,7 +20,13 @@ func PadNumber(num int) string {
 
 // GetFormattedTimestamp generates a UTC timestamp in YYYY:MM:DD-HH:MM:SS format.
 func GetFormattedTimestamp() string {
-	now := time.Now().UTC()
+	return FormatTimestamp(time.Now())
+}
+
+// FormatTimestamp formats t in UTC using the YYYY:MM:DD-HH:MM:SS layout of
+// GetFormattedTimestamp.
+func FormatTimestamp(t time.Time) string {
+	now := t.UTC()
 	year := now.Year()
 	month := PadNumber(int(now.Month()))
 	day := PadNumber(now.Day())
@@ -27,6 +36,26 @@ func GetFormattedTimestamp() string {
 	return fmt.Sprintf("%d:%s:%s-%s:%s:%s", year, month, day, hours, minutes, seconds)
 }
 
+// TimestampLayout is the time.Parse layout of the protocol's
+// YYYY:MM:DD-HH:MM:SS timestamps.
+const TimestampLayout = "2006:01:02-15:04:05"
+
+// ErrInvalidTimestamp is wrapped by the errors returned from ParseTimestamp.
+var ErrInvalidTimestamp = errors.New("invalid timestamp")
+
+// ParseTimestamp parses a YYYY:MM:DD-HH:MM:SS timestamp as a UTC time. Every
+// field must be zero-padded to its full width.
+func ParseTimestamp(timestamp string) (time.Time, error) {
+	if len(timestamp) != len(TimestampLayout) {
+		return time.Time{}, fmt.Errorf("%w %q: expected YYYY:MM:DD-HH:MM:SS", ErrInvalidTimestamp, timestamp)
+	}
+	t, err := time.Parse(TimestampLayout, timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q: %v", ErrInvalidTimestamp, timestamp, err)
+	}
+	return t, nil
+}
+
 // HexFix removes '0x' prefix from hexadecimal strings if present.
 func HexFix(word string) string {
 	if strings.HasPrefix(word, "0x") {
@@ -35,18 +64,67 @@ func HexFix(word string) string {
 	return word
 }
 
+// hexDigits is the lowercase alphabet used by StringToHex.
+const hexDigits = "0123456789abcdef"
+
 // StringToHex converts a string to its hexadecimal representation.
+// The result is built directly from the string's bytes into a preallocated
+// builder, avoiding the intermediate []byte copy of hex.EncodeToString.
 func StringToHex(str string) string {
-	return hex.EncodeToString([]byte(str))
+	var sb strings.Builder
+	sb.Grow(hex.EncodedLen(len(str)))
+	var chunk [512]byte
+	for len(str) > 0 {
+		n := min(len(str), len(chunk)/2)
+		for i := 0; i < n; i++ {
+			chunk[2*i] = hexDigits[str[i]>>4]
+			chunk[2*i+1] = hexDigits[str[i]&0x0f]
+		}
+		sb.Write(chunk[:2*n])
+		str = str[n:]
+	}
+	return sb.String()
 }
 
 // HexToString converts a hexadecimal string back to its original string form.
 func HexToString(hexStr string) string {
+	str, err := DecodeHexString(hexStr)
+	if err != nil {
+		return ""
+	}
+	return str
+}
+
+// DecodeHexString is like HexToString but reports invalid hexadecimal input
+// as an error instead of returning an empty string.
+func DecodeHexString(hexStr string) (string, error) {
 	cleanedHex := HexFix(hexStr)
 	bytes, err := hex.DecodeString(cleanedHex)
 	if err != nil {
-		return ""
+		return "", fmt.Errorf("invalid hex string: %w", err)
 	}
 	// Strip null bytes to match the reference implementation
-	return strings.ReplaceAll(string(bytes), "\x00", "")
+	return strings.ReplaceAll(string(bytes), "\x00", ""), nil
+}
+
+// HexEncodeTo streams the hexadecimal encoding of everything read from r into
+// w, without holding the whole payload or its encoding in memory. It returns
+// the number of hex characters written.
+func HexEncodeTo(w io.Writer, r io.Reader) (int64, error) {
+	enc := hex.NewEncoder(w)
+	// Hide any WriterTo implementation on r so the copy always goes through a
+	// fixed-size buffer instead of handing the encoder the whole payload.
+	n, err := io.CopyBuffer(enc, struct{ io.Reader }{r}, make([]byte, 32*1024))
+	return int64(hex.EncodedLen(int(n))), err
+}
+
+// HexDecodeFrom streams the bytes encoded by the hexadecimal text read from r
+// into w. A leading "0x" prefix is ignored, matching HexFix. It returns the
+// number of decoded bytes written.
+func HexDecodeFrom(w io.Writer, r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+	if prefix, err := br.Peek(2); err == nil && string(prefix) == "0x" {
+		br.Discard(2)
+	}
+	return io.Copy(w, hex.NewDecoder(br))
 }
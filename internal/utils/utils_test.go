@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -204,3 +205,151 @@ func TestHexToString(t *testing.T) {
 		}
 	})
 }
+
+func TestHexEncodeTo(t *testing.T) {
+	t.Run("ascii string", func(t *testing.T) {
+		var sb strings.Builder
+		n, err := HexEncodeTo(&sb, strings.NewReader("hello"))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if sb.String() != "68656c6c6f" {
+			t.Errorf("Expected 68656c6c6f, but got %s", sb.String())
+		}
+		if n != 10 {
+			t.Errorf("Expected 10 characters written, but got %d", n)
+		}
+	})
+
+	t.Run("matches StringToHex for large input", func(t *testing.T) {
+		input := strings.Repeat("circular", 100000)
+		var sb strings.Builder
+		if _, err := HexEncodeTo(&sb, strings.NewReader(input)); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if sb.String() != StringToHex(input) {
+			t.Error("Streaming encoding does not match StringToHex")
+		}
+	})
+}
+
+func TestHexDecodeFrom(t *testing.T) {
+	t.Run("valid hex string", func(t *testing.T) {
+		var sb strings.Builder
+		n, err := HexDecodeFrom(&sb, strings.NewReader("68656c6c6f"))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if sb.String() != "hello" || n != 5 {
+			t.Errorf("Expected hello (5 bytes), but got %s (%d bytes)", sb.String(), n)
+		}
+	})
+
+	t.Run("with 0x prefix", func(t *testing.T) {
+		var sb strings.Builder
+		if _, err := HexDecodeFrom(&sb, strings.NewReader("0x68656c6c6f")); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if sb.String() != "hello" {
+			t.Errorf("Expected hello, but got %s", sb.String())
+		}
+	})
+
+	t.Run("invalid hex string", func(t *testing.T) {
+		var sb strings.Builder
+		if _, err := HexDecodeFrom(&sb, strings.NewReader("invalid")); err == nil {
+			t.Error("Expected an error for invalid hex, but got nil")
+		}
+	})
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	testCases := []struct {
+		name     string
+		time     time.Time
+		expected string
+	}{
+		{"Padded Fields", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024:01:02-03:04:05"},
+		{"Converted To UTC", time.Date(2024, 12, 31, 23, 30, 0, 0, time.FixedZone("UTC-1", -3600)), "2025:01:01-00:30:00"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatTimestamp(tc.time); got != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecodeHexString(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{"Plain", "68656c6c6f", "hello", false},
+		{"Prefixed", "0x68656c6c6f", "hello", false},
+		{"Null Bytes Stripped", "6100620063", "abc", false},
+		{"Empty", "", "", false},
+		{"Odd Length", "686", "", true},
+		{"Invalid Characters", "zz", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DecodeHexString(tc.input)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %q, but got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    time.Time
+		expectError bool
+	}{
+		{"Valid", "2024:01:02-03:04:05", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), false},
+		{"End Of Year", "2024:12:31-23:59:59", time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC), false},
+		{"Unpadded", "2024:1:2-3:4:5", time.Time{}, true},
+		{"ISO Layout", "2024-01-02T03:04:05", time.Time{}, true},
+		{"Out Of Range", "2024:13:02-03:04:05", time.Time{}, true},
+		{"Trailing Data", "2024:01:02-03:04:05Z", time.Time{}, true},
+		{"Empty", "", time.Time{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseTimestamp(tc.input)
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidTimestamp) {
+					t.Errorf("Expected ErrInvalidTimestamp, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if !got.Equal(tc.expected) {
+				t.Errorf("Expected %v, but got %v", tc.expected, got)
+			}
+			if FormatTimestamp(got) != tc.input {
+				t.Errorf("Expected %s to round-trip, but got %s", tc.input, FormatTimestamp(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+// Package sqliteledger implements cep.Ledger on a SQLite database, so the
+// record of submissions survives restarts and can be joined against business
+// data with plain SQL. It lives in its own module so the core SDK does not
+// depend on a SQLite driver; the driver used is pure Go and needs no cgo.
+//
+//	ledger, err := sqliteledger.Open("ledger.db")
+//	defer ledger.Close()
+//	account := cep.NewCEPAccount(nagURL, chain, version, cep.WithLedger(ledger))
+//
+// Entries are kept in three tables:
+//
+//	submissions(tx_id PRIMARY KEY, address, blockchain, payload_hash, size,
+//	            correlation_id, status, result, submitted, updated)
+//	transitions(tx_id, status, at, detail)
+//	labels(tx_id, key, value)
+//
+// Times are stored as Unix nanoseconds. Transaction IDs and addresses are
+// stored in lowercase without a 0x prefix.
+package sqliteledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS submissions (
+	tx_id          TEXT PRIMARY KEY,
+	address        TEXT NOT NULL,
+	blockchain     TEXT NOT NULL,
+	payload_hash   TEXT NOT NULL,
+	size           INTEGER NOT NULL,
+	correlation_id TEXT NOT NULL,
+	status         TEXT NOT NULL,
+	result         INTEGER NOT NULL,
+	submitted      INTEGER NOT NULL,
+	updated        INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS submissions_status ON submissions (status, submitted);
+CREATE INDEX IF NOT EXISTS submissions_address ON submissions (address, submitted);
+CREATE TABLE IF NOT EXISTS transitions (
+	tx_id  TEXT NOT NULL REFERENCES submissions (tx_id),
+	status TEXT NOT NULL,
+	at     INTEGER NOT NULL,
+	detail TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS transitions_tx_id ON transitions (tx_id, at);
+CREATE TABLE IF NOT EXISTS labels (
+	tx_id TEXT NOT NULL REFERENCES submissions (tx_id),
+	key   TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (tx_id, key)
+);
+CREATE INDEX IF NOT EXISTS labels_key ON labels (key, value);
+`
+
+// Ledger is a cep.Ledger stored in a SQLite database.
+type Ledger struct {
+	db    *sql.DB
+	owned bool
+}
+
+// Open opens or creates the SQLite database at path and returns a Ledger
+// using it. Close closes the database.
+func Open(path string) (*Ledger, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	l, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	l.owned = true
+	return l, nil
+}
+
+// New returns a Ledger using an already open SQLite database, creating its
+// tables if needed. Close leaves the database open.
+func New(db *sql.DB) (*Ledger, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to create the ledger tables: %w", err)
+	}
+	return &Ledger{db: db}, nil
+}
+
+// DB returns the underlying database, for queries joining the ledger with
+// other tables.
+func (l *Ledger) DB() *sql.DB {
+	return l.db
+}
+
+// Close closes the database if it was opened by Open.
+func (l *Ledger) Close() error {
+	if !l.owned {
+		return nil
+	}
+	return l.db.Close()
+}
+
+// Record implements cep.Ledger. Recording a transaction again replaces its
+// entry and history.
+func (l *Ledger) Record(ctx context.Context, entry cep.LedgerEntry) error {
+	txID := normalize(entry.TxID)
+	return l.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM transitions WHERE tx_id = ?`, txID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM labels WHERE tx_id = ?`, txID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO submissions
+			(tx_id, address, blockchain, payload_hash, size, correlation_id, status, result, submitted, updated)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			txID, normalize(entry.Address), entry.Blockchain, entry.PayloadHash, entry.Size, entry.CorrelationID,
+			string(entry.Status), entry.Result, entry.Submitted.UnixNano(), entry.Updated.UnixNano())
+		if err != nil {
+			return err
+		}
+		for _, transition := range entry.History {
+			if err := insertTransition(ctx, tx, txID, transition); err != nil {
+				return err
+			}
+		}
+		for key, value := range entry.Labels {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO labels (tx_id, key, value) VALUES (?, ?, ?)`, txID, key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Transition implements cep.Ledger.
+func (l *Ledger) Transition(ctx context.Context, txID string, transition cep.LedgerTransition) error {
+	id := normalize(txID)
+	return l.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE submissions SET status = ?, updated = ? WHERE tx_id = ?`,
+			string(transition.Status), transition.At.UnixNano(), id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return fmt.Errorf("%w: %s", cep.ErrLedgerEntryNotFound, txID)
+		}
+		return insertTransition(ctx, tx, id, transition)
+	})
+}
+
+// Query implements cep.Ledger.
+func (l *Ledger) Query(ctx context.Context, q cep.LedgerQuery) ([]cep.LedgerEntry, error) {
+	var where []string
+	var args []interface{}
+	if q.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, string(q.Status))
+	}
+	if !q.Since.IsZero() {
+		where = append(where, "submitted >= ?")
+		args = append(args, q.Since.UnixNano())
+	}
+	if q.Address != "" {
+		where = append(where, "address = ?")
+		args = append(args, normalize(q.Address))
+	}
+	for key, value := range q.Labels {
+		where = append(where, "EXISTS (SELECT 1 FROM labels WHERE labels.tx_id = submissions.tx_id AND key = ? AND value = ?)")
+		args = append(args, key, value)
+	}
+	query := `SELECT tx_id, address, blockchain, payload_hash, size, correlation_id, status, result, submitted, updated FROM submissions`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY submitted, tx_id"
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the ledger: %w", err)
+	}
+	var entries []cep.LedgerEntry
+	for rows.Next() {
+		var entry cep.LedgerEntry
+		var status string
+		var submitted, updated int64
+		if err := rows.Scan(&entry.TxID, &entry.Address, &entry.Blockchain, &entry.PayloadHash, &entry.Size, &entry.CorrelationID,
+			&status, &entry.Result, &submitted, &updated); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read the ledger: %w", err)
+		}
+		entry.Status = cep.LedgerStatus(status)
+		entry.Submitted = time.Unix(0, submitted)
+		entry.Updated = time.Unix(0, updated)
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the ledger: %w", err)
+	}
+
+	for i := range entries {
+		if entries[i].History, err = l.history(ctx, entries[i].TxID); err != nil {
+			return nil, err
+		}
+		if entries[i].Labels, err = l.labels(ctx, entries[i].TxID); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// history returns the transitions of txID, oldest first.
+func (l *Ledger) history(ctx context.Context, txID string) ([]cep.LedgerTransition, error) {
+	rows, err := l.db.QueryContext(ctx, `SELECT status, at, detail FROM transitions WHERE tx_id = ? ORDER BY at, rowid`, txID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the history of %s: %w", txID, err)
+	}
+	defer rows.Close()
+	var history []cep.LedgerTransition
+	for rows.Next() {
+		var transition cep.LedgerTransition
+		var status string
+		var at int64
+		if err := rows.Scan(&status, &at, &transition.Detail); err != nil {
+			return nil, fmt.Errorf("failed to read the history of %s: %w", txID, err)
+		}
+		transition.Status = cep.LedgerStatus(status)
+		transition.At = time.Unix(0, at)
+		history = append(history, transition)
+	}
+	return history, rows.Err()
+}
+
+// labels returns the labels of txID, or nil if it has none.
+func (l *Ledger) labels(ctx context.Context, txID string) (map[string]string, error) {
+	rows, err := l.db.QueryContext(ctx, `SELECT key, value FROM labels WHERE tx_id = ?`, txID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the labels of %s: %w", txID, err)
+	}
+	defer rows.Close()
+	var labels map[string]string
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to read the labels of %s: %w", txID, err)
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+	}
+	return labels, rows.Err()
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds.
+func (l *Ledger) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin a ledger transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertTransition(ctx context.Context, tx *sql.Tx, txID string, transition cep.LedgerTransition) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO transitions (tx_id, status, at, detail) VALUES (?, ?, ?, ?)`,
+		txID, string(transition.Status), transition.At.UnixNano(), transition.Detail)
+	return err
+}
+
+// normalize lowercases a hex value and strips its 0x prefix.
+func normalize(value string) string {
+	return strings.TrimPrefix(strings.ToLower(value), "0x")
+}
+
+var _ cep.Ledger = (*Ledger)(nil)
@@ -0,0 +1,96 @@
+package sqliteledger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+func TestLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.db")
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour)
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	for i, entry := range []cep.LedgerEntry{
+		{TxID: "0xAA", Address: "0xABC", Status: cep.LedgerAccepted, Submitted: start, Labels: map[string]string{"tenant": "acme", "env": "prod"}},
+		{TxID: "bb", Address: "abc", Status: cep.LedgerRejected, Result: 108, Size: 42, Submitted: start.Add(time.Minute), Labels: map[string]string{"tenant": "acme"}},
+		{TxID: "cc", Address: "def", Status: cep.LedgerAccepted, Submitted: start.Add(2 * time.Minute)},
+	} {
+		entry.Updated = entry.Submitted
+		entry.History = []cep.LedgerTransition{{Status: entry.Status, At: entry.Submitted}}
+		if err := l.Record(ctx, entry); err != nil {
+			t.Fatalf("Expected entry %d to be recorded, but got: %v", i, err)
+		}
+	}
+	if err := l.Transition(ctx, "0xaa", cep.LedgerTransition{Status: cep.LedgerConfirmed, At: time.Now(), Detail: "Executed"}); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if err := l.Transition(ctx, "dd", cep.LedgerTransition{Status: cep.LedgerConfirmed, At: time.Now()}); !errors.Is(err, cep.ErrLedgerEntryNotFound) {
+		t.Errorf("Expected cep.ErrLedgerEntryNotFound, but got %v", err)
+	}
+	l.Close()
+
+	// The entries survive reopening the database.
+	l, err = Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	defer l.Close()
+
+	testCases := []struct {
+		name     string
+		query    cep.LedgerQuery
+		expected []string
+	}{
+		{"All", cep.LedgerQuery{}, []string{"aa", "bb", "cc"}},
+		{"Status", cep.LedgerQuery{Status: cep.LedgerAccepted}, []string{"cc"}},
+		{"Address", cep.LedgerQuery{Address: "0xAbc"}, []string{"aa", "bb"}},
+		{"Since", cep.LedgerQuery{Since: start.Add(time.Minute)}, []string{"bb", "cc"}},
+		{"Limit", cep.LedgerQuery{Limit: 1}, []string{"aa"}},
+		{"Label", cep.LedgerQuery{Labels: map[string]string{"tenant": "acme"}}, []string{"aa", "bb"}},
+		{"Labels", cep.LedgerQuery{Labels: map[string]string{"tenant": "acme", "env": "prod"}}, []string{"aa"}},
+		{"LabelValue", cep.LedgerQuery{Labels: map[string]string{"tenant": "other"}}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := l.Query(ctx, tc.query)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			var ids []string
+			for _, entry := range entries {
+				ids = append(ids, entry.TxID)
+			}
+			if len(ids) != len(tc.expected) {
+				t.Fatalf("Expected %v, but got %v", tc.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != tc.expected[i] {
+					t.Errorf("Expected %v, but got %v", tc.expected, ids)
+				}
+			}
+		})
+	}
+
+	if entries, _ := l.Query(ctx, cep.LedgerQuery{Status: cep.LedgerRejected}); len(entries) != 1 || entries[0].Size != 42 || entries[0].Result != 108 {
+		t.Errorf("Expected the rejected entry with its size and result, but got %+v", entries)
+	}
+	entries, _ := l.Query(ctx, cep.LedgerQuery{Status: cep.LedgerConfirmed})
+	if len(entries) != 1 || len(entries[0].History) != 2 || entries[0].History[1].Detail != "Executed" {
+		t.Errorf("Expected the confirmed entry with its history, but got %+v", entries)
+	}
+	if len(entries[0].Labels) != 2 || entries[0].Labels["env"] != "prod" {
+		t.Errorf("Expected the confirmed entry with its labels, but got %v", entries[0].Labels)
+	}
+	if entries[0].Submitted.UnixNano() != start.UnixNano() {
+		t.Errorf("Expected the submission time to round-trip, but got %v", entries[0].Submitted)
+	}
+}
@@ -2,48 +2,46 @@ package circular_enterprise_apis
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
+	"sync"
 	"time"
 
-	"github.com/Circular-Protocol/CEP-Go-APIs/internal/utils"
-	"github.com/decred/dcrd/dcrec/secp256k1/v4"
-	decdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
 )
 
 // CEPAccount holds the data for a Circular Enterprise Protocol account.
+// It embeds a Client, so the network configuration and every read-only
+// operation are available directly on the account.
 type CEPAccount struct {
-	Address     string
-	PublicKey   string
-	Info        interface{}
-	CodeVersion string
-	LastError   string
-	NAGURL      string
-	NetworkNode string
-	Blockchain  string
-	LatestTxID  string
-	Nonce       int
-	Data        map[string]interface{}
-	IntervalSec int
-	NetworkURL  string
+	Client
+
+	Address    string
+	PublicKey  string
+	Info       interface{}
+	LastError  string
+	LatestTxID string
+	Nonce      int
+	Data       map[string]interface{}
+
+	// txMu guards LatestTxID against concurrent submissions.
+	txMu sync.Mutex
 }
 
 // NewCEPAccount is a factory function that creates and initializes a new CEPAccount.
-func NewCEPAccount(nagURL, chain, version string) *CEPAccount {
-	return &CEPAccount{
-		CodeVersion: version,
-		NAGURL:      nagURL,
-		Blockchain:  chain,
-		Nonce:       0,
-		Data:        make(map[string]interface{}),
-		IntervalSec: 2,
-	}
+// Options are applied after the defaults have been set.
+func NewCEPAccount(nagURL, chain, version string, opts ...Option) *CEPAccount {
+	a := &CEPAccount{
+		Nonce: 0,
+		Data:  make(map[string]interface{}),
+	}
+	a.Client.init(nagURL, chain, version, opts)
+	return a
 }
 
 // Open sets the account address. This is a prerequisite for many other
@@ -61,6 +59,14 @@ func (a *CEPAccount) Open(address string) error {
 // via the NAG (Network Access Gateway). It updates the account's public key,
 // nonce, and other network-related details.
 func (a *CEPAccount) UpdateAccount() (bool, error) {
+	return a.UpdateAccountContext(context.Background())
+}
+
+// UpdateAccountContext is like UpdateAccount but uses ctx for the network request.
+func (a *CEPAccount) UpdateAccountContext(ctx context.Context) (ok bool, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
 	if a.Address == "" {
 		return false, errors.New("Account is not open")
 	}
@@ -76,203 +82,171 @@ func (a *CEPAccount) UpdateAccount() (bool, error) {
 		Version:    a.CodeVersion,
 	}
 
-	jsonData, err := json.Marshal(requestData)
-	if err != nil {
-		return false, fmt.Errorf("failed to marshal request data: %w", err)
-	}
-
-	// Construct the full URL for the API endpoint
-	url := fmt.Sprintf("%s/Circular_GetWalletNonce_%s", a.NAGURL, a.NetworkNode)
-
-	// Make the HTTP POST request
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return false, fmt.Errorf("http post request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("network request failed with status: %s", resp.Status)
-	}
-
-	// Decode the JSON response
-	var responseData struct {
-		Result   int `json:"Result"`
-		Response struct {
-			Nonce int `json:"Nonce"`
-		} `json:"Response"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&responseData); err != nil {
-		return false, fmt.Errorf("failed to decode response body: %w", err)
-	}
-
-	// Check for a successful result and update the nonce
-	if responseData.Result == 200 {
-		a.Nonce = responseData.Response.Nonce + 1
-		return true, nil
-	}
-
-	return false, errors.New("failed to update account, invalid response from server")
-}
-
-// SetNetwork configures the account to use a specific blockchain network.
-// It fetches the correct Network Access Gateway (NAG) URL for the given
-// network identifier (e.g., "devnet", "testnet", "mainnet") and updates the
-// NAG_URL field on the CEPAccount struct. A custom network URL can also be used.
-func (a *CEPAccount) SetNetwork(network string) error {
-	// Construct the full URL by appending the network identifier to the base network URL.
-	nagURL, err := url.Parse(a.NetworkURL + network)
+	body, err := readAt(ctx, &a.Client, a.NAGURL, "GetWalletNonce", requestData)
 	if err != nil {
-		return fmt.Errorf("invalid network URL: %w", err)
+		return false, err
 	}
 
-	// Perform an HTTP GET request to retrieve network configuration details.
-	resp, err := http.Get(nagURL.String())
+	nonce, err := parseNonceResponse(body, a.ParseMode == ParseStrict)
 	if err != nil {
-		return fmt.Errorf("failed to fetch network URL: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("network request failed with status: %s", resp.Status)
-	}
-
-	// The response body is expected to be a JSON object containing the status
-	// and the specific NAG URL for the requested network. We decode it into a
-	// temporary struct.
-	var result struct {
-		Status  string `json:"status"`
-		URL     string `json:"url"`
-		Message string `json:"message"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return fmt.Errorf("failed to decode network response: %w", err)
+		return false, err
 	}
-
-	// If the request was successful, update the account's NAG_URL.
-	// Otherwise, return an error with the message from the provider.
-	if result.Status == "success" && result.URL != "" {
-		a.NAGURL = result.URL
-	} else {
-		// The 'message' field in the JSON response provides context for the failure.
-		return fmt.Errorf("failed to set network: %s", result.Message)
+	previous := a.Nonce
+	a.Nonce = nonce + 1
+	if a.events != nil {
+		a.events.Publish(NonceResyncedEvent{EventInfo: a.eventInfo(ctx), Address: a.Address, Previous: previous, Nonce: a.Nonce})
 	}
-
-	return nil
+	return true, nil
 }
 
 // Close securely clears all sensitive credential data from the CEPAccount instance.
-// It zeroes out the private key, public key, address, and permissions fields.
+// It zeroes out the public key and address fields. Private keys are never held
+// by the account: they are passed to each call that signs.
 // It is a best practice to call this method when the account object is no longer
 // needed to prevent sensitive data from lingering in the application's memory.
 func (a *CEPAccount) Close() {
 	// Setting the fields to their zero value effectively clears them.
-	a.PrivateKey = nil
 	a.PublicKey = ""
 	a.Address = ""
 }
 
 // SignData creates a cryptographic signature for the given data using the
 // provided private key. It operates by first hashing the input data with
-// SHA-256 and then signing the resulting hash using ECDSA with the secp256k1 curve.
+// SHA-256 and then signing the resulting hash with the account's curve: ECDSA
+// with the secp256k1 curve unless WithCurve selected another.
 //
 // The dataToSign parameter is the raw data to be signed.
 // The privateKeyHex parameter is the hex-encoded private key string.
 //
-// It returns the signature as a hex-encoded string in ASN.1 DER format.
-// An error is returned if the private key is invalid or if the
+// It returns the signature as a hex-encoded string, in ASN.1 DER format for
+// secp256k1. An error is returned if the private key is invalid or if the
 // signing process fails.
 func (a *CEPAccount) SignData(dataToSign []byte, privateKeyHex string) (string, error) {
 	// Decode the hex-encoded private key string into a byte slice.
 	privateKeyBytes, err := hex.DecodeString(utils.HexFix(privateKeyHex))
 	if err != nil {
+		// The decoding error quotes the offending character of the key.
+		if !a.sensitiveLogging {
+			return "", errors.New("invalid private key hex string")
+		}
 		return "", fmt.Errorf("invalid private key hex string: %w", err)
 	}
 
-	// Parse the private key bytes into a secp256k1.PrivateKey object.
-	privateKey := secp256k1.PrivKeyFromBytes(privateKeyBytes)
-	if privateKey == nil {
-		return "", fmt.Errorf("failed to parse private key from bytes")
-	}
-
 	// Hash the input data using SHA-256. The signing algorithm operates on a
 	// fixed-size hash of the data, not the raw data itself.
 	hasher := sha256.New()
 	hasher.Write(dataToSign)
 	hashedData := hasher.Sum(nil)
 
-	// Sign the hashed data with the private key using the secp256k1 library.
-	// The Sign function from decred/dcrd/dcrec/secp256k1/v4/ecdsa is deterministic by default.
-	signature := decdsa.Sign(privateKey, hashedData)
-
-	// The signature is returned as a raw byte slice. We need to serialize it to DER format
-	// for compatibility, as the original function returned ASN.1 DER.
-	return hex.EncodeToString(signature.Serialize()), nil
+	curve := a.signingCurve()
+	signature, err := curve.Sign(privateKeyBytes, hashedData)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign with %s: %w", curve.Name(), err)
+	}
+	return hex.EncodeToString(signature), nil
 }
 
 
 
-// GetTransactionByID retrieves the details of a specific transaction from the blockchain
-// using its unique transaction ID, and optionally a start and end block.
-//
-// The transactionID parameter is the unique string identifying the transaction.
-// The startBlock and endBlock parameters are optional and can be empty strings.
-//
-// On success, it returns a map[string]interface{} containing the transaction
-// details. An error is returned if the NAG_URL is not set, the network request
-// fails, or the response body cannot be properly parsed.
-func (a *CEPAccount) GetTransactionByID(transactionID, startBlock, endBlock string) (map[string]interface{}, error) {
-	// A Network Access Gateway URL must be configured to identify the target network.
-	if a.NAGURL == "" {
-		return nil, fmt.Errorf("network is not set. Please call SetNetwork() first")
-	}
+// certificatePayload is the object that is hex-encoded into the Payload field
+// of a certificate transaction.
+type certificatePayload struct {
+	Data string `json:"data"`
+}
 
-	// Prepare the request payload
-	requestData := struct {
-		TxID  string `json:"TxID"`
-		Start string `json:"Start"`
-		End   string `json:"End"`
-	}{
-		TxID:  transactionID,
-		Start: startBlock,
-		End:   endBlock,
-	}
+// certificateRequest is the body posted to the NAG by SubmitCertificate. Its
+// fields are declared in sorted order so the encoded bytes match what the
+// library historically produced by marshalling a map.
+type certificateRequest struct {
+	Address    string `json:"Address"`
+	Blockchain string `json:"Blockchain"`
+	Curve      string `json:"Curve,omitempty"`
+	ID         string `json:"ID"`
+	Nonce      string `json:"Nonce,omitempty"`
+	Payload    string `json:"Payload"`
+	Signature  string `json:"Signature"`
+	Timestamp  string `json:"Timestamp"`
+	To         string `json:"To,omitempty"`
+	Type       TxType `json:"Type,omitempty"`
+}
 
-	jsonData, err := json.Marshal(requestData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request data: %w", err)
+// encodePayload returns the Payload field for pdata: the hex encoding of the
+// JSON object {"data": pdata}. scratch is used as working space.
+func encodePayload(scratch *bytes.Buffer, pdata string) (string, error) {
+	scratch.Reset()
+	if err := encodeJSON(scratch, certificatePayload{Data: pdata}); err != nil {
+		return "", fmt.Errorf("failed to marshal payload object: %w", err)
 	}
+	payloadStart := scratch.Len()
+	appendHex(scratch, scratch.Bytes())
+	return string(scratch.Bytes()[payloadStart:]), nil
+}
 
-	// Construct the full URL for the API endpoint
-	requestURL := fmt.Sprintf("%s/Circular_GetTransactionbyID_%s", a.NAGURL, a.NetworkNode)
-
-	// Make the HTTP POST request
-	resp, err := http.Post(requestURL, "application/json", bytes.NewBuffer(jsonData))
+// decodePayload reverses encodePayload, returning the data carried by a
+// hex-encoded certificate payload. Payloads encoded with a codec other than
+// JSON are returned as compact JSON with object keys sorted.
+func decodePayload(payload string) (string, error) {
+	decoded, err := hex.DecodeString(utils.HexFix(payload))
 	if err != nil {
-		return nil, fmt.Errorf("http post request failed: %w", err)
+		return "", fmt.Errorf("failed to decode payload hex: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("network request failed with status: %s", resp.Status)
+	if len(decoded) > 0 && decoded[0] == codecFrameMarker {
+		return decodeCodecFrame(decoded)
 	}
-
-	// Read the entire body of the HTTP response.
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	var object certificatePayload
+	if err := json.Unmarshal(decoded, &object); err != nil {
+		return "", fmt.Errorf("failed to decode payload object: %w", err)
 	}
+	return object.Data, nil
+}
+
+// signCertificate computes the transaction ID and signature of a certificate
+// addressed to to, which is empty for certificates without a recipient, and
+// returns the request to post. scratch is used as working space.
+func (a *CEPAccount) signCertificate(ctx context.Context, scratch *bytes.Buffer, blockchain, to, payload, timestamp, privateKey string) (certificateRequest, error) {
+	return a.signTransaction(ctx, scratch, certificateRequest{Blockchain: blockchain, To: to, Payload: payload, Timestamp: timestamp}, privateKey)
+}
 
-	// Unmarshal the JSON response into a map. This provides a flexible structure
-	// for accessing transaction data, which can have a variable schema.
-	var transactionDetails map[string]interface{}
-	if err := json.Unmarshal(body, &transactionDetails); err != nil {
-		return nil, fmt.Errorf("failed to decode transaction JSON: %w", err)
+// signTransaction completes request, whose Blockchain, Payload and Timestamp
+// and optional To, Nonce and Type are set, with the account's address and the
+// transaction ID and signature. The ID is computed as by ComputeTransactionID,
+// and the same string is signed, with the account's Signer if it has one and
+// with privateKey otherwise. scratch is used as working space.
+func (a *CEPAccount) signTransaction(ctx context.Context, scratch *bytes.Buffer, request certificateRequest, privateKey string) (certificateRequest, error) {
+	scratch.Reset()
+	writeTransactionIDInput(scratch, request.Blockchain, a.Address, request.To, request.Payload, request.Nonce, request.Timestamp)
+	str := scratch.Bytes()
+
+	sum := sha256.Sum256(str)
+
+	var signature string
+	if a.signer != nil {
+		raw, err := a.signer.Sign(ctx, SigningRequest{
+			Address:    a.Address,
+			Blockchain: request.Blockchain,
+			TxID:       hex.EncodeToString(sum[:]),
+			Digest:     sum[:],
+			Curve:      a.signingCurve().Name(),
+		})
+		if err != nil {
+			return certificateRequest{}, fmt.Errorf("failed to sign data: %w", err)
+		}
+		if len(raw) == 0 {
+			return certificateRequest{}, fmt.Errorf("failed to sign data: signer returned no signature")
+		}
+		signature = hex.EncodeToString(raw)
+	} else {
+		var err error
+		if signature, err = a.SignData(str, privateKey); err != nil {
+			return certificateRequest{}, fmt.Errorf("failed to sign data: %w", err)
+		}
 	}
 
-	return transactionDetails, nil
+	request.Address = a.Address
+	request.Curve = curveMetadata(a.signingCurve())
+	request.ID = hex.EncodeToString(sum[:])
+	request.Signature = signature
+	return request, nil
 }
 
 // SubmitCertificate sends a given certificate to the blockchain for processing
@@ -286,129 +260,211 @@ func (a *CEPAccount) GetTransactionByID(transactionID, startBlock, endBlock stri
 // the network, which typically includes a transaction hash. An error is returned
 // if the NAG_URL is not set, if the certificate cannot be serialized, or if the
 // network request fails.
-func (a *CEPAccount) SubmitCertificate(pdata string, privateKey string) (map[string]interface{}, error) {
-	// A Network Access Gateway URL must be configured to identify the target network.
-	if a.NAGURL == "" {
-		return nil, fmt.Errorf("network is not set. Please call SetNetwork() first")
-	}
+//
+// Options such as WithChain adjust the individual submission.
+func (a *CEPAccount) SubmitCertificate(pdata string, privateKey string, opts ...SubmitOption) (map[string]interface{}, error) {
+	return a.SubmitCertificateContext(context.Background(), pdata, privateKey, opts...)
+}
+
+// SubmitCertificateContext is like SubmitCertificate but uses ctx for the
+// network request.
+func (a *CEPAccount) SubmitCertificateContext(ctx context.Context, pdata string, privateKey string, opts ...SubmitOption) (response map[string]interface{}, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
 
-	// Create the PayloadObject
-	payloadObject := map[string]interface{}{
-		"data": pdata,
+	done, err := a.beginSubmit()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	// Every attempt that gets this far is audited, including those rejected
+	// before anything is sent.
+	var record AuditRecord
+	if a.auditSink != nil || a.ledger != nil || a.receipts != nil || a.events != nil {
+		record = AuditRecord{
+			Started:       time.Now(),
+			CorrelationID: correlationID,
+			Address:       a.Address,
+			Nonce:         a.Nonce,
+			PayloadHash:   payloadHash(pdata),
+			PayloadSize:   len(pdata),
+		}
+		defer func() { a.audit(ctx, record, response, err) }()
 	}
 
-	// Marshal PayloadObject to JSON string
-	payloadObjectBytes, err := json.Marshal(payloadObject)
+	options := newSubmitOptions(opts)
+
+	// Scratch buffers come from a pool because this path runs once per
+	// certificate and otherwise copies the payload several times over.
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+
+	request, nagURL, err := a.prepareCertificate(ctx, scratch, pdata, privateKey, options, &record, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload object: %w", err)
+		return nil, err
 	}
-	payload := hex.EncodeToString(payloadObjectBytes)
 
-	// Generate Timestamp
-	timestamp := utils.GetFormattedTimestamp()
+	responseMap, err := a.postTransaction(ctx, nagURL, request)
+	if err != nil {
+		return nil, err
+	}
 
-	// Construct the string for hashing
-	str := fmt.Sprintf("%s%s%s%s", a.Address, a.Blockchain, payload, timestamp)
+	if result, ok := responseMap["Result"].(float64); ok && result == 200 {
+		a.txMu.Lock()
+		a.LatestTxID = request.ID
+		a.txMu.Unlock()
+		a.countAccepted(ctx, len(pdata))
+	}
 
-	// Generate ID using SHA-256
-	hasher := sha256.New()
-	hasher.Write([]byte(str))
-	id := hex.EncodeToString(hasher.Sum(nil))
+	return responseMap, nil
+}
 
-	// Call SignData to get the Signature
-	signature, err := a.SignData([]byte(str), privateKey)
+// prepareCertificate validates, encodes and signs pdata for the chain chosen
+// by options, filling in record as it goes, and returns the request together
+// with the NAG it is meant for. The NAG is only required when requireNetwork
+// is set or a pre-flight check has to query it. scratch is used as working
+// space.
+func (a *CEPAccount) prepareCertificate(ctx context.Context, scratch *bytes.Buffer, pdata, privateKey string, options submitOptions, record *AuditRecord, requireNetwork bool) (certificateRequest, string, error) {
+	request, nagURL, err := a.buildPayload(ctx, scratch, pdata, options, requireNetwork || options.preflight != nil)
+	record.Blockchain = request.Blockchain
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign data: %w", err)
+		return certificateRequest{}, "", err
 	}
 
-	// Construct the final data payload for the HTTP request
-	requestData := map[string]interface{}{
-		"ID":         id,
-		"Address":    a.Address,
-		"Blockchain": a.Blockchain,
-		"Payload":    payload,
-		"Timestamp":  timestamp,
-		"Signature":  signature,
+	// Reject oversized payloads before anything is signed or sent.
+	if a.MaxPayloadSize > 0 && len(request.Payload) > a.MaxPayloadSize {
+		return certificateRequest{}, "", &PayloadTooLargeError{Size: len(request.Payload), Limit: a.MaxPayloadSize}
 	}
 
-	jsonData, err := json.Marshal(requestData)
+	if options.preflight != nil {
+		if err := a.preflight(ctx, *options.preflight, request.Blockchain, nagURL, privateKey); err != nil {
+			return certificateRequest{}, "", err
+		}
+	}
+
+	// Generate Timestamp, corrected for clock skew when enabled
+	timestamp := utils.FormatTimestamp(a.now())
+
+	request, err = a.signCertificate(ctx, scratch, request.Blockchain, request.To, request.Payload, timestamp, privateKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request data: %w", err)
+		return certificateRequest{}, "", err
+	}
+	record.Timestamp = timestamp
+	record.TxID = request.ID
+	if options.txID != nil {
+		*options.txID = request.ID
 	}
+	return request, nagURL, nil
+}
 
-	// Create a new HTTP POST request. The body of the request is the JSON payload.
-	req, err := http.NewRequest("POST", a.NAGURL, bytes.NewBuffer(jsonData))
+// buildPayload builds the unsigned certificate transaction for pdata, as
+// submitted by prepareCertificate and sized by EstimateCertificate: it
+// resolves the chain chosen by options, validates pdata against the schema,
+// encrypts it and encodes it with the codec in force, and resolves the
+// recipient. It returns the request with Blockchain, Payload and To set,
+// together with the NAG of the chain, which is only required when
+// requireNetwork is set. The Blockchain is set even when an error is
+// returned, if the chain could be resolved. scratch is used as working space.
+func (a *CEPAccount) buildPayload(ctx context.Context, scratch *bytes.Buffer, pdata string, options submitOptions, requireNetwork bool) (certificateRequest, string, error) {
+	blockchain, nagURL, err := a.resolveChain(options.chain)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return certificateRequest{}, "", err
+	}
+	request := certificateRequest{Blockchain: blockchain}
+
+	// Validate structured data before anything is signed or sent.
+	schema := a.payloadSchema
+	if options.schema != nil {
+		schema = options.schema
+	}
+	if schema != nil {
+		if err := schema.Validate(pdata); err != nil {
+			return request, "", err
+		}
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// Execute the HTTP request using a default client.
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// A Network Access Gateway URL must be configured to identify the target network.
+	if nagURL == "" && requireNetwork {
+		return request, "", fmt.Errorf("network is not set. Please call SetNetwork() first")
+	}
+
+	if options.encryptTo != "" {
+		if pdata, err = EncryptCertificateData(options.encryptTo, pdata); err != nil {
+			return request, "", err
+		}
+	}
+
+	codec := a.payloadCodec
+	if options.codec != nil {
+		codec = options.codec
+	}
+	if isLegacyCodec(codec) {
+		request.Payload, err = encodePayload(scratch, pdata)
+	} else {
+		request.Payload, err = encodeCodecPayload(scratch, pdata, codec)
+	}
+	if err != nil {
+		return request, "", err
+	}
+
+	if options.recipient != "" {
+		if request.To, err = a.resolveRecipient(ctx, blockchain, nagURL, options.recipient); err != nil {
+			return request, "", err
+		}
+	}
+	return request, nagURL, nil
+}
+
+// postTransaction posts a signed transaction to nagURL and returns the
+// decoded response, whatever its Result.
+func (c *Client) postTransaction(ctx context.Context, nagURL string, request certificateRequest) (map[string]interface{}, error) {
+	// Construct the final data payload for the HTTP request. The buffer is
+	// handed to the request body and released when the transport closes it.
+	requestBuf := getBuffer()
+	if err := encodeJSON(requestBuf, request); err != nil {
+		putBuffer(requestBuf)
+		return nil, fmt.Errorf("failed to marshal request data: %w", err)
+	}
+	body := newPooledBody(requestBuf)
+
+	// Send the HTTP POST request using the client. The body of the request
+	// is the JSON payload.
+	c.countSubmission(ctx)
+	if c.events != nil {
+		c.events.Publish(BroadcastEvent{EventInfo: c.eventInfo(ctx), TxID: request.ID, NAGURL: nagURL, Transaction: SignedTransaction(request)})
+	}
+	if c.dryRun != nil {
+		return c.dryRun.submit(ctx, request)
+	}
+	resp, err := c.postJSON(ctx, nagURL, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to submit certificate: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read the response from the network.
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := c.readBody(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Check for non-successful HTTP status codes.
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("network returned an error - status: %s, body: %s", resp.Status, string(body))
+		return nil, fmt.Errorf("network returned an error - status: %w, body: %s", statusError(resp), c.redact(string(respBody)))
 	}
 
 	// Unmarshal the JSON response into a map for flexible access to the result.
 	var responseMap map[string]interface{}
-	if err := json.Unmarshal(body, &responseMap); err != nil {
+	if err := json.Unmarshal(respBody, &responseMap); err != nil {
 		return nil, fmt.Errorf("failed to decode response JSON: %w", err)
 	}
 
-	return responseMap, nil
-}
-
-// GetTransactionOutcome retrieves the final processing result of a transaction
-// from the blockchain using its unique ID. This is often used to confirm
-// whether a submitted transaction was successfully validated and included in a block.
-//
-// The 'transactionID' parameter is the unique string identifying the transaction.
-//
-// It returns a map[string]interface{} containing the outcome details on success.
-// An error is returned if the NAG_URL is not configured, the network request fails,
-// or the JSON response cannot be parsed.
-func (a *CEPAccount) GetTransactionOutcome(TxID string, timeoutSec int) (map[string]interface{}, error) {
-	if a.NAGURL == "" {
-		return nil, fmt.Errorf("network is not set. Please call SetNetwork() first")
-	}
-	startTime := time.Now()
-	timeout := time.Duration(timeoutSec) * time.Second
-
-	for {
-		elapsedTime := time.Since(startTime)
-		if elapsedTime > timeout {
-			return nil, fmt.Errorf("timeout exceeded")
-		}
-
-		data, err := a.GetTransactionByID(TxID, "", "")
-		if err != nil {
-			// Continue polling even if there's an error, in case it's a temporary issue
-			fmt.Printf("Error fetching transaction: %v, polling again...\n", err)
-		} else {
-			// Check for a definitive status
-			if result, ok := data["Result"].(float64); ok && result == 200 {
-				if response, ok := data["Response"].(map[string]interface{}); ok {
-					if status, ok := response["Status"].(string); ok && status != "Pending" {
-						return response, nil // Resolve if transaction is found and not pending
-					}
-				}
-			}
+	if c.ParseMode == ParseStrict {
+		if err := validateFields("AddTransaction", responseMap, fieldSpec{"Result", "number"}); err != nil {
+			return nil, err
 		}
-
-		fmt.Println("Transaction not yet confirmed or not found, polling again...")
-		time.Sleep(time.Duration(a.IntervalSec) * time.Second) // Continue polling
 	}
+	return responseMap, nil
 }
+
@@ -1,6 +1,8 @@
 package circular_enterprise_apis
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -272,28 +274,30 @@ func TestSignData(t *testing.T) {
 		t.Fatalf("Failed to generate private key: %v", err)
 	}
 
+	privateKeyHex := hex.EncodeToString(privateKey.Serialize())
+
 	testCases := []struct {
 		name        string
 		dataToSign  []byte
-		privateKey  *secp256k1.PrivateKey
+		privateKey  string
 		expectError bool
 	}{
 		{
 			name:        "Successful Signing",
 			dataToSign:  []byte("test data to be signed"),
-			privateKey:  privateKey,
+			privateKey:  privateKeyHex,
 			expectError: false,
 		},
 		{
-			name:        "No Private Key",
+			name:        "Invalid Private Key",
 			dataToSign:  []byte("some data"),
-			privateKey:  nil, // Simulate no private key
+			privateKey:  "not hex", // Simulate a malformed private key
 			expectError: true,
 		},
 		{
 			name:        "Empty Data",
 			dataToSign:  []byte(""),
-			privateKey:  privateKey,
+			privateKey:  privateKeyHex,
 			expectError: false, // Signing empty data should still work
 		},
 	}
@@ -301,9 +305,8 @@ func TestSignData(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
-			acc.PrivateKey = tc.privateKey
 
-			signature, err := acc.SignData(tc.dataToSign)
+			signature, err := acc.SignData(tc.dataToSign, tc.privateKey)
 
 			if tc.expectError {
 				if err == nil {
@@ -332,17 +335,17 @@ func TestSignDataRFC6979(t *testing.T) {
 	}
 
 	acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
-	acc.PrivateKey = privateKey
+	privateKeyHex := hex.EncodeToString(privateKey.Serialize())
 
 	data := []byte("test message for RFC 6979")
 
 	// Sign the same data twice.
-	sig1, err1 := acc.SignData(data)
+	sig1, err1 := acc.SignData(data, privateKeyHex)
 	if err1 != nil {
 		t.Fatalf("First signature generation failed: %v", err1)
 	}
 
-	sig2, err2 := acc.SignData(data)
+	sig2, err2 := acc.SignData(data, privateKeyHex)
 	if err2 != nil {
 		t.Fatalf("Second signature generation failed: %v", err2)
 	}
@@ -359,11 +362,6 @@ func TestClose(t *testing.T) {
 	acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
 
 	// Populate fields with dummy values
-	privateKey, err := secp256k1.GeneratePrivateKey()
-	if err != nil {
-		t.Fatalf("Failed to generate private key: %v", err)
-	}
-	acc.PrivateKey = privateKey
 	acc.PublicKey = "testPublicKey"
 	acc.Address = "testAddress"
 
@@ -371,9 +369,6 @@ func TestClose(t *testing.T) {
 	acc.Close()
 
 	// Assert that fields are cleared
-	if acc.PrivateKey != nil {
-		t.Errorf("Expected PrivateKey to be nil, but got %v", acc.PrivateKey)
-	}
 	if acc.PublicKey != "" {
 		t.Errorf("Expected PublicKey to be empty, but got %s", acc.PublicKey)
 	}
@@ -381,7 +376,9 @@ func TestClose(t *testing.T) {
 		t.Errorf("Expected Address to be empty, but got %s", acc.Address)
 	}
 }
-func TestGetTransaction(t *testing.T) {
+// TestGetTransactionInBlockRange covers GetTransactionByID with the search
+// limited to a range of blocks.
+func TestGetTransactionInBlockRange(t *testing.T) {
 	testCases := []struct {
 		name             string
 		transactionHash  string
@@ -393,7 +390,7 @@ func TestGetTransaction(t *testing.T) {
 		expectedResult   map[string]interface{}
 	}{
 		{
-			name:            "Successful GetTransaction",
+			name:            "Successful GetTransactionByID In Range",
 			transactionHash: "0xabcdef123456",
 			mockResponse:    `{"status":"success", "details":"transaction details"}`,
 			mockStatusCode:  http.StatusOK,
@@ -417,7 +414,7 @@ func TestGetTransaction(t *testing.T) {
 			mockStatusCode:   http.StatusNotFound,
 			nagURL:           "http://localhost:8080",
 			expectError:      true,
-			expectedErrorMsg: "network returned an error: 404 Not Found",
+			expectedErrorMsg: "network request failed with status: 404 Not Found",
 		},
 		{
 			name:             "Invalid JSON Response",
@@ -426,7 +423,7 @@ func TestGetTransaction(t *testing.T) {
 			mockStatusCode:   http.StatusOK,
 			nagURL:           "http://localhost:8080",
 			expectError:      true,
-			expectedErrorMsg: "failed to decode transaction details",
+			expectedErrorMsg: "failed to decode transaction JSON",
 		},
 	}
 
@@ -435,6 +432,11 @@ func TestGetTransaction(t *testing.T) {
 			var server *httptest.Server
 			if tc.nagURL != "" {
 				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var request map[string]string
+					json.NewDecoder(r.Body).Decode(&request)
+					if request["Start"] != "10" || request["End"] != "20" {
+						t.Errorf("Expected blocks 10 to 20 to be searched, but got %v", request)
+					}
 					w.WriteHeader(tc.mockStatusCode)
 					w.Write([]byte(tc.mockResponse))
 				}))
@@ -448,7 +450,7 @@ func TestGetTransaction(t *testing.T) {
 				acc.NAGURL = ""
 			}
 
-			result, err := acc.GetTransaction(tc.transactionHash)
+			result, err := acc.GetTransactionByID(tc.transactionHash, "10", "20")
 
 			if tc.expectError {
 				if err == nil {
@@ -510,7 +512,7 @@ func TestGetTransactionByID(t *testing.T) {
 			mockStatusCode:   http.StatusNotFound,
 			nagURL:           "http://localhost:8080",
 			expectError:      true,
-			expectedErrorMsg: "network returned a non-200 status code: 404 Not Found",
+			expectedErrorMsg: "network request failed with status: 404 Not Found",
 		},
 		{
 			name:             "Invalid JSON Response",
@@ -541,7 +543,7 @@ func TestGetTransactionByID(t *testing.T) {
 				acc.NAGURL = ""
 			}
 
-			result, err := acc.GetTransactionByID(tc.transactionID)
+			result, err := acc.GetTransactionByID(tc.transactionID, "", "")
 
 			if tc.expectError {
 				if err == nil {
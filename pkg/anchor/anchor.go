@@ -0,0 +1,303 @@
+// Package anchor runs the batching of package merkle continuously: items are
+// added as they are produced, rolled into a Merkle tree every so many items
+// or so much time, and the root is certified on-chain, after which a receipt
+// holding the inclusion proof is emitted for every item.
+//
+//	service := anchor.New(account, privateKey,
+//		anchor.WithMaxItems(10000),
+//		anchor.WithInterval(5*time.Minute),
+//		anchor.WithReceiptHandler(func(r anchor.Receipt) { ... }))
+//	defer service.Close(context.Background())
+//	...
+//	err := service.Add(record)
+package anchor
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/merkle"
+)
+
+const (
+	// DefaultMaxItems is how many items are batched before the batch is
+	// anchored without waiting for the interval.
+	DefaultMaxItems = 1000
+	// DefaultInterval is how often the items added since the last batch are
+	// anchored.
+	DefaultInterval = time.Minute
+)
+
+// ErrClosed is returned by Add once the Service is closed.
+var ErrClosed = errors.New("anchor service is closed")
+
+// Receipt tells the owner of an item how it was anchored.
+type Receipt struct {
+	// Hash is the payload hash of the item, the leaf of the batch.
+	Hash string
+	// Proof ties Hash to the root certified by the transaction in its
+	// TxID. It is nil when Err is set.
+	Proof *merkle.Proof
+	// Anchored is when the batch was certified.
+	Anchored time.Time
+	// Err holds the reason the batch of the item could not be anchored.
+	// Failed items are not retried; add them again to do so.
+	Err error
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithMaxItems anchors a batch as soon as it holds n items. See
+// DefaultMaxItems.
+func WithMaxItems(n int) Option {
+	return func(s *Service) {
+		s.maxItems = n
+	}
+}
+
+// WithInterval anchors the items added since the last batch every d. Zero or
+// negative leaves batches to WithMaxItems, Flush and Close. See
+// DefaultInterval.
+func WithInterval(d time.Duration) Option {
+	return func(s *Service) {
+		s.interval = d
+	}
+}
+
+// WithReceiptHandler calls fn with the receipt of every item once its batch
+// was anchored, or failed to be. fn is called from the goroutine anchoring the
+// batch, one receipt at a time, so a slow handler delays the next batch.
+func WithReceiptHandler(fn func(Receipt)) Option {
+	return func(s *Service) {
+		s.handler = fn
+	}
+}
+
+// WithSubmitOptions applies opts, such as cep.WithChain, when certifying each
+// root.
+func WithSubmitOptions(opts ...cep.SubmitOption) Option {
+	return func(s *Service) {
+		s.submitOpts = opts
+	}
+}
+
+// WithReceiptIndex adds the receipt of every anchored item, with its proof,
+// to index, where it can be found by payload hash with FindReceipt.
+func WithReceiptIndex(index *cep.ReceiptIndex) Option {
+	return func(s *Service) {
+		s.index = index
+	}
+}
+
+// WithLogger reports failed batches to fn instead of standard output.
+func WithLogger(fn cep.LogFunc) Option {
+	return func(s *Service) {
+		s.logger = fn
+	}
+}
+
+// Service anchors the items added to it in Merkle batches. It is safe for
+// concurrent use.
+type Service struct {
+	account    *cep.CEPAccount
+	privateKey string
+	maxItems   int
+	interval   time.Duration
+	handler    func(Receipt)
+	submitOpts []cep.SubmitOption
+	index      *cep.ReceiptIndex
+	logger     cep.LogFunc
+
+	mu      sync.Mutex
+	pending []string
+	closed  bool
+
+	// flushMu serializes batches so they are anchored in the order their
+	// items were added.
+	flushMu sync.Mutex
+
+	full      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New returns a Service certifying roots as account with privateKey, which
+// may be empty when the account has a Signer, and starts its background
+// goroutine. Close stops it.
+func New(account *cep.CEPAccount, privateKey string, opts ...Option) *Service {
+	s := &Service{
+		account:    account,
+		privateKey: privateKey,
+		maxItems:   DefaultMaxItems,
+		interval:   DefaultInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.maxItems = max(s.maxItems, 1)
+	s.full = make(chan struct{}, 1)
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return s
+}
+
+// Add queues data for the next batch. Its leaf is merkle.HashData(data), so
+// the data itself is neither sent nor kept.
+func (s *Service) Add(data string) error {
+	return s.AddHash(merkle.HashData(data))
+}
+
+// AddHash queues a payload hash, the hex SHA-256 of an item, for the next
+// batch.
+func (s *Service) AddHash(hash string) error {
+	decoded, err := hex.DecodeString(utils.HexFix(hash))
+	if err != nil || len(decoded) != 32 {
+		return fmt.Errorf("invalid hash %q: not a hex SHA-256", hash)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	s.pending = append(s.pending, hex.EncodeToString(decoded))
+	if len(s.pending) >= s.maxItems {
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Pending returns the number of items waiting for the next batch.
+func (s *Service) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Flush anchors the items added so far, in batches of at most WithMaxItems, and
+// returns once their receipts were emitted. It returns the first error, which
+// is also carried by the receipts of the failed batch.
+func (s *Service) Flush(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	var firstErr error
+	for len(pending) > 0 {
+		n := min(len(pending), s.maxItems)
+		if err := s.anchor(ctx, pending[:n]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		pending = pending[n:]
+	}
+	return firstErr
+}
+
+// Close stops the background goroutine and anchors the items still pending,
+// giving up when ctx is done. Add fails with ErrClosed afterwards.
+func (s *Service) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		close(s.stop)
+	})
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.Flush(ctx)
+}
+
+// run anchors a batch every interval and whenever one is full, until Close.
+func (s *Service) run() {
+	defer close(s.done)
+	var tick <-chan time.Time
+	if s.interval > 0 {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-tick:
+		case <-s.full:
+		}
+		if err := s.Flush(context.Background()); err != nil {
+			s.logf(context.Background(), "failed to anchor batch: %v", err)
+		}
+	}
+}
+
+// anchor certifies the root of one batch and emits its receipts.
+func (s *Service) anchor(ctx context.Context, hashes []string) error {
+	tree, err := merkle.NewTree(hashes)
+	var anchored *merkle.Anchor
+	if err == nil {
+		anchored, err = merkle.Certify(ctx, s.account, tree, s.privateKey, s.submitOpts...)
+	}
+	now := time.Now()
+	for i, hash := range hashes {
+		receipt := Receipt{Hash: hash, Anchored: now, Err: err}
+		if err == nil {
+			receipt.Proof, receipt.Err = anchored.Proof(i)
+		}
+		if receipt.Err == nil && s.index != nil {
+			s.indexReceipt(ctx, receipt)
+		}
+		if s.handler != nil {
+			s.handler(receipt)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to anchor %d items: %w", len(hashes), err)
+	}
+	return nil
+}
+
+// indexReceipt adds the receipt of an anchored item to the receipt index.
+// Failures are logged: the item is anchored either way.
+func (s *Service) indexReceipt(ctx context.Context, receipt Receipt) {
+	proof, err := json.Marshal(receipt.Proof)
+	if err == nil {
+		err = s.index.Add(ctx, cep.Receipt{
+			PayloadHash: receipt.Hash,
+			TxID:        receipt.Proof.TxID,
+			Address:     s.account.Address,
+			Recorded:    receipt.Anchored,
+			Proof:       proof,
+		})
+	}
+	if err != nil {
+		s.logf(ctx, "failed to index the receipt of %s: %v", receipt.Hash, err)
+	}
+}
+
+// logf formats a message and hands it to the service's logger.
+func (s *Service) logf(ctx context.Context, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if s.logger != nil {
+		s.logger(ctx, message)
+		return
+	}
+	fmt.Println(message)
+}
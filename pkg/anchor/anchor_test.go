@@ -0,0 +1,161 @@
+package anchor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/merkle"
+)
+
+// collector records the receipts emitted by a Service.
+type collector struct {
+	mu       sync.Mutex
+	receipts []Receipt
+}
+
+func (c *collector) handle(r Receipt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receipts = append(c.receipts, r)
+}
+
+func (c *collector) get() []Receipt {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Receipt(nil), c.receipts...)
+}
+
+func newAccount(t *testing.T, result *atomic.Int32, submissions *atomic.Int32) *cep.CEPAccount {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		submissions.Add(1)
+		fmt.Fprintf(w, `{"Result":%d,"Response":"ok"}`, result.Load())
+	}))
+	t.Cleanup(server.Close)
+	account := cep.NewCEPAccount(server.URL, cep.DefaultChain, cep.LibVersion)
+	account.Open("0x" + strings.Repeat("a", 64))
+	return account
+}
+
+func TestService(t *testing.T) {
+	var result, submissions atomic.Int32
+	result.Store(200)
+	account := newAccount(t, &result, &submissions)
+	privateKey := strings.Repeat("1", 64)
+
+	t.Run("Max Items", func(t *testing.T) {
+		submissions.Store(0)
+		var receipts collector
+		service := New(account, privateKey, WithMaxItems(3), WithInterval(0), WithReceiptHandler(receipts.handle))
+		for i := 0; i < 3; i++ {
+			if err := service.Add(fmt.Sprintf("record %d", i)); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for len(receipts.get()) < 3 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		got := receipts.get()
+		if len(got) != 3 || submissions.Load() != 1 {
+			t.Fatalf("Expected 3 receipts from 1 submission, but got %d from %d", len(got), submissions.Load())
+		}
+		for i, receipt := range got {
+			if receipt.Err != nil || receipt.Hash != merkle.HashData(fmt.Sprintf("record %d", i)) {
+				t.Errorf("Expected the receipt of record %d, but got %+v", i, receipt)
+				continue
+			}
+			if receipt.Proof.TxID == "" || receipt.Proof.Verify() != nil {
+				t.Errorf("Expected a valid anchored proof, but got %+v", receipt.Proof)
+			}
+		}
+		service.Close(context.Background())
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		submissions.Store(0)
+		var receipts collector
+		service := New(account, privateKey, WithInterval(20*time.Millisecond), WithReceiptHandler(receipts.handle))
+		defer service.Close(context.Background())
+		service.Add("a")
+		service.Add("b")
+		deadline := time.Now().Add(5 * time.Second)
+		for len(receipts.get()) < 2 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if len(receipts.get()) != 2 || submissions.Load() != 1 {
+			t.Errorf("Expected 2 receipts from 1 submission, but got %d from %d", len(receipts.get()), submissions.Load())
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		submissions.Store(0)
+		var receipts collector
+		service := New(account, privateKey, WithMaxItems(2), WithInterval(0), WithReceiptHandler(receipts.handle))
+		service.Flush(context.Background())
+		if submissions.Load() != 0 {
+			t.Errorf("Expected an empty flush not to submit, but got %d submissions", submissions.Load())
+		}
+		service.Add("a")
+		if err := service.Close(context.Background()); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(receipts.get()) != 1 || service.Pending() != 0 {
+			t.Errorf("Expected Close to anchor the pending item, but got %d receipts", len(receipts.get()))
+		}
+		if err := service.Add("b"); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, but got %v", err)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		result.Store(108)
+		defer result.Store(200)
+		var receipts collector
+		service := New(account, privateKey, WithInterval(0), WithReceiptHandler(receipts.handle), WithLogger(func(context.Context, string) {}))
+		defer service.Close(context.Background())
+		service.Add("a")
+		var resultErr *cep.ResultError
+		if err := service.Flush(context.Background()); !errors.As(err, &resultErr) {
+			t.Errorf("Expected a *cep.ResultError, but got %v", err)
+		}
+		got := receipts.get()
+		if len(got) != 1 || got[0].Err == nil || got[0].Proof != nil {
+			t.Errorf("Expected a failed receipt, but got %+v", got)
+		}
+	})
+
+	t.Run("Receipt Index", func(t *testing.T) {
+		ctx := context.Background()
+		index := cep.NewReceiptIndex(cep.NewMemoryStore())
+		service := New(account, privateKey, WithInterval(0), WithReceiptIndex(index))
+		service.Add("a")
+		service.Add("b")
+		if err := service.Close(ctx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		receipt, err := index.Find(ctx, merkle.HashData("b"))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		var proof merkle.Proof
+		if err := json.Unmarshal(receipt.Proof, &proof); err != nil || proof.TxID != receipt.TxID || proof.Index != 1 || proof.Verify() != nil {
+			t.Errorf("Expected the indexed receipt to hold a valid proof, but got %s (%v)", receipt.Proof, err)
+		}
+	})
+
+	service := New(account, privateKey)
+	defer service.Close(context.Background())
+	if err := service.AddHash("abcd"); err == nil {
+		t.Error("Expected an error for an invalid hash, but got nil")
+	}
+}
@@ -0,0 +1,65 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"time"
+)
+
+// QueryAPI is the set of read-only network operations. It is implemented by
+// *Client and, through embedding, by *CEPAccount, so code that only reads
+// from the network can depend on the interface and be tested against a mock.
+type QueryAPI interface {
+	SetNetworkContext(ctx context.Context, network string) error
+	DiscoverNAG(ctx context.Context, network string) (string, error)
+	Ping(ctx context.Context) (*PingResult, error)
+	GetTransactionByIDContext(ctx context.Context, transactionID, startBlock, endBlock string) (map[string]interface{}, error)
+	GetTransactionByHash(ctx context.Context, txID string) (map[string]interface{}, error)
+	ResolveDomain(ctx context.Context, domain string) (string, error)
+	GetCertificate(ctx context.Context, txID string) (*RetrievedCertificate, error)
+	FindReceipt(ctx context.Context, payloadHash string) (*Receipt, error)
+	LookupTombstone(ctx context.Context, txID, from string) (*Tombstone, error)
+	IndexTombstones(ctx context.Context, start, end int64) (int, error)
+	GetTransactionOutcomeContext(ctx context.Context, TxID string, timeoutSec int, opts ...PollOption) (map[string]interface{}, error)
+	GetOutcome(ctx context.Context, txID string) (*Outcome, error)
+	WaitForOutcome(ctx context.Context, txID string, timeoutSec int, opts ...PollOption) (*Outcome, error)
+	WaitForOutcomes(ctx context.Context, txIDs []string, timeoutSec int, opts ...PollOption) ([]OutcomeResult, error)
+	GetBlockRangeContext(ctx context.Context, startBlock, endBlock int64, fn func(block map[string]interface{}) error) error
+	IterateBlocks(ctx context.Context, start, end int64, fn func(Block) error, opts ...IterateOption) error
+	GetBlock(ctx context.Context, blockNumber int64) (map[string]interface{}, error)
+	GetBlockCount(ctx context.Context) (map[string]interface{}, error)
+	GetBlockHeight(ctx context.Context) (int64, error)
+	WatchBlocks(ctx context.Context) (<-chan BlockHeader, error)
+	GetWallet(ctx context.Context, address string) (map[string]interface{}, error)
+	CheckWallet(ctx context.Context, address string) (map[string]interface{}, error)
+	GetWalletBalance(ctx context.Context, address, asset string) (map[string]interface{}, error)
+	GetAsset(ctx context.Context, assetName string) (map[string]interface{}, error)
+	GetAssetList(ctx context.Context) (map[string]interface{}, error)
+	GetAssetSupply(ctx context.Context, assetName string) (map[string]interface{}, error)
+}
+
+// AccountAPI adds the operations that act on behalf of an open account to
+// QueryAPI. It is implemented by *CEPAccount.
+type AccountAPI interface {
+	QueryAPI
+
+	Open(address string) error
+	UpdateAccountContext(ctx context.Context) (bool, error)
+	GetAccountInfo(ctx context.Context) (*AccountInfo, error)
+	GetPermissions(ctx context.Context) (*Permissions, error)
+	Preflight(ctx context.Context, p Preflight) error
+	EstimateCertificate(ctx context.Context, pdata string, opts ...SubmitOption) (*Estimate, error)
+	Summary(ctx context.Context, since time.Time) (*ActivitySummary, error)
+	SignData(dataToSign []byte, privateKeyHex string) (string, error)
+	SubmitCertificateContext(ctx context.Context, pdata string, privateKey string, opts ...SubmitOption) (map[string]interface{}, error)
+	SignCertificate(ctx context.Context, pdata, privateKey string, opts ...SubmitOption) (*SignedTransaction, error)
+	IssueTombstone(ctx context.Context, txID string, action TombstoneAction, reason, privateKey string, opts ...SubmitOption) (*Tombstone, error)
+	Shutdown(ctx context.Context) error
+	Close()
+}
+
+// Compile-time checks that the concrete types satisfy the interfaces.
+var (
+	_ QueryAPI   = (*Client)(nil)
+	_ QueryAPI   = (*CEPAccount)(nil)
+	_ AccountAPI = (*CEPAccount)(nil)
+)
@@ -0,0 +1,59 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubQuery implements QueryAPI by embedding the interface and overriding only
+// the method under test, the way downstream code is expected to mock it.
+type stubQuery struct {
+	QueryAPI
+	wallet map[string]interface{}
+	err    error
+}
+
+func (s *stubQuery) GetWallet(ctx context.Context, address string) (map[string]interface{}, error) {
+	return s.wallet, s.err
+}
+
+// walletExists stands in for business logic written against QueryAPI.
+func walletExists(ctx context.Context, q QueryAPI, address string) bool {
+	wallet, err := q.GetWallet(ctx, address)
+	return err == nil && wallet["Result"] == float64(200)
+}
+
+func TestQueryAPIMock(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stub     *stubQuery
+		expected bool
+	}{
+		{
+			name:     "Wallet Found",
+			stub:     &stubQuery{wallet: map[string]interface{}{"Result": float64(200)}},
+			expected: true,
+		},
+		{
+			name:     "Network Error",
+			stub:     &stubQuery{err: errors.New("unreachable")},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := walletExists(context.Background(), tc.stub, "0xabc"); got != tc.expected {
+				t.Errorf("Expected %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAccountImplementsAccountAPI(t *testing.T) {
+	var api AccountAPI = NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
+	if err := api.Open("0xabc"); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditOutcome summarises how a submission attempt ended.
+type AuditOutcome string
+
+const (
+	// AuditAccepted is a submission the NAG accepted with Result 200.
+	AuditAccepted AuditOutcome = "accepted"
+	// AuditRejected is a submission the NAG answered with another Result.
+	AuditRejected AuditOutcome = "rejected"
+	// AuditFailed is a submission that failed before the NAG answered, for
+	// example during validation or because of a network error.
+	AuditFailed AuditOutcome = "failed"
+)
+
+// AuditRecord describes one submission attempt. Records are passed by value
+// and never modified after they are handed to a sink.
+type AuditRecord struct {
+	Started       time.Time `json:"started"`
+	Completed     time.Time `json:"completed"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	Address       string    `json:"address"`
+	Blockchain    string    `json:"blockchain,omitempty"`
+	Nonce         int       `json:"nonce"`
+	// PayloadHash is the hex SHA-256 of the certificate data, so a record can
+	// be matched to its data without storing the data itself.
+	PayloadHash string `json:"payloadHash"`
+	// PayloadSize is the size of the certificate data in bytes.
+	PayloadSize int `json:"payloadSize"`
+	// Timestamp is the certificate timestamp that was signed, if signing
+	// was reached.
+	Timestamp string       `json:"timestamp,omitempty"`
+	TxID      string       `json:"txId,omitempty"`
+	Outcome   AuditOutcome `json:"outcome"`
+	// Result is the NAG result code, or zero if the NAG did not answer.
+	Result int    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Labels are the labels of the account; see WithLabels.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AuditSink receives a record for every submission attempt. Record is called
+// synchronously once the attempt has finished; an error is logged but does
+// not change the result of the submission.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// WithAuditSink sends a record of every certificate submission to sink.
+func WithAuditSink(sink AuditSink) Option {
+	return func(c *Client) {
+		c.auditSink = sink
+	}
+}
+
+// audit completes record with the result of a submission and hands it to the
+// client's sink, ledger, receipt index and event bus.
+func (c *Client) audit(ctx context.Context, record AuditRecord, response map[string]interface{}, err error) {
+	record.Completed = time.Now()
+	record.Labels = c.Labels()
+	switch {
+	case err != nil:
+		record.Outcome = AuditFailed
+		record.Error = err.Error()
+	default:
+		result, _ := response["Result"].(float64)
+		record.Result = int(result)
+		record.Outcome = AuditRejected
+		if result == 200 {
+			record.Outcome = AuditAccepted
+		}
+	}
+	if c.ledger != nil {
+		c.recordLedger(ctx, record)
+	}
+	if c.receipts != nil && record.Outcome == AuditAccepted {
+		c.indexReceipt(ctx, record)
+	}
+	if c.events != nil {
+		c.emitSubmission(ctx, record.TxID, record.Address, record.Blockchain, response, err)
+	}
+	if c.auditSink == nil {
+		return
+	}
+	if sinkErr := c.auditSink.Record(ctx, record); sinkErr != nil {
+		c.logf(ctx, "failed to record audit entry for %s: %v", record.TxID, sinkErr)
+	}
+}
+
+// payloadHash returns the hex SHA-256 of data.
+func payloadHash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// JSONLinesAuditSink writes each record as a line of JSON. It is safe for
+// concurrent use.
+type JSONLinesAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLinesAuditSink returns a sink writing to w.
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// OpenAuditLog opens, or creates, the file at path for appending and returns a
+// sink writing to it. The file is readable by its owner only.
+func OpenAuditLog(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return NewJSONLinesAuditSink(f), nil
+}
+
+// Record writes record followed by a newline in a single write.
+func (s *JSONLinesAuditSink) Record(ctx context.Context, record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (s *JSONLinesAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
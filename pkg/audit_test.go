@@ -0,0 +1,104 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// memoryAuditSink keeps records in memory.
+type memoryAuditSink struct {
+	records []AuditRecord
+}
+
+func (s *memoryAuditSink) Record(ctx context.Context, record AuditRecord) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestSubmissionAudit(t *testing.T) {
+	result := `{"Result":200,"Response":{"TxID":"abc"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(result))
+	}))
+	defer server.Close()
+
+	sink := &memoryAuditSink{}
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithAuditSink(sink))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+
+	acc.SubmitCertificate("data", privateKey)
+	result = `{"Result":108,"Response":"Invalid signature"}`
+	acc.SubmitCertificate("data", privateKey)
+	acc.SubmitCertificate("data", "not a key")
+
+	if len(sink.records) != 3 {
+		t.Fatalf("Expected 3 records, but got %d", len(sink.records))
+	}
+
+	accepted := sink.records[0]
+	if accepted.Outcome != AuditAccepted || accepted.Result != 200 || accepted.TxID != acc.LatestTxID {
+		t.Errorf("Expected an accepted record for %s, but got %+v", acc.LatestTxID, accepted)
+	}
+	if accepted.PayloadHash != payloadHash("data") || accepted.Blockchain != DefaultChain || accepted.Timestamp == "" {
+		t.Errorf("Expected payload hash, blockchain and timestamp to be recorded, but got %+v", accepted)
+	}
+	if accepted.Completed.Before(accepted.Started) {
+		t.Errorf("Expected completion after start, but got %v before %v", accepted.Completed, accepted.Started)
+	}
+
+	if rejected := sink.records[1]; rejected.Outcome != AuditRejected || rejected.Result != 108 {
+		t.Errorf("Expected a rejected record with result 108, but got %+v", rejected)
+	}
+	if failed := sink.records[2]; failed.Outcome != AuditFailed || failed.Error == "" || failed.TxID != "" {
+		t.Errorf("Expected a failed record without a TxID, but got %+v", failed)
+	}
+}
+
+func TestJSONLinesAuditSink(t *testing.T) {
+	t.Run("Writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := NewJSONLinesAuditSink(&buf)
+		sink.Record(context.Background(), AuditRecord{TxID: "a", Outcome: AuditAccepted})
+		sink.Record(context.Background(), AuditRecord{TxID: "b", Outcome: AuditFailed})
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 lines, but got %d", len(lines))
+		}
+		var record AuditRecord
+		if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if record.TxID != "b" || record.Outcome != AuditFailed {
+			t.Errorf("Expected record b, but got %+v", record)
+		}
+	})
+
+	t.Run("File Appends", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		for _, id := range []string{"a", "b"} {
+			sink, err := OpenAuditLog(path)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			sink.Record(context.Background(), AuditRecord{TxID: id})
+			sink.Close()
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if lines := strings.Count(string(data), "\n"); lines != 2 {
+			t.Errorf("Expected 2 lines, but got %d", lines)
+		}
+	})
+}
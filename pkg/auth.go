@@ -0,0 +1,230 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HeaderAuthorization carries the bearer token from a TokenProvider.
+const HeaderAuthorization = "Authorization"
+
+// tokenExpiryDelta is how long before its expiry a cached token is refreshed,
+// so a token does not expire while a request is in flight.
+const tokenExpiryDelta = 30 * time.Second
+
+// Token is an access token for a private NAG deployment.
+type Token struct {
+	AccessToken string
+	// TokenType is the authorization scheme; empty means "Bearer".
+	TokenType string
+	// Expiry is when the token expires; zero means it does not.
+	Expiry time.Time
+}
+
+// valid reports whether the token can still be used at now.
+func (t *Token) valid(now time.Time) bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || now.Add(tokenExpiryDelta).Before(t.Expiry))
+}
+
+// authorization returns the Authorization header value for the token.
+func (t *Token) authorization() string {
+	scheme := t.TokenType
+	if scheme == "" || strings.EqualFold(scheme, "bearer") {
+		scheme = "Bearer"
+	}
+	return scheme + " " + t.AccessToken
+}
+
+// TokenProvider supplies the access token sent with every request. Token is
+// called once per request attempt and must be safe for concurrent use;
+// providers that fetch tokens should cache them until they expire.
+type TokenProvider interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenInvalidator is implemented by providers that cache tokens. When the
+// NAG answers 401 Unauthorized, Invalidate is called so the next request
+// fetches a new token.
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// WithTokenProvider authenticates every request to the NAG with a token from
+// provider in the Authorization header, replacing any Authorization set with
+// WithHeaders.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *Client) {
+		c.tokenProvider = provider
+	}
+}
+
+// StaticToken returns a provider that always supplies the given bearer token.
+func StaticToken(accessToken string) TokenProvider {
+	return staticToken{&Token{AccessToken: accessToken}}
+}
+
+type staticToken struct{ token *Token }
+
+func (s staticToken) Token(context.Context) (*Token, error) { return s.token, nil }
+
+// authorize sets the Authorization header of req from the client's token
+// provider, if any.
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	if c.tokenProvider == nil {
+		return nil
+	}
+	token, err := c.tokenProvider.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to obtain access token: %w", err)
+	}
+	req.Header.Set(HeaderAuthorization, token.authorization())
+	return nil
+}
+
+// observeAuth invalidates the cached token when the NAG rejects it.
+func (c *Client) observeAuth(resp *http.Response) {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return
+	}
+	if invalidator, ok := c.tokenProvider.(TokenInvalidator); ok {
+		invalidator.Invalidate()
+	}
+}
+
+// TokenError reports a failed request to an OAuth2 token endpoint. It never
+// includes the client secret.
+type TokenError struct {
+	StatusCode int
+	// Code and Description are the "error" and "error_description" fields
+	// of the response, if present.
+	Code        string
+	Description string
+}
+
+// Error implements the error interface.
+func (e *TokenError) Error() string {
+	msg := fmt.Sprintf("token endpoint returned status %d", e.StatusCode)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Description != "" {
+		msg += " (" + e.Description + ")"
+	}
+	return msg
+}
+
+// Retryable reports whether the token endpoint failed on its side.
+func (e *TokenError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// ClientCredentials is a TokenProvider for the OAuth2 client credentials
+// grant (RFC 6749 section 4.4). Tokens are cached until shortly before they
+// expire, and concurrent requests share a single fetch.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// EndpointParams are sent with the token request, for example an
+	// "audience" required by the identity provider.
+	EndpointParams url.Values
+	// HTTPClient is used for the token request. When nil, the library's
+	// shared client is used.
+	HTTPClient *http.Client
+
+	mu    sync.Mutex
+	token *Token
+	now   func() time.Time
+}
+
+// Token returns the cached token, fetching a new one when it is missing or
+// about to expire.
+func (cc *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	now := time.Now
+	if cc.now != nil {
+		now = cc.now
+	}
+	if cc.token.valid(now()) {
+		return cc.token, nil
+	}
+	token, err := cc.fetch(ctx, now())
+	if err != nil {
+		return nil, err
+	}
+	cc.token = token
+	return token, nil
+}
+
+// Invalidate discards the cached token.
+func (cc *ClientCredentials) Invalidate() {
+	cc.mu.Lock()
+	cc.token = nil
+	cc.mu.Unlock()
+}
+
+// fetch requests a new token. The client is authenticated with HTTP Basic
+// authentication, as RFC 6749 requires servers to support.
+func (cc *ClientCredentials) fetch(ctx context.Context, now time.Time) (*Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(cc.Scopes) > 0 {
+		form.Set("scope", strings.Join(cc.Scopes, " "))
+	}
+	for key, values := range cc.EndpointParams {
+		form[key] = values
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cc.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(cc.ClientID), url.QueryEscape(cc.ClientSecret))
+
+	client := cc.HTTPClient
+	if client == nil {
+		client = sharedHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var response struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		ExpiresIn        json.Number `json:"expires_in"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	decodeErr := json.Unmarshal(body, &response)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &TokenError{StatusCode: resp.StatusCode, Code: response.Error, Description: response.ErrorDescription}
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", decodeErr)
+	}
+	if response.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+
+	token := &Token{AccessToken: response.AccessToken, TokenType: response.TokenType}
+	if seconds, err := response.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		token.Expiry = now.Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,139 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientCredentials(t *testing.T) {
+	var fetches int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		r.ParseForm()
+		if id != "client" || secret != "s3cret" || r.Form.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"bad credentials"}`))
+			return
+		}
+		if r.Form.Get("scope") != "nag.read nag.write" || r.Form.Get("audience") != "nag" {
+			t.Errorf("Expected scopes and audience in the token request, but got %v", r.Form)
+		}
+		n := atomic.AddInt32(&fetches, 1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600}`, n)
+	}))
+	defer tokenServer.Close()
+
+	var mu sync.Mutex
+	var authorizations []string
+	nag := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get(HeaderAuthorization))
+		mu.Unlock()
+		if r.Header.Get(HeaderAuthorization) == "Bearer revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":1}`))
+	}))
+	defer nag.Close()
+
+	now := time.Now()
+	provider := &ClientCredentials{
+		TokenURL:       tokenServer.URL,
+		ClientID:       "client",
+		ClientSecret:   "s3cret",
+		Scopes:         []string{"nag.read", "nag.write"},
+		EndpointParams: map[string][]string{"audience": {"nag"}},
+		now:            func() time.Time { return now },
+	}
+	c := NewClient(nag.URL, DefaultChain, LibVersion,
+		WithHeaders(map[string]string{HeaderAuthorization: "ignored"}),
+		WithTokenProvider(provider))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetBlockCount(context.Background()); err != nil {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if fetches != 1 {
+		t.Errorf("Expected concurrent requests to share one token fetch, but got %d", fetches)
+	}
+	for _, authorization := range authorizations {
+		if authorization != "Bearer token-1" {
+			t.Errorf("Expected the cached bearer token, but got %q", authorization)
+		}
+	}
+
+	t.Run("Refresh Before Expiry", func(t *testing.T) {
+		now = now.Add(time.Hour - tokenExpiryDelta)
+		c.GetBlockCount(context.Background())
+		if fetches != 2 || authorizations[len(authorizations)-1] != "Bearer token-2" {
+			t.Errorf("Expected a new token near expiry, but got %d fetches and %q", fetches, authorizations[len(authorizations)-1])
+		}
+	})
+
+	t.Run("Invalidate On 401", func(t *testing.T) {
+		provider.mu.Lock()
+		provider.token.AccessToken = "revoked"
+		provider.mu.Unlock()
+		if _, err := c.GetBlockCount(context.Background()); err == nil {
+			t.Fatal("Expected the revoked token to be rejected")
+		}
+		if _, err := c.GetBlockCount(context.Background()); err != nil {
+			t.Fatalf("Expected a fresh token after the 401, but got: %v", err)
+		}
+		if fetches != 3 {
+			t.Errorf("Expected the 401 to trigger a new fetch, but got %d fetches", fetches)
+		}
+	})
+
+	t.Run("Bad Credentials", func(t *testing.T) {
+		bad := NewClient(nag.URL, DefaultChain, LibVersion, WithTokenProvider(&ClientCredentials{
+			TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "wrong-secret",
+		}))
+		_, err := bad.GetBlockCount(context.Background())
+		var tokenErr *TokenError
+		if !errors.As(err, &tokenErr) || tokenErr.Code != "invalid_client" || tokenErr.Description != "bad credentials" {
+			t.Fatalf("Expected a *TokenError, but got %v", err)
+		}
+		if strings.Contains(err.Error(), "wrong-secret") {
+			t.Error("Expected the error not to contain the client secret")
+		}
+		if IsRetryable(err) {
+			t.Error("Expected rejected credentials not to be retryable")
+		}
+	})
+}
+
+func TestStaticToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get(HeaderAuthorization)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":1}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithTokenProvider(StaticToken("abc")))
+	if _, err := c.GetBlockCount(context.Background()); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if authorization != "Bearer abc" {
+		t.Errorf("Expected Bearer abc, but got %q", authorization)
+	}
+}
@@ -0,0 +1,236 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotStarted is matched by errors.Is for the items of a batch that were not
+// started because its context was done. Their errors also match the context's
+// error. Unlike items cut off in flight, they certainly had no effect, so a
+// checkpointed import can safely submit them again.
+var ErrNotStarted = errors.New("batch item not started")
+
+// DefaultBatchConcurrency is the number of requests a batch keeps in flight
+// at once.
+const DefaultBatchConcurrency = 8
+
+// WithBatchConcurrency sets how many requests SubmitBatch and
+// GetTransactionsByIDs keep in flight at once. Values below one are treated
+// as one.
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) {
+		c.BatchConcurrency = n
+	}
+}
+
+// SubmitResult is the outcome of one certificate submitted by SubmitBatch or
+// a SubmitQueue.
+// Unlike SubmitCertificateContext, a certificate the NAG rejects has Err set
+// to a *ResultError, with the NAG's answer still in Response.
+type SubmitResult struct {
+	// TxID is the ID of the signed transaction; empty if the certificate
+	// failed before it was signed.
+	TxID     string
+	Response map[string]interface{}
+	Err      error
+	// DeadLetterID is the ID under which a failed certificate was stored
+	// when the client has a dead-letter store.
+	DeadLetterID string
+}
+
+// OutcomeResult is the outcome of one transaction polled by WaitForOutcomes.
+type OutcomeResult struct {
+	TxID    string
+	Outcome *Outcome
+	Err     error
+}
+
+// TransactionResult is the outcome of one lookup in a GetTransactionsByIDs
+// call. A Result other than 200, such as a transaction that is not found,
+// sets Err to a *ResultError.
+type TransactionResult struct {
+	TxID        string
+	Transaction map[string]interface{}
+	Err         error
+}
+
+// BatchError reports the items of a batch that failed. The results returned
+// alongside it hold the error of each item.
+type BatchError struct {
+	Total  int
+	Errors []error
+	// NotStarted counts the failed items that were not started because the
+	// batch's context was done; see ErrNotStarted.
+	NotStarted int
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d batch items failed, first error: %v", len(e.Errors), e.Total, e.Errors[0])
+}
+
+// Unwrap returns the errors of the failed items, so errors.Is and errors.As
+// match any of them.
+func (e *BatchError) Unwrap() []error {
+	return e.Errors
+}
+
+// SubmitBatch submits each entry of data as a certificate and returns the
+// results in the same order. The gateway has no multi-transaction endpoint,
+// so the certificates are sent as concurrent single submissions, at most
+// BatchConcurrency at a time; each is validated, audited and retried exactly
+// as by SubmitCertificateContext. If any certificate fails, the error is a
+// *BatchError and the results still describe every entry.
+//
+// When ctx is done, typically because its deadline expired, the certificates
+// already accepted keep their results. Entries not started yet fail with an
+// error matching ErrNotStarted and can be submitted again. Entries cut off in
+// flight fail with the context's error; if they have a TxID they may still
+// have been accepted, which GetOutcome tells.
+//
+// With WithDeadLetterStore, every certificate that fails is also stored as a
+// DeadLetter, to be inspected and retried with RetryDeadLetter, except those
+// that failed only because ctx was done.
+func (a *CEPAccount) SubmitBatch(ctx context.Context, data []string, privateKey string, opts ...SubmitOption) ([]SubmitResult, error) {
+	results := make([]SubmitResult, len(data))
+	runBatch(ContextWithBulk(ctx), len(data), a.BatchConcurrency, func(ctx context.Context, i int) {
+		results[i] = a.submitItem(ctx, data[i], privateKey, opts)
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return results, newBatchError(errs)
+}
+
+// submitItem submits one certificate of a batch or queue. A Result other than
+// 200 becomes a *ResultError, and a failed certificate is dead-lettered unless
+// it failed only because ctx is done.
+func (a *CEPAccount) submitItem(ctx context.Context, data, privateKey string, opts []SubmitOption) SubmitResult {
+	ctx, correlationID := withCorrelationID(ctx)
+	var result SubmitResult
+	itemOpts := append(opts[:len(opts):len(opts)], CaptureTxID(&result.TxID))
+	result.Response, result.Err = a.SubmitCertificateContext(ctx, data, privateKey, itemOpts...)
+	if result.Err == nil {
+		result.Err = a.resultError("AddTransaction", result.Response)
+		annotateError(&result.Err, correlationID)
+	}
+	if result.Err != nil && a.deadLetters != nil && (ctx.Err() == nil || !errors.Is(result.Err, ctx.Err())) {
+		result.DeadLetterID = a.deadLetter(context.WithoutCancel(ctx), data, result.TxID, result.Err)
+	}
+	return result
+}
+
+// GetTransactionsByIDs looks up each transaction ID between startBlock and
+// endBlock and returns the results in the same order. As with SubmitBatch,
+// the lookups are concurrent single calls, at most BatchConcurrency at a
+// time, and the error is a *BatchError if any of them fails.
+func (c *Client) GetTransactionsByIDs(ctx context.Context, txIDs []string, startBlock, endBlock string) ([]TransactionResult, error) {
+	results := make([]TransactionResult, len(txIDs))
+	for i, txID := range txIDs {
+		results[i].TxID = txID
+	}
+	runBatch(ContextWithBulk(ctx), len(txIDs), c.BatchConcurrency, func(ctx context.Context, i int) {
+		results[i].Transaction, results[i].Err = c.GetTransactionByIDContext(ctx, txIDs[i], startBlock, endBlock)
+		if results[i].Err == nil {
+			results[i].Err = c.resultError("GetTransactionbyID", results[i].Transaction)
+		}
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return results, newBatchError(errs)
+}
+
+// WaitForOutcomes polls each transaction ID as by WaitForOutcome and returns
+// the results in the same order, at most BatchConcurrency transactions at a
+// time. As with SubmitBatch, the error is a *BatchError if any of them fails,
+// and when ctx is done the outcomes already known are still returned while
+// the other transactions fail with the context's error, matching
+// ErrNotStarted for those not polled at all.
+func (c *Client) WaitForOutcomes(ctx context.Context, txIDs []string, timeoutSec int, opts ...PollOption) ([]OutcomeResult, error) {
+	results := make([]OutcomeResult, len(txIDs))
+	for i, txID := range txIDs {
+		results[i].TxID = txID
+	}
+	runBatch(ContextWithBulk(ctx), len(txIDs), c.BatchConcurrency, func(ctx context.Context, i int) {
+		results[i].Outcome, results[i].Err = c.WaitForOutcome(ctx, txIDs[i], timeoutSec, opts...)
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return results, newBatchError(errs)
+}
+
+// newBatchError returns a *BatchError for the non-nil errors of a batch of
+// len(errs) items, or nil if there are none.
+func newBatchError(errs []error) error {
+	batchErr := &BatchError{Total: len(errs)}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		batchErr.Errors = append(batchErr.Errors, err)
+		if errors.Is(err, ErrNotStarted) {
+			batchErr.NotStarted++
+		}
+	}
+	if len(batchErr.Errors) == 0 {
+		return nil
+	}
+	return batchErr
+}
+
+// runBatch calls run for each of n items, at most limit at a time, and waits
+// for them to finish. Values of limit below one are treated as one. Items not
+// started when ctx is done are passed to skip with an error matching both
+// ErrNotStarted and the context's error instead. The batch methods pass the
+// client's BatchConcurrency and a bulk context.
+func runBatch(ctx context.Context, n, limit int, run func(ctx context.Context, i int), skip func(i int, err error)) {
+	if limit < 1 {
+		limit = 1
+	}
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		// A free slot must not win over a context that is already done, nor
+		// over one that ended while waiting for the slot.
+		started := false
+		if ctx.Err() == nil {
+			select {
+			case slots <- struct{}{}:
+				started = true
+				if ctx.Err() != nil {
+					<-slots
+					started = false
+				}
+			case <-ctx.Done():
+			}
+		}
+		if !started {
+			skip(i, fmt.Errorf("%w: %w", ErrNotStarted, ctx.Err()))
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			run(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,160 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubmitBatch(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := decodePayload(body["Payload"].(string))
+		w.WriteHeader(http.StatusOK)
+		if data == "bad" {
+			w.Write([]byte(`{"Result":108,"Response":"Invalid Payload"}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"ok"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithBatchConcurrency(2))
+	acc.Open("0x" + strings.Repeat("a", 64))
+
+	data := make([]string, 10)
+	for i := range data {
+		data[i] = fmt.Sprintf("certificate %d", i)
+	}
+	results, err := acc.SubmitBatch(context.Background(), data, strings.Repeat("1", 64))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if len(results) != len(data) {
+		t.Fatalf("Expected %d results, but got %d", len(data), len(results))
+	}
+	seen := make(map[string]bool)
+	for i, result := range results {
+		if result.Err != nil || result.TxID == "" || result.Response["Result"] != float64(200) {
+			t.Errorf("Expected result %d to succeed with a TxID, but got %+v", i, result)
+		}
+		seen[result.TxID] = true
+	}
+	if len(seen) != len(data) {
+		t.Errorf("Expected %d distinct TxIDs, but got %d", len(data), len(seen))
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 2 {
+		t.Errorf("Expected at most 2 requests in flight, but got %d", max)
+	}
+
+	t.Run("Partial Failure", func(t *testing.T) {
+		results, err := acc.SubmitBatch(context.Background(), []string{"good", "bad"}, strings.Repeat("1", 64))
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || batchErr.Total != 2 || len(batchErr.Errors) != 1 {
+			t.Fatalf("Expected a *BatchError with one failure, but got %v", err)
+		}
+		if results[0].Err != nil || results[1].Response["Result"] != float64(108) {
+			t.Errorf("Expected the results to describe each item, but got %+v", results)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := acc.SubmitBatch(ctx, data, strings.Repeat("1", 64))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, but got %v", err)
+		}
+		for i, result := range results {
+			if !errors.Is(result.Err, ErrNotStarted) || !errors.Is(result.Err, context.Canceled) {
+				t.Errorf("Expected result %d not to be started, but got %v", i, result.Err)
+			}
+		}
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || batchErr.NotStarted != len(data) {
+			t.Errorf("Expected %d items not started, but got %v", len(data), err)
+		}
+	})
+}
+
+func TestWaitForOutcomes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["TxID"] == "pending" {
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Pending"}}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","BlockID":"7","BlockTimestamp":"t","Position":0,"NodeID":"n"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithBatchConcurrency(1), WithLogger(func(context.Context, string) {}))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	txIDs := []string{"aa", "pending", "bb", "cc"}
+	results, err := c.WaitForOutcomes(ctx, txIDs, 5, WithPollStrategy(FixedPoll{Interval: 10 * time.Millisecond}))
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 3 || batchErr.NotStarted != 2 {
+		t.Fatalf("Expected a *BatchError with 3 failures, 2 not started, but got %v", err)
+	}
+	if results[0].Outcome == nil || results[0].Outcome.Status != TxConfirmed {
+		t.Errorf("Expected the outcome polled before the deadline to be kept, but got %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, context.DeadlineExceeded) || errors.Is(results[1].Err, ErrNotStarted) {
+		t.Errorf("Expected the pending transaction to be cut off by the deadline, but got %v", results[1].Err)
+	}
+	for _, result := range results[2:] {
+		if !errors.Is(result.Err, ErrNotStarted) || result.TxID == "" {
+			t.Errorf("Expected %s not to be polled, but got %v", result.TxID, result.Err)
+		}
+	}
+}
+
+func TestGetTransactionsByIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+		if body["TxID"] == "missing" {
+			w.Write([]byte(`{"Result":118,"Response":"Transaction Not Found"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"Result":200,"Response":{"ID":"%s","Status":"Executed"}}`, body["TxID"])
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	results, err := c.GetTransactionsByIDs(context.Background(), []string{"aa", "missing", "bb"}, "0", "10")
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 {
+		t.Fatalf("Expected a *BatchError with one failure, but got %v", err)
+	}
+	for i, txID := range []string{"aa", "missing", "bb"} {
+		if results[i].TxID != txID {
+			t.Errorf("Expected result %d to be for %s, but got %s", i, txID, results[i].TxID)
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil || results[1].Err == nil {
+		t.Errorf("Expected only the missing transaction to fail, but got %+v", results)
+	}
+}
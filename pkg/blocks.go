@@ -0,0 +1,288 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// BlockHeader summarises a block without its transactions.
+type BlockHeader struct {
+	// Number is the position of the block in the chain, counting from zero.
+	Number           int64
+	Hash             string
+	PreviousHash     string
+	Timestamp        string
+	TransactionCount int
+}
+
+// blockFields is the subset of a block decoded into a BlockHeader. Gateways
+// differ in the names they use for the hashes, so both forms are accepted.
+type blockFields struct {
+	Hash              string            `json:"Hash"`
+	BlockHash         string            `json:"BlockHash"`
+	PreviousHash      string            `json:"PreviousHash"`
+	PreviousBlockHash string            `json:"PreviousBlockHash"`
+	Timestamp         string            `json:"Timestamp"`
+	Transactions      []json.RawMessage `json:"Transactions"`
+}
+
+// blockHeader builds the header of block number from a GetBlock response,
+// whose Response is either the block itself or an object with a Block field.
+func blockHeader(number int64, response map[string]interface{}) (BlockHeader, error) {
+	raw, err := json.Marshal(unwrapBlock(response["Response"]))
+	if err != nil {
+		return BlockHeader{}, fmt.Errorf("failed to decode block %d: %w", number, err)
+	}
+	var fields blockFields
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return BlockHeader{}, fmt.Errorf("failed to decode block %d: %w", number, err)
+	}
+
+	header := BlockHeader{
+		Number:           number,
+		Hash:             fields.Hash,
+		PreviousHash:     fields.PreviousHash,
+		Timestamp:        fields.Timestamp,
+		TransactionCount: len(fields.Transactions),
+	}
+	if header.Hash == "" {
+		header.Hash = fields.BlockHash
+	}
+	if header.PreviousHash == "" {
+		header.PreviousHash = fields.PreviousBlockHash
+	}
+	return header, nil
+}
+
+// unwrapBlock returns the Block field of v if v is an object that has one, and
+// v itself otherwise. Gateways return blocks either bare or wrapped.
+func unwrapBlock(v interface{}) interface{} {
+	if wrapper, ok := v.(map[string]interface{}); ok {
+		if inner, ok := wrapper["Block"].(map[string]interface{}); ok {
+			return inner
+		}
+	}
+	return v
+}
+
+// blockCount extracts the count from a GetBlockCount response. The count is
+// either the Response itself or its Blocks or BlockCount field, as a number
+// or a numeric string.
+func blockCount(response map[string]interface{}) (int64, error) {
+	value := response["Response"]
+	if fields, ok := value.(map[string]interface{}); ok {
+		value = fields["Blocks"]
+		if value == nil {
+			value = fields["BlockCount"]
+		}
+	}
+	switch v := value.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("unexpected block count %v", value)
+	}
+}
+
+// GetBlockHeight returns the number of blocks on the client's blockchain.
+func (c *Client) GetBlockHeight(ctx context.Context) (int64, error) {
+	response, err := c.GetBlockCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return blockCount(response)
+}
+
+// WatchBlocks polls the block count every IntervalSec seconds (every second
+// when IntervalSec is not positive) and sends the header of every block added
+// after the call, in order. The channel is closed when ctx is done or the
+// client is shut down. Failed polls are logged and retried on the next tick;
+// only the initial block count is returned as an error.
+func (c *Client) WatchBlocks(ctx context.Context) (<-chan BlockHeader, error) {
+	ctx, done, err := c.startWorker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	next, err := c.GetBlockHeight(ctx)
+	if err != nil {
+		done()
+		return nil, fmt.Errorf("failed to read the initial block count: %w", err)
+	}
+
+	interval := time.Duration(c.IntervalSec) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	headers := make(chan BlockHeader)
+	go func() {
+		defer done()
+		defer close(headers)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			count, err := c.GetBlockHeight(ctx)
+			if err != nil {
+				c.logf(ctx, "Error fetching block count: %v, polling again...", err)
+				continue
+			}
+			for next < count {
+				response, err := c.GetBlock(ctx, next)
+				if err != nil {
+					c.logf(ctx, "Error fetching block %d: %v, polling again...", next, err)
+					break
+				}
+				header, err := blockHeader(next, response)
+				if err != nil {
+					c.logf(ctx, "%v, polling again...", err)
+					break
+				}
+				select {
+				case headers <- header:
+				case <-ctx.Done():
+					return
+				}
+				next++
+			}
+		}
+	}()
+	return headers, nil
+}
+
+// DefaultBlockBatchSize is how many blocks IterateBlocks requests at once.
+const DefaultBlockBatchSize = 100
+
+// WithBlockBatchSize sets the client's BlockBatchSize.
+func WithBlockBatchSize(n int) Option {
+	return func(c *Client) {
+		c.BlockBatchSize = n
+	}
+}
+
+// Block is a block with its transactions, as passed to IterateBlocks.
+type Block struct {
+	BlockHeader
+	// Transactions are the block's transactions, in order. Their BlockID and
+	// Position default to the block's number and their index in it.
+	Transactions []Transaction
+	// Fields is the block as returned by the gateway.
+	Fields map[string]interface{}
+}
+
+// newBlock builds the Block numbered number from a block returned by the
+// gateway, bare or wrapped. A BlockID field takes precedence over number.
+func newBlock(number int64, block map[string]interface{}) Block {
+	fields, _ := unwrapBlock(block).(map[string]interface{})
+	if id, err := strconv.ParseInt(firstField(fields, "BlockID"), 10, 64); err == nil {
+		number = id
+	}
+	items, _ := fields["Transactions"].([]interface{})
+	b := Block{
+		BlockHeader: BlockHeader{
+			Number:           number,
+			Hash:             firstField(fields, "Hash", "BlockHash"),
+			PreviousHash:     firstField(fields, "PreviousHash", "PreviousBlockHash"),
+			Timestamp:        firstField(fields, "Timestamp"),
+			TransactionCount: len(items),
+		},
+		Fields: fields,
+	}
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tx := NewTransaction(fields)
+		if tx.BlockID == "" {
+			tx.BlockID = strconv.FormatInt(number, 10)
+		}
+		if tx.Position < 0 {
+			tx.Position = i
+		}
+		b.Transactions = append(b.Transactions, tx)
+	}
+	return b
+}
+
+// IterateOption adjusts a single call to IterateBlocks.
+type IterateOption func(*iterateOptions)
+
+type iterateOptions struct {
+	detector *ReorgDetector
+	onReorg  func(ReorgEvent) error
+}
+
+// WithReorgDetection passes every block to detector before fn and calls
+// onReorg with any reorg it reveals. Sharing the detector between calls lets
+// an indexer that scans the chain incrementally notice blocks replaced since
+// the previous scan. An error returned by onReorg stops the iteration and is
+// returned unchanged, typically so that the caller can roll back and scan
+// again from the event's Number.
+func WithReorgDetection(detector *ReorgDetector, onReorg func(ReorgEvent) error) IterateOption {
+	return func(o *iterateOptions) {
+		o.detector = detector
+		o.onReorg = onReorg
+	}
+}
+
+// IterateBlocks calls fn for every block from start to end (inclusive), in
+// order. Blocks are requested BlockBatchSize at a time and streamed as by
+// GetBlockRange, so a historical scan holds at most one block in memory
+// however long the range. Iteration stops at the first error returned by fn,
+// which is returned unchanged, or when ctx is done. The blocks are requested
+// as bulk traffic; see WithBulkThrottle.
+func (c *Client) IterateBlocks(ctx context.Context, start, end int64, fn func(Block) error, opts ...IterateOption) (err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+	ctx = ContextWithBulk(ctx)
+
+	var options iterateOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if start > end {
+		return fmt.Errorf("invalid block range: start %d is after end %d", start, end)
+	}
+	size := int64(c.BlockBatchSize)
+	if size <= 0 {
+		size = DefaultBlockBatchSize
+	}
+
+	for from := start; from <= end; from += size {
+		to := min(from+size-1, end)
+		number := from
+		err := c.GetBlockRangeContext(ctx, from, to, func(block map[string]interface{}) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			b := newBlock(number, block)
+			number++
+			if options.detector != nil {
+				if event := options.detector.Observe(b.BlockHeader); event != nil && options.onReorg != nil {
+					if err := options.onReorg(*event); err != nil {
+						return err
+					}
+				}
+			}
+			return fn(b)
+		})
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
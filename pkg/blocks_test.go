@@ -0,0 +1,194 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBlockCount(t *testing.T) {
+	testCases := []struct {
+		name        string
+		response    string
+		expected    int64
+		expectError bool
+	}{
+		{"Bare Number", `{"Result":200,"Response":42}`, 42, false},
+		{"Blocks Field", `{"Result":200,"Response":{"Blocks":42}}`, 42, false},
+		{"BlockCount String", `{"Result":200,"Response":{"BlockCount":"42"}}`, 42, false},
+		{"Missing", `{"Result":200,"Response":{}}`, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var response map[string]interface{}
+			json.Unmarshal([]byte(tc.response), &response)
+			got, err := blockCount(response)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %d, but got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBlockHeader(t *testing.T) {
+	var wrapped, flat map[string]interface{}
+	json.Unmarshal([]byte(`{"Result":200,"Response":{"Block":{"BlockHash":"h1","PreviousBlockHash":"h0","Timestamp":"2024:01:02-03:04:05","Transactions":[{},{}]}}}`), &wrapped)
+	json.Unmarshal([]byte(`{"Result":200,"Response":{"Hash":"h1","PreviousHash":"h0","Timestamp":"2024:01:02-03:04:05","Transactions":[{},{}]}}`), &flat)
+
+	expected := BlockHeader{Number: 7, Hash: "h1", PreviousHash: "h0", Timestamp: "2024:01:02-03:04:05", TransactionCount: 2}
+	for name, response := range map[string]map[string]interface{}{"Wrapped": wrapped, "Flat": flat} {
+		header, err := blockHeader(7, response)
+		if err != nil {
+			t.Fatalf("%s: expected no error, but got: %v", name, err)
+		}
+		if header != expected {
+			t.Errorf("%s: expected %+v, but got %+v", name, expected, header)
+		}
+	}
+}
+
+func TestWatchBlocks(t *testing.T) {
+	var count int64 = 10
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		switch {
+		case strings.Contains(r.URL.Path, "GetBlockCount"):
+			fmt.Fprintf(w, `{"Result":200,"Response":{"Blocks":%d}}`, atomic.LoadInt64(&count))
+		case strings.Contains(r.URL.Path, "GetBlock"):
+			var request map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&request)
+			fmt.Fprintf(w, `{"Result":200,"Response":{"Block":{"Hash":"h%s"}}}`, request["BlockNumber"])
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	c.IntervalSec = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	headers, err := c.WatchBlocks(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	atomic.StoreInt64(&count, 12)
+
+	for _, expected := range []int64{10, 11} {
+		select {
+		case header := <-headers:
+			if header.Number != expected || header.Hash != fmt.Sprintf("h%d", expected) {
+				t.Errorf("Expected block %d, but got %+v", expected, header)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for block %d", expected)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-headers:
+		if ok {
+			t.Error("Expected no further blocks after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the channel to be closed after cancellation")
+	}
+}
+
+func TestIterateBlocks(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct{ Start, End string }
+		json.NewDecoder(r.Body).Decode(&request)
+		ranges = append(ranges, request.Start+"-"+request.End)
+		start, _ := strconv.Atoi(request.Start)
+		end, _ := strconv.Atoi(request.End)
+		var blocks []string
+		for i := start; i <= end; i++ {
+			blocks = append(blocks, fmt.Sprintf(`{"Block":{"BlockID":"%d","Hash":"h%d","PreviousHash":"h%d","Transactions":[{"ID":"tx%d"}]}}`, i, i, i-1, i))
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"Result":200,"Response":{"Blocks":[%s]}}`, strings.Join(blocks, ","))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithBlockBatchSize(3))
+
+	t.Run("Batches", func(t *testing.T) {
+		ranges = nil
+		var numbers []int64
+		err := c.IterateBlocks(context.Background(), 2, 9, func(b Block) error {
+			if b.Hash != fmt.Sprintf("h%d", b.Number) || len(b.Transactions) != 1 || b.Transactions[0].ID != fmt.Sprintf("tx%d", b.Number) ||
+				b.Transactions[0].BlockID != fmt.Sprint(b.Number) || b.Transactions[0].Position != 0 {
+				t.Errorf("Expected block %d to be decoded, but got %+v", b.Number, b)
+			}
+			numbers = append(numbers, b.Number)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if fmt.Sprint(numbers) != "[2 3 4 5 6 7 8 9]" {
+			t.Errorf("Expected blocks 2 to 9 in order, but got %v", numbers)
+		}
+		if strings.Join(ranges, ",") != "2-4,5-7,8-9" {
+			t.Errorf("Expected three batches, but got %v", ranges)
+		}
+	})
+
+	t.Run("Callback Error", func(t *testing.T) {
+		ranges = nil
+		stop := errors.New("stop")
+		err := c.IterateBlocks(context.Background(), 0, 9, func(b Block) error {
+			if b.Number == 4 {
+				return stop
+			}
+			return nil
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("Expected the callback error, but got %v", err)
+		}
+		if len(ranges) != 2 {
+			t.Errorf("Expected iteration to stop in the second batch, but got %v", ranges)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ranges = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		err := c.IterateBlocks(ctx, 0, 9, func(b Block) error {
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, but got %v", err)
+		}
+		if len(ranges) != 1 {
+			t.Errorf("Expected no request after cancellation, but got %v", ranges)
+		}
+	})
+
+	t.Run("Invalid Range", func(t *testing.T) {
+		if err := c.IterateBlocks(context.Background(), 5, 4, func(Block) error { return nil }); err == nil {
+			t.Error("Expected an error for an inverted range")
+		}
+	})
+}
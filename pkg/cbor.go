@@ -0,0 +1,394 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// CBORCodec encodes certificate data as CBOR (RFC 8949), which is typically
+// noticeably smaller than JSON for structured data. Map keys are written in
+// the deterministic order of RFC 8949 section 4.2.1, so equal values always
+// encode to equal bytes. Values other than the JSON types and []byte are
+// converted through encoding/json first.
+//
+// Unmarshal accepts any well-formed CBOR whose map keys are text strings.
+// Integers decode as int64 (uint64 above math.MaxInt64), other numbers as
+// float64, byte strings as []byte, and tags are dropped.
+var CBORCodec Codec = cborCodec{}
+
+type cborCodec struct{}
+
+func (cborCodec) Name() string { return "cbor" }
+
+func (cborCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cborEncode(&buf, v, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (cborCodec) Unmarshal(data []byte) (interface{}, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, fmt.Errorf("cbor: %d trailing bytes", len(data)-d.pos)
+	}
+	return v, nil
+}
+
+// cborMaxDepth bounds the nesting of encoded and decoded values.
+const cborMaxDepth = 512
+
+// CBOR major types.
+const (
+	cborUnsigned byte = iota
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// cborBreak ends an indefinite-length item.
+const cborBreak = 0xff
+
+var errCBORDepth = errors.New("cbor: value nested too deeply")
+
+// cborHead writes the initial bytes of an item with the given major type and
+// argument, using the shortest form.
+func cborHead(buf *bytes.Buffer, major byte, arg uint64) {
+	major <<= 5
+	switch {
+	case arg < 24:
+		buf.WriteByte(major | byte(arg))
+	case arg <= math.MaxUint8:
+		buf.Write([]byte{major | 24, byte(arg)})
+	case arg <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(arg)))
+	case arg <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(arg)))
+	default:
+		buf.WriteByte(major | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, arg))
+	}
+}
+
+func cborEncode(buf *bytes.Buffer, v interface{}, depth int) error {
+	if depth > cborMaxDepth {
+		return errCBORDepth
+	}
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(cborSimple<<5 | 22)
+	case bool:
+		if v {
+			buf.WriteByte(cborSimple<<5 | 21)
+		} else {
+			buf.WriteByte(cborSimple<<5 | 20)
+		}
+	case int:
+		cborEncodeInt(buf, int64(v))
+	case int64:
+		cborEncodeInt(buf, v)
+	case uint64:
+		cborHead(buf, cborUnsigned, v)
+	case float32:
+		cborEncodeFloat(buf, float64(v))
+	case float64:
+		cborEncodeFloat(buf, v)
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			cborEncodeInt(buf, i)
+			return nil
+		}
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return fmt.Errorf("cbor: invalid number %q", v)
+		}
+		cborEncodeFloat(buf, f)
+	case string:
+		cborHead(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case []byte:
+		cborHead(buf, cborBytes, uint64(len(v)))
+		buf.Write(v)
+	case []interface{}:
+		cborHead(buf, cborArray, uint64(len(v)))
+		for _, item := range v {
+			if err := cborEncode(buf, item, depth+1); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		// Deterministic order: keys sorted by their encoded bytes, which for
+		// text strings is shorter keys first, then bytewise.
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		cborHead(buf, cborMap, uint64(len(v)))
+		for _, key := range keys {
+			cborHead(buf, cborText, uint64(len(key)))
+			buf.WriteString(key)
+			if err := cborEncode(buf, v[key], depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("cbor: unsupported value of type %T: %w", v, err)
+		}
+		generic, err := JSONCodec.Unmarshal(raw)
+		if err != nil {
+			return fmt.Errorf("cbor: unsupported value of type %T: %w", v, err)
+		}
+		return cborEncode(buf, generic, depth)
+	}
+	return nil
+}
+
+func cborEncodeInt(buf *bytes.Buffer, i int64) {
+	if i >= 0 {
+		cborHead(buf, cborUnsigned, uint64(i))
+	} else {
+		cborHead(buf, cborNegative, uint64(-1-i))
+	}
+}
+
+// cborEncodeFloat writes integral values as integers and other values in the
+// smallest float width that represents them exactly.
+func cborEncodeFloat(buf *bytes.Buffer, f float64) {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		cborEncodeInt(buf, int64(f))
+		return
+	}
+	if float64(float32(f)) == f || math.IsNaN(f) {
+		buf.WriteByte(cborSimple<<5 | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(f))))
+		return
+	}
+	buf.WriteByte(cborSimple<<5 | 27)
+	buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+}
+
+// cborDecoder reads CBOR items from data.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// take returns the next n bytes.
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("cbor: unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// head reads the initial bytes of an item. indefinite is set for additional
+// information 31, which is an indefinite length or, for major type 7, break.
+func (d *cborDecoder) head() (major byte, info byte, arg uint64, indefinite bool, err error) {
+	b, err := d.take(1)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	major, info = b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info == 31:
+		return major, info, 0, true, nil
+	case info > 27:
+		return 0, 0, 0, false, fmt.Errorf("cbor: invalid additional information %d", info)
+	}
+	size := uint64(1) << (info - 24)
+	raw, err := d.take(size)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	for _, b := range raw {
+		arg = arg<<8 | uint64(b)
+	}
+	return major, info, arg, false, nil
+}
+
+// atBreak consumes a break code if it is next.
+func (d *cborDecoder) atBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+func (d *cborDecoder) value(depth int) (interface{}, error) {
+	if depth > cborMaxDepth {
+		return nil, errCBORDepth
+	}
+	major, info, arg, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major == cborUnsigned || major == cborNegative || major == cborTag) {
+		return nil, fmt.Errorf("cbor: invalid indefinite length for major type %d", major)
+	}
+
+	switch major {
+	case cborUnsigned:
+		if arg > math.MaxInt64 {
+			return arg, nil
+		}
+		return int64(arg), nil
+	case cborNegative:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: negative integer out of range")
+		}
+		return -1 - int64(arg), nil
+	case cborBytes, cborText:
+		s, err := d.stringItem(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborBytes {
+			return s, nil
+		}
+		if !utf8.Valid(s) {
+			return nil, errors.New("cbor: text string is not valid UTF-8")
+		}
+		return string(s), nil
+	case cborArray:
+		var items []interface{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if items == nil {
+			items = []interface{}{}
+		}
+		return items, nil
+	case cborMap:
+		m := make(map[string]interface{})
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("cbor: map key of type %T is not supported", key)
+			}
+			if m[name], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborTag:
+		return d.value(depth + 1)
+	default:
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		case 25:
+			return float16ToFloat64(uint16(arg)), nil
+		case 26:
+			return float64(math.Float32frombits(uint32(arg))), nil
+		case 27:
+			return math.Float64frombits(arg), nil
+		case 31:
+			return nil, errors.New("cbor: unexpected break")
+		default:
+			return nil, fmt.Errorf("cbor: unsupported simple value %d", arg)
+		}
+	}
+}
+
+// stringItem reads the content of a byte or text string, joining the chunks
+// of an indefinite-length string.
+func (d *cborDecoder) stringItem(major byte, length uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		s, err := d.take(length)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), s...), nil
+	}
+	var joined []byte
+	for !d.atBreak() {
+		chunkMajor, _, chunkLength, chunkIndefinite, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkIndefinite {
+			return nil, errors.New("cbor: invalid chunk in indefinite-length string")
+		}
+		chunk, err := d.take(chunkLength)
+		if err != nil {
+			return nil, err
+		}
+		joined = append(joined, chunk...)
+	}
+	if joined == nil {
+		joined = []byte{}
+	}
+	return joined, nil
+}
+
+// float16ToFloat64 converts an IEEE 754 half-precision value.
+func float16ToFloat64(h uint16) float64 {
+	exponent := int(h>>10) & 0x1f
+	mantissa := float64(h & 0x3ff)
+	var f float64
+	switch exponent {
+	case 0:
+		f = math.Ldexp(mantissa, -24)
+	case 31:
+		if mantissa == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mantissa+1024, exponent-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
@@ -0,0 +1,126 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// The vectors are taken from RFC 8949 Appendix A.
+func TestCBORMarshal(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"Zero", int64(0), "00"},
+		{"Small Integer", json.Number("23"), "17"},
+		{"One Byte Integer", json.Number("100"), "1864"},
+		{"Large Integer", json.Number("1000000000000"), "1b000000e8d4a51000"},
+		{"Negative Integer", json.Number("-1000"), "3903e7"},
+		{"Integral Float", 1.0, "01"},
+		{"Float32", 100000.5, "fa47c35040"},
+		{"Float64", 1.1, "fb3ff199999999999a"},
+		{"Booleans And Null", []interface{}{false, true, nil}, "83f4f5f6"},
+		{"Text", "IETF", "6449455446"},
+		{"Unicode Text", "ü", "62c3bc"},
+		{"Bytes", []byte{1, 2, 3, 4}, "4401020304"},
+		{"Nested Array", []interface{}{int64(1), []interface{}{int64(2), int64(3)}}, "8201820203"},
+		{"Sorted Map", map[string]interface{}{"bb": int64(2), "a": int64(1), "c": int64(3)}, "a3616101616303626262" + "02"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := CBORCodec.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if got := hex.EncodeToString(encoded); got != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCBORUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"Unsigned", "1903e8", int64(1000)},
+		{"Max Uint64", "1bffffffffffffffff", uint64(math.MaxUint64)},
+		{"Negative", "20", int64(-1)},
+		{"Float16", "f93c00", 1.0},
+		{"Float16 Negative", "f9c400", -4.0},
+		{"Float32", "fa47c35000", 100000.0},
+		{"Undefined", "f7", nil},
+		{"Tagged", "c074323031332d30332d32315432303a30343a30305a", "2013-03-21T20:04:00Z"},
+		{"Indefinite Bytes", "5f42010243030405ff", []byte{1, 2, 3, 4, 5}},
+		{"Indefinite Text", "7f657374726561646d696e67ff", "streaming"},
+		{"Indefinite Array", "9f018202039f0405ffff", []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}}},
+		{"Indefinite Map", "bf61610161629f0203ffff", map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}},
+		{"Empty Array", "80", []interface{}{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input, _ := hex.DecodeString(tc.input)
+			value, err := CBORCodec.Unmarshal(input)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if !reflect.DeepEqual(value, tc.expected) {
+				t.Errorf("Expected %#v, but got %#v", tc.expected, value)
+			}
+		})
+	}
+}
+
+func TestCBORUnmarshalErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"Empty", ""},
+		{"Truncated Text", "64494554"},
+		{"Trailing Bytes", "0000"},
+		{"Non-Text Key", "a10102"},
+		{"Invalid UTF-8", "61ff"},
+		{"Unexpected Break", "ff"},
+		{"Reserved Additional Information", "1c"},
+		{"Huge Length", "5bffffffffffffffff"},
+		{"Bad Chunk", "5f6161ff"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input, _ := hex.DecodeString(tc.input)
+			if _, err := CBORCodec.Unmarshal(input); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	type student struct {
+		Name  string   `json:"name"`
+		Grade float64  `json:"grade"`
+		Tags  []string `json:"tags"`
+	}
+	encoded, err := CBORCodec.Marshal(student{Name: "Ada", Grade: 9.5, Tags: []string{"physics"}})
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	value, err := CBORCodec.Unmarshal(encoded)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	expected := map[string]interface{}{"name": "Ada", "grade": 9.5, "tags": []interface{}{"physics"}}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("Expected %v, but got %v", expected, value)
+	}
+}
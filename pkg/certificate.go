@@ -1,6 +1,7 @@
 package circular_enterprise_apis
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -12,8 +13,28 @@ type Certificate struct {
 	PreviousTxID  string `json:"previousTxID"`
 	PreviousBlock string `json:"previousBlock"`
 	Version       string `json:"version"`
+	// DigestAlgorithm records the algorithm of a digest set with SetDigest.
+	DigestAlgorithm HashAlgorithm `json:"digestAlgorithm,omitempty"`
+	// Codec names the codec of data set with SetValue; empty means JSON or
+	// plain text.
+	Codec string `json:"codec,omitempty"`
+	// Encoding is the text encoding of Data; empty means hex.
+	Encoding DataEncoding `json:"encoding,omitempty"`
 }
 
+// DataEncoding is the text encoding of a certificate's Data field.
+type DataEncoding string
+
+const (
+	// HexEncoding is the default encoding of certificate data.
+	HexEncoding DataEncoding = "hex"
+	// Base64Encoding stores certificate data as standard, padded base64,
+	// which is about a third smaller than hex. It applies to the Data field
+	// of a Certificate only: the payload of a submitted transaction is always
+	// hex, as the gateway requires.
+	Base64Encoding DataEncoding = "base64"
+)
+
 // NewCertificate creates and initializes a new Certificate instance.
 func NewCertificate(version string) *Certificate {
 	return &Certificate{
@@ -21,18 +42,83 @@ func NewCertificate(version string) *Certificate {
 	}
 }
 
-// SetData inserts application data into the certificate after converting it to a hexadecimal string.
+// SetData inserts application data into the certificate after converting it to a hexadecimal string,
+// or to base64 when Encoding is Base64Encoding.
 // The `data` parameter is the string data to be stored.
 func (c *Certificate) SetData(data string) {
-	c.Data = hex.EncodeToString([]byte(data))
+	c.setRawData([]byte(data))
+}
+
+// SetEncoding changes the encoding of the certificate data, re-encoding any
+// data already set.
+func (c *Certificate) SetEncoding(encoding DataEncoding) error {
+	if encoding != HexEncoding && encoding != Base64Encoding {
+		return fmt.Errorf("unsupported data encoding %q", encoding)
+	}
+	data, err := c.rawData()
+	if err != nil {
+		return err
+	}
+	c.Encoding = encoding
+	if encoding == HexEncoding {
+		c.Encoding = ""
+	}
+	c.setRawData(data)
+	return nil
+}
+
+// setRawData stores data in the certificate's encoding.
+func (c *Certificate) setRawData(data []byte) {
+	if c.Encoding == Base64Encoding {
+		c.Data = base64.StdEncoding.EncodeToString(data)
+		return
+	}
+	c.Data = hex.EncodeToString(data)
+}
+
+// rawData decodes the certificate data according to its encoding.
+func (c *Certificate) rawData() ([]byte, error) {
+	switch c.Encoding {
+	case "", HexEncoding:
+		decoded, err := hex.DecodeString(c.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode certificate data: %w", err)
+		}
+		return decoded, nil
+	case Base64Encoding:
+		decoded, err := base64.StdEncoding.DecodeString(c.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode certificate data: %w", err)
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("unsupported data encoding %q", c.Encoding)
+	}
+}
+
+// SetDigest anchors a digest of the application data instead of the data
+// itself. The certificate data becomes the digest in "algorithm:hex" form and
+// the algorithm is recorded in DigestAlgorithm.
+func (c *Certificate) SetDigest(d Digest) {
+	c.SetData(d.String())
+	c.DigestAlgorithm = d.Algorithm
+}
+
+// GetDigest returns the digest stored by SetDigest.
+func (c *Certificate) GetDigest() (Digest, error) {
+	data, err := c.GetData()
+	if err != nil {
+		return Digest{}, err
+	}
+	return ParseDigest(data)
 }
 
-// GetData decodes the hexadecimal data from the certificate into a string.
-// It returns the decoded string and an error if the data is not a valid hexadecimal format.
+// GetData decodes the hexadecimal, or base64, data from the certificate into a string.
+// It returns the decoded string and an error if the data is not valid in the certificate's encoding.
 func (c *Certificate) GetData() (string, error) {
-	decodedData, err := hex.DecodeString(c.Data)
+	decodedData, err := c.rawData()
 	if err != nil {
-		return "", fmt.Errorf("failed to decode certificate data: %w", err)
+		return "", err
 	}
 	return string(decodedData), nil
 }
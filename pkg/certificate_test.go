@@ -1,6 +1,7 @@
 package circular_enterprise_apis
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"reflect"
@@ -239,3 +240,45 @@ func TestGetCertificateSize(t *testing.T) {
 }
 
 
+
+func TestBase64Encoding(t *testing.T) {
+	data := "你好, 世界 and a long enough document"
+
+	cert := NewCertificate(LibVersion)
+	cert.SetData(data)
+	hexSize := len(cert.Data)
+	if err := cert.SetEncoding(Base64Encoding); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if cert.Data != base64.StdEncoding.EncodeToString([]byte(data)) {
+		t.Errorf("Expected the data to be re-encoded as base64, but got %s", cert.Data)
+	}
+	if len(cert.Data) >= hexSize {
+		t.Errorf("Expected base64 data to be smaller than %d bytes, but got %d", hexSize, len(cert.Data))
+	}
+
+	jsonCert, err := cert.GetJSONCertificate()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	var decoded Certificate
+	if err := json.Unmarshal([]byte(jsonCert), &decoded); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if decoded.Encoding != Base64Encoding {
+		t.Errorf("Expected the encoding to be recorded, but got %q", decoded.Encoding)
+	}
+	if got, err := decoded.GetData(); err != nil || got != data {
+		t.Errorf("Expected GetData to decode base64 transparently, but got %q (%v)", got, err)
+	}
+
+	if err := decoded.SetEncoding(HexEncoding); err != nil || decoded.Data != hex.EncodeToString([]byte(data)) || decoded.Encoding != "" {
+		t.Errorf("Expected switching back to hex to restore the default form, but got %+v (%v)", decoded, err)
+	}
+	if err := decoded.SetEncoding("base32"); err == nil {
+		t.Error("Expected an unsupported encoding to fail")
+	}
+	if _, err := (&Certificate{Data: "!!", Encoding: Base64Encoding}).GetData(); err == nil {
+		t.Error("Expected invalid base64 data to fail")
+	}
+}
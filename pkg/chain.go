@@ -0,0 +1,75 @@
+package circular_enterprise_apis
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownChain is returned when an operation names a chain that has not
+// been registered with the client.
+var ErrUnknownChain = errors.New("unknown chain")
+
+// Chain describes one of the blockchains an application works with. The
+// protocol is multi-chain, so a single client can register several and have
+// individual calls target one by name.
+type Chain struct {
+	// Name is the application's name for the chain, such as "audit-chain".
+	Name string
+	// ID is the blockchain identifier sent to the NAG.
+	ID string
+	// NAGURL overrides the client's NAG URL for this chain when not empty.
+	NAGURL string
+}
+
+// WithChains registers chains with the client at construction time.
+func WithChains(chains ...Chain) Option {
+	return func(c *Client) {
+		for _, chain := range chains {
+			c.RegisterChain(chain)
+		}
+	}
+}
+
+// RegisterChain adds chain to the client's registry, replacing any chain
+// already registered under the same name. It is not safe to call while the
+// client is in use by other goroutines.
+func (c *Client) RegisterChain(chain Chain) {
+	if c.chains == nil {
+		c.chains = make(map[string]Chain)
+	}
+	c.chains[chain.Name] = chain
+}
+
+// Chain returns the chain registered under name.
+func (c *Client) Chain(name string) (Chain, bool) {
+	chain, ok := c.chains[name]
+	return chain, ok
+}
+
+// Chains returns the registered chains sorted by name.
+func (c *Client) Chains() []Chain {
+	chains := make([]Chain, 0, len(c.chains))
+	for _, chain := range c.chains {
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].Name < chains[j].Name })
+	return chains
+}
+
+// resolveChain returns the blockchain ID and NAG URL for the named chain, or
+// the client's own settings when name is empty.
+func (c *Client) resolveChain(name string) (blockchain, nagURL string, err error) {
+	if name == "" {
+		return c.Blockchain, c.NAGURL, nil
+	}
+	chain, ok := c.Chain(name)
+	if !ok {
+		return "", "", fmt.Errorf("%w: %q", ErrUnknownChain, name)
+	}
+	nagURL = c.NAGURL
+	if chain.NAGURL != "" {
+		nagURL = chain.NAGURL
+	}
+	return chain.ID, nagURL, nil
+}
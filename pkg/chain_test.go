@@ -0,0 +1,89 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChainRegistry(t *testing.T) {
+	c := NewClient(DefaultNAG, DefaultChain, LibVersion,
+		WithChains(Chain{Name: "billing", ID: "0x02"}, Chain{Name: "audit-chain", ID: "0x01"}))
+	c.RegisterChain(Chain{Name: "billing", ID: "0x03", NAGURL: "https://billing.example/NAG.php?cep="})
+
+	chains := c.Chains()
+	if len(chains) != 2 || chains[0].Name != "audit-chain" || chains[1].ID != "0x03" {
+		t.Errorf("Expected sorted chains with billing replaced, got %+v", chains)
+	}
+
+	testCases := []struct {
+		name          string
+		chain         string
+		expectedID    string
+		expectedNAG   string
+		expectUnknown bool
+	}{
+		{"Default", "", DefaultChain, DefaultNAG, false},
+		{"Registered", "audit-chain", "0x01", DefaultNAG, false},
+		{"NAG Override", "billing", "0x03", "https://billing.example/NAG.php?cep=", false},
+		{"Unknown", "missing", "", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, nag, err := c.resolveChain(tc.chain)
+			if tc.expectUnknown {
+				if !errors.Is(err, ErrUnknownChain) {
+					t.Errorf("Expected ErrUnknownChain, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if id != tc.expectedID || nag != tc.expectedNAG {
+				t.Errorf("Expected (%s, %s), but got (%s, %s)", tc.expectedID, tc.expectedNAG, id, nag)
+			}
+		})
+	}
+}
+
+func TestSubmitCertificateWithChain(t *testing.T) {
+	var blockchain string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		blockchain, _ = request["Blockchain"].(string)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithChains(Chain{Name: "audit-chain", ID: "0x01"}))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+
+	if _, err := acc.SubmitCertificate("data", privateKey, WithChain("audit-chain")); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if blockchain != "0x01" {
+		t.Errorf("Expected Blockchain 0x01, but got %s", blockchain)
+	}
+	if acc.LatestTxID == "" {
+		t.Error("Expected LatestTxID to be recorded after a successful submission")
+	}
+
+	if _, err := acc.SubmitCertificate("data", privateKey); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if blockchain != DefaultChain {
+		t.Errorf("Expected default Blockchain, but got %s", blockchain)
+	}
+
+	if _, err := acc.SubmitCertificate("data", privateKey, WithChain("missing")); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("Expected ErrUnknownChain, but got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is the cause of the connection failures produced by a
+// FaultInjector.
+var ErrInjectedFault = errors.New("injected fault")
+
+// DefaultNonceConflictBody is the rejection a FaultInjector returns for an
+// injected nonce conflict.
+const DefaultNonceConflictBody = `{"Result":108,"Response":"Duplicate Nonce"}`
+
+// FaultConfig sets the faults a FaultInjector introduces. Rates are
+// probabilities between 0 and 1, drawn independently for each request in the
+// order the fields are listed.
+type FaultConfig struct {
+	// Latency delays every request, plus a random extra of up to Jitter.
+	Latency time.Duration
+	Jitter  time.Duration
+	// DropRate fails requests with a connection error before they reach the
+	// NAG, like a network outage.
+	DropRate float64
+	// ServerErrorRate answers requests with 503 Service Unavailable without
+	// forwarding them.
+	ServerErrorRate float64
+	// NonceConflictRate rejects certificate submissions, without forwarding
+	// them, with NonceConflictBody (DefaultNonceConflictBody if empty).
+	NonceConflictRate float64
+	NonceConflictBody string
+	// MalformedRate forwards requests and then truncates the NAG's answer, so
+	// the request takes effect but its response cannot be decoded.
+	MalformedRate float64
+	// Seed makes the injected faults reproducible; zero uses a random seed.
+	Seed int64
+}
+
+// FaultStats counts the requests seen by a FaultInjector and the faults it
+// injected.
+type FaultStats struct {
+	Requests       int64
+	Delayed        int64
+	Dropped        int64
+	ServerErrors   int64
+	NonceConflicts int64
+	Malformed      int64
+}
+
+// FaultInjector simulates NAG outages and misbehaviour so that applications
+// can rehearse them in tests and staging. Install it with WithFaultInjector,
+// or around any transport with Wrap. Never use it in production.
+type FaultInjector struct {
+	mu     sync.Mutex
+	config FaultConfig
+	rng    *rand.Rand
+	stats  FaultStats
+}
+
+// NewFaultInjector returns an injector that introduces the faults of config.
+func NewFaultInjector(config FaultConfig) *FaultInjector {
+	f := &FaultInjector{}
+	f.SetConfig(config)
+	return f
+}
+
+// WithFaultInjector routes the client's requests through f.
+func WithFaultInjector(f *FaultInjector) Option {
+	return func(c *Client) {
+		c.faultInjector = f
+	}
+}
+
+// SetConfig replaces the faults to inject, for example to start or end a
+// simulated outage while the application runs. The random source is reseeded
+// from config.Seed.
+func (f *FaultInjector) SetConfig(config FaultConfig) {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	f.mu.Lock()
+	f.config = config
+	f.rng = rand.New(rand.NewSource(seed))
+	f.mu.Unlock()
+}
+
+// Stats returns the counts of requests and injected faults so far.
+func (f *FaultInjector) Stats() FaultStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+// Wrap returns a transport that injects faults into requests sent through
+// next, or through http.DefaultTransport if next is nil.
+func (f *FaultInjector) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &faultTransport{injector: f, next: next}
+}
+
+// fault is the outcome drawn for one request.
+type fault int
+
+const (
+	faultNone fault = iota
+	faultDrop
+	faultServerError
+	faultNonceConflict
+	faultMalformed
+)
+
+// draw picks the delay and fault for a request.
+func (f *FaultInjector) draw(submission bool) (time.Duration, fault, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := &f.config
+	f.stats.Requests++
+
+	delay := c.Latency
+	if c.Jitter > 0 {
+		delay += time.Duration(f.rng.Int63n(int64(c.Jitter) + 1))
+	}
+	if delay > 0 {
+		f.stats.Delayed++
+	}
+
+	switch {
+	case f.hit(c.DropRate):
+		f.stats.Dropped++
+		return delay, faultDrop, ""
+	case f.hit(c.ServerErrorRate):
+		f.stats.ServerErrors++
+		return delay, faultServerError, ""
+	case submission && f.hit(c.NonceConflictRate):
+		f.stats.NonceConflicts++
+		body := c.NonceConflictBody
+		if body == "" {
+			body = DefaultNonceConflictBody
+		}
+		return delay, faultNonceConflict, body
+	case f.hit(c.MalformedRate):
+		f.stats.Malformed++
+		return delay, faultMalformed, ""
+	}
+	return delay, faultNone, ""
+}
+
+// hit reports whether an event of probability rate occurs; f.mu must be held.
+func (f *FaultInjector) hit(rate float64) bool {
+	return rate > 0 && f.rng.Float64() < rate
+}
+
+// faultTransport applies a FaultInjector to the requests sent through next.
+type faultTransport struct {
+	injector *FaultInjector
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Submissions are posted to the NAG URL itself; every other endpoint is
+	// a Circular_ method.
+	submission := req.Method == http.MethodPost && !strings.Contains(req.URL.Path, "Circular_")
+	delay, fault, body := t.injector.draw(submission)
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+
+	switch fault {
+	case faultDrop:
+		closeBody(req)
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: ErrInjectedFault}
+	case faultServerError:
+		closeBody(req)
+		return fakeResponse(req, http.StatusServiceUnavailable, "injected outage"), nil
+	case faultNonceConflict:
+		closeBody(req)
+		return fakeResponse(req, http.StatusOK, body), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || fault != faultMalformed {
+		return resp, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// Cut the answer in half and drop its framing headers, which would
+	// otherwise disagree with the new body.
+	data = data[:len(data)/2]
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	resp.Header.Del("Content-Length")
+	resp.Header.Del("Content-Encoding")
+	resp.Uncompressed = false
+	return resp, nil
+}
+
+// closeBody closes the body of a request that is not forwarded, as a
+// RoundTripper must.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// fakeResponse builds a response to req that never reached the NAG.
+func fakeResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
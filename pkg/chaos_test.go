@@ -0,0 +1,146 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFaultInjector(t *testing.T) {
+	var forwarded int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&forwarded, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Blocks":42}}`))
+	}))
+	defer server.Close()
+
+	noRetry := WithRetryPolicy(RetryPolicy{MaxAttempts: 1})
+
+	testCases := []struct {
+		name      string
+		config    FaultConfig
+		forwarded int32
+		check     func(t *testing.T, err error)
+	}{
+		{
+			name:   "Drop",
+			config: FaultConfig{DropRate: 1},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrInjectedFault) || !IsRetryable(err) {
+					t.Errorf("Expected a retryable injected fault, but got %v", err)
+				}
+			},
+		},
+		{
+			name:   "Server Error",
+			config: FaultConfig{ServerErrorRate: 1},
+			check: func(t *testing.T, err error) {
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+					t.Errorf("Expected a 503 *StatusError, but got %v", err)
+				}
+			},
+		},
+		{
+			name:      "Malformed",
+			config:    FaultConfig{MalformedRate: 1},
+			forwarded: 1,
+			check: func(t *testing.T, err error) {
+				if err == nil || !strings.Contains(err.Error(), "decode") {
+					t.Errorf("Expected a decoding error, but got %v", err)
+				}
+			},
+		},
+		{
+			name:      "Nonce Conflict Spares Queries",
+			config:    FaultConfig{NonceConflictRate: 1},
+			forwarded: 1,
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("Expected no error, but got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&forwarded, 0)
+			injector := NewFaultInjector(tc.config)
+			c := NewClient(server.URL, DefaultChain, LibVersion, noRetry, WithFaultInjector(injector))
+			_, err := c.GetBlockCount(context.Background())
+			tc.check(t, err)
+			if got := atomic.LoadInt32(&forwarded); got != tc.forwarded {
+				t.Errorf("Expected %d requests to reach the NAG, but got %d", tc.forwarded, got)
+			}
+			if injector.Stats().Requests != 1 {
+				t.Errorf("Expected 1 request, but got %+v", injector.Stats())
+			}
+		})
+	}
+
+	t.Run("Nonce Conflict", func(t *testing.T) {
+		injector := NewFaultInjector(FaultConfig{NonceConflictRate: 1})
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithFaultInjector(injector))
+		acc.Open("0x" + strings.Repeat("a", 64))
+		results, _ := acc.SubmitBatch(context.Background(), []string{"certificate"}, strings.Repeat("1", 64))
+		var resultErr *ResultError
+		if !errors.As(results[0].Err, &resultErr) || resultErr.Message != "Duplicate Nonce" {
+			t.Errorf("Expected an injected nonce conflict, but got %v", results[0].Err)
+		}
+		if injector.Stats().NonceConflicts != 1 {
+			t.Errorf("Expected 1 nonce conflict, but got %+v", injector.Stats())
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		injector := NewFaultInjector(FaultConfig{Latency: 50 * time.Millisecond})
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithFaultInjector(injector))
+		start := time.Now()
+		if _, err := c.GetBlockCount(context.Background()); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("Expected at least 50ms of latency, but took %v", elapsed)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := c.GetBlockCount(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the delay to honour the deadline, but got %v", err)
+		}
+	})
+
+	t.Run("Reproducible", func(t *testing.T) {
+		run := func() FaultStats {
+			injector := NewFaultInjector(FaultConfig{DropRate: 0.3, ServerErrorRate: 0.3, Seed: 42})
+			c := NewClient(server.URL, DefaultChain, LibVersion, noRetry, WithFaultInjector(injector))
+			for i := 0; i < 20; i++ {
+				c.GetBlockCount(context.Background())
+			}
+			return injector.Stats()
+		}
+		first, second := run(), run()
+		if first != second || first.Dropped == 0 || first.ServerErrors == 0 {
+			t.Errorf("Expected the same faults for the same seed, but got %+v and %+v", first, second)
+		}
+	})
+
+	t.Run("Recovery", func(t *testing.T) {
+		injector := NewFaultInjector(FaultConfig{DropRate: 1})
+		c := NewClient(server.URL, DefaultChain, LibVersion, noRetry, WithFaultInjector(injector))
+		if _, err := c.GetBlockCount(context.Background()); err == nil {
+			t.Fatal("Expected the outage to fail the request")
+		}
+		injector.SetConfig(FaultConfig{})
+		if _, err := c.GetBlockCount(context.Background()); err != nil {
+			t.Errorf("Expected the request to succeed after the outage, but got: %v", err)
+		}
+	})
+}
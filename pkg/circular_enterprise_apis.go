@@ -1,12 +1,5 @@
 package circular_enterprise_apis
 
-import (
-	"io"
-	"net/http"
-
-	"github.com/Circular-Protocol/CEP-Go-APIs/internal/utils"
-)
-
 // Constants define default network parameters and library metadata.
 const (
 	// LibVersion specifies the current version of the library.
@@ -24,15 +17,20 @@ const (
 
 
 // GetNAG is a standalone utility function for discovering the NAG URL for a
-// given network identifier. It makes an HTTP request to the public NetworkURL endpoint.
+// given network identifier. It makes an HTTP request to the public NetworkURL
+// endpoint using the library's shared HTTP client, and returns the response
+// body as is.
+//
+// Deprecated: Use Client.DiscoverNAG, which honours the client's NetworkURL,
+// HTTP client, timeout and context, and returns the parsed NAG URL.
 func GetNAG(network string) (string, error) {
-	resp, err := http.Get(NetworkURL + network)
+	resp, err := sharedHTTPClient.Get(NetworkURL + network)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readLimited(resp.Body, DefaultMaxResponseSize)
 	if err != nil {
 		return "", err
 	}
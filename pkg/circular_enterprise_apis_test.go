@@ -2,6 +2,7 @@ package circular_enterprise_apis
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -57,14 +58,14 @@ func (e *errorReader) Read(p []byte) (n int, err error) {
 
 // TestGetNAG tests the network discovery function.
 func TestGetNAG(t *testing.T) {
-	originalTransport := http.DefaultClient.Transport
+	originalTransport := sharedHTTPClient.Transport
 	defer func() {
-		http.DefaultClient.Transport = originalTransport
+		sharedHTTPClient.Transport = originalTransport
 	}()
 
 	t.Run("SuccessfulDiscovery", func(t *testing.T) {
 		expectedNAG := "https://test-nag.circularlabs.io/"
-		http.DefaultClient.Transport = &mockRoundTripper{
+		sharedHTTPClient.Transport = &mockRoundTripper{
 			response: &http.Response{
 				StatusCode: http.StatusOK,
 				Body:       io.NopCloser(strings.NewReader(expectedNAG)),
@@ -81,7 +82,7 @@ func TestGetNAG(t *testing.T) {
 	})
 
 	t.Run("FailedDiscovery", func(t *testing.T) {
-		http.DefaultClient.Transport = &mockRoundTripper{
+		sharedHTTPClient.Transport = &mockRoundTripper{
 			err: fmt.Errorf("network error"),
 		}
 
@@ -92,7 +93,7 @@ func TestGetNAG(t *testing.T) {
 	})
 
 	t.Run("Non200StatusCode", func(t *testing.T) {
-		http.DefaultClient.Transport = &mockRoundTripper{
+		sharedHTTPClient.Transport = &mockRoundTripper{
 			response: &http.Response{
 				StatusCode: http.StatusNotFound,
 				Body:       io.NopCloser(strings.NewReader("Not Found")),
@@ -109,7 +110,7 @@ func TestGetNAG(t *testing.T) {
 	})
 
 	t.Run("BodyReadError", func(t *testing.T) {
-		http.DefaultClient.Transport = &mockRoundTripper{
+		sharedHTTPClient.Transport = &mockRoundTripper{
 			response: &http.Response{
 				StatusCode: http.StatusOK,
 				Body:       io.NopCloser(&errorReader{}),
@@ -124,7 +125,7 @@ func TestGetNAG(t *testing.T) {
 
 	t.Run("EmptyNetworkString", func(t *testing.T) {
 		expectedNAG := "https://default-nag.circularlabs.io/"
-		http.DefaultClient.Transport = &mockRoundTripper{
+		sharedHTTPClient.Transport = &mockRoundTripper{
 			response: &http.Response{
 				StatusCode: http.StatusOK,
 				Body:       io.NopCloser(strings.NewReader(expectedNAG)),
@@ -186,7 +187,7 @@ func TestSubmitCertificate(t *testing.T) {
 				Data:    "test data",
 			},
 			expectError:   true,
-			expectedError: "network returned an error - status: 500 Internal Server Error, body: Internal Server Error",
+			expectedError: "network returned an error - status: 500 Internal Server Error, body: [redacted 21 bytes]",
 		},
 		{
 			name:           "Invalid JSON Response",
@@ -236,7 +237,13 @@ func TestSubmitCertificate(t *testing.T) {
 				acc.NAGURL = tc.nagURL
 			}
 
-			result, err := acc.SubmitCertificate(tc.cert)
+			acc.Open("0x" + strings.Repeat("a", 64))
+			certificate, err := tc.cert.GetJSONCertificate()
+			if err != nil {
+				t.Fatalf("Failed to marshal certificate: %v", err)
+			}
+
+			result, err := acc.SubmitCertificate(certificate, strings.Repeat("1", 64))
 
 			if tc.expectError {
 				if err == nil {
@@ -259,13 +266,19 @@ func TestSubmitCertificate(t *testing.T) {
 					}
 				}
 
-				// Verify the request body sent to the mock server
-				expectedRequestBody, marshalErr := json.Marshal(tc.cert)
-				if marshalErr != nil {
-					t.Fatalf("Failed to marshal expected certificate: %v", marshalErr)
+				// Verify that the request sent to the mock server carries the
+				// certificate as the data of its hex-encoded payload.
+				var request map[string]string
+				if err := json.Unmarshal(capturedRequestBody, &request); err != nil {
+					t.Fatalf("Failed to decode request body: %v", err)
+				}
+				payload, err := hex.DecodeString(request["Payload"])
+				if err != nil {
+					t.Fatalf("Failed to decode payload: %v", err)
 				}
-				if !bytes.Equal(capturedRequestBody, expectedRequestBody) {
-					t.Errorf("Request body mismatch. Expected %s, got %s", string(expectedRequestBody), string(capturedRequestBody))
+				expectedPayload, _ := json.Marshal(map[string]string{"data": certificate})
+				if !bytes.Equal(payload, expectedPayload) {
+					t.Errorf("Payload mismatch. Expected %s, got %s", expectedPayload, payload)
 				}
 			}
 		})
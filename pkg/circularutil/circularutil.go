@@ -0,0 +1,108 @@
+// Package circularutil exposes the encoding, timestamp and hashing helpers
+// used by the Circular Enterprise APIs, so applications that build
+// compatible payloads themselves get exactly the same semantics as the SDK.
+package circularutil
+
+import (
+	"io"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// HexFix removes a "0x" prefix from a hexadecimal string if present.
+func HexFix(word string) string {
+	return utils.HexFix(word)
+}
+
+// StringToHex returns the lowercase hexadecimal encoding of the bytes of str.
+func StringToHex(str string) string {
+	return utils.StringToHex(str)
+}
+
+// HexToString decodes a hexadecimal string, with or without a "0x" prefix,
+// and strips null bytes from the result. Invalid input yields an empty
+// string; use DecodeHexString to tell the two apart.
+func HexToString(hexStr string) string {
+	return utils.HexToString(hexStr)
+}
+
+// DecodeHexString is like HexToString but returns an error for invalid
+// hexadecimal input.
+func DecodeHexString(hexStr string) (string, error) {
+	return utils.DecodeHexString(hexStr)
+}
+
+// HexEncodeTo streams the hexadecimal encoding of r into w and returns the
+// number of hex characters written.
+func HexEncodeTo(w io.Writer, r io.Reader) (int64, error) {
+	return utils.HexEncodeTo(w, r)
+}
+
+// HexDecodeFrom streams the bytes encoded by the hexadecimal text in r into w,
+// ignoring a leading "0x", and returns the number of bytes written.
+func HexDecodeFrom(w io.Writer, r io.Reader) (int64, error) {
+	return utils.HexDecodeFrom(w, r)
+}
+
+// GetFormattedTimestamp returns the current UTC time in the protocol's
+// YYYY:MM:DD-HH:MM:SS format.
+func GetFormattedTimestamp() string {
+	return utils.GetFormattedTimestamp()
+}
+
+// FormatTimestamp formats t in UTC in the protocol's YYYY:MM:DD-HH:MM:SS
+// format.
+func FormatTimestamp(t time.Time) string {
+	return utils.FormatTimestamp(t)
+}
+
+// SHA256 returns the SHA-256 digest of data.
+func SHA256(data []byte) []byte {
+	return utils.SHA256(data)
+}
+
+// SHA256Hex returns the hex-encoded SHA-256 digest of str.
+func SHA256Hex(str string) string {
+	return utils.SHA256Hex(str)
+}
+
+// SHA3_256 returns the FIPS 202 SHA3-256 digest of data.
+func SHA3_256(data []byte) []byte {
+	return utils.SHA3_256(data)
+}
+
+// SHA3_256Hex returns the hex-encoded SHA3-256 digest of str.
+func SHA3_256Hex(str string) string {
+	return utils.SHA3_256Hex(str)
+}
+
+// Keccak256 returns the legacy Keccak-256 digest of data, the pre-standard
+// variant used by Ethereum-style tooling. It is not interchangeable with
+// SHA3_256.
+func Keccak256(data []byte) []byte {
+	return utils.Keccak256(data)
+}
+
+// Keccak256Hex returns the hex-encoded legacy Keccak-256 digest of str.
+func Keccak256Hex(str string) string {
+	return utils.Keccak256Hex(str)
+}
+
+// ErrInvalidTimestamp is matched by errors.Is for timestamps rejected by
+// ParseCircularTimestamp.
+var ErrInvalidTimestamp = utils.ErrInvalidTimestamp
+
+// ParseCircularTimestamp parses a protocol timestamp in YYYY:MM:DD-HH:MM:SS
+// format, as found on transactions read back from the chain, into a UTC time.
+// It is the inverse of FormatTimestamp.
+func ParseCircularTimestamp(timestamp string) (time.Time, error) {
+	return utils.ParseTimestamp(timestamp)
+}
+
+// ValidateTimestamp reports whether timestamp is a well-formed protocol
+// timestamp, returning the parse error if not.
+func ValidateTimestamp(timestamp string) error {
+	_, err := utils.ParseTimestamp(timestamp)
+	return err
+}
@@ -0,0 +1,88 @@
+package circularutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHexRoundTrip(t *testing.T) {
+	for _, input := range []string{"", "hello world", "你好, 世界"} {
+		encoded := StringToHex(input)
+		if got := HexToString("0x" + encoded); got != input {
+			t.Errorf("Expected %q to round-trip, but got %q", input, got)
+		}
+		decoded, err := DecodeHexString(encoded)
+		if err != nil || decoded != input {
+			t.Errorf("Expected %q to decode without error, but got %q, %v", input, decoded, err)
+		}
+	}
+
+	if _, err := DecodeHexString("0xzz"); err == nil {
+		t.Error("Expected an error for invalid hex but got nil")
+	}
+	if HexFix("0xabc") != "abc" || HexFix("abc") != "abc" {
+		t.Error("Expected HexFix to strip only a 0x prefix")
+	}
+}
+
+func TestHexStreaming(t *testing.T) {
+	var encoded, decoded bytes.Buffer
+	if _, err := HexEncodeTo(&encoded, strings.NewReader("stream")); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if _, err := HexDecodeFrom(&decoded, &encoded); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if decoded.String() != "stream" {
+		t.Errorf("Expected stream to round-trip, but got %q", decoded.String())
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	if got := FormatTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)); got != "2024:01:02-03:04:05" {
+		t.Errorf("Expected 2024:01:02-03:04:05, but got %s", got)
+	}
+	if err := ValidateTimestamp(GetFormattedTimestamp()); err != nil {
+		t.Errorf("Expected current timestamp to use the protocol layout, but got: %v", err)
+	}
+}
+
+func TestParseCircularTimestamp(t *testing.T) {
+	parsed, err := ParseCircularTimestamp("2024:01:02-03:04:05")
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !parsed.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Expected 2024-01-02 03:04:05 UTC, but got %v", parsed)
+	}
+	if err := ValidateTimestamp("2024-01-02 03:04:05"); !errors.Is(err, ErrInvalidTimestamp) {
+		t.Errorf("Expected ErrInvalidTimestamp, but got %v", err)
+	}
+}
+
+func TestHashes(t *testing.T) {
+	if SHA256Hex("abc") != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Error("Unexpected SHA-256 digest")
+	}
+	if SHA3_256Hex("abc") != "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532" {
+		t.Error("Unexpected SHA3-256 digest")
+	}
+	if Keccak256Hex("abc") != "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45" {
+		t.Error("Unexpected Keccak-256 digest")
+	}
+	for name, hash := range map[string]func([]byte) []byte{"SHA-256": SHA256, "SHA3-256": SHA3_256, "Keccak-256": Keccak256} {
+		if len(hash([]byte("abc"))) != 32 {
+			t.Errorf("Expected a 32-byte %s digest", name)
+		}
+	}
+	if hex.EncodeToString(Keccak256([]byte("abc"))) != Keccak256Hex("abc") {
+		t.Error("Expected Keccak256 to match Keccak256Hex")
+	}
+	if hex.EncodeToString(SHA3_256([]byte("abc"))) != SHA3_256Hex("abc") {
+		t.Error("Expected SHA3_256 to match SHA3_256Hex")
+	}
+}
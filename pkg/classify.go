@@ -0,0 +1,91 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+// RetryableError is implemented by errors that know whether the operation
+// that produced them may succeed if attempted again.
+type RetryableError interface {
+	error
+	Retryable() bool
+}
+
+// StatusError reports an HTTP response with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	// Status is the status line, such as "503 Service Unavailable".
+	Status string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return e.Status
+}
+
+// Retryable reports whether the status is a server-side or transient failure:
+// any 5xx, 408 Request Timeout or 429 Too Many Requests. Other 4xx statuses
+// mean the request itself is wrong and will fail again.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests
+}
+
+// statusError returns the *StatusError for resp.
+func statusError(resp *http.Response) *StatusError {
+	return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+}
+
+// Retryable reports true: the server asked the client to try again later.
+func (e *ThrottledError) Retryable() bool { return true }
+
+// Retryable reports whether the NAG result code is in the 5xx range, which
+// the gateway uses like HTTP for failures on its side. Other codes reject the
+// request itself.
+func (e *ResultError) Retryable() bool { return e.Result >= 500 && e.Result < 600 }
+
+// Retryable reports false: the payload will be too large on every attempt.
+func (e *PayloadTooLargeError) Retryable() bool { return false }
+
+// Retryable reports false: the account must be registered or funded first.
+func (e *PreflightError) Retryable() bool { return false }
+
+// Retryable reports false: the gateway returned a response of the wrong shape.
+func (e *SchemaError) Retryable() bool { return false }
+
+// IsRetryable reports whether the operation that returned err may succeed if
+// attempted again. Errors implementing RetryableError anywhere in the chain
+// decide for themselves. Otherwise timeouts, connection failures, temporary
+// DNS failures and truncated responses are retryable; cancellation and
+// everything else, including TLS verification failures, are not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var classified RetryableError
+	if errors.As(err, &classified) {
+		return classified.Retryable()
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
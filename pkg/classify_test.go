@@ -0,0 +1,64 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"Nil", nil, false},
+		{"Server Error", &StatusError{StatusCode: 502, Status: "502 Bad Gateway"}, true},
+		{"Request Timeout Status", &StatusError{StatusCode: 408, Status: "408 Request Timeout"}, true},
+		{"Client Error", &StatusError{StatusCode: 404, Status: "404 Not Found"}, false},
+		{"Wrapped Status", fmt.Errorf("request failed: %w", &StatusError{StatusCode: 503}), true},
+		{"Throttled", &ThrottledError{StatusCode: 429}, true},
+		{"NAG Server Result", &ResultError{Result: 500}, true},
+		{"NAG Rejection", &ResultError{Result: 118}, false},
+		{"Payload Too Large", &PayloadTooLargeError{Size: 2, Limit: 1}, false},
+		{"Preflight", &PreflightError{Err: ErrNotRegistered}, false},
+		{"Schema", &SchemaError{Endpoint: "GetWallet"}, false},
+		{"Canceled", context.Canceled, false},
+		{"Deadline", fmt.Errorf("poll: %w", context.DeadlineExceeded), true},
+		{"DNS Not Found", &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{"DNS Temporary", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, true},
+		{"Connection Refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"Truncated Response", io.ErrUnexpectedEOF, true},
+		{"Unknown", errors.New("something else"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsRetryable(tc.err); got != tc.expected {
+				t.Errorf("Expected %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestStatusErrorFromResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	_, err := c.GetTransactionByIDContext(context.Background(), "tx", "", "")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected a *StatusError with status 502, but got %v", err)
+	}
+	if !IsRetryable(err) {
+		t.Error("Expected a 502 response to be retryable")
+	}
+}
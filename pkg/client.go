@@ -0,0 +1,616 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Client provides access to the read-only operations of a Network Access
+// Gateway (NAG): transactions, blocks, wallets and assets. It holds no keys or
+// account state, which makes it suitable for explorers and monitors. A
+// CEPAccount embeds a Client for the same operations.
+type Client struct {
+	CodeVersion string
+	NAGURL      string
+	NetworkNode string
+	Blockchain  string
+	IntervalSec int
+	ParseMode   ParseMode
+
+	// NetworkURL is the discovery endpoint SetNetwork and DiscoverNAG append
+	// the network identifier to. It defaults to the NetworkURL constant and
+	// is set per client, so clients discovering different environments do
+	// not interfere. See WithNetworkURL.
+	NetworkURL string
+
+	// HTTPClient is used for all network requests made by the client. When
+	// nil, a client backed by a shared, tuned transport is used.
+	HTTPClient *http.Client
+
+	// RequestTimeout bounds each individual HTTP request. It can be
+	// overridden per call with ContextWithRequestTimeout.
+	RequestTimeout time.Duration
+
+	// MaxPayloadSize is the largest hex-encoded certificate payload that is
+	// submitted; larger ones fail with ErrPayloadTooLarge. Zero, the
+	// default, or negative disables the check. See DefaultMaxPayloadSize.
+	MaxPayloadSize int
+
+	// MaxResponseSize is the largest response body read into memory; larger
+	// ones fail with a *ResponseTooLargeError. Zero or negative disables the
+	// check. See DefaultMaxResponseSize.
+	MaxResponseSize int64
+
+	// PollStrategy chooses the delay between polls in GetTransactionOutcome.
+	// When nil, the client polls every IntervalSec seconds.
+	PollStrategy PollStrategy
+
+	// NotFoundGrace is how long GetTransactionOutcome waits for a transaction
+	// reported as not found to appear before failing with
+	// ErrTransactionDropped. Zero or negative, the default, polls until the
+	// timeout. See DefaultNotFoundGrace.
+	NotFoundGrace time.Duration
+
+	// BatchConcurrency is how many requests SubmitBatch and
+	// GetTransactionsByIDs keep in flight at once. See
+	// DefaultBatchConcurrency.
+	BatchConcurrency int
+
+	// SearchDepth is how many of the latest blocks are searched for a
+	// transaction whose block is not known, as by GetTransactionByHash. See
+	// DefaultSearchDepth.
+	SearchDepth int
+
+	// BlockBatchSize is how many blocks IterateBlocks requests at once. See
+	// DefaultBlockBatchSize.
+	BlockBatchSize int
+
+	// Retry controls how failed requests are retried. The zero value makes a
+	// single attempt.
+	Retry RetryPolicy
+
+	// Logger receives diagnostic messages such as polling progress. When nil,
+	// messages are printed to standard output.
+	Logger LogFunc
+
+	// transportOptions customise a dedicated transport for this client.
+	transportOptions []func(*http.Transport)
+	// resolver caches and pins the addresses of the hosts the client dials;
+	// see WithDNSCache and WithPinnedIPs.
+	resolver *hostResolver
+	// connStats receives the statistics of every request attempt; see
+	// WithConnStats.
+	connStats func(ctx context.Context, stats ConnStats)
+	// bulkThrottle limits the requests of bulk operations; see
+	// WithBulkThrottle.
+	bulkThrottle *throttle
+	// adaptivePolls tunes polling per network; see WithAdaptivePolling.
+	adaptivePolls *adaptivePolls
+	// labels attribute the client's activity; see WithLabels.
+	labels map[string]string
+	// dryRun, if set, accepts submissions locally instead of broadcasting
+	// them; see WithDryRun.
+	dryRun *dryRun
+
+	// userAgentSuffix identifies the application in the User-Agent header.
+	userAgentSuffix string
+
+	// headers are added to every request; see WithHeaders.
+	headers http.Header
+
+	// tokenProvider authenticates requests; see WithTokenProvider.
+	tokenProvider TokenProvider
+
+	// requestSigner signs requests; see WithRequestSigner.
+	requestSigner RequestSigner
+
+	// compressMinSize is the smallest request body that is gzip-compressed;
+	// zero disables request compression.
+	compressMinSize int
+
+	// compressionRejected is set once the NAG refuses compressed requests.
+	compressionRejected atomic.Bool
+
+	// chains maps registered chain names to their settings.
+	chains map[string]Chain
+
+	// feeEstimator prices certificates for EstimateCertificate.
+	feeEstimator FeeEstimator
+
+	// clockSkew is the last observed offset of the NAG's clock from the local
+	// clock in nanoseconds; clockSkewKnown is set once one has been observed.
+	clockSkew      atomic.Int64
+	clockSkewKnown atomic.Bool
+	skewWarned     atomic.Bool
+
+	// clockCorrection is the smallest skew corrected in outgoing timestamps;
+	// zero disables correction.
+	clockCorrection time.Duration
+
+	// sensitiveLogging allows payload-bearing content in errors and logs.
+	sensitiveLogging bool
+
+	// auditSink receives a record of every submission attempt.
+	auditSink AuditSink
+
+	// ledger records signed submissions and their statuses; see WithLedger.
+	ledger Ledger
+
+	// events receives the client's lifecycle events; see WithEventBus.
+	events *EventBus
+
+	// receipts indexes the receipts of accepted certificates; see
+	// WithReceiptIndex.
+	receipts *ReceiptIndex
+
+	// networks maps network identifiers to NAG URLs; see WithNetworks.
+	networks map[string]string
+
+	// tombstones holds the tombstones known to the client; see
+	// WithTombstoneStore.
+	tombstones Store
+
+	// payloadSchema validates certificate data before it is signed.
+	payloadSchema *PayloadSchema
+
+	// payloadCodec encodes certificate data; nil uses the JSON format.
+	payloadCodec Codec
+
+	// curve signs transactions; nil uses Secp256k1Curve.
+	curve Curve
+
+	// hashAlgorithms are the digest algorithms added with WithHashAlgorithm.
+	hashAlgorithms map[HashAlgorithm]func() hash.Hash
+
+	// signer, if set, signs transactions instead of a private key; see
+	// WithSigner.
+	signer Signer
+
+	// nodes holds the gateway nodes added with WithNAGNodes.
+	nodes *nodePool
+
+	// usage counts the client's activity; see Usage.
+	usage usageCounters
+
+	// deadLetters receives certificates SubmitBatch fails to submit; see
+	// WithDeadLetterStore.
+	deadLetters DeadLetterStore
+
+	// faultInjector, if set, wraps the transport; see WithFaultInjector.
+	faultInjector *FaultInjector
+
+	// lifecycle tracks background workers and in-flight submissions for
+	// Shutdown.
+	lifecycle lifecycle
+}
+
+// NewClient creates a Client for the given NAG URL and blockchain.
+// Options are applied after the defaults have been set.
+func NewClient(nagURL, chain, version string, opts ...Option) *Client {
+	c := &Client{}
+	c.init(nagURL, chain, version, opts)
+	return c
+}
+
+// init sets the defaults and applies opts. It is shared by NewClient and
+// NewCEPAccount so an embedded Client is configured in place.
+func (c *Client) init(nagURL, chain, version string, opts []Option) {
+	c.CodeVersion = version
+	c.NAGURL = nagURL
+	c.NetworkURL = NetworkURL
+	c.Blockchain = chain
+	c.IntervalSec = 2
+	c.RequestTimeout = DefaultRequestTimeout
+	c.MaxResponseSize = DefaultMaxResponseSize
+	c.BatchConcurrency = DefaultBatchConcurrency
+	c.SearchDepth = DefaultSearchDepth
+	c.BlockBatchSize = DefaultBlockBatchSize
+	c.usage.since = time.Now()
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tombstones == nil {
+		c.tombstones = NewMemoryStore()
+	}
+	if len(c.transportOptions) > 0 && c.HTTPClient == nil {
+		c.HTTPClient = newCustomClient(c.transportOptions)
+	}
+	if c.faultInjector != nil {
+		wrapped := *c.httpClient()
+		wrapped.Transport = c.faultInjector.Wrap(wrapped.Transport)
+		c.HTTPClient = &wrapped
+	}
+}
+
+// SetNetwork configures the client to use a specific blockchain network.
+// It fetches the correct Network Access Gateway (NAG) URL for the given
+// network identifier (e.g., "devnet", "testnet", "mainnet") and updates the
+// NAGURL field on the Client. A custom network URL can also be used.
+func (c *Client) SetNetwork(network string) error {
+	return c.SetNetworkContext(context.Background(), network)
+}
+
+// SetNetworkContext is like SetNetwork but uses ctx for the network request.
+func (c *Client) SetNetworkContext(ctx context.Context, network string) (err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	nag, err := c.DiscoverNAG(ctx, network)
+	if err != nil {
+		return err
+	}
+	c.NAGURL = nag
+
+	return nil
+}
+
+// DiscoverNAG returns the NAG URL of network from the client's NetworkURL,
+// using the client's HTTP client and request timeout, without changing the
+// client. Networks mapped with WithNetworks are answered from the mapping. It
+// replaces the package-level GetNAG.
+func (c *Client) DiscoverNAG(ctx context.Context, network string) (nag string, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if nag, ok := c.networks[network]; ok {
+		return nag, nil
+	}
+
+	// Construct the full URL by appending the network identifier to the base network URL.
+	nagURL, err := url.Parse(c.NetworkURL + network)
+	if err != nil {
+		return "", fmt.Errorf("invalid network URL: %w", err)
+	}
+
+	// Perform an HTTP GET request to retrieve network configuration details.
+	resp, err := c.get(ctx, nagURL.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch network URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("network request failed with status: %w", statusError(resp))
+	}
+
+	body, err := c.readBody(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read network response: %w", err)
+	}
+
+	return parseNAGResponse(body, c.ParseMode == ParseStrict)
+}
+
+// GetTransactionByID retrieves the details of a specific transaction from the blockchain
+// using its unique transaction ID, and optionally a start and end block.
+//
+// The transactionID parameter is the unique string identifying the transaction.
+// The startBlock and endBlock parameters are optional and can be empty strings.
+//
+// On success, it returns a map[string]interface{} containing the transaction
+// details. An error is returned if the NAG_URL is not set, the network request
+// fails, or the response body cannot be properly parsed.
+func (c *Client) GetTransactionByID(transactionID, startBlock, endBlock string) (map[string]interface{}, error) {
+	return c.GetTransactionByIDContext(context.Background(), transactionID, startBlock, endBlock)
+}
+
+// GetTransactionByIDContext is like GetTransactionByID but uses ctx for the
+// network request.
+func (c *Client) GetTransactionByIDContext(ctx context.Context, transactionID, startBlock, endBlock string) (transaction map[string]interface{}, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if c.dryRun != nil {
+		if transaction, ok := c.dryRun.lookup(transactionID); ok {
+			return transaction, nil
+		}
+	}
+
+	// Prepare the request payload
+	requestData := struct {
+		TxID  string `json:"TxID"`
+		Start string `json:"Start"`
+		End   string `json:"End"`
+	}{
+		TxID:  transactionID,
+		Start: startBlock,
+		End:   endBlock,
+	}
+
+	// A non-200 Result, such as a transaction not found yet, is part of the
+	// response rather than an error, so the body is parsed here instead of
+	// by callAt.
+	body, err := readAt(ctx, c, c.NAGURL, "GetTransactionbyID", requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionDetails, err := parseTransactionResponse(body, c.ParseMode == ParseStrict)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionDetails, nil
+}
+
+// GetBlockRange retrieves every block between startBlock and endBlock
+// (inclusive) and invokes fn once per block, in order, as each block is
+// decoded from the response stream. The response body is never buffered as a
+// whole, so memory use is bounded by the largest single block rather than by
+// the size of the range.
+//
+// Decoding stops at the first error returned by fn, and that error is
+// returned unchanged. An error is also returned if the NAG_URL is not set, the
+// network request fails, or the response cannot be parsed.
+func (c *Client) GetBlockRange(startBlock, endBlock int64, fn func(block map[string]interface{}) error) error {
+	return c.GetBlockRangeContext(context.Background(), startBlock, endBlock, fn)
+}
+
+// GetBlockRangeContext is like GetBlockRange but uses ctx for the network
+// request. The request timeout covers streaming the whole response.
+func (c *Client) GetBlockRangeContext(ctx context.Context, startBlock, endBlock int64, fn func(block map[string]interface{}) error) (err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if c.NAGURL == "" {
+		return fmt.Errorf("network is not set. Please call SetNetwork() first")
+	}
+	if startBlock > endBlock {
+		return fmt.Errorf("invalid block range: start %d is after end %d", startBlock, endBlock)
+	}
+
+	requestData := struct {
+		Blockchain string `json:"Blockchain"`
+		Start      string `json:"Start"`
+		End        string `json:"End"`
+		Version    string `json:"Version"`
+	}{
+		Blockchain: c.Blockchain,
+		Start:      strconv.FormatInt(startBlock, 10),
+		End:        strconv.FormatInt(endBlock, 10),
+		Version:    c.CodeVersion,
+	}
+
+	resp, err := postAt(ctx, c, c.NAGURL, "GetBlockRange", requestData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return streamResponseArray(resp.Body, "GetBlockRange", "Blocks", c.ParseMode == ParseStrict, func(raw json.RawMessage) error {
+		var block map[string]interface{}
+		if err := json.Unmarshal(raw, &block); err != nil {
+			return fmt.Errorf("failed to decode block JSON: %w", err)
+		}
+		return fn(block)
+	})
+}
+
+// GetTransactionOutcome retrieves the final processing result of a transaction
+// from the blockchain using its unique ID. This is often used to confirm
+// whether a submitted transaction was successfully validated and included in a block.
+//
+// The 'transactionID' parameter is the unique string identifying the transaction.
+//
+// It returns a map[string]interface{} containing the outcome details on success.
+// An error is returned if the NAG_URL is not configured, the network request fails,
+// or the JSON response cannot be parsed. In strict mode a malformed response ends
+// polling immediately with a *SchemaError instead of being retried until timeout.
+//
+// The delay between polls is chosen by the client's PollStrategy, or by the
+// one passed with WithPollStrategy. With an AdaptivePoll, a timeoutSec of zero
+// or less derives the timeout from the observed latency; see
+// AdaptivePoll.Timeout. OnAttempt reports the progress of each poll. A
+// transaction still reported as not found once the NotFoundGrace period has
+// passed fails with ErrTransactionDropped instead of being polled until
+// timeout.
+func (c *Client) GetTransactionOutcome(TxID string, timeoutSec int, opts ...PollOption) (map[string]interface{}, error) {
+	return c.GetTransactionOutcomeContext(context.Background(), TxID, timeoutSec, opts...)
+}
+
+// GetTransactionOutcomeContext is like GetTransactionOutcome but stops polling
+// when ctx is done. Each poll is an individual request bounded by the
+// client's request timeout, independently of timeoutSec.
+func (c *Client) GetTransactionOutcomeContext(ctx context.Context, TxID string, timeoutSec int, opts ...PollOption) (outcome map[string]interface{}, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if c.NAGURL == "" {
+		return nil, fmt.Errorf("network is not set. Please call SetNetwork() first")
+	}
+	options := c.newPollOptions(opts)
+	startTime := time.Now()
+	timeout := time.Duration(timeoutSec) * time.Second
+	if adaptive, ok := options.strategy.(*AdaptivePoll); ok && timeoutSec <= 0 {
+		timeout = adaptive.Timeout(DefaultAdaptiveTimeout)
+	}
+	defer func() {
+		if observer, ok := options.strategy.(ConfirmationObserver); ok && err == nil {
+			observer.ObserveConfirmation(time.Since(startTime))
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		elapsedTime := time.Since(startTime)
+		if elapsedTime > timeout {
+			return nil, fmt.Errorf("timeout exceeded")
+		}
+
+		data, err := c.GetTransactionByIDContext(ctx, TxID, "", "")
+		if options.onAttempt != nil {
+			options.onAttempt(attempt, outcomeStatus(data), err)
+		}
+		var schemaErr *SchemaError
+		if errors.As(err, &schemaErr) {
+			return nil, err
+		}
+		if err != nil {
+			// Continue polling even if there's an error, in case it's a temporary issue
+			c.logf(ctx, "Error fetching transaction: %v, polling again...", err)
+		} else if notFoundResponse(data) {
+			// Not indexed yet, whatever the Result; this is not a schema
+			// violation even in strict mode.
+		} else if c.ParseMode == ParseStrict {
+			if err := validateOutcome(data); err != nil {
+				return nil, err
+			}
+			if data["Result"].(float64) == 200 {
+				response := data["Response"].(map[string]interface{})
+				if ParseTxStatus(response["Status"].(string)) != TxPending {
+					return response, nil
+				}
+			}
+		} else {
+			// Check for a definitive status
+			if result, ok := data["Result"].(float64); ok && result == 200 {
+				if response, ok := data["Response"].(map[string]interface{}); ok {
+					if status, ok := response["Status"].(string); ok && ParseTxStatus(status) != TxPending {
+						return response, nil // Resolve if transaction is found and not pending
+					}
+				}
+			}
+		}
+
+		if err == nil && options.notFoundGrace > 0 && newOutcome(TxID, data).Status == TxNotFound {
+			if elapsed := time.Since(startTime); elapsed >= options.notFoundGrace {
+				return nil, fmt.Errorf("%w: %s still not found after %v", ErrTransactionDropped, TxID, elapsed.Round(time.Second))
+			}
+		}
+
+		// When the gateway asks us to slow down, wait at least as long as it
+		// advised rather than adding to the load with the regular interval.
+		interval := options.strategy.Next(attempt, time.Since(startTime))
+		var throttled *ThrottledError
+		if errors.As(err, &throttled) && throttled.RetryAfter > interval {
+			interval = throttled.RetryAfter
+		}
+
+		c.logf(ctx, "Transaction not yet confirmed or not found, polling again...")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval): // Continue polling
+		}
+	}
+}
+
+// outcomeStatus returns the transaction Status carried by a successful
+// GetTransactionbyID response, or an empty string if there is none yet.
+func outcomeStatus(data map[string]interface{}) string {
+	if result, ok := data["Result"].(float64); !ok || result != 200 {
+		return ""
+	}
+	response, _ := data["Response"].(map[string]interface{})
+	status, _ := response["Status"].(string)
+	return status
+}
+
+// validateOutcome checks the shape of a GetTransactionbyID response used while
+// polling for an outcome. A response with a non-200 Result is acceptable (the
+// transaction may not be indexed yet), but a 200 must carry a Response object
+// with a string Status.
+func validateOutcome(data map[string]interface{}) error {
+	const endpoint = "GetTransactionbyID"
+	if err := validateFields(endpoint, data, fieldSpec{"Result", "number"}); err != nil {
+		return err
+	}
+	if data["Result"].(float64) != 200 {
+		return nil
+	}
+	return validateFields(endpoint, data, fieldSpec{"Response", "object"}, fieldSpec{"Response.Status", "string"})
+}
+
+// resultError returns a *ResultError if response carries a Result other than
+// 200, and nil otherwise.
+func (c *Client) resultError(endpoint string, response map[string]interface{}) error {
+	if result, ok := response["Result"].(float64); ok && result != 200 {
+		return &ResultError{Endpoint: endpoint, Result: int(result), Message: c.responseMessage(response["Response"])}
+	}
+	return nil
+}
+
+// GetWallet retrieves the wallet registered at address on the client's
+// blockchain, including its public key, balances and nonce.
+func (c *Client) GetWallet(ctx context.Context, address string) (map[string]interface{}, error) {
+	return c.getWallet(ctx, c.Blockchain, c.NAGURL, address)
+}
+
+// getWallet retrieves a wallet from the given blockchain and NAG.
+func (c *Client) getWallet(ctx context.Context, blockchain, nagURL, address string) (map[string]interface{}, error) {
+	return callAt[map[string]interface{}](ctx, c, nagURL, "GetWallet", map[string]interface{}{
+		"Blockchain": blockchain,
+		"Address":    address,
+		"Version":    c.CodeVersion,
+	})
+}
+
+// CheckWallet reports through the gateway's response whether a wallet is
+// registered at address on the client's blockchain.
+func (c *Client) CheckWallet(ctx context.Context, address string) (map[string]interface{}, error) {
+	return Call[map[string]interface{}](ctx, c, "CheckWallet", map[string]interface{}{
+		"Blockchain": c.Blockchain,
+		"Address":    address,
+		"Version":    c.CodeVersion,
+	})
+}
+
+// GetWalletBalance retrieves the balance of asset held by the wallet at address.
+func (c *Client) GetWalletBalance(ctx context.Context, address, asset string) (map[string]interface{}, error) {
+	return Call[map[string]interface{}](ctx, c, "GetWalletBalance", map[string]interface{}{
+		"Blockchain": c.Blockchain,
+		"Address":    address,
+		"Asset":      asset,
+		"Version":    c.CodeVersion,
+	})
+}
+
+// GetBlock retrieves a single block by number.
+func (c *Client) GetBlock(ctx context.Context, blockNumber int64) (map[string]interface{}, error) {
+	return Call[map[string]interface{}](ctx, c, "GetBlock", map[string]interface{}{
+		"Blockchain":  c.Blockchain,
+		"BlockNumber": strconv.FormatInt(blockNumber, 10),
+		"Version":     c.CodeVersion,
+	})
+}
+
+// GetBlockCount retrieves the number of blocks on the client's blockchain.
+func (c *Client) GetBlockCount(ctx context.Context) (map[string]interface{}, error) {
+	return Call[map[string]interface{}](ctx, c, "GetBlockCount", map[string]interface{}{
+		"Blockchain": c.Blockchain,
+		"Version":    c.CodeVersion,
+	})
+}
+
+// GetAsset retrieves the description of the named asset.
+func (c *Client) GetAsset(ctx context.Context, assetName string) (map[string]interface{}, error) {
+	return Call[map[string]interface{}](ctx, c, "GetAsset", map[string]interface{}{
+		"Blockchain": c.Blockchain,
+		"AssetName":  assetName,
+		"Version":    c.CodeVersion,
+	})
+}
+
+// GetAssetList retrieves every asset defined on the client's blockchain.
+func (c *Client) GetAssetList(ctx context.Context) (map[string]interface{}, error) {
+	return Call[map[string]interface{}](ctx, c, "GetAssetList", map[string]interface{}{
+		"Blockchain": c.Blockchain,
+		"Version":    c.CodeVersion,
+	})
+}
+
+// GetAssetSupply retrieves the total and circulating supply of the named asset.
+func (c *Client) GetAssetSupply(ctx context.Context, assetName string) (map[string]interface{}, error) {
+	return Call[map[string]interface{}](ctx, c, "GetAssetSupply", map[string]interface{}{
+		"Blockchain": c.Blockchain,
+		"AssetName":  assetName,
+		"Version":    c.CodeVersion,
+	})
+}
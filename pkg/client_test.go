@@ -0,0 +1,192 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	c := NewClient(DefaultNAG, DefaultChain, LibVersion, WithUserAgentSuffix("explorer/1.0"))
+
+	if c.NAGURL != DefaultNAG {
+		t.Errorf("Expected NAGURL to be %s, got %s", DefaultNAG, c.NAGURL)
+	}
+	if c.Blockchain != DefaultChain {
+		t.Errorf("Expected Blockchain to be %s, got %s", DefaultChain, c.Blockchain)
+	}
+	if c.RequestTimeout != DefaultRequestTimeout {
+		t.Errorf("Expected RequestTimeout to be %v, got %v", DefaultRequestTimeout, c.RequestTimeout)
+	}
+	if c.NetworkURL != NetworkURL {
+		t.Errorf("Expected NetworkURL to be %s, got %s", NetworkURL, c.NetworkURL)
+	}
+	if !strings.HasSuffix(c.UserAgent(), " explorer/1.0") {
+		t.Errorf("Expected option to be applied, got User-Agent %s", c.UserAgent())
+	}
+}
+
+func TestDiscoverNAG(t *testing.T) {
+	newDiscovery := func(nag string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"status":"success","url":"` + nag + r.URL.Query().Get("network") + `"}`))
+		}))
+	}
+	staging := newDiscovery("https://staging.example/")
+	defer staging.Close()
+	production := newDiscovery("https://production.example/")
+	defer production.Close()
+
+	testCases := []struct {
+		name      string
+		discovery string
+		expected  string
+	}{
+		{"Staging", staging.URL, "https://staging.example/testnet"},
+		{"Production", production.URL, "https://production.example/testnet"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewClient("", DefaultChain, LibVersion)
+			c.NetworkURL = tc.discovery + "/getNAG?network="
+			nag, err := c.DiscoverNAG(context.Background(), "testnet")
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if nag != tc.expected || c.NAGURL != "" {
+				t.Errorf("Expected %s without changing the client, but got %s (NAGURL %q)", tc.expected, nag, c.NAGURL)
+			}
+			if err := c.SetNetworkContext(context.Background(), "testnet"); err != nil || c.NAGURL != tc.expected {
+				t.Errorf("Expected SetNetwork to use the client's NetworkURL, but got %q (%v)", c.NAGURL, err)
+			}
+		})
+	}
+}
+
+func TestAccountEmbedsClient(t *testing.T) {
+	acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
+	if acc.NAGURL != DefaultNAG || acc.Client.NAGURL != DefaultNAG {
+		t.Error("Expected the account's network configuration to live on its embedded Client")
+	}
+	if acc.IntervalSec != 2 {
+		t.Errorf("Expected IntervalSec to default to 2, got %d", acc.IntervalSec)
+	}
+}
+
+func TestClientReadOnlyQueries(t *testing.T) {
+	var path string
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		request = nil
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"ok":true}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name            string
+		call            func() (map[string]interface{}, error)
+		expectedPath    string
+		expectedRequest map[string]interface{}
+	}{
+		{
+			name:            "GetWallet",
+			call:            func() (map[string]interface{}, error) { return c.GetWallet(ctx, "0xabc") },
+			expectedPath:    "/Circular_GetWallet_",
+			expectedRequest: map[string]interface{}{"Address": "0xabc"},
+		},
+		{
+			name:            "CheckWallet",
+			call:            func() (map[string]interface{}, error) { return c.CheckWallet(ctx, "0xabc") },
+			expectedPath:    "/Circular_CheckWallet_",
+			expectedRequest: map[string]interface{}{"Address": "0xabc"},
+		},
+		{
+			name:            "GetWalletBalance",
+			call:            func() (map[string]interface{}, error) { return c.GetWalletBalance(ctx, "0xabc", "CIRX") },
+			expectedPath:    "/Circular_GetWalletBalance_",
+			expectedRequest: map[string]interface{}{"Address": "0xabc", "Asset": "CIRX"},
+		},
+		{
+			name:            "GetBlock",
+			call:            func() (map[string]interface{}, error) { return c.GetBlock(ctx, 42) },
+			expectedPath:    "/Circular_GetBlock_",
+			expectedRequest: map[string]interface{}{"BlockNumber": "42"},
+		},
+		{
+			name:         "GetBlockCount",
+			call:         func() (map[string]interface{}, error) { return c.GetBlockCount(ctx) },
+			expectedPath: "/Circular_GetBlockCount_",
+		},
+		{
+			name:            "GetAsset",
+			call:            func() (map[string]interface{}, error) { return c.GetAsset(ctx, "CIRX") },
+			expectedPath:    "/Circular_GetAsset_",
+			expectedRequest: map[string]interface{}{"AssetName": "CIRX"},
+		},
+		{
+			name:         "GetAssetList",
+			call:         func() (map[string]interface{}, error) { return c.GetAssetList(ctx) },
+			expectedPath: "/Circular_GetAssetList_",
+		},
+		{
+			name:            "GetAssetSupply",
+			call:            func() (map[string]interface{}, error) { return c.GetAssetSupply(ctx, "CIRX") },
+			expectedPath:    "/Circular_GetAssetSupply_",
+			expectedRequest: map[string]interface{}{"AssetName": "CIRX"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.call()
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if result["Result"] != float64(200) {
+				t.Errorf("Expected Result 200, but got %v", result["Result"])
+			}
+			if path != tc.expectedPath {
+				t.Errorf("Expected path %s, but got %s", tc.expectedPath, path)
+			}
+			if request["Blockchain"] != DefaultChain || request["Version"] != LibVersion {
+				t.Errorf("Expected Blockchain and Version in request, but got %v", request)
+			}
+			for k, v := range tc.expectedRequest {
+				if request[k] != v {
+					t.Errorf("Expected request[%s] to be %v, but got %v", k, v, request[k])
+				}
+			}
+		})
+	}
+}
+
+func TestClientCallErrorResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":118,"Response":"Wallet Not Found"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	_, err := c.GetWallet(context.Background(), "0xabc")
+	if err == nil {
+		t.Fatal("Expected an error but got nil")
+	}
+	if !strings.Contains(err.Error(), "Wallet Not Found") {
+		t.Errorf("Expected error to contain the gateway message, but got %s", err.Error())
+	}
+	var resultErr *ResultError
+	if !errors.As(err, &resultErr) || resultErr.Result != 118 {
+		t.Errorf("Expected a *ResultError with result 118, but got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultSkewWarningThreshold is the clock skew above which the client logs a
+// warning. The timestamp is part of the transaction ID hash, so a NAG may
+// reject transactions from a machine whose clock has drifted this far.
+const DefaultSkewWarningThreshold = 5 * time.Second
+
+// WithClockCorrection shifts the timestamps of outgoing transactions by the
+// observed skew between the local clock and the NAG's whenever the skew is at
+// least threshold. The NAG only reports time to the second, so thresholds
+// below a couple of seconds correct noise rather than drift.
+func WithClockCorrection(threshold time.Duration) Option {
+	return func(c *Client) {
+		c.clockCorrection = threshold
+	}
+}
+
+// ClockSkew returns the offset of the NAG's clock from the local clock as
+// last observed from a response, and whether one has been observed yet. A
+// positive skew means the local clock is behind.
+func (c *Client) ClockSkew() (time.Duration, bool) {
+	if !c.clockSkewKnown.Load() {
+		return 0, false
+	}
+	return time.Duration(c.clockSkew.Load()), true
+}
+
+// MeasureClockSkew makes a lightweight query to the NAG and returns the skew
+// measured from its response, which also updates ClockSkew.
+func (c *Client) MeasureClockSkew(ctx context.Context) (skew time.Duration, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	resp, err := postAt(ctx, c, c.NAGURL, "GetBlockCount", map[string]interface{}{
+		"Blockchain": c.Blockchain,
+		"Version":    c.CodeVersion,
+	})
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if _, err := http.ParseTime(resp.Header.Get("Date")); err != nil {
+		return 0, errors.New("the network did not report its time")
+	}
+	skew, _ = c.ClockSkew()
+	return skew, nil
+}
+
+// observeClock records the skew implied by a response's Date header. The
+// server's time is compared with the midpoint of the exchange, and half a
+// second is added because the header is truncated to whole seconds.
+func (c *Client) observeClock(ctx context.Context, resp *http.Response, start, end time.Time) {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	midpoint := start.Add(end.Sub(start) / 2)
+	skew := date.Add(500 * time.Millisecond).Sub(midpoint).Round(time.Second)
+	c.clockSkew.Store(int64(skew))
+	c.clockSkewKnown.Store(true)
+
+	if abs(skew) >= DefaultSkewWarningThreshold && !c.skewWarned.Swap(true) {
+		c.logf(ctx, "local clock differs from the network by %v; transactions may be rejected (see WithClockCorrection)", skew)
+	}
+}
+
+// now returns the time used for transaction timestamps: the local time,
+// shifted by the observed skew when clock correction applies.
+func (c *Client) now() time.Time {
+	now := time.Now()
+	if c.clockCorrection <= 0 {
+		return now
+	}
+	if skew, ok := c.ClockSkew(); ok && abs(skew) >= c.clockCorrection {
+		return now.Add(skew)
+	}
+	return now
+}
+
+// abs returns the absolute value of d.
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,109 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// skewedServer reports a clock offset from the local one by skew and records
+// the timestamp of the last submitted transaction.
+func skewedServer(skew time.Duration, timestamp *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		if ts, ok := request["Timestamp"].(string); ok && timestamp != nil {
+			*timestamp = ts
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+}
+
+func TestMeasureClockSkew(t *testing.T) {
+	server := skewedServer(-time.Hour, nil)
+	defer server.Close()
+
+	var warnings []string
+	c := NewClient(server.URL, DefaultChain, LibVersion,
+		WithLogger(func(ctx context.Context, message string) { warnings = append(warnings, message) }))
+
+	if _, ok := c.ClockSkew(); ok {
+		t.Error("Expected no skew before any response")
+	}
+
+	skew, err := c.MeasureClockSkew(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if skew < -time.Hour-2*time.Second || skew > -time.Hour+2*time.Second {
+		t.Errorf("Expected skew of about -1h, but got %v", skew)
+	}
+
+	c.GetBlockCount(context.Background())
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "WithClockCorrection") {
+		t.Errorf("Expected a single skew warning, but got %v", warnings)
+	}
+}
+
+func TestMeasureClockSkewWithoutDate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Date"] = nil
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	if _, err := c.MeasureClockSkew(context.Background()); err == nil {
+		t.Error("Expected an error but got nil")
+	}
+}
+
+func TestClockCorrection(t *testing.T) {
+	testCases := []struct {
+		name       string
+		correction time.Duration
+		expectSkew bool
+	}{
+		{"Disabled", 0, false},
+		{"Enabled", 10 * time.Second, true},
+		{"Below Threshold", 2 * time.Hour, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var timestamp string
+			server := skewedServer(time.Hour, &timestamp)
+			defer server.Close()
+
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion,
+				WithClockCorrection(tc.correction),
+				WithLogger(func(ctx context.Context, message string) {}))
+			acc.Open("0xabc")
+
+			if _, err := acc.MeasureClockSkew(context.Background()); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if _, err := acc.SubmitCertificate("data", strings.Repeat("1", 64)); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			submitted, err := utils.ParseTimestamp(timestamp)
+			if err != nil {
+				t.Fatalf("Expected a valid submitted timestamp, but got: %v", err)
+			}
+			offset := submitted.Sub(time.Now())
+			shifted := offset > 50*time.Minute
+			if shifted != tc.expectSkew {
+				t.Errorf("Expected timestamp shifted %v, but it was offset by %v", tc.expectSkew, offset)
+			}
+		})
+	}
+}
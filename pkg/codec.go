@@ -0,0 +1,210 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Codec encodes certificate data for storage on chain. Marshal receives the
+// data as decoded JSON (maps, slices, strings, json.Number, booleans and
+// nil) unless the application passes its own values, and Unmarshal returns an
+// equivalent value. Implementations must be safe for concurrent use.
+type Codec interface {
+	// Name identifies the codec in certificate metadata, such as "cbor".
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte) (interface{}, error)
+}
+
+// JSONCodec is the default codec. Certificates submitted with it keep the
+// historical payload format.
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{"json": JSONCodec, "cbor": CBORCodec}
+)
+
+// RegisterCodec makes codec available for decoding certificates that name
+// it, replacing any codec registered under the same name.
+func RegisterCodec(codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[codec.Name()] = codec
+}
+
+// LookupCodec returns the codec registered under name.
+func LookupCodec(name string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown codec %q", name)
+	}
+	return codec, nil
+}
+
+// Codecs returns the names of the registered codecs, sorted.
+func Codecs() []string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithPayloadCodec encodes the data of every certificate submitted by the
+// client with codec. See WithCodec.
+func WithPayloadCodec(codec Codec) Option {
+	return func(c *Client) {
+		c.payloadCodec = codec
+	}
+}
+
+// WithCodec encodes the certificate data with codec instead of the client's
+// payload codec. The data passed to SubmitCertificate must then be JSON; it
+// is decoded and re-encoded with codec, and the codec's name is stored in the
+// payload so it can be decoded again. JSONCodec keeps the historical format.
+func WithCodec(codec Codec) SubmitOption {
+	return func(o *submitOptions) {
+		o.codec = codec
+	}
+}
+
+// codecFrameMarker starts a payload encoded with a codec other than JSON. It
+// is followed by the length of the codec name, the name and the encoded data.
+// A JSON payload always starts with '{', so the two cannot be confused.
+const codecFrameMarker = 0x00
+
+// isLegacyCodec reports whether codec produces the historical JSON payload.
+func isLegacyCodec(codec Codec) bool {
+	return codec == nil || codec.Name() == JSONCodec.Name()
+}
+
+// encodeCodecPayload decodes the JSON pdata, encodes it with codec and
+// returns the framed payload in hex, using scratch as working space.
+func encodeCodecPayload(scratch *bytes.Buffer, pdata string, codec Codec) (string, error) {
+	value, err := JSONCodec.Unmarshal([]byte(pdata))
+	if err != nil {
+		return "", fmt.Errorf("data must be JSON to use the %s codec: %w", codec.Name(), err)
+	}
+	encoded, err := codec.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode payload with %s codec: %w", codec.Name(), err)
+	}
+	name := codec.Name()
+	if len(name) == 0 || len(name) > 255 {
+		return "", fmt.Errorf("invalid codec name %q", name)
+	}
+
+	scratch.Reset()
+	scratch.WriteByte(codecFrameMarker)
+	scratch.WriteByte(byte(len(name)))
+	scratch.WriteString(name)
+	scratch.Write(encoded)
+	payloadStart := scratch.Len()
+	appendHex(scratch, scratch.Bytes())
+	return string(scratch.Bytes()[payloadStart:]), nil
+}
+
+// decodeCodecFrame decodes a framed payload and returns the data as compact
+// JSON with object keys sorted.
+func decodeCodecFrame(frame []byte) (string, error) {
+	if len(frame) < 2 || len(frame) < 2+int(frame[1]) {
+		return "", fmt.Errorf("truncated codec payload")
+	}
+	name := string(frame[2 : 2+int(frame[1])])
+	codec, err := LookupCodec(name)
+	if err != nil {
+		return "", err
+	}
+	value, err := codec.Unmarshal(frame[2+int(frame[1]):])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode payload with %s codec: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, jsonCompatible(value)); err != nil {
+		return "", fmt.Errorf("failed to convert %s payload to JSON: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// jsonCompatible converts byte strings in a decoded value to hex so the value
+// can be marshalled as JSON.
+func jsonCompatible(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return hex.EncodeToString(v)
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = jsonCompatible(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = jsonCompatible(value)
+		}
+	}
+	return v
+}
+
+// SetValue encodes v with codec and stores it as the certificate data in the
+// certificate's Encoding, recording the codec in its Codec field. JSONCodec
+// leaves Codec empty so the certificate is identical to one built with
+// SetData.
+func (c *Certificate) SetValue(v interface{}, codec Codec) error {
+	encoded, err := codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode certificate data with %s codec: %w", codec.Name(), err)
+	}
+	c.setRawData(encoded)
+	c.Codec = ""
+	if !isLegacyCodec(codec) {
+		c.Codec = codec.Name()
+	}
+	return nil
+}
+
+// Value decodes the certificate data with the codec named in its Codec
+// field, or as JSON when it is empty.
+func (c *Certificate) Value() (interface{}, error) {
+	name := c.Codec
+	if name == "" {
+		name = JSONCodec.Name()
+	}
+	codec, err := LookupCodec(name)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := c.rawData()
+	if err != nil {
+		return nil, err
+	}
+	value, err := codec.Unmarshal(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode certificate data with %s codec: %w", name, err)
+	}
+	return value, nil
+}
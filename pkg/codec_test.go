@@ -0,0 +1,148 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSubmitCertificateCodec(t *testing.T) {
+	data := `{"student": {"name": "Ada", "id": 42}, "grades": [9.5, 8], "passed": true}`
+
+	testCases := []struct {
+		name     string
+		codec    Codec
+		expected string
+	}{
+		{name: "Default", expected: data},
+		{name: "JSON", codec: JSONCodec, expected: data},
+		{name: "CBOR", codec: CBORCodec, expected: `{"grades":[9.5,8],"passed":true,"student":{"id":42,"name":"Ada"}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var payload string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				payload, _ = body["Payload"].(string)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+			}))
+			defer server.Close()
+
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+			acc.Open("0x" + strings.Repeat("a", 64))
+			var opts []SubmitOption
+			if tc.codec != nil {
+				opts = append(opts, WithCodec(tc.codec))
+			}
+			if _, err := acc.SubmitCertificateContext(context.Background(), data, strings.Repeat("1", 64), opts...); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			decoded, err := decodePayload(payload)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if decoded != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, decoded)
+			}
+		})
+	}
+}
+
+func TestSubmitCertificateCodecRequiresJSON(t *testing.T) {
+	acc := NewCEPAccount("http://127.0.0.1:0", DefaultChain, LibVersion, WithPayloadCodec(CBORCodec))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	_, err := acc.SubmitCertificateContext(context.Background(), "plain text", strings.Repeat("1", 64))
+	if err == nil || !strings.Contains(err.Error(), "must be JSON") {
+		t.Errorf("Expected an error for non-JSON data, but got %v", err)
+	}
+}
+
+func TestCertificateSetValue(t *testing.T) {
+	value := map[string]interface{}{"name": "Ada", "id": int64(42)}
+
+	for _, codec := range []Codec{JSONCodec, CBORCodec} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			cert := NewCertificate(LibVersion)
+			if err := cert.SetValue(value, codec); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if codec == JSONCodec && cert.Codec != "" {
+				t.Errorf("Expected no codec to be recorded for JSON, but got %q", cert.Codec)
+			}
+			if codec == CBORCodec && cert.Codec != "cbor" {
+				t.Errorf("Expected codec cbor, but got %q", cert.Codec)
+			}
+
+			decoded, err := cert.Value()
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			roundTrip, _ := json.Marshal(decoded)
+			if string(roundTrip) != `{"id":42,"name":"Ada"}` {
+				t.Errorf("Expected the value to round-trip, but got %s", roundTrip)
+			}
+		})
+	}
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Name() string { return "upper" }
+func (upperCodec) Marshal(v interface{}) ([]byte, error) {
+	return []byte(strings.ToUpper(v.(string))), nil
+}
+func (upperCodec) Unmarshal(data []byte) (interface{}, error) {
+	return strings.ToLower(string(data)), nil
+}
+
+func TestRegisterCodec(t *testing.T) {
+	if _, err := LookupCodec("upper"); err == nil {
+		t.Fatal("Expected an unknown codec to fail lookup")
+	}
+	RegisterCodec(upperCodec{})
+	defer func() {
+		codecsMu.Lock()
+		delete(codecs, "upper")
+		codecsMu.Unlock()
+	}()
+
+	if names := Codecs(); !reflect.DeepEqual(names, []string{"cbor", "json", "upper"}) {
+		t.Errorf("Expected the registered codecs, but got %v", names)
+	}
+	cert := NewCertificate(LibVersion)
+	cert.SetValue("hello", upperCodec{})
+	if value, err := cert.Value(); err != nil || value != "hello" {
+		t.Errorf("Expected hello, but got %v (%v)", value, err)
+	}
+}
+
+func FuzzDecodePayload(f *testing.F) {
+	f.Add("", "")
+	f.Add("68656c6c6f", "certificate data")
+	f.Add("00044a534f4e7b7d", `{"a":[1,2]}`)
+	f.Add("0004434f524aa0", "")
+	f.Add("0xzz", "\x00\xff")
+	f.Fuzz(func(t *testing.T, payload, data string) {
+		// Arbitrary payloads, as read back from the chain, must not panic.
+		decodePayload(payload)
+
+		encoded, err := encodePayload(new(bytes.Buffer), data)
+		if err != nil {
+			return
+		}
+		decoded, err := decodePayload(encoded)
+		if err != nil || (decoded != data && utf8.ValidString(data)) {
+			t.Errorf("Expected %q to round-trip, but got %q, %v", data, decoded, err)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+// Package compat holds test vectors shared between the Circular Enterprise
+// API SDKs. Each vector fixes every input of a certificate submission,
+// including the timestamp, so any SDK can check that it reproduces the same
+// canonical payload, transaction ID, signature and request body byte for byte.
+//
+// The vectors live in testdata/vectors.json and are embedded in the package.
+// Every vector records its Source and SourceVersion. Vectors exported from
+// another SDK are added to the file unchanged, with that SDK named as their
+// source and its release as the version: for example "circular-js" and the
+// version in its package.json, "circular-python" and its PyPI release, or
+// "circular-java" and its Maven version. Such SDKs may derive ECDSA nonces
+// differently, so their signatures are checked by verification rather than
+// byte for byte; payloads, signature inputs and IDs must match exactly.
+package compat
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed testdata/vectors.json
+var vectorsJSON []byte
+
+// CertificateVector is one certificate submission with all inputs fixed.
+type CertificateVector struct {
+	Name string `json:"name"`
+	// Source names the implementation that produced the vector, such as
+	// "go-reference" for this SDK, and SourceVersion its release.
+	Source        string `json:"source"`
+	SourceVersion string `json:"source_version"`
+
+	// Inputs.
+	Address    string `json:"address"`
+	Blockchain string `json:"blockchain"`
+	Data       string `json:"data"`
+	Timestamp  string `json:"timestamp"`
+	PrivateKey string `json:"private_key"`
+
+	// Expected outputs. Payload is the hex-encoded {"data": ...} object,
+	// SignatureInput the string whose SHA-256 digest is the transaction ID
+	// and is signed, ID the transaction ID, Signature the hex DER signature
+	// and Request the exact body posted to the NAG.
+	Payload        string `json:"payload"`
+	SignatureInput string `json:"signature_input"`
+	ID             string `json:"id"`
+	Signature      string `json:"signature"`
+	Request        string `json:"request"`
+}
+
+// HexVector is a string and its hexadecimal encoding.
+type HexVector struct {
+	Input string `json:"input"`
+	Hex   string `json:"hex"`
+}
+
+// TimestampVector is an instant and its protocol timestamp.
+type TimestampVector struct {
+	Unix      int64  `json:"unix"`
+	Formatted string `json:"formatted"`
+}
+
+// GoReference is the Source of the vectors produced by this SDK.
+const GoReference = "go-reference"
+
+// Vectors is the full set of shared test vectors.
+type Vectors struct {
+	Certificates []CertificateVector `json:"certificates"`
+	Hex          []HexVector         `json:"hex"`
+	Timestamps   []TimestampVector   `json:"timestamps"`
+}
+
+// Load decodes the embedded vectors.
+func Load() (*Vectors, error) {
+	var v Vectors
+	if err := json.Unmarshal(vectorsJSON, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode compat vectors: %w", err)
+	}
+	return &v, nil
+}
@@ -0,0 +1,18 @@
+package compat
+
+import "testing"
+
+func TestLoad(t *testing.T) {
+	v, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if len(v.Certificates) == 0 || len(v.Hex) == 0 || len(v.Timestamps) == 0 {
+		t.Fatalf("Expected every vector group to be populated, got %d/%d/%d", len(v.Certificates), len(v.Hex), len(v.Timestamps))
+	}
+	for _, c := range v.Certificates {
+		if c.Name == "" || c.Source == "" || c.SourceVersion == "" || c.SignatureInput == "" || c.ID == "" || c.Request == "" {
+			t.Errorf("Expected vector %q to have a name, source, source version and expected outputs", c.Name)
+		}
+	}
+}
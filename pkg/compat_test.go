@@ -0,0 +1,99 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	decdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/compat"
+)
+
+// TestCompatVectors checks that the Go implementation reproduces the shared
+// cross-SDK vectors byte for byte.
+func TestCompatVectors(t *testing.T) {
+	vectors, err := compat.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	for _, v := range vectors.Certificates {
+		t.Run(v.Name+"/"+v.Source, func(t *testing.T) {
+			acc := NewCEPAccount(DefaultNAG, v.Blockchain, LibVersion)
+			acc.Open(v.Address)
+
+			var scratch bytes.Buffer
+			payload, err := encodePayload(&scratch, v.Data)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if payload != v.Payload {
+				t.Errorf("Expected payload %s, but got %s", v.Payload, payload)
+			}
+
+			scratch.Reset()
+			writeTransactionIDInput(&scratch, v.Blockchain, v.Address, "", payload, "", v.Timestamp)
+			if scratch.String() != v.SignatureInput {
+				t.Errorf("Expected signature input %s, but got %s", v.SignatureInput, scratch.String())
+			}
+
+			request, err := acc.signCertificate(context.Background(), &scratch, v.Blockchain, "", payload, v.Timestamp, v.PrivateKey)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if request.ID != v.ID {
+				t.Errorf("Expected ID %s, but got %s", v.ID, request.ID)
+			}
+
+			// Other SDKs may derive ECDSA nonces differently, so only this
+			// SDK's own vectors are expected to match its signature and
+			// request byte for byte.
+			if v.Source == compat.GoReference {
+				if request.Signature != v.Signature {
+					t.Errorf("Expected signature %s, but got %s", v.Signature, request.Signature)
+				}
+				scratch.Reset()
+				if err := encodeJSON(&scratch, request); err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				if scratch.String() != v.Request {
+					t.Errorf("Expected request %s, but got %s", v.Request, scratch.String())
+				}
+			}
+
+			// The expected signature must also verify on its own, so a vector
+			// from another SDK is checked even if its nonce derivation differs.
+			keyBytes, _ := hex.DecodeString(utils.HexFix(v.PrivateKey))
+			publicKey := secp256k1.PrivKeyFromBytes(keyBytes).PubKey()
+			sigBytes, _ := hex.DecodeString(v.Signature)
+			signature, err := decdsa.ParseDERSignature(sigBytes)
+			if err != nil {
+				t.Fatalf("Expected a DER signature, but got: %v", err)
+			}
+			digest := sha256.Sum256([]byte(v.SignatureInput))
+			if !signature.Verify(digest[:], publicKey) {
+				t.Error("Expected vector signature to verify against the private key's public key")
+			}
+		})
+	}
+
+	for _, v := range vectors.Hex {
+		if got := utils.StringToHex(v.Input); got != v.Hex {
+			t.Errorf("Expected hex of %q to be %s, but got %s", v.Input, v.Hex, got)
+		}
+		if got := utils.HexToString(v.Hex); got != v.Input {
+			t.Errorf("Expected %s to decode to %q, but got %q", v.Hex, v.Input, got)
+		}
+	}
+
+	for _, v := range vectors.Timestamps {
+		if got := utils.FormatTimestamp(time.Unix(v.Unix, 0)); got != v.Formatted {
+			t.Errorf("Expected timestamp for %d to be %s, but got %s", v.Unix, v.Formatted, got)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// gzipWriterPool reuses gzip writers, which are expensive to allocate.
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(io.Discard) },
+}
+
+// WithRequestCompression gzip-compresses request bodies of at least minSize
+// bytes. If the NAG answers a compressed request with 415 Unsupported Media
+// Type, the request is resent uncompressed and compression is switched off for
+// the client. Responses are always requested and decompressed with gzip.
+func WithRequestCompression(minSize int) Option {
+	return func(c *Client) {
+		c.compressMinSize = minSize
+	}
+}
+
+// shouldCompress reports whether a request body qualifies for compression.
+func (c *Client) shouldCompress(body io.Reader) bool {
+	if c.compressMinSize <= 0 || c.compressionRejected.Load() {
+		return false
+	}
+	sized, ok := body.(interface{ Len() int })
+	return ok && sized.Len() >= c.compressMinSize
+}
+
+// gzipBytes compresses data into a pooled buffer.
+func gzipBytes(data []byte) (*bytes.Buffer, error) {
+	buf := getBuffer()
+	zw := gzipWriterPool.Get().(*gzip.Writer)
+	defer gzipWriterPool.Put(zw)
+	zw.Reset(buf)
+	if _, err := zw.Write(data); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// postCompressed sends body gzip-compressed, falling back to an uncompressed
+// request if the server rejects the encoding.
+func (c *Client) postCompressed(ctx context.Context, requestURL string, body io.Reader) (*http.Response, error) {
+	data, err := io.ReadAll(body)
+	if closer, ok := body.(io.Closer); ok {
+		closer.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	compressed, err := gzipBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	resp, err := c.sendJSON(ctx, requestURL, newPooledBody(compressed), "gzip")
+	if err != nil || resp.StatusCode != http.StatusUnsupportedMediaType {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	c.compressionRejected.Store(true)
+	return c.sendJSON(ctx, requestURL, bytes.NewReader(data), "")
+}
+
+// decompressResponse transparently replaces a gzip-encoded response body with
+// its decompressed form.
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decompress response: %w", err)
+	}
+	resp.Body = &gzipReadCloser{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipReadCloser closes both the gzip stream and the underlying body.
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the decompressor and the response body.
+func (g *gzipReadCloser) Close() error {
+	g.Reader.Close()
+	return g.body.Close()
+}
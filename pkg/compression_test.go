@@ -0,0 +1,132 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipResponseDecompression(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(`{"Result":200,"Response":{"Nonce":41}}`))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	// A client with transport-level decompression disabled proves the
+	// library decompresses on its own.
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.HTTPClient = &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	acc.Open("0x123")
+
+	if _, err := acc.UpdateAccount(); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if acceptEncoding != "gzip" {
+		t.Errorf("Expected Accept-Encoding gzip, but got %q", acceptEncoding)
+	}
+	if acc.Nonce != 42 {
+		t.Errorf("Expected Nonce 42, but got %d", acc.Nonce)
+	}
+}
+
+func TestRequestCompression(t *testing.T) {
+	var encodings []string
+	var bodies []string
+	rejectGzip := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		encodings = append(encodings, encoding)
+		if encoding == "gzip" && rejectGzip {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		var reader io.Reader = r.Body
+		if encoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("Failed to read gzip body: %v", err)
+				return
+			}
+			reader = zr
+		}
+		body, _ := io.ReadAll(reader)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	t.Run("Large Body Compressed", func(t *testing.T) {
+		encodings, bodies, rejectGzip = nil, nil, false
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRequestCompression(10))
+		acc.Open("0x123")
+
+		if _, err := acc.UpdateAccount(); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(encodings) != 1 || encodings[0] != "gzip" {
+			t.Fatalf("Expected one gzip request, but got %v", encodings)
+		}
+		if !strings.Contains(bodies[0], `"Address":"0x123"`) {
+			t.Errorf("Expected the decompressed body to contain the address, but got %s", bodies[0])
+		}
+	})
+
+	t.Run("Small Body Not Compressed", func(t *testing.T) {
+		encodings, bodies, rejectGzip = nil, nil, false
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRequestCompression(1<<20))
+		acc.Open("0x123")
+
+		if _, err := acc.UpdateAccount(); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(encodings) != 1 || encodings[0] != "" {
+			t.Errorf("Expected one uncompressed request, but got %v", encodings)
+		}
+	})
+
+	t.Run("Rejected Compression Falls Back", func(t *testing.T) {
+		encodings, bodies, rejectGzip = nil, nil, true
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRequestCompression(10))
+		acc.Open("0x123")
+
+		if _, err := acc.UpdateAccount(); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if _, err := acc.UpdateAccount(); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		expected := []string{"gzip", "", ""}
+		if strings.Join(encodings, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected encodings %v, but got %v", expected, encodings)
+		}
+	})
+}
+
+func TestGzipBytesRoundTrip(t *testing.T) {
+	input := bytes.Repeat([]byte("certificate "), 1000)
+	compressed, err := gzipBytes(input)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	defer putBuffer(compressed)
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open gzip stream: %v", err)
+	}
+	output, err := io.ReadAll(zr)
+	if err != nil || !bytes.Equal(output, input) {
+		t.Errorf("Expected round trip to reproduce input (err=%v)", err)
+	}
+}
@@ -0,0 +1,293 @@
+// Package config loads Circular Enterprise API settings from a configuration
+// file and the environment and turns them into a ready-to-use client or
+// account.
+//
+// Settings are resolved in order of increasing precedence: library defaults,
+// then the configuration file, then environment variables. Every setting has
+// a flat key that is used in files and, upper-cased with a CIRCULAR_ prefix,
+// as its environment variable:
+//
+//	nag_url             CIRCULAR_NAG_URL
+//	network             CIRCULAR_NETWORK
+//	network_url         CIRCULAR_NETWORK_URL
+//	networks_file       CIRCULAR_NETWORKS_FILE
+//	chain_id            CIRCULAR_CHAIN_ID
+//	address             CIRCULAR_ADDRESS
+//	key_source          CIRCULAR_KEY_SOURCE
+//	interval_sec        CIRCULAR_INTERVAL_SEC
+//	request_timeout     CIRCULAR_REQUEST_TIMEOUT
+//	retry_max_attempts  CIRCULAR_RETRY_MAX_ATTEMPTS
+//	retry_backoff       CIRCULAR_RETRY_BACKOFF
+//	retry_max_backoff   CIRCULAR_RETRY_MAX_BACKOFF
+//	chains              CIRCULAR_CHAINS
+//	max_payload_size    CIRCULAR_MAX_PAYLOAD_SIZE
+//
+// Durations use time.ParseDuration syntax, for example "30s". Additional
+// chains are listed as comma-separated name=id pairs, for example
+// "audit-chain=0x1234,billing=0x5678". The networks file is a static
+// network-to-NAG mapping read with cep.LoadNetworks, for environments that
+// cannot reach the discovery endpoint.
+//
+// MarshalConfig writes a configuration, for example one captured from a
+// running account with FromAccount, as a JSON file in the same format, and
+// UnmarshalConfig reads it back and validates it.
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+// EnvPrefix is prepended to the upper-cased key of each setting to form the
+// name of its environment variable.
+const EnvPrefix = "CIRCULAR_"
+
+// DefaultKeySource reads the private key from the variable already used by
+// the integration tests.
+const DefaultKeySource = "env:CIRCULAR_PRIVATE_KEY"
+
+// Config holds the resolved settings.
+type Config struct {
+	NAGURL         string
+	Network        string
+	NetworkURL     string
+	NetworksFile   string
+	ChainID        string
+	Address        string
+	KeySource      string
+	IntervalSec    int
+	RequestTimeout time.Duration
+	Retry          cep.RetryPolicy
+	Chains         []cep.Chain
+	MaxPayloadSize int
+
+	// Networks is the mapping read from NetworksFile by Load.
+	Networks map[string]string
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() *Config {
+	return &Config{
+		NetworkURL:     cep.NetworkURL,
+		ChainID:        cep.DefaultChain,
+		KeySource:      DefaultKeySource,
+		IntervalSec:    2,
+		RequestTimeout: cep.DefaultRequestTimeout,
+	}
+}
+
+// Load resolves the configuration from the file at path, if path is not
+// empty, and the environment. The file format is chosen by its extension:
+// .json, .yaml, .yml or .toml.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.apply(values, path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.apply(environment(), "environment"); err != nil {
+		return nil, err
+	}
+	if cfg.NetworksFile != "" {
+		networks, err := cep.LoadNetworks(cfg.NetworksFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Networks = networks
+	}
+	return cfg, nil
+}
+
+// setting describes how one key is stored in a Config.
+type setting struct {
+	key string
+	set func(c *Config, value string) error
+}
+
+// settings lists every recognised key.
+var settings = []setting{
+	{"nag_url", func(c *Config, v string) error { c.NAGURL = v; return nil }},
+	{"network", func(c *Config, v string) error { c.Network = v; return nil }},
+	{"network_url", func(c *Config, v string) error { c.NetworkURL = v; return nil }},
+	{"networks_file", func(c *Config, v string) error { c.NetworksFile = v; return nil }},
+	{"chain_id", func(c *Config, v string) error { c.ChainID = v; return nil }},
+	{"address", func(c *Config, v string) error { c.Address = v; return nil }},
+	{"key_source", func(c *Config, v string) error { c.KeySource = v; return nil }},
+	{"interval_sec", func(c *Config, v string) error { return setInt(&c.IntervalSec, v) }},
+	{"request_timeout", func(c *Config, v string) error { return setDuration(&c.RequestTimeout, v) }},
+	{"retry_max_attempts", func(c *Config, v string) error { return setInt(&c.Retry.MaxAttempts, v) }},
+	{"retry_backoff", func(c *Config, v string) error { return setDuration(&c.Retry.Backoff, v) }},
+	{"retry_max_backoff", func(c *Config, v string) error { return setDuration(&c.Retry.MaxBackoff, v) }},
+	{"chains", func(c *Config, v string) error { return setChains(&c.Chains, v) }},
+	{"max_payload_size", func(c *Config, v string) error { return setInt(&c.MaxPayloadSize, v) }},
+}
+
+// apply stores each recognised value in c. Unknown keys are rejected so that
+// a misspelt setting does not silently fall back to its default.
+func (c *Config) apply(values map[string]string, source string) error {
+	for key, value := range values {
+		s, ok := lookupSetting(key)
+		if !ok {
+			return fmt.Errorf("%s: unknown setting %q", source, key)
+		}
+		if err := s.set(c, value); err != nil {
+			return fmt.Errorf("%s: invalid %s: %w", source, key, err)
+		}
+	}
+	return nil
+}
+
+// lookupSetting finds the setting for key.
+func lookupSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// environment returns the settings present in the process environment.
+func environment() map[string]string {
+	values := make(map[string]string)
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(EnvPrefix + strings.ToUpper(s.key)); ok {
+			values[s.key] = value
+		}
+	}
+	return values
+}
+
+func setInt(dst *int, value string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return err
+	}
+	*dst = n
+	return nil
+}
+
+func setDuration(dst *time.Duration, value string) error {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return err
+	}
+	*dst = d
+	return nil
+}
+
+func setChains(dst *[]cep.Chain, value string) error {
+	var chains []cep.Chain
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, id, ok := strings.Cut(entry, "=")
+		name, id = strings.TrimSpace(name), strings.TrimSpace(id)
+		if !ok || name == "" || id == "" {
+			return fmt.Errorf("expected name=id, got %q", entry)
+		}
+		chains = append(chains, cep.Chain{Name: name, ID: id})
+	}
+	*dst = chains
+	return nil
+}
+
+// Options returns the client options described by the configuration.
+func (c *Config) Options() []cep.Option {
+	opts := []cep.Option{
+		cep.WithRetryPolicy(c.Retry),
+		cep.WithChains(c.Chains...),
+		cep.WithMaxPayloadSize(c.MaxPayloadSize),
+		cep.WithNetworkURL(c.NetworkURL),
+		cep.WithNetworks(c.Networks),
+	}
+	if c.RequestTimeout > 0 {
+		opts = append(opts, cep.WithRequestTimeout(c.RequestTimeout))
+	}
+	return opts
+}
+
+// NewClient creates a client from the configuration. When no NAG URL is
+// configured but a network is, the NAG is discovered with SetNetworkContext.
+func (c *Config) NewClient(ctx context.Context, opts ...cep.Option) (*cep.Client, error) {
+	client := cep.NewClient(c.nagURL(), c.ChainID, cep.LibVersion, append(c.Options(), opts...)...)
+	if err := c.configure(ctx, client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewAccount creates an account from the configuration and opens it when an
+// address is configured. NAG discovery works as for NewClient.
+func (c *Config) NewAccount(ctx context.Context, opts ...cep.Option) (*cep.CEPAccount, error) {
+	account := cep.NewCEPAccount(c.nagURL(), c.ChainID, cep.LibVersion, append(c.Options(), opts...)...)
+	if err := c.configure(ctx, &account.Client); err != nil {
+		return nil, err
+	}
+	if c.Address != "" {
+		if err := account.Open(c.Address); err != nil {
+			return nil, err
+		}
+	}
+	return account, nil
+}
+
+// nagURL returns the NAG URL to start from: the configured one, the default
+// public NAG when no network is named, or empty when it must be discovered.
+func (c *Config) nagURL() string {
+	if c.NAGURL == "" && c.Network == "" {
+		return cep.DefaultNAG
+	}
+	return c.NAGURL
+}
+
+// configure applies the settings that are not expressed as options.
+func (c *Config) configure(ctx context.Context, client *cep.Client) error {
+	if c.IntervalSec > 0 {
+		client.IntervalSec = c.IntervalSec
+	}
+	if client.NAGURL == "" {
+		if err := client.SetNetworkContext(ctx, c.Network); err != nil {
+			return fmt.Errorf("failed to discover NAG for network %q: %w", c.Network, err)
+		}
+	}
+	return nil
+}
+
+// PrivateKey resolves the key source to the hex-encoded private key. The
+// source is either "env:NAME", naming an environment variable, or
+// "file:PATH", naming a file whose trimmed contents are the key.
+func (c *Config) PrivateKey() (string, error) {
+	kind, ref, ok := strings.Cut(c.KeySource, ":")
+	if !ok || ref == "" {
+		return "", fmt.Errorf("invalid key source %q: expected env:NAME or file:PATH", c.KeySource)
+	}
+	switch kind {
+	case "env":
+		key, ok := os.LookupEnv(ref)
+		if !ok || key == "" {
+			return "", fmt.Errorf("private key variable %s is not set", ref)
+		}
+		return key, nil
+	case "file":
+		data, err := os.ReadFile(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to read private key file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	default:
+		return "", fmt.Errorf("invalid key source %q: unsupported kind %q", c.KeySource, kind)
+	}
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if cfg.ChainID != cep.DefaultChain {
+		t.Errorf("Expected ChainID to be %s, got %s", cep.DefaultChain, cfg.ChainID)
+	}
+	if cfg.RequestTimeout != cep.DefaultRequestTimeout {
+		t.Errorf("Expected RequestTimeout to be %v, got %v", cep.DefaultRequestTimeout, cfg.RequestTimeout)
+	}
+	if cfg.MaxPayloadSize != 0 {
+		t.Errorf("Expected MaxPayloadSize to be 0, got %d", cfg.MaxPayloadSize)
+	}
+	if cfg.KeySource != DefaultKeySource {
+		t.Errorf("Expected KeySource to be %s, got %s", DefaultKeySource, cfg.KeySource)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, "circular.yaml", "nag_url: https://file.example/NAG.php?cep=\ninterval_sec: 5\nretry_max_attempts: 3\nchains: audit-chain=0x01, billing=0x02\n")
+	t.Setenv("CIRCULAR_NAG_URL", "https://env.example/NAG.php?cep=")
+	t.Setenv("CIRCULAR_RETRY_BACKOFF", "250ms")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if cfg.NAGURL != "https://env.example/NAG.php?cep=" {
+		t.Errorf("Expected environment to override file, got %s", cfg.NAGURL)
+	}
+	if cfg.IntervalSec != 5 {
+		t.Errorf("Expected IntervalSec from file to be 5, got %d", cfg.IntervalSec)
+	}
+	if cfg.Retry.MaxAttempts != 3 || cfg.Retry.Backoff != 250*time.Millisecond {
+		t.Errorf("Expected retry policy {3 250ms}, got %+v", cfg.Retry)
+	}
+	if len(cfg.Chains) != 2 || cfg.Chains[0] != (cep.Chain{Name: "audit-chain", ID: "0x01"}) {
+		t.Errorf("Expected two chains starting with audit-chain, got %+v", cfg.Chains)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"Unknown Setting", "c.json", `{"nag_urll":"x"}`},
+		{"Invalid Duration", "c.toml", `request_timeout = "soon"`},
+		{"Unsupported Format", "c.ini", `nag_url=x`},
+		{"Malformed Chains", "c.yaml", `chains: audit-chain`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Load(writeFile(t, tc.file, tc.content)); err == nil {
+				t.Error("Expected an error but got nil")
+			}
+		})
+	}
+}
+
+func TestPrivateKey(t *testing.T) {
+	t.Setenv("TEST_CIRCULAR_KEY", "abc123")
+	keyFile := writeFile(t, "key.hex", "def456\n")
+
+	testCases := []struct {
+		name        string
+		source      string
+		expected    string
+		expectError bool
+	}{
+		{"Environment", "env:TEST_CIRCULAR_KEY", "abc123", false},
+		{"File", "file:" + keyFile, "def456", false},
+		{"Unset Variable", "env:TEST_CIRCULAR_MISSING", "", true},
+		{"Unknown Kind", "vault:secret", "", true},
+		{"Malformed", "abc123", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := (&Config{KeySource: tc.source}).PrivateKey()
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if key != tc.expected {
+				t.Errorf("Expected key %s, but got %s", tc.expected, key)
+			}
+		})
+	}
+}
+
+func TestNewAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","url":"https://discovered.example/NAG.php?cep="}`))
+	}))
+	defer server.Close()
+
+	cfg := Default()
+	cfg.Network = "testnet"
+	cfg.NetworkURL = server.URL + "/getNAG?network="
+	cfg.Address = "0xabc"
+	cfg.IntervalSec = 7
+	cfg.Retry = cep.RetryPolicy{MaxAttempts: 2}
+
+	acc, err := cfg.NewAccount(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if acc.NAGURL != "https://discovered.example/NAG.php?cep=" {
+		t.Errorf("Expected NAG to be discovered, got %s", acc.NAGURL)
+	}
+	if acc.Address != "0xabc" || acc.IntervalSec != 7 || acc.Retry.MaxAttempts != 2 {
+		t.Errorf("Expected configuration to be applied, got address %s, interval %d, retry %+v", acc.Address, acc.IntervalSec, acc.Retry)
+	}
+}
+
+func TestNewClientDefaultNAG(t *testing.T) {
+	client, err := Default().NewClient(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if client.NAGURL != cep.DefaultNAG {
+		t.Errorf("Expected NAGURL to be %s, got %s", cep.DefaultNAG, client.NAGURL)
+	}
+}
+
+func TestNetworksFile(t *testing.T) {
+	networks := writeFile(t, "networks.json", `{"airgap":"https://nag.internal/NAG.php?cep="}`)
+	t.Setenv("CIRCULAR_NETWORK", "airgap")
+	t.Setenv("CIRCULAR_NETWORKS_FILE", networks)
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	acc, err := cfg.NewAccount(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if acc.NAGURL != "https://nag.internal/NAG.php?cep=" {
+		t.Errorf("Expected the NAG from the networks file, got %s", acc.NAGURL)
+	}
+
+	t.Setenv("CIRCULAR_NETWORKS_FILE", writeFile(t, "broken.json", "{"))
+	if _, err := Load(""); err == nil {
+		t.Error("Expected an error for an invalid networks file, but got nil")
+	}
+}
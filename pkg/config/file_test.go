@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormats(t *testing.T) {
+	expected := map[string]string{
+		"nag_url":         "https://nag.example/NAG.php?cep=",
+		"interval_sec":    "4",
+		"request_timeout": "10s",
+	}
+
+	testCases := []struct {
+		name    string
+		parse   func([]byte) (map[string]string, error)
+		content string
+	}{
+		{
+			name:    "JSON",
+			parse:   parseJSON,
+			content: `{"nag_url":"https://nag.example/NAG.php?cep=","interval_sec":4,"request_timeout":"10s"}`,
+		},
+		{
+			name:    "YAML",
+			parse:   func(b []byte) (map[string]string, error) { return parseLines(b, ":") },
+			content: "---\n# gateway\nnag_url: \"https://nag.example/NAG.php?cep=\"\ninterval_sec: 4 # seconds\nrequest_timeout: '10s'\n",
+		},
+		{
+			name:    "TOML",
+			parse:   func(b []byte) (map[string]string, error) { return parseLines(b, "=") },
+			content: "# gateway\nnag_url = \"https://nag.example/NAG.php?cep=\"\ninterval_sec = 4\nrequest_timeout = \"10s\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := tc.parse([]byte(tc.content))
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if !reflect.DeepEqual(values, expected) {
+				t.Errorf("Expected %v, but got %v", expected, values)
+			}
+		})
+	}
+}
+
+func TestParseRejectsNesting(t *testing.T) {
+	if _, err := parseLines([]byte("[retry]\nmax_attempts = 3\n"), "="); err == nil {
+		t.Error("Expected an error for a TOML table but got nil")
+	}
+	if _, err := parseJSON([]byte(`{"retry":{"max_attempts":3}}`)); err == nil {
+		t.Error("Expected an error for a nested JSON object but got nil")
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+// MarshalConfig encodes the configuration as a flat JSON object using the
+// same keys as configuration files, so the result can be templated, checked
+// into a deployment and read back with UnmarshalConfig or Load. Empty string
+// settings are omitted, while numbers and durations are always written since
+// zero does not always mean the default. The private key itself is never part of a
+// configuration, only its key source; the networks read from NetworksFile
+// are not included either, only the path of the file.
+func MarshalConfig(c *Config) ([]byte, error) {
+	return json.MarshalIndent(c.values(), "", "  ")
+}
+
+// UnmarshalConfig decodes a configuration encoded by MarshalConfig, or
+// written by hand in the same format, on top of the defaults and validates
+// it. Unlike Load, it neither reads the environment nor the networks file.
+func UnmarshalConfig(data []byte) (*Config, error) {
+	values, err := parseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	cfg := Default()
+	if err := cfg.apply(values, "config"); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// FromClient returns the configuration of an existing client, for example to
+// export the settings a service was built with. The NAG URL of registered
+// chains is not part of a configuration and is dropped.
+func FromClient(client *cep.Client) *Config {
+	cfg := Default()
+	cfg.NAGURL = client.NAGURL
+	cfg.NetworkURL = client.NetworkURL
+	cfg.ChainID = client.Blockchain
+	cfg.IntervalSec = client.IntervalSec
+	cfg.RequestTimeout = client.RequestTimeout
+	cfg.Retry = client.Retry
+	cfg.Chains = client.Chains()
+	cfg.MaxPayloadSize = client.MaxPayloadSize
+	for i := range cfg.Chains {
+		cfg.Chains[i].NAGURL = ""
+	}
+	return cfg
+}
+
+// FromAccount returns the configuration of an existing account, including
+// its address.
+func FromAccount(account *cep.CEPAccount) *Config {
+	cfg := FromClient(&account.Client)
+	cfg.Address = account.Address
+	return cfg
+}
+
+// Validate checks the settings for values that cannot work, reporting every
+// problem found rather than only the first.
+func (c *Config) Validate() error {
+	var errs []error
+	for _, s := range []struct{ key, value string }{{"nag_url", c.NAGURL}, {"network_url", c.NetworkURL}} {
+		if s.value == "" {
+			continue
+		}
+		if u, err := url.Parse(s.value); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid %s: %q is not an absolute URL", s.key, s.value))
+		}
+	}
+	if !isHex(c.ChainID) {
+		errs = append(errs, fmt.Errorf("invalid chain_id: %q is not a hex identifier", c.ChainID))
+	}
+	for _, chain := range c.Chains {
+		if !isHex(chain.ID) {
+			errs = append(errs, fmt.Errorf("invalid chains: %q is not a hex identifier for %s", chain.ID, chain.Name))
+		}
+	}
+	if c.Address != "" && !isHex(c.Address) {
+		errs = append(errs, fmt.Errorf("invalid address: %q is not hex", c.Address))
+	}
+	if c.KeySource != "" {
+		if kind, ref, ok := strings.Cut(c.KeySource, ":"); !ok || ref == "" || (kind != "env" && kind != "file") {
+			errs = append(errs, fmt.Errorf("invalid key_source: %q is not env:NAME or file:PATH", c.KeySource))
+		}
+	}
+	for _, s := range []struct {
+		key   string
+		value int64
+	}{
+		{"interval_sec", int64(c.IntervalSec)},
+		{"retry_max_attempts", int64(c.Retry.MaxAttempts)},
+		{"request_timeout", int64(c.RequestTimeout)},
+		{"retry_backoff", int64(c.Retry.Backoff)},
+		{"retry_max_backoff", int64(c.Retry.MaxBackoff)},
+	} {
+		if s.value < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s: must not be negative", s.key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// values returns the settings of c by key, the inverse of apply.
+func (c *Config) values() map[string]interface{} {
+	var chains []string
+	for _, chain := range c.Chains {
+		chains = append(chains, chain.Name+"="+chain.ID)
+	}
+	values := map[string]interface{}{
+		"interval_sec":       c.IntervalSec,
+		"request_timeout":    c.RequestTimeout.String(),
+		"retry_max_attempts": c.Retry.MaxAttempts,
+		"retry_backoff":      c.Retry.Backoff.String(),
+		"retry_max_backoff":  c.Retry.MaxBackoff.String(),
+		"max_payload_size":   c.MaxPayloadSize,
+	}
+	for key, value := range map[string]string{
+		"nag_url":       c.NAGURL,
+		"network":       c.Network,
+		"network_url":   c.NetworkURL,
+		"networks_file": c.NetworksFile,
+		"chain_id":      c.ChainID,
+		"address":       c.Address,
+		"key_source":    c.KeySource,
+		"chains":        strings.Join(chains, ","),
+	} {
+		if value != "" {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// isHex reports whether value is a non-empty hex string, with or without a
+// 0x prefix.
+func isHex(value string) bool {
+	value = strings.TrimPrefix(strings.ToLower(value), "0x")
+	if len(value)%2 == 1 {
+		value = "0" + value
+	}
+	_, err := hex.DecodeString(value)
+	return value != "" && err == nil
+}
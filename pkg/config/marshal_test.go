@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+func TestMarshalConfigRoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.NAGURL = "https://nag.example/NAG.php?cep="
+	cfg.Address = "0xabc"
+	cfg.IntervalSec = 5
+	cfg.RequestTimeout = 0
+	cfg.Retry = cep.RetryPolicy{MaxAttempts: 3, Backoff: 250 * time.Millisecond, MaxBackoff: 5 * time.Second}
+	cfg.Chains = []cep.Chain{{Name: "audit-chain", ID: "0x01"}, {Name: "billing", ID: "0x02"}}
+	cfg.MaxPayloadSize = cep.DefaultMaxPayloadSize
+
+	data, err := MarshalConfig(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Expected a JSON object, but got: %v", err)
+	}
+	for _, key := range []string{"network", "networks_file"} {
+		if _, ok := raw[key]; ok {
+			t.Errorf("Expected the empty %s to be omitted", key)
+		}
+	}
+
+	decoded, err := UnmarshalConfig(data)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, cfg) {
+		t.Errorf("Expected %+v, but got %+v", cfg, decoded)
+	}
+
+	// The output is also a configuration file Load accepts.
+	loaded, err := Load(writeFile(t, "circular.json", string(data)))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("Expected %+v, but got %+v", cfg, loaded)
+	}
+}
+
+func TestFromAccount(t *testing.T) {
+	account := cep.NewCEPAccount("https://nag.example/NAG.php?cep=", cep.DefaultChain, cep.LibVersion,
+		cep.WithRetryPolicy(cep.RetryPolicy{MaxAttempts: 2}),
+		cep.WithChains(cep.Chain{Name: "audit-chain", ID: "0x01", NAGURL: "https://other.example/"}))
+	account.Open("0xabc")
+
+	cfg := FromAccount(account)
+	if cfg.NAGURL != account.NAGURL || cfg.ChainID != cep.DefaultChain || cfg.Address != "0xabc" {
+		t.Errorf("Expected the account's NAG, chain and address, but got %+v", cfg)
+	}
+	if cfg.Retry.MaxAttempts != 2 || cfg.IntervalSec != account.IntervalSec {
+		t.Errorf("Expected the account's retry policy and interval, but got %+v", cfg)
+	}
+	if len(cfg.Chains) != 1 || cfg.Chains[0] != (cep.Chain{Name: "audit-chain", ID: "0x01"}) {
+		t.Errorf("Expected the registered chain without its NAG, but got %+v", cfg.Chains)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected the exported configuration to be valid, but got: %v", err)
+	}
+}
+
+func TestUnmarshalConfigErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"Malformed", `{`, "failed to parse config"},
+		{"Unknown Setting", `{"nag_uri":"https://nag.example/"}`, "unknown setting"},
+		{"Invalid Duration", `{"retry_backoff":"soon"}`, "invalid retry_backoff"},
+		{"Relative NAG", `{"nag_url":"nag.example"}`, "invalid nag_url"},
+		{"Chain ID", `{"chain_id":"main"}`, "invalid chain_id"},
+		{"Chains", `{"chains":"audit=zz"}`, "invalid chains"},
+		{"Key Source", `{"key_source":"vault:secret"}`, "invalid key_source"},
+		{"Negative", `{"interval_sec":-1}`, "invalid interval_sec"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnmarshalConfig([]byte(tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected an error containing %q, but got %v", tc.expected, err)
+			}
+		})
+	}
+
+	// Every problem is reported at once.
+	_, err := UnmarshalConfig([]byte(`{"chain_id":"main","interval_sec":-1}`))
+	if err == nil || !strings.Contains(err.Error(), "chain_id") || !strings.Contains(err.Error(), "interval_sec") {
+		t.Errorf("Expected both problems to be reported, but got %v", err)
+	}
+}
+
+func TestMarshalConfigOmitsSecrets(t *testing.T) {
+	t.Setenv("CIRCULAR_PRIVATE_KEY", strings.Repeat("1", 64))
+	data, err := MarshalConfig(Default())
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if strings.Contains(string(data), os.Getenv("CIRCULAR_PRIVATE_KEY")) {
+		t.Errorf("Expected the private key not to be exported, but got %s", data)
+	}
+}
@@ -0,0 +1,136 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnStats describes the connection that carried one attempt of a request,
+// so operators can confirm keep-alive and HTTP/2 work against their gateway.
+// The phase durations are zero when a pooled connection was reused, since no
+// new connection was set up.
+type ConnStats struct {
+	Host string
+	Path string
+	// Reused reports whether the connection had already carried a request.
+	Reused bool
+	// WasIdle reports whether the connection came from the idle pool, and
+	// IdleTime how long it had been there.
+	WasIdle  bool
+	IdleTime time.Duration
+	// Protocol is the protocol of the response, such as "HTTP/1.1" or
+	// "HTTP/2.0".
+	Protocol string
+	// TLSVersion and ALPN describe the TLS session, when there is one.
+	TLSVersion string
+	ALPN       string
+	// DNS, Connect and TLSHandshake time the phases of setting up a new
+	// connection.
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	// FirstByte is the time from sending the request to the first byte of
+	// the response.
+	FirstByte time.Duration
+	// Err is the error of an attempt that got no response.
+	Err error
+}
+
+// WithConnStats calls fn with the statistics of the connection behind every
+// request attempt the client makes, including retries and polling. fn runs
+// synchronously on the goroutine of the request, so it should return quickly.
+func WithConnStats(fn func(ctx context.Context, stats ConnStats)) Option {
+	return func(c *Client) {
+		c.connStats = fn
+	}
+}
+
+// connTracer collects ConnStats through an httptrace.ClientTrace. Its hooks
+// may run on the transport's dialing goroutines.
+type connTracer struct {
+	mu    sync.Mutex
+	stats ConnStats
+	start time.Time
+
+	dnsStart, connectStart, tlsStart time.Time
+}
+
+// trace attaches the tracer to ctx.
+func (t *connTracer) trace(ctx context.Context) context.Context {
+	t.start = time.Now()
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.DNS = since(t.dnsStart)
+		},
+		ConnectStart: func(_, _ string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(_, _ string, _ error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.Connect = since(t.connectStart)
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.TLSHandshake = since(t.tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.Reused = info.Reused
+			t.stats.WasIdle = info.WasIdle
+			t.stats.IdleTime = info.IdleTime
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.FirstByte = time.Since(t.start)
+		},
+	})
+}
+
+// since returns the time elapsed since start, or zero if start is unset.
+func since(start time.Time) time.Duration {
+	if start.IsZero() {
+		return 0
+	}
+	return time.Since(start)
+}
+
+// reportConnStats passes the statistics of the attempt of req to the
+// client's hook.
+func (c *Client) reportConnStats(ctx context.Context, t *connTracer, req *http.Request, resp *http.Response, err error) {
+	t.mu.Lock()
+	stats := t.stats
+	t.mu.Unlock()
+	stats.Host = req.URL.Host
+	stats.Path = req.URL.Path
+	stats.Err = err
+	if resp != nil {
+		stats.Protocol = resp.Proto
+		if resp.TLS != nil {
+			stats.TLSVersion = tls.VersionName(resp.TLS.Version)
+			stats.ALPN = resp.TLS.NegotiatedProtocol
+		}
+	}
+	c.connStats(ctx, stats)
+}
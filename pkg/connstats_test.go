@@ -0,0 +1,55 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestConnStats(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Result":200,"Response":"ok"}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	var mu sync.Mutex
+	var got []ConnStats
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithConnStats(func(_ context.Context, stats ConnStats) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, stats)
+	}))
+	c.HTTPClient = server.Client()
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.get(context.Background(), server.URL+"/stats")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 stats, but got %d", len(got))
+	}
+	first, second := got[0], got[1]
+	if first.Reused || first.TLSHandshake == 0 || first.Connect == 0 {
+		t.Errorf("Expected a new connection first, but got %+v", first)
+	}
+	if !second.Reused || second.TLSHandshake != 0 {
+		t.Errorf("Expected the connection to be reused, but got %+v", second)
+	}
+	for _, stats := range got {
+		if stats.Protocol != "HTTP/2.0" || stats.ALPN != "h2" || stats.TLSVersion == "" || stats.Path != "/stats" || stats.FirstByte == 0 {
+			t.Errorf("Expected an HTTP/2 request to /stats, but got %+v", stats)
+		}
+	}
+}
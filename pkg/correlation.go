@@ -0,0 +1,113 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// HeaderCorrelationID carries the correlation ID of an operation on every
+// request sent to the NAG.
+const HeaderCorrelationID = "X-Correlation-ID"
+
+// correlationIDKey is the context key for the correlation ID.
+type correlationIDKey struct{}
+
+// NewCorrelationID returns a random 128-bit identifier encoded as hex.
+func NewCorrelationID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id. Operations
+// started with this context send id to the NAG and attach it to errors and log
+// messages, so a certificate can be traced across services.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, if any.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// withCorrelationID returns ctx unchanged if it already carries a correlation
+// ID, or a copy carrying a freshly generated one otherwise.
+func withCorrelationID(ctx context.Context) (context.Context, string) {
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		return ctx, id
+	}
+	id := NewCorrelationID()
+	return ContextWithCorrelationID(ctx, id), id
+}
+
+// CorrelatedError annotates an error with the correlation ID of the operation
+// that produced it. The original error is available through errors.Unwrap.
+type CorrelatedError struct {
+	CorrelationID string
+	Err           error
+}
+
+// Error implements the error interface.
+func (e *CorrelatedError) Error() string {
+	return fmt.Sprintf("%v (correlation ID %s)", e.Err, e.CorrelationID)
+}
+
+// Unwrap returns the underlying error.
+func (e *CorrelatedError) Unwrap() error {
+	return e.Err
+}
+
+// annotateError wraps *err with the correlation ID unless it is nil or
+// already annotated by a nested operation.
+func annotateError(err *error, id string) {
+	if *err == nil || id == "" {
+		return
+	}
+	var correlated *CorrelatedError
+	if errors.As(*err, &correlated) {
+		return
+	}
+	*err = &CorrelatedError{CorrelationID: id, Err: *err}
+}
+
+// LogFunc receives diagnostic messages produced by a client. The
+// correlation ID of the operation can be read from ctx with
+// CorrelationIDFromContext.
+type LogFunc func(ctx context.Context, message string)
+
+// WithLogger routes the client's diagnostic messages to fn instead of
+// standard output.
+func WithLogger(fn LogFunc) Option {
+	return func(c *Client) {
+		c.Logger = fn
+	}
+}
+
+// logf formats a diagnostic message and hands it to the client's logger,
+// with the client's labels in the context. Standard output gets the labels
+// appended to the message.
+func (c *Client) logf(ctx context.Context, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if c.Logger != nil {
+		if len(c.labels) > 0 {
+			ctx = context.WithValue(ctx, labelsKey{}, c.Labels())
+		}
+		c.Logger(ctx, message)
+		return
+	}
+	if len(c.labels) > 0 {
+		message += " {" + formatLabels(c.labels) + "}"
+	}
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		fmt.Printf("[%s] %s\n", id, message)
+		return
+	}
+	fmt.Println(message)
+}
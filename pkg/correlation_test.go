@@ -0,0 +1,100 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCorrelationIDHeader(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(HeaderCorrelationID))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x123")
+
+	t.Run("Generated", func(t *testing.T) {
+		received = nil
+		if _, err := acc.UpdateAccount(); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(received) != 1 || len(received[0]) != 32 {
+			t.Errorf("Expected a generated 32-character correlation ID, but got %v", received)
+		}
+	})
+
+	t.Run("From Context", func(t *testing.T) {
+		received = nil
+		ctx := ContextWithCorrelationID(context.Background(), "ticket-4711")
+		if _, err := acc.UpdateAccountContext(ctx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(received) != 1 || received[0] != "ticket-4711" {
+			t.Errorf("Expected correlation ID ticket-4711, but got %v", received)
+		}
+	})
+}
+
+func TestCorrelationIDInErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.ParseMode = ParseStrict
+	acc.Open("0x123")
+
+	ctx := ContextWithCorrelationID(context.Background(), "ticket-4711")
+	_, err := acc.UpdateAccountContext(ctx)
+
+	var correlated *CorrelatedError
+	if !errors.As(err, &correlated) {
+		t.Fatalf("Expected a *CorrelatedError, but got %v", err)
+	}
+	if correlated.CorrelationID != "ticket-4711" {
+		t.Errorf("Expected correlation ID ticket-4711, but got %s", correlated.CorrelationID)
+	}
+	if !strings.Contains(err.Error(), "ticket-4711") {
+		t.Errorf("Expected error message to contain the correlation ID, but got %s", err.Error())
+	}
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Errorf("Expected the underlying *SchemaError to remain accessible, but got %v", err)
+	}
+}
+
+func TestLoggerReceivesCorrelationID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Status":"Pending"}}`))
+	}))
+	defer server.Close()
+
+	var logged []string
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithLogger(func(ctx context.Context, message string) {
+		id, _ := CorrelationIDFromContext(ctx)
+		logged = append(logged, id+": "+message)
+	}))
+	acc.IntervalSec = 1
+
+	ctx := ContextWithCorrelationID(context.Background(), "ticket-4711")
+	if _, err := acc.GetTransactionOutcomeContext(ctx, "0xabc", 1); err == nil {
+		t.Fatal("Expected a timeout error but got nil")
+	}
+	if len(logged) == 0 {
+		t.Fatal("Expected the logger to receive polling messages")
+	}
+	if !strings.HasPrefix(logged[0], "ticket-4711: ") {
+		t.Errorf("Expected log message to carry the correlation ID, but got %s", logged[0])
+	}
+}
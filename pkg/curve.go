@@ -0,0 +1,182 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	decdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// Curve is a signature scheme used to sign transactions. The data signed is
+// always the SHA-256 digest of the transaction. Implementations must be safe
+// for concurrent use. Curves whose signatures are longer than the 72 bytes of
+// a DER-encoded secp256k1 signature should also have a MaxSignatureSize() int
+// method, so that EstimateCertificate can bound the size of the request.
+type Curve interface {
+	// Name identifies the curve in transaction metadata, such as "ed25519".
+	Name() string
+	// Sign signs digest with the raw private key and returns the signature
+	// in the curve's wire encoding.
+	Sign(privateKey, digest []byte) ([]byte, error)
+	// Verify returns an error unless signature is a valid signature of
+	// digest by the raw public key.
+	Verify(publicKey, digest, signature []byte) error
+	// PublicKey returns the raw public key of privateKey.
+	PublicKey(privateKey []byte) ([]byte, error)
+}
+
+// Secp256k1Curve is the default curve: ECDSA over secp256k1 with
+// DER-encoded signatures, as required by the Circular protocol today.
+// Transactions signed with it carry no curve metadata.
+var Secp256k1Curve Curve = secp256k1Curve{}
+
+// Ed25519Curve signs with Ed25519. Private keys are 32-byte seeds or 64-byte
+// keys as produced by crypto/ed25519. Transactions signed with it record the
+// curve, for networks that accept it.
+var Ed25519Curve Curve = ed25519Curve{}
+
+type secp256k1Curve struct{}
+
+func (secp256k1Curve) Name() string { return "secp256k1" }
+
+// MaxSignatureSize is the length of the longest DER-encoded signature.
+func (secp256k1Curve) MaxSignatureSize() int { return 72 }
+
+func (secp256k1Curve) Sign(privateKey, digest []byte) ([]byte, error) {
+	// The Sign function of decred/dcrd/dcrec/secp256k1/v4/ecdsa is
+	// deterministic (RFC 6979).
+	return decdsa.Sign(secp256k1.PrivKeyFromBytes(privateKey), digest).Serialize(), nil
+}
+
+func (secp256k1Curve) Verify(publicKey, digest, signature []byte) error {
+	key, err := secp256k1.ParsePubKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	sig, err := decdsa.ParseDERSignature(signature)
+	if err != nil {
+		return fmt.Errorf("invalid secp256k1 signature: %w", err)
+	}
+	if !sig.Verify(digest, key) {
+		return fmt.Errorf("secp256k1 signature does not match")
+	}
+	return nil
+}
+
+func (secp256k1Curve) PublicKey(privateKey []byte) ([]byte, error) {
+	return secp256k1.PrivKeyFromBytes(privateKey).PubKey().SerializeCompressed(), nil
+}
+
+type ed25519Curve struct{}
+
+func (ed25519Curve) Name() string { return "ed25519" }
+
+// MaxSignatureSize is the length of every Ed25519 signature.
+func (ed25519Curve) MaxSignatureSize() int { return ed25519.SignatureSize }
+
+// key returns the ed25519.PrivateKey for a seed or a full private key.
+func (ed25519Curve) key(privateKey []byte) (ed25519.PrivateKey, error) {
+	switch len(privateKey) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(privateKey), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(privateKey), nil
+	default:
+		return nil, fmt.Errorf("ed25519 private key must be %d or %d bytes, not %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(privateKey))
+	}
+}
+
+func (c ed25519Curve) Sign(privateKey, digest []byte) ([]byte, error) {
+	key, err := c.key(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(key, digest), nil
+}
+
+func (ed25519Curve) Verify(publicKey, digest, signature []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid ed25519 public key length %d", len(publicKey))
+	}
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), digest, signature) {
+		return fmt.Errorf("ed25519 signature does not match")
+	}
+	return nil
+}
+
+func (c ed25519Curve) PublicKey(privateKey []byte) ([]byte, error) {
+	key, err := c.key(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return key.Public().(ed25519.PublicKey), nil
+}
+
+var (
+	curvesMu sync.RWMutex
+	curves   = map[string]Curve{"secp256k1": Secp256k1Curve, "ed25519": Ed25519Curve}
+)
+
+// RegisterCurve makes curve available to LookupCurve, replacing any curve
+// registered under the same name.
+func RegisterCurve(curve Curve) {
+	curvesMu.Lock()
+	defer curvesMu.Unlock()
+	curves[curve.Name()] = curve
+}
+
+// LookupCurve returns the curve registered under name. The empty name is the
+// default Secp256k1Curve, as recorded by transactions without curve metadata.
+func LookupCurve(name string) (Curve, error) {
+	if name == "" {
+		return Secp256k1Curve, nil
+	}
+	curvesMu.RLock()
+	defer curvesMu.RUnlock()
+	curve, ok := curves[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown curve %q", name)
+	}
+	return curve, nil
+}
+
+// Curves returns the names of the registered curves, sorted.
+func Curves() []string {
+	curvesMu.RLock()
+	defer curvesMu.RUnlock()
+	names := make([]string, 0, len(curves))
+	for name := range curves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithCurve signs every transaction of the account with curve instead of
+// Secp256k1Curve. Only use curves the target network accepts.
+func WithCurve(curve Curve) Option {
+	return func(c *Client) {
+		c.curve = curve
+	}
+}
+
+// signingCurve returns the client's curve, Secp256k1Curve by default.
+func (c *Client) signingCurve() Curve {
+	if c.curve == nil {
+		return Secp256k1Curve
+	}
+	return c.curve
+}
+
+// curveMetadata returns the curve name recorded in transactions signed with
+// curve: empty for the default curve, so such transactions keep their
+// historical encoding.
+func curveMetadata(curve Curve) string {
+	if curve.Name() == Secp256k1Curve.Name() {
+		return ""
+	}
+	return curve.Name()
+}
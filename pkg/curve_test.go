@@ -0,0 +1,101 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCurves(t *testing.T) {
+	digest := sha256.Sum256([]byte("data"))
+	other := sha256.Sum256([]byte("other"))
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i + 1)
+	}
+
+	for _, curve := range []Curve{Secp256k1Curve, Ed25519Curve} {
+		t.Run(curve.Name(), func(t *testing.T) {
+			publicKey, err := curve.PublicKey(key)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			signature, err := curve.Sign(key, digest[:])
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if err := curve.Verify(publicKey, digest[:], signature); err != nil {
+				t.Errorf("Expected the signature to verify, but got: %v", err)
+			}
+			if err := curve.Verify(publicKey, other[:], signature); err == nil {
+				t.Error("Expected a signature of other data to be rejected")
+			}
+			if err := curve.Verify([]byte{1, 2, 3}, digest[:], signature); err == nil {
+				t.Error("Expected an invalid public key to be rejected")
+			}
+			if found, err := LookupCurve(curve.Name()); err != nil || found != curve {
+				t.Errorf("Expected the curve to be registered, but got %v (%v)", found, err)
+			}
+		})
+	}
+
+	if _, err := Ed25519Curve.Sign(key[:5], digest[:]); err == nil {
+		t.Error("Expected a short ed25519 key to be rejected")
+	}
+	if curve, err := LookupCurve(""); err != nil || curve != Secp256k1Curve {
+		t.Errorf("Expected the empty name to be secp256k1, but got %v (%v)", curve, err)
+	}
+	if _, err := LookupCurve("p256"); err == nil {
+		t.Error("Expected an unknown curve to be rejected")
+	}
+	if names := strings.Join(Curves(), ","); names != "ed25519,secp256k1" {
+		t.Errorf("Expected the built-in curves, but got %s", names)
+	}
+}
+
+func TestWithCurve(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = nil
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	privateKey := strings.Repeat("1", 64)
+	testCases := []struct {
+		name          string
+		opts          []Option
+		curve         Curve
+		expectedCurve interface{}
+	}{
+		{name: "Default", curve: Secp256k1Curve, expectedCurve: nil},
+		{name: "Ed25519", opts: []Option{WithCurve(Ed25519Curve)}, curve: Ed25519Curve, expectedCurve: "ed25519"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, tc.opts...)
+			acc.Open("0xabc")
+			if _, err := acc.SubmitCertificateContext(context.Background(), "hello", privateKey); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if request["Curve"] != tc.expectedCurve {
+				t.Errorf("Expected curve metadata %v, but got %v", tc.expectedCurve, request["Curve"])
+			}
+
+			keyBytes, _ := hex.DecodeString(privateKey)
+			publicKey, _ := tc.curve.PublicKey(keyBytes)
+			signature, _ := hex.DecodeString(request["Signature"].(string))
+			id, _ := hex.DecodeString(request["ID"].(string))
+			if err := tc.curve.Verify(publicKey, id, signature); err != nil {
+				t.Errorf("Expected the signature to verify with %s, but got: %v", tc.curve.Name(), err)
+			}
+		})
+	}
+}
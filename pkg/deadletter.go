@@ -0,0 +1,185 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrDeadLetterNotFound is returned by a DeadLetterStore for an unknown ID.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter is a certificate whose submission failed for good: the NAG
+// rejected it, or it failed after the client's retries were exhausted. It
+// holds the certificate data so it can be corrected and retried, but never
+// the private key.
+type DeadLetter struct {
+	ID   string `json:"id"`
+	Data string `json:"data"`
+	// TxID is the ID of the last signed transaction, if signing was reached.
+	TxID          string `json:"txId,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Result is the NAG result code of a rejection, or zero.
+	Result int    `json:"result,omitempty"`
+	Error  string `json:"error"`
+	// Retryable reports whether the last error was transient, so retrying
+	// the certificate unchanged may succeed.
+	Retryable   bool      `json:"retryable"`
+	Attempts    int       `json:"attempts"`
+	FirstFailed time.Time `json:"firstFailed"`
+	LastFailed  time.Time `json:"lastFailed"`
+}
+
+// record updates the letter with a failed attempt.
+func (d *DeadLetter) record(txID string, err error, now time.Time) {
+	d.TxID = txID
+	d.Error = err.Error()
+	d.Retryable = IsRetryable(err)
+	d.Result = 0
+	var resultErr *ResultError
+	if errors.As(err, &resultErr) {
+		d.Result = resultErr.Result
+	}
+	var correlated *CorrelatedError
+	if errors.As(err, &correlated) {
+		d.CorrelationID = correlated.CorrelationID
+	}
+	d.Attempts++
+	if d.FirstFailed.IsZero() {
+		d.FirstFailed = now
+	}
+	d.LastFailed = now
+}
+
+// DeadLetterStore keeps dead letters. Implementations must be safe for
+// concurrent use; applications needing durability can back one with a
+// database.
+type DeadLetterStore interface {
+	// Put stores letter, replacing any letter with the same ID.
+	Put(ctx context.Context, letter DeadLetter) error
+	Get(ctx context.Context, id string) (DeadLetter, error)
+	// List returns every letter, oldest first.
+	List(ctx context.Context) ([]DeadLetter, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// WithDeadLetterStore sends certificates that SubmitBatch fails to submit to
+// store instead of only reporting them in the results.
+func WithDeadLetterStore(store DeadLetterStore) Option {
+	return func(c *Client) {
+		c.deadLetters = store
+	}
+}
+
+// deadLetter stores a failed batch certificate and returns its ID.
+func (c *Client) deadLetter(ctx context.Context, data, txID string, err error) string {
+	letter := DeadLetter{ID: NewCorrelationID(), Data: data}
+	letter.record(txID, err, time.Now())
+	if putErr := c.deadLetters.Put(ctx, letter); putErr != nil {
+		c.logf(ctx, "failed to store dead letter for %s: %v", txID, putErr)
+		return ""
+	}
+	return letter.ID
+}
+
+// RetryDeadLetter submits the certificate of the dead letter id again, with
+// its current, possibly edited, data. On success the letter is deleted;
+// otherwise it is updated with the new error and attempt count. A Result
+// other than 200 counts as a failure and is returned as a *ResultError.
+func (a *CEPAccount) RetryDeadLetter(ctx context.Context, id, privateKey string, opts ...SubmitOption) (response map[string]interface{}, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if a.deadLetters == nil {
+		return nil, fmt.Errorf("no dead letter store configured")
+	}
+	letter, err := a.deadLetters.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var txID string
+	response, err = a.SubmitCertificateContext(ctx, letter.Data, privateKey, append(opts[:len(opts):len(opts)], CaptureTxID(&txID))...)
+	if err == nil {
+		err = a.resultError("AddTransaction", response)
+		annotateError(&err, correlationID)
+	}
+	if err == nil {
+		if deleteErr := a.deadLetters.Delete(ctx, id); deleteErr != nil {
+			return response, fmt.Errorf("certificate submitted but dead letter %s was not deleted: %w", id, deleteErr)
+		}
+		return response, nil
+	}
+
+	letter.record(txID, err, time.Now())
+	if putErr := a.deadLetters.Put(ctx, letter); putErr != nil {
+		a.logf(ctx, "failed to update dead letter %s: %v", id, putErr)
+	}
+	return response, err
+}
+
+// MemoryDeadLetterStore is an in-memory DeadLetterStore.
+type MemoryDeadLetterStore struct {
+	mu      sync.Mutex
+	letters map[string]DeadLetter
+}
+
+// NewMemoryDeadLetterStore returns an empty store.
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{letters: make(map[string]DeadLetter)}
+}
+
+// Put implements DeadLetterStore.
+func (s *MemoryDeadLetterStore) Put(ctx context.Context, letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters[letter.ID] = letter
+	return nil
+}
+
+// Get implements DeadLetterStore.
+func (s *MemoryDeadLetterStore) Get(ctx context.Context, id string) (DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letter, ok := s.letters[id]
+	if !ok {
+		return DeadLetter{}, fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+	return letter, nil
+}
+
+// List implements DeadLetterStore.
+func (s *MemoryDeadLetterStore) List(ctx context.Context) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letters := make([]DeadLetter, 0, len(s.letters))
+	for _, letter := range s.letters {
+		letters = append(letters, letter)
+	}
+	sortDeadLetters(letters)
+	return letters, nil
+}
+
+// sortDeadLetters sorts letters oldest first.
+func sortDeadLetters(letters []DeadLetter) {
+	sort.Slice(letters, func(i, j int) bool {
+		if !letters[i].FirstFailed.Equal(letters[j].FirstFailed) {
+			return letters[i].FirstFailed.Before(letters[j].FirstFailed)
+		}
+		return letters[i].ID < letters[j].ID
+	})
+}
+
+// Delete implements DeadLetterStore.
+func (s *MemoryDeadLetterStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.letters[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+	delete(s.letters, id)
+	return nil
+}
@@ -0,0 +1,93 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeadLetters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := decodePayload(body["Payload"].(string))
+		w.WriteHeader(http.StatusOK)
+		if strings.HasPrefix(data, "bad") {
+			w.Write([]byte(`{"Result":108,"Response":"Invalid Payload"}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"ok"}}`))
+	}))
+	defer server.Close()
+
+	store := NewMemoryDeadLetterStore()
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithDeadLetterStore(store))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+
+	results, err := acc.SubmitBatch(context.Background(), []string{"good", "bad one", "bad two"}, privateKey)
+	if err == nil {
+		t.Fatal("Expected a batch error")
+	}
+	if results[0].DeadLetterID != "" {
+		t.Errorf("Expected no dead letter for an accepted certificate, but got %q", results[0].DeadLetterID)
+	}
+
+	letters, _ := store.List(context.Background())
+	if len(letters) != 2 {
+		t.Fatalf("Expected 2 dead letters, but got %d", len(letters))
+	}
+	letter, err := store.Get(context.Background(), results[1].DeadLetterID)
+	if err != nil {
+		t.Fatalf("Expected the dead letter to be stored, but got: %v", err)
+	}
+	if letter.Data != "bad one" || letter.TxID != results[1].TxID || letter.Result != 108 || letter.Attempts != 1 || letter.Retryable {
+		t.Errorf("Expected the dead letter to hold the failure context, but got %+v", letter)
+	}
+	if letter.CorrelationID == "" || letter.Error == "" || letter.FirstFailed.IsZero() {
+		t.Errorf("Expected the error, correlation ID and time, but got %+v", letter)
+	}
+
+	t.Run("Retry Failure", func(t *testing.T) {
+		_, err := acc.RetryDeadLetter(context.Background(), letter.ID, privateKey)
+		var resultErr *ResultError
+		if !errors.As(err, &resultErr) {
+			t.Fatalf("Expected a *ResultError, but got %v", err)
+		}
+		updated, _ := store.Get(context.Background(), letter.ID)
+		if updated.Attempts != 2 || updated.CorrelationID == letter.CorrelationID || !updated.FirstFailed.Equal(letter.FirstFailed) {
+			t.Errorf("Expected the dead letter to record the new attempt, but got %+v", updated)
+		}
+	})
+
+	t.Run("Edit And Retry", func(t *testing.T) {
+		letter.Data = "fixed"
+		store.Put(context.Background(), letter)
+		response, err := acc.RetryDeadLetter(context.Background(), letter.ID, privateKey)
+		if err != nil || response["Result"] != float64(200) {
+			t.Fatalf("Expected the edited certificate to be accepted, but got %v, %v", response, err)
+		}
+		if _, err := store.Get(context.Background(), letter.ID); !errors.Is(err, ErrDeadLetterNotFound) {
+			t.Errorf("Expected the dead letter to be deleted, but got %v", err)
+		}
+	})
+
+	t.Run("Unknown ID", func(t *testing.T) {
+		if _, err := acc.RetryDeadLetter(context.Background(), "missing", privateKey); !errors.Is(err, ErrDeadLetterNotFound) {
+			t.Errorf("Expected ErrDeadLetterNotFound, but got %v", err)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		acc.SubmitBatch(ctx, []string{"good"}, privateKey)
+		if letters, _ := store.List(context.Background()); len(letters) != 1 {
+			t.Errorf("Expected cancelled certificates not to be dead-lettered, but got %d letters", len(letters))
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSignedTransaction is matched by errors.Is when a
+// SignedTransaction is incomplete or its ID does not match its contents.
+var ErrInvalidSignedTransaction = errors.New("invalid signed transaction")
+
+// SignedTransaction is a transaction signed by SignCertificate or a
+// TransactionBuilder and ready to be broadcast by Broadcast. It marshals to
+// the JSON body posted to the NAG, so it can be handed from a signing enclave
+// to an internet-facing service that holds no key. Curve names the curve of
+// the signature, and is empty for secp256k1. Nonce and Type are empty for
+// certificates signed by SignCertificate, and To is empty unless
+// WithRecipient was used.
+type SignedTransaction struct {
+	Address    string `json:"Address"`
+	Blockchain string `json:"Blockchain"`
+	Curve      string `json:"Curve,omitempty"`
+	ID         string `json:"ID"`
+	Nonce      string `json:"Nonce,omitempty"`
+	Payload    string `json:"Payload"`
+	Signature  string `json:"Signature"`
+	Timestamp  string `json:"Timestamp"`
+	To         string `json:"To,omitempty"`
+	Type       TxType `json:"Type,omitempty"`
+}
+
+// ParseSignedTransaction decodes and validates a SignedTransaction exported
+// as JSON.
+func ParseSignedTransaction(data []byte) (*SignedTransaction, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var tx SignedTransaction
+	if err := decoder.Decode(&tx); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignedTransaction, err)
+	}
+	if err := tx.Validate(); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// Validate checks that every field of tx is set and that its ID is the hash
+// of its contents, which catches truncated or altered transactions before
+// they are broadcast. The signature itself is verified by the network.
+func (tx *SignedTransaction) Validate() error {
+	for name, value := range map[string]string{
+		"Address":    tx.Address,
+		"Blockchain": tx.Blockchain,
+		"ID":         tx.ID,
+		"Payload":    tx.Payload,
+		"Signature":  tx.Signature,
+		"Timestamp":  tx.Timestamp,
+	} {
+		if value == "" {
+			return fmt.Errorf("%w: %s is empty", ErrInvalidSignedTransaction, name)
+		}
+	}
+
+	var input bytes.Buffer
+	writeTransactionIDInput(&input, tx.Blockchain, tx.Address, tx.To, tx.Payload, tx.Nonce, tx.Timestamp)
+	sum := sha256.Sum256(input.Bytes())
+	if normalizeHex(tx.ID) != hex.EncodeToString(sum[:]) {
+		return fmt.Errorf("%w: ID %s does not match the transaction", ErrInvalidSignedTransaction, tx.ID)
+	}
+	return nil
+}
+
+// SignCertificate validates, encodes and signs pdata exactly as
+// SubmitCertificateContext does, but returns the signed transaction instead
+// of submitting it. No network access is needed unless WithPreflight is
+// used, so it can run where the key is kept; the transaction is then
+// submitted with Broadcast.
+func (a *CEPAccount) SignCertificate(ctx context.Context, pdata, privateKey string, opts ...SubmitOption) (tx *SignedTransaction, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if a.Address == "" {
+		return nil, errors.New("Account is not open")
+	}
+
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+
+	var record AuditRecord
+	request, _, err := a.prepareCertificate(ctx, scratch, pdata, privateKey, newSubmitOptions(opts), &record, false)
+	if err != nil {
+		return nil, err
+	}
+	signed := SignedTransaction(request)
+	return &signed, nil
+}
+
+// Broadcast submits a transaction signed elsewhere with SignCertificate to
+// the client's NAG and returns the NAG's response, as SubmitCertificate
+// does. The transaction is validated first, and is posted unchanged.
+func (c *Client) Broadcast(ctx context.Context, tx *SignedTransaction) (response map[string]interface{}, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if c.NAGURL == "" {
+		return nil, fmt.Errorf("network is not set. Please call SetNetwork() first")
+	}
+	if err := tx.Validate(); err != nil {
+		return nil, err
+	}
+	response, err = c.postTransaction(ctx, c.NAGURL, certificateRequest(*tx))
+	if c.events != nil {
+		c.emitSubmission(ctx, tx.ID, tx.Address, tx.Blockchain, response, err)
+	}
+	return response, err
+}
@@ -0,0 +1,94 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDelegatedSubmission(t *testing.T) {
+	var posted []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	// The signer has no network; the broadcaster has no key.
+	signer := NewCEPAccount("", DefaultChain, LibVersion)
+	signer.Open("0x" + strings.Repeat("a", 64))
+	tx, err := signer.SignCertificate(context.Background(), "hello", strings.Repeat("1", 64))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if tx.Blockchain != DefaultChain || tx.Signature == "" || tx.ID == "" {
+		t.Errorf("Expected a signed transaction, but got %+v", tx)
+	}
+	if data, err := decodePayload(tx.Payload); err != nil || data != "hello" {
+		t.Errorf("Expected the payload to carry the data, but got %q (%v)", data, err)
+	}
+
+	exported, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	imported, err := ParseSignedTransaction(exported)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	broadcaster := NewClient(server.URL, DefaultChain, LibVersion)
+	response, err := broadcaster.Broadcast(context.Background(), imported)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if response["Result"] != float64(200) {
+		t.Errorf("Expected the NAG's response, but got %v", response)
+	}
+	if strings.TrimSpace(string(posted)) != string(exported) {
+		t.Errorf("Expected the transaction to be posted unchanged, but got %s", posted)
+	}
+}
+
+func TestSignedTransactionValidate(t *testing.T) {
+	signer := NewCEPAccount("", DefaultChain, LibVersion)
+	signer.Open("0xabc")
+	valid, err := signer.SignCertificate(context.Background(), "hello", strings.Repeat("1", 64))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(tx *SignedTransaction)
+	}{
+		{"Altered Payload", func(tx *SignedTransaction) { tx.Payload += "00" }},
+		{"Other Chain", func(tx *SignedTransaction) { tx.Blockchain = "0x1" }},
+		{"Missing Signature", func(tx *SignedTransaction) { tx.Signature = "" }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := *valid
+			tc.modify(&tx)
+			if err := tx.Validate(); !errors.Is(err, ErrInvalidSignedTransaction) {
+				t.Errorf("Expected ErrInvalidSignedTransaction, but got %v", err)
+			}
+			if _, err := NewClient("http://localhost:1", DefaultChain, LibVersion).Broadcast(context.Background(), &tx); !errors.Is(err, ErrInvalidSignedTransaction) {
+				t.Errorf("Expected Broadcast to refuse the transaction, but got %v", err)
+			}
+		})
+	}
+
+	if _, err := ParseSignedTransaction([]byte(`{"ID":"x","Extra":1}`)); !errors.Is(err, ErrInvalidSignedTransaction) {
+		t.Errorf("Expected unknown fields to be rejected, but got %v", err)
+	}
+	if _, err := signer.SignCertificate(context.Background(), "hello", strings.Repeat("1", 64), WithPreflight(Preflight{})); err == nil || !strings.Contains(err.Error(), "network is not set") {
+		t.Errorf("Expected a pre-flight check without a network to fail, but got %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/blake3"
+	xsha3 "golang.org/x/crypto/sha3"
+)
+
+// HashAlgorithm names a digest algorithm used to fingerprint data and files
+// before they are anchored in a certificate. It never affects the transaction
+// ID, which the protocol fixes to SHA-256.
+type HashAlgorithm string
+
+// Built-in digest algorithms. Others can be added to a client with
+// WithHashAlgorithm.
+const (
+	SHA256    HashAlgorithm = "sha256"
+	SHA512    HashAlgorithm = "sha512"
+	SHA3_256  HashAlgorithm = "sha3-256"
+	SHA3_512  HashAlgorithm = "sha3-512"
+	KECCAK256 HashAlgorithm = "keccak256"
+	BLAKE3    HashAlgorithm = "blake3"
+)
+
+// DefaultHashAlgorithm is used when no algorithm is specified.
+const DefaultHashAlgorithm = SHA256
+
+// ErrUnknownHashAlgorithm is returned for an algorithm that has not been
+// registered.
+var ErrUnknownHashAlgorithm = errors.New("unknown hash algorithm")
+
+// builtinHashAlgorithms are available to every client and to the
+// package-level digest functions.
+var builtinHashAlgorithms = map[HashAlgorithm]func() hash.Hash{
+	SHA256:   sha256.New,
+	SHA512:   sha512.New,
+	SHA3_256: func() hash.Hash { return sha3.New256() },
+	SHA3_512: func() hash.Hash { return sha3.New512() },
+	// Keccak-256 matches the payload convention of the other Circular SDKs.
+	KECCAK256: xsha3.NewLegacyKeccak256,
+	// BLAKE3 with its default 256-bit output.
+	BLAKE3: blake3.New,
+}
+
+var (
+	hashAlgorithmsMu sync.RWMutex
+	hashAlgorithms   = map[HashAlgorithm]func() hash.Hash{}
+)
+
+// RegisterHashAlgorithm makes a digest algorithm available by name to the
+// package-level DigestData, DigestReader, DigestFile and Digest.Verify,
+// replacing any existing registration.
+//
+// Deprecated: Use WithHashAlgorithm, which scopes the algorithm to a client.
+// Algorithms registered here are not seen by clients.
+func RegisterHashAlgorithm(name HashAlgorithm, newHash func() hash.Hash) {
+	hashAlgorithmsMu.Lock()
+	defer hashAlgorithmsMu.Unlock()
+	hashAlgorithms[name] = newHash
+}
+
+// WithHashAlgorithm makes a digest algorithm available by name to the
+// client's DigestData, DigestReader, DigestFile and VerifyDigest, replacing
+// any built-in algorithm of the same name for this client only.
+func WithHashAlgorithm(name HashAlgorithm, newHash func() hash.Hash) Option {
+	return func(c *Client) {
+		if c.hashAlgorithms == nil {
+			c.hashAlgorithms = make(map[HashAlgorithm]func() hash.Hash)
+		}
+		c.hashAlgorithms[name] = newHash
+	}
+}
+
+// HashAlgorithms returns the names of the algorithms available to the
+// package-level digest functions, sorted.
+func HashAlgorithms() []HashAlgorithm {
+	hashAlgorithmsMu.RLock()
+	defer hashAlgorithmsMu.RUnlock()
+	return hashAlgorithmNames(hashAlgorithms)
+}
+
+// HashAlgorithms returns the names of the algorithms available to the
+// client, sorted.
+func (c *Client) HashAlgorithms() []HashAlgorithm {
+	return hashAlgorithmNames(c.hashAlgorithms)
+}
+
+// hashAlgorithmNames returns the names of the built-in algorithms and of
+// those in registered, sorted.
+func hashAlgorithmNames(registered map[HashAlgorithm]func() hash.Hash) []HashAlgorithm {
+	names := make([]HashAlgorithm, 0, len(builtinHashAlgorithms)+len(registered))
+	for name := range builtinHashAlgorithms {
+		names = append(names, name)
+	}
+	for name := range registered {
+		if builtinHashAlgorithms[name] == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// newHash returns a new hash for the algorithm, or DefaultHashAlgorithm if
+// it is empty, as seen by the package-level digest functions.
+func newHash(algorithm HashAlgorithm) (hash.Hash, error) {
+	hashAlgorithmsMu.RLock()
+	defer hashAlgorithmsMu.RUnlock()
+	return lookupHash(hashAlgorithms, algorithm)
+}
+
+// newHash is like the package-level newHash but sees the client's
+// algorithms.
+func (c *Client) newHash(algorithm HashAlgorithm) (hash.Hash, error) {
+	return lookupHash(c.hashAlgorithms, algorithm)
+}
+
+// lookupHash returns a new hash for the algorithm, or DefaultHashAlgorithm if
+// it is empty, from registered or else the built-in algorithms.
+func lookupHash(registered map[HashAlgorithm]func() hash.Hash, algorithm HashAlgorithm) (hash.Hash, error) {
+	if algorithm == "" {
+		algorithm = DefaultHashAlgorithm
+	}
+	newFunc, ok := registered[algorithm]
+	if !ok {
+		newFunc, ok = builtinHashAlgorithms[algorithm]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownHashAlgorithm, algorithm)
+	}
+	return newFunc(), nil
+}
+
+// Digest is a fingerprint of data together with the algorithm that produced
+// it, so a verifier knows how to recompute it.
+type Digest struct {
+	Algorithm HashAlgorithm `json:"alg"`
+	// Value is the hex-encoded digest.
+	Value string `json:"digest"`
+}
+
+// String returns the digest as "algorithm:hex".
+func (d Digest) String() string {
+	return string(d.Algorithm) + ":" + d.Value
+}
+
+// ParseDigest parses the "algorithm:hex" form produced by Digest.String.
+func ParseDigest(s string) (Digest, error) {
+	algorithm, value, ok := strings.Cut(s, ":")
+	if !ok || algorithm == "" {
+		return Digest{}, fmt.Errorf("invalid digest %q: expected algorithm:hex", s)
+	}
+	if _, err := hex.DecodeString(value); err != nil || value == "" {
+		return Digest{}, fmt.Errorf("invalid digest %q: value is not hex", s)
+	}
+	return Digest{Algorithm: HashAlgorithm(algorithm), Value: value}, nil
+}
+
+// DigestData computes the digest of data with the given algorithm.
+func DigestData(algorithm HashAlgorithm, data []byte) (Digest, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+	h.Write(data)
+	return digestOf(algorithm, h), nil
+}
+
+// DigestData is like the package-level DigestData but can use the
+// algorithms added to the client with WithHashAlgorithm.
+func (c *Client) DigestData(algorithm HashAlgorithm, data []byte) (Digest, error) {
+	h, err := c.newHash(algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+	h.Write(data)
+	return digestOf(algorithm, h), nil
+}
+
+// DigestReader computes the digest of everything read from r.
+func DigestReader(algorithm HashAlgorithm, r io.Reader) (Digest, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+	return digestRead(algorithm, h, r)
+}
+
+// DigestReader is like the package-level DigestReader but can use the
+// algorithms added to the client with WithHashAlgorithm.
+func (c *Client) DigestReader(algorithm HashAlgorithm, r io.Reader) (Digest, error) {
+	h, err := c.newHash(algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+	return digestRead(algorithm, h, r)
+}
+
+// DigestFile computes the digest of the file at path.
+func DigestFile(algorithm HashAlgorithm, path string) (Digest, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+	return digestFile(algorithm, h, path)
+}
+
+// DigestFile is like the package-level DigestFile but can use the
+// algorithms added to the client with WithHashAlgorithm.
+func (c *Client) DigestFile(algorithm HashAlgorithm, path string) (Digest, error) {
+	h, err := c.newHash(algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+	return digestFile(algorithm, h, path)
+}
+
+// digestRead feeds everything read from r to h.
+func digestRead(algorithm HashAlgorithm, h hash.Hash, r io.Reader) (Digest, error) {
+	if _, err := io.Copy(h, r); err != nil {
+		return Digest{}, fmt.Errorf("failed to read data to digest: %w", err)
+	}
+	return digestOf(algorithm, h), nil
+}
+
+// digestFile feeds the file at path to h.
+func digestFile(algorithm HashAlgorithm, h hash.Hash, path string) (Digest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Digest{}, fmt.Errorf("failed to open file to digest: %w", err)
+	}
+	defer f.Close()
+	return digestRead(algorithm, h, f)
+}
+
+// digestOf builds the Digest for a finished hash.
+func digestOf(algorithm HashAlgorithm, h hash.Hash) Digest {
+	if algorithm == "" {
+		algorithm = DefaultHashAlgorithm
+	}
+	return Digest{Algorithm: algorithm, Value: hex.EncodeToString(h.Sum(nil))}
+}
+
+// Verify reports whether data has this digest.
+func (d Digest) Verify(data []byte) (bool, error) {
+	computed, err := DigestData(d.Algorithm, data)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(computed.Value, d.Value), nil
+}
+
+// VerifyDigest is like Digest.Verify but can use the algorithms added to the
+// client with WithHashAlgorithm.
+func (c *Client) VerifyDigest(d Digest, data []byte) (bool, error) {
+	computed, err := c.DigestData(d.Algorithm, data)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(computed.Value, d.Value), nil
+}
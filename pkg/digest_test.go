@@ -0,0 +1,143 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/md5"
+	"errors"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDigestData(t *testing.T) {
+	testCases := []struct {
+		name      string
+		algorithm HashAlgorithm
+		expected  string
+	}{
+		{"Default", "", "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"SHA-256", SHA256, "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"SHA-512", SHA512, "sha512:ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+		{"SHA3-256", SHA3_256, "sha3-256:3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"},
+		{"Keccak-256", KECCAK256, "keccak256:4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"BLAKE3", BLAKE3, "blake3:6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := DigestData(tc.algorithm, []byte("abc"))
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if d.String() != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, d.String())
+			}
+			if ok, err := d.Verify([]byte("abc")); !ok || err != nil {
+				t.Errorf("Expected digest to verify, got %v, %v", ok, err)
+			}
+		})
+	}
+}
+
+func TestDigestUnknownAlgorithm(t *testing.T) {
+	if _, err := DigestData("md4", []byte("abc")); !errors.Is(err, ErrUnknownHashAlgorithm) {
+		t.Errorf("Expected ErrUnknownHashAlgorithm for unregistered md4, but got %v", err)
+	}
+}
+
+func TestWithHashAlgorithm(t *testing.T) {
+	client := NewClient(DefaultNAG, DefaultChain, LibVersion, WithHashAlgorithm("md5-client", func() hash.Hash { return md5.New() }))
+	d, err := client.DigestData("md5-client", []byte("abc"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if d.Value != "900150983cd24fb0d6963f7d28e17f72" {
+		t.Errorf("Expected the client's algorithm to be used, but got %s", d.Value)
+	}
+	if ok, err := client.VerifyDigest(d, []byte("abc")); !ok || err != nil {
+		t.Errorf("Expected digest to verify, got %v, %v", ok, err)
+	}
+	if builtin, err := client.DigestData(SHA256, []byte("abc")); err != nil || builtin.Value != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("Expected the built-in algorithms to remain available, but got %v, %v", builtin, err)
+	}
+
+	// The algorithm is scoped to the client that added it.
+	other := NewClient(DefaultNAG, DefaultChain, LibVersion)
+	if _, err := other.DigestData("md5-client", []byte("abc")); !errors.Is(err, ErrUnknownHashAlgorithm) {
+		t.Errorf("Expected ErrUnknownHashAlgorithm from another client, but got %v", err)
+	}
+	if _, err := DigestData("md5-client", []byte("abc")); !errors.Is(err, ErrUnknownHashAlgorithm) {
+		t.Errorf("Expected ErrUnknownHashAlgorithm from DigestData, but got %v", err)
+	}
+	found := false
+	for _, name := range client.HashAlgorithms() {
+		found = found || name == "md5-client"
+	}
+	if !found {
+		t.Error("Expected the client's algorithm to be listed")
+	}
+}
+
+func TestRegisterHashAlgorithm(t *testing.T) {
+	RegisterHashAlgorithm("md5-test", func() hash.Hash { return md5.New() })
+	d, err := DigestData("md5-test", []byte("abc"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if d.Value != "900150983cd24fb0d6963f7d28e17f72" {
+		t.Errorf("Expected registered algorithm to be used, but got %s", d.Value)
+	}
+	found := false
+	for _, name := range HashAlgorithms() {
+		found = found || name == "md5-test"
+	}
+	if !found {
+		t.Error("Expected registered algorithm to be listed")
+	}
+}
+
+func TestDigestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(path, []byte("abc"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	fromFile, err := DigestFile(SHA512, path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	fromReader, _ := DigestReader(SHA512, strings.NewReader("abc"))
+	if fromFile != fromReader {
+		t.Errorf("Expected file and reader digests to match, got %v and %v", fromFile, fromReader)
+	}
+}
+
+func TestCertificateDigest(t *testing.T) {
+	d, _ := DigestData(SHA512, []byte("contract.pdf contents"))
+	cert := NewCertificate(LibVersion)
+	cert.SetDigest(d)
+
+	if cert.DigestAlgorithm != SHA512 {
+		t.Errorf("Expected DigestAlgorithm %s, but got %s", SHA512, cert.DigestAlgorithm)
+	}
+	got, err := cert.GetDigest()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if got != d {
+		t.Errorf("Expected %v, but got %v", d, got)
+	}
+
+	jsonCert, _ := cert.GetJSONCertificate()
+	if !strings.Contains(jsonCert, `"digestAlgorithm":"sha512"`) {
+		t.Errorf("Expected algorithm in certificate JSON, but got %s", jsonCert)
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	for _, input := range []string{"", "sha256", ":abcd", "sha256:xyz", "sha256:"} {
+		if _, err := ParseDigest(input); err == nil {
+			t.Errorf("Expected an error for %q but got nil", input)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithDNSCache caches the addresses the client resolves for a host for ttl,
+// so bursts of requests do not each wait on DNS. When a host has several
+// addresses they are tried in turn until one accepts the connection, and a
+// host whose addresses all fail is resolved again on the next dial.
+//
+// Like the other transport options it gives the client its own transport. It
+// wraps the dialer in place when the option is applied, so pass
+// WithDialContext before it to combine the two.
+func WithDNSCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.hostResolver().ttl = ttl
+	}
+}
+
+// WithPinnedIPs connects to host at the given IP addresses instead of
+// resolving it, trying them in order until one accepts the connection. TLS is
+// still verified against host. Ports come from the request as usual. See
+// WithDNSCache for how it combines with WithDialContext.
+func WithPinnedIPs(host string, ips ...string) Option {
+	return func(c *Client) {
+		r := c.hostResolver()
+		r.pins[host] = append([]string(nil), ips...)
+	}
+}
+
+// hostResolver resolves the hosts the client dials, from its pins or from DNS
+// through its cache.
+type hostResolver struct {
+	ttl  time.Duration
+	pins map[string][]string
+	// lookup resolves a host; it is net.DefaultResolver.LookupHost outside
+	// of tests.
+	lookup func(ctx context.Context, host string) ([]string, error)
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]resolvedHost
+}
+
+// resolvedHost is a cached resolution.
+type resolvedHost struct {
+	addrs   []string
+	expires time.Time
+}
+
+// hostResolver returns the client's resolver, creating it and installing its
+// dialer in the client's transport on first use.
+func (c *Client) hostResolver() *hostResolver {
+	if c.resolver == nil {
+		r := &hostResolver{
+			pins:   make(map[string][]string),
+			lookup: net.DefaultResolver.LookupHost,
+			now:    time.Now,
+			cache:  make(map[string]resolvedHost),
+		}
+		c.resolver = r
+		c.transportOptions = append(c.transportOptions, func(t *http.Transport) {
+			t.DialContext = r.dialer(t.DialContext)
+		})
+	}
+	return c.resolver
+}
+
+// dialer returns a DialContext function that resolves hosts with r and dials
+// each address with next until one succeeds.
+func (r *hostResolver) dialer(next func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if next == nil {
+		next = (&net.Dialer{Timeout: DefaultDialTimeout, KeepAlive: DefaultKeepAlive}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return next(ctx, network, addr)
+		}
+		addrs, pinned, err := r.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var errs []error
+		for _, ip := range addrs {
+			conn, err := next(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if !pinned {
+			r.forget(host)
+		}
+		return nil, fmt.Errorf("failed to connect to %s at any of %v: %w", host, addrs, errors.Join(errs...))
+	}
+}
+
+// resolve returns the addresses of host and whether they are pinned.
+func (r *hostResolver) resolve(ctx context.Context, host string) ([]string, bool, error) {
+	if pins, ok := r.pins[host]; ok {
+		return pins, true, nil
+	}
+	now := r.now()
+	if r.ttl > 0 {
+		r.mu.Lock()
+		cached, ok := r.cache[host]
+		r.mu.Unlock()
+		if ok && now.Before(cached.expires) {
+			return cached.addrs, false, nil
+		}
+	}
+	addrs, err := r.lookup(ctx, host)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(addrs) == 0 {
+		return nil, false, fmt.Errorf("no addresses found for %s", host)
+	}
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.cache[host] = resolvedHost{addrs: addrs, expires: now.Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return addrs, false, nil
+}
+
+// forget drops the cached resolution of host.
+func (r *hostResolver) forget(host string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cache, host)
+}
@@ -0,0 +1,86 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHostResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Close every connection so that each request dials.
+		w.Header().Set("Connection", "close")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	_, port, _ := net.SplitHostPort(serverURL.Host)
+	nagURL := "http://nag.invalid:" + port + "/"
+
+	get := func(client *http.Client) error {
+		resp, err := client.Get(nagURL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	t.Run("Pinned IPs", func(t *testing.T) {
+		// Nothing listens on 127.0.0.2, so the dial fails over to 127.0.0.1.
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithPinnedIPs("nag.invalid", "127.0.0.2", "127.0.0.1"))
+		c.resolver.lookup = func(context.Context, string) ([]string, error) {
+			t.Error("Expected a pinned host not to be resolved")
+			return nil, nil
+		}
+		if err := get(c.HTTPClient); err != nil {
+			t.Errorf("Expected no error, but got: %v", err)
+		}
+
+		c = NewClient(server.URL, DefaultChain, LibVersion, WithPinnedIPs("nag.invalid", "127.0.0.2"))
+		if err := get(c.HTTPClient); err == nil {
+			t.Error("Expected an error when no pinned IP accepts the connection, but got nil")
+		}
+	})
+
+	t.Run("DNS Cache", func(t *testing.T) {
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithDNSCache(time.Minute))
+		var lookups atomic.Int32
+		addrs := []string{"127.0.0.1"}
+		c.resolver.lookup = func(_ context.Context, host string) ([]string, error) {
+			lookups.Add(1)
+			return addrs, nil
+		}
+		now := time.Now()
+		c.resolver.now = func() time.Time { return now }
+
+		get(c.HTTPClient)
+		get(c.HTTPClient)
+		if lookups.Load() != 1 {
+			t.Errorf("Expected 1 lookup within the TTL, but got %d", lookups.Load())
+		}
+		now = now.Add(2 * time.Minute)
+		get(c.HTTPClient)
+		if lookups.Load() != 2 {
+			t.Errorf("Expected a lookup after the TTL, but got %d", lookups.Load())
+		}
+
+		addrs = []string{"127.0.0.2"}
+		c.resolver.forget("nag.invalid")
+		if err := get(c.HTTPClient); err == nil {
+			t.Error("Expected an error when no address accepts the connection, but got nil")
+		}
+		addrs = []string{"127.0.0.1"}
+		if err := get(c.HTTPClient); err != nil {
+			t.Errorf("Expected a failed host to be resolved again, but got: %v", err)
+		}
+		if lookups.Load() != 4 {
+			t.Errorf("Expected 4 lookups, but got %d", lookups.Load())
+		}
+	})
+}
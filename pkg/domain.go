@@ -0,0 +1,69 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDomainNotFound is returned by ResolveDomain for a domain that is not
+// registered on the blockchain.
+var ErrDomainNotFound = errors.New("domain not found")
+
+// ResolveDomain returns the wallet address a Circular domain name, such as
+// "acme.circular", is registered to on the client's blockchain. An
+// unregistered domain fails with ErrDomainNotFound; any other Result than
+// 200 with a *ResultError.
+func (c *Client) ResolveDomain(ctx context.Context, domain string) (address string, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	return c.resolveDomain(ctx, c.Blockchain, c.NAGURL, domain)
+}
+
+// resolveDomain resolves domain on the given blockchain through nagURL.
+func (c *Client) resolveDomain(ctx context.Context, blockchain, nagURL, domain string) (string, error) {
+	if domain == "" {
+		return "", errors.New("domain is empty")
+	}
+	data, err := callAt[map[string]interface{}](ctx, c, nagURL, "GetDomain", map[string]interface{}{
+		"Blockchain": blockchain,
+		"Domain":     domain,
+		"Version":    c.CodeVersion,
+	})
+	var resultErr *ResultError
+	if errors.As(err, &resultErr) && strings.Contains(strings.ToLower(resultErr.Message), "not found") {
+		return "", fmt.Errorf("%w: %s", ErrDomainNotFound, domain)
+	}
+	if err != nil {
+		return "", err
+	}
+	var address string
+	switch response := data["Response"].(type) {
+	case string:
+		address = response
+	case map[string]interface{}:
+		address = firstField(response, "Address", "Owner", "Wallet")
+	}
+	if !isHex(address) {
+		return "", fmt.Errorf("%w: %s resolved to %q", ErrDomainNotFound, domain, address)
+	}
+	return address, nil
+}
+
+// resolveRecipient returns recipient unchanged if it is an address, or the
+// address its domain resolves to otherwise.
+func (c *Client) resolveRecipient(ctx context.Context, blockchain, nagURL, recipient string) (string, error) {
+	if isHex(recipient) {
+		return recipient, nil
+	}
+	if nagURL == "" {
+		return "", fmt.Errorf("network is not set. Please call SetNetwork() first")
+	}
+	address, err := c.resolveDomain(ctx, blockchain, nagURL, recipient)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve recipient %s: %w", recipient, err)
+	}
+	return address, nil
+}
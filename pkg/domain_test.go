@@ -0,0 +1,99 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecipient(t *testing.T) {
+	recipient := "0x" + strings.Repeat("b", 64)
+	var submitted map[string]interface{}
+	var lookups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		if strings.Contains(r.URL.Path, "GetDomain") {
+			domain, _ := request["Domain"].(string)
+			lookups = append(lookups, domain)
+			switch domain {
+			case "acme.circular":
+				w.Write([]byte(`{"Result":200,"Response":{"Domain":"acme.circular","Address":"` + recipient + `"}}`))
+			case "bare.circular":
+				w.Write([]byte(`{"Result":200,"Response":"` + recipient + `"}`))
+			case "broken.circular":
+				w.Write([]byte(`{"Result":500,"Response":"Internal error"}`))
+			default:
+				w.Write([]byte(`{"Result":119,"Response":"Domain Not Found"}`))
+			}
+			return
+		}
+		submitted = request
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+	ctx := context.Background()
+
+	t.Run("Resolve", func(t *testing.T) {
+		testCases := []struct {
+			domain      string
+			expectedErr error
+		}{
+			{"acme.circular", nil},
+			{"bare.circular", nil},
+			{"missing.circular", ErrDomainNotFound},
+		}
+		for _, tc := range testCases {
+			address, err := acc.ResolveDomain(ctx, tc.domain)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("%s: expected error %v, but got %v", tc.domain, tc.expectedErr, err)
+			}
+			if tc.expectedErr == nil && address != recipient {
+				t.Errorf("%s: expected %s, but got %s", tc.domain, recipient, address)
+			}
+		}
+		var resultErr *ResultError
+		if _, err := acc.ResolveDomain(ctx, "broken.circular"); !errors.As(err, &resultErr) {
+			t.Errorf("Expected a *ResultError, but got %v", err)
+		}
+	})
+
+	for _, to := range []string{recipient, "acme.circular"} {
+		t.Run("Submit "+to, func(t *testing.T) {
+			lookups = nil
+			var txID string
+			if _, err := acc.SubmitCertificate("data", privateKey, WithRecipient(to), CaptureTxID(&txID)); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if submitted["To"] != recipient {
+				t.Errorf("Expected the recipient to be sent, but got %v", submitted["To"])
+			}
+			payload, _ := submitted["Payload"].(string)
+			timestamp, _ := submitted["Timestamp"].(string)
+			if expected := ComputeTransactionID(DefaultChain, acc.Address, recipient, payload, "", timestamp); txID != expected {
+				t.Errorf("Expected the recipient to be part of the ID %s, but got %s", expected, txID)
+			}
+			if (to == recipient) != (len(lookups) == 0) {
+				t.Errorf("Expected only domains to be resolved, but got lookups %v", lookups)
+			}
+		})
+	}
+
+	t.Run("Unresolved", func(t *testing.T) {
+		submitted = nil
+		if _, err := acc.SubmitCertificate("data", privateKey, WithRecipient("missing.circular")); !errors.Is(err, ErrDomainNotFound) {
+			t.Errorf("Expected ErrDomainNotFound, but got %v", err)
+		}
+		if submitted != nil {
+			t.Error("Expected nothing to be submitted")
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// DryRunNodeID is the NodeID reported in the outcomes of dry-run
+// transactions.
+const DryRunNodeID = "dry-run"
+
+// DryRunConfig shapes the fake network of WithDryRun.
+type DryRunConfig struct {
+	// Latency delays every dry-run submission, to approximate the time a NAG
+	// takes to answer.
+	Latency time.Duration
+	// ConfirmAfter is how long a dry-run transaction is reported as pending
+	// before it is reported as executed. Zero confirms it at once.
+	ConfirmAfter time.Duration
+}
+
+// WithDryRun builds, signs, validates and records submissions as usual but
+// never broadcasts them, for staging and load tests that must not touch a
+// real network. Every signed transaction is checked with
+// SignedTransaction.Validate and accepted with a Result of 200 unless it is
+// invalid; audit sinks, the ledger, receipts, events and usage see it like a
+// real submission. Lookups of dry-run transactions, and so GetOutcome and
+// WaitForOutcome, are answered locally with a fake outcome, as shaped by
+// config. Every other request, such as nonce and block lookups, still goes
+// to the NAG.
+func WithDryRun(config DryRunConfig) Option {
+	return func(c *Client) {
+		c.dryRun = &dryRun{config: config, transactions: make(map[string]*dryRunTransaction)}
+	}
+}
+
+// DryRun reports whether the client is in dry-run mode; see WithDryRun.
+func (c *Client) DryRun() bool {
+	return c.dryRun != nil
+}
+
+// DryRunTransactions returns the transactions accepted in dry-run mode,
+// oldest first.
+func (c *Client) DryRunTransactions() []SignedTransaction {
+	if c.dryRun == nil {
+		return nil
+	}
+	return c.dryRun.list()
+}
+
+// dryRun holds the transactions accepted in dry-run mode.
+type dryRun struct {
+	config DryRunConfig
+
+	mu           sync.Mutex
+	transactions map[string]*dryRunTransaction
+	blocks       int
+}
+
+// dryRunTransaction is a transaction accepted in dry-run mode.
+type dryRunTransaction struct {
+	tx        SignedTransaction
+	submitted time.Time
+	block     int
+}
+
+// submit stands in for posting request to a NAG, answering as a NAG would.
+func (d *dryRun) submit(ctx context.Context, request certificateRequest) (map[string]interface{}, error) {
+	if d.config.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to submit certificate: %w", ctx.Err())
+		case <-time.After(d.config.Latency):
+		}
+	}
+	tx := SignedTransaction(request)
+	if err := tx.Validate(); err != nil {
+		return map[string]interface{}{"Result": float64(108), "Response": err.Error()}, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := normalizeHex(tx.ID)
+	if _, ok := d.transactions[id]; ok {
+		return map[string]interface{}{"Result": float64(108), "Response": "Duplicate Transaction"}, nil
+	}
+	d.blocks++
+	d.transactions[id] = &dryRunTransaction{tx: tx, submitted: time.Now(), block: d.blocks}
+	return map[string]interface{}{"Result": float64(200), "Response": map[string]interface{}{"TxID": tx.ID}}, nil
+}
+
+// lookup returns the GetTransactionbyID response for a dry-run transaction,
+// if txID is one.
+func (d *dryRun) lookup(txID string) (map[string]interface{}, bool) {
+	d.mu.Lock()
+	entry, ok := d.transactions[normalizeHex(txID)]
+	d.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	// Round-trip the transaction through JSON so the response holds the
+	// same fields, of the same types, as one decoded from a NAG.
+	var transaction map[string]interface{}
+	data, _ := json.Marshal(entry.tx)
+	json.Unmarshal(data, &transaction)
+	transaction["From"] = entry.tx.Address
+	if time.Since(entry.submitted) < d.config.ConfirmAfter {
+		transaction["Status"] = "Pending"
+	} else {
+		transaction["Status"] = "Executed"
+		transaction["BlockID"] = strconv.Itoa(entry.block)
+		transaction["BlockTimestamp"] = utils.FormatTimestamp(entry.submitted.Add(d.config.ConfirmAfter))
+		transaction["Position"] = float64(0)
+		transaction["NodeID"] = DryRunNodeID
+	}
+	return map[string]interface{}{"Result": float64(200), "Response": transaction}, true
+}
+
+// list returns the accepted transactions in the order they were accepted.
+func (d *dryRun) list() []SignedTransaction {
+	d.mu.Lock()
+	entries := make([]*dryRunTransaction, 0, len(d.transactions))
+	for _, entry := range d.transactions {
+		entries = append(entries, entry)
+	}
+	d.mu.Unlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].block < entries[j].block })
+	transactions := make([]SignedTransaction, len(entries))
+	for i, entry := range entries {
+		transactions[i] = entry.tx
+	}
+	return transactions
+}
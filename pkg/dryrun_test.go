@@ -0,0 +1,100 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDryRun(t *testing.T) {
+	var submissions atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"Payload"`) {
+			submissions.Add(1)
+		}
+		w.Write([]byte(`{"Result":118,"Response":"Transaction Not Found"}`))
+	}))
+	defer server.Close()
+	privateKey := strings.Repeat("1", 64)
+	ctx := context.Background()
+
+	t.Run("Confirmed", func(t *testing.T) {
+		ledger := NewMemoryLedger()
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithDryRun(DryRunConfig{}), WithLedger(ledger))
+		acc.Open("0x" + strings.Repeat("a", 64))
+		if !acc.DryRun() {
+			t.Fatal("Expected the account to be in dry-run mode")
+		}
+
+		response, err := acc.SubmitCertificateContext(ctx, "data", privateKey)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if result, _ := response["Result"].(float64); result != 200 || acc.LatestTxID == "" {
+			t.Fatalf("Expected the submission to be accepted, but got %v", response)
+		}
+
+		outcome, err := acc.WaitForOutcome(ctx, acc.LatestTxID, 5)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if outcome.Status != TxConfirmed || outcome.NodeID != DryRunNodeID || !outcome.complete() {
+			t.Errorf("Expected a complete confirmed outcome, but got %+v", outcome)
+		}
+		if data, err := outcome.CertificateData(); err != nil || data != "data" {
+			t.Errorf("Expected the certificate data to round-trip, but got %q, %v", data, err)
+		}
+
+		transactions := acc.DryRunTransactions()
+		if len(transactions) != 1 || transactions[0].ID != acc.LatestTxID {
+			t.Errorf("Expected the dry-run transaction to be listed, but got %+v", transactions)
+		}
+		if entries, _ := ledger.Query(ctx, LedgerQuery{Status: LedgerConfirmed}); len(entries) != 1 {
+			t.Errorf("Expected the transaction to be confirmed in the ledger, but got %+v", entries)
+		}
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithDryRun(DryRunConfig{ConfirmAfter: time.Hour}))
+		acc.Open("0x" + strings.Repeat("a", 64))
+		tx, err := acc.SignCertificate(ctx, "data", privateKey)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if _, err := acc.Broadcast(ctx, tx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		outcome, err := acc.GetOutcome(ctx, tx.ID)
+		if err != nil || outcome.Status != TxPending {
+			t.Errorf("Expected the transaction to be pending, but got %+v, %v", outcome, err)
+		}
+
+		response, err := acc.Broadcast(ctx, tx)
+		if result, _ := response["Result"].(float64); err != nil || result != 108 {
+			t.Errorf("Expected a duplicate to be rejected, but got %v, %v", response, err)
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithDryRun(DryRunConfig{Latency: time.Hour}))
+		acc.Open("0x" + strings.Repeat("a", 64))
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if _, err := acc.SubmitCertificateContext(timeoutCtx, "data", privateKey); err == nil {
+			t.Error("Expected the submission to wait for the latency, but got nil")
+		}
+	})
+
+	if n := submissions.Load(); n != 0 {
+		t.Errorf("Expected nothing to be broadcast, but %d submissions reached the NAG", n)
+	}
+	if NewClient(server.URL, DefaultChain, LibVersion).DryRun() {
+		t.Error("Expected dry-run mode to be off by default")
+	}
+}
@@ -0,0 +1,139 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// ErrDecryptionFailed is matched by errors.Is when encrypted certificate data
+// is malformed or was not encrypted to the given key.
+var ErrDecryptionFailed = errors.New("failed to decrypt certificate data")
+
+// EncryptedDataPrefix starts certificate data encrypted by
+// EncryptCertificateData.
+const EncryptedDataPrefix = "ecies1:"
+
+// eciesInfo binds the derived keys to this scheme and version.
+const eciesInfo = "circular-certificate-ecies-v1"
+
+// WithEncryption encrypts the certificate data to the secp256k1 public key of
+// its recipient with EncryptCertificateData, so only the holder of the
+// matching private key can read it once retrieved from the chain. Schemas are
+// validated and audit records hashed against the data before encryption.
+// Combine with WithRecipient to record the addressee on-chain as well.
+func WithEncryption(recipientPublicKey string) SubmitOption {
+	return func(o *submitOptions) {
+		o.encryptTo = recipientPublicKey
+	}
+}
+
+// EncryptCertificateData encrypts data to a hex-encoded secp256k1 public key,
+// compressed or not, with an ECIES-style hybrid scheme: an ephemeral key pair
+// is generated, ECDH with the recipient's key and HKDF-SHA256 derive an
+// AES-256-GCM key, and the data is sealed with it. The result is
+// EncryptedDataPrefix followed by the base64 encoding of the ephemeral
+// compressed public key, the GCM nonce and the sealed data.
+func EncryptCertificateData(recipientPublicKey, data string) (string, error) {
+	keyBytes, err := hex.DecodeString(utils.HexFix(recipientPublicKey))
+	if err != nil {
+		return "", fmt.Errorf("invalid recipient public key hex: %w", err)
+	}
+	recipient, err := secp256k1.ParsePubKey(keyBytes)
+	if err != nil {
+		return "", fmt.Errorf("invalid recipient public key: %w", err)
+	}
+	ephemeral, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+	defer ephemeral.Zero()
+	ephemeralPublic := ephemeral.PubKey().SerializeCompressed()
+
+	aead, err := eciesCipher(secp256k1.GenerateSharedSecret(ephemeral, recipient), ephemeralPublic)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	envelope := make([]byte, 0, len(ephemeralPublic)+len(nonce)+len(data)+aead.Overhead())
+	envelope = append(envelope, ephemeralPublic...)
+	envelope = append(envelope, nonce...)
+	envelope = aead.Seal(envelope, nonce, []byte(data), ephemeralPublic)
+	return EncryptedDataPrefix + base64.StdEncoding.EncodeToString(envelope), nil
+}
+
+// DecryptCertificateData decrypts data produced by EncryptCertificateData with
+// the recipient's hex-encoded secp256k1 private key. Data that is not
+// encrypted, or not to this key, fails with ErrDecryptionFailed.
+func DecryptCertificateData(privateKey, data string) (string, error) {
+	encoded, ok := strings.CutPrefix(data, EncryptedDataPrefix)
+	if !ok {
+		return "", fmt.Errorf("%w: data does not start with %q", ErrDecryptionFailed, EncryptedDataPrefix)
+	}
+	envelope, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	keyBytes, err := hex.DecodeString(utils.HexFix(privateKey))
+	if err != nil || len(keyBytes) != secp256k1.PrivKeyBytesLen {
+		return "", errors.New("invalid private key hex string")
+	}
+	key := secp256k1.PrivKeyFromBytes(keyBytes)
+	defer key.Zero()
+
+	if len(envelope) < secp256k1.PubKeyBytesLenCompressed {
+		return "", fmt.Errorf("%w: data is truncated", ErrDecryptionFailed)
+	}
+	ephemeralPublic := envelope[:secp256k1.PubKeyBytesLenCompressed]
+	ephemeral, err := secp256k1.ParsePubKey(ephemeralPublic)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	aead, err := eciesCipher(secp256k1.GenerateSharedSecret(key, ephemeral), ephemeralPublic)
+	if err != nil {
+		return "", err
+	}
+	sealed := envelope[len(ephemeralPublic):]
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return "", fmt.Errorf("%w: data is truncated", ErrDecryptionFailed)
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], ephemeralPublic)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	return string(plaintext), nil
+}
+
+// IsEncryptedCertificateData reports whether data was produced by
+// EncryptCertificateData.
+func IsEncryptedCertificateData(data string) bool {
+	return strings.HasPrefix(data, EncryptedDataPrefix)
+}
+
+// eciesCipher derives the AES-256-GCM cipher of a shared secret. The
+// ephemeral public key salts the derivation.
+func eciesCipher(secret, ephemeralPublic []byte) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, secret, ephemeralPublic, eciesInfo, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,92 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCertificateEncryption(t *testing.T) {
+	recipientKey := strings.Repeat("1", 64)
+	otherKey := strings.Repeat("2", 64)
+
+	encrypted, err := EncryptCertificateData(testPublicKey, "contract #42")
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !IsEncryptedCertificateData(encrypted) || strings.Contains(encrypted, "contract") {
+		t.Fatalf("Expected opaque encrypted data, but got %q", encrypted)
+	}
+	if again, _ := EncryptCertificateData(testPublicKey, "contract #42"); again == encrypted {
+		t.Error("Expected a fresh ephemeral key for every encryption")
+	}
+
+	t.Run("Decrypt", func(t *testing.T) {
+		data, err := DecryptCertificateData("0x"+recipientKey, encrypted)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if data != "contract #42" {
+			t.Errorf("Expected the original data, but got %q", data)
+		}
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		envelope, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, EncryptedDataPrefix))
+		envelope[len(envelope)-1] ^= 1
+		tampered := EncryptedDataPrefix + base64.StdEncoding.EncodeToString(envelope)
+
+		testCases := []struct {
+			name string
+			key  string
+			data string
+		}{
+			{"Wrong Key", otherKey, encrypted},
+			{"Tampered", recipientKey, tampered},
+			{"Truncated", recipientKey, encrypted[:40]},
+			{"Plaintext", recipientKey, "contract #42"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := DecryptCertificateData(tc.key, tc.data); !errors.Is(err, ErrDecryptionFailed) {
+					t.Errorf("Expected ErrDecryptionFailed, but got %v", err)
+				}
+			})
+		}
+		if _, err := EncryptCertificateData("0x04abcd", "data"); err == nil {
+			t.Error("Expected an invalid public key to be rejected")
+		}
+	})
+
+	t.Run("Submit", func(t *testing.T) {
+		var payload string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&request)
+			payload, _ = request["Payload"].(string)
+			w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+		}))
+		defer server.Close()
+
+		sink := &memoryAuditSink{}
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithAuditSink(sink))
+		acc.Open("0x" + strings.Repeat("a", 64))
+		if _, err := acc.SubmitCertificate("contract #42", otherKey, WithEncryption(testPublicKey)); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		onChain, err := decodePayload(payload)
+		if err != nil || !IsEncryptedCertificateData(onChain) {
+			t.Fatalf("Expected encrypted data on chain, but got %q, %v", onChain, err)
+		}
+		if data, err := DecryptCertificateData(recipientKey, onChain); err != nil || data != "contract #42" {
+			t.Errorf("Expected the recipient to decrypt the certificate, but got %q, %v", data, err)
+		}
+		if sink.records[0].PayloadHash != payloadHash("contract #42") {
+			t.Error("Expected the audit record to hash the data before encryption")
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Endpoint declares a NAG method that the library does not wrap, such as an
+// extension of a private gateway, so it can be invoked by name through the
+// client's transport, retries, authentication and parse mode.
+type Endpoint struct {
+	// Name is the NAG method, posted to Circular_<Name>_<NetworkNode>.
+	Name string
+	// Request builds the request body from the client and the parameters
+	// passed to Invoke. When nil, the body is the parameters plus the
+	// client's Blockchain and Version, as sent by the built-in methods.
+	Request func(c *Client, params map[string]interface{}) (interface{}, error)
+	// Response returns a pointer to a new value to decode the response
+	// into, such as &Envelope[MyType]{}. When nil, the response is decoded
+	// into a map[string]interface{}.
+	Response func() interface{}
+}
+
+var (
+	endpointsMu sync.RWMutex
+	endpoints   = map[string]Endpoint{}
+)
+
+// RegisterEndpoint makes endpoint available to Invoke, replacing any
+// endpoint registered under the same name. The name must be non-empty and
+// made of letters, digits and underscores, since it becomes part of the URL.
+func RegisterEndpoint(endpoint Endpoint) error {
+	if endpoint.Name == "" {
+		return fmt.Errorf("endpoint name is empty")
+	}
+	for _, r := range endpoint.Name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return fmt.Errorf("invalid endpoint name %q", endpoint.Name)
+		}
+	}
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+	endpoints[endpoint.Name] = endpoint
+	return nil
+}
+
+// LookupEndpoint returns the endpoint registered under name.
+func LookupEndpoint(name string) (Endpoint, error) {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	endpoint, ok := endpoints[name]
+	if !ok {
+		return Endpoint{}, fmt.Errorf("unknown endpoint %q", name)
+	}
+	return endpoint, nil
+}
+
+// Endpoints returns the names of the registered endpoints, sorted.
+func Endpoints() []string {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Invoke calls the registered endpoint name with params and returns the
+// decoded response: the value returned by the endpoint's Response function,
+// or a map[string]interface{}. As with Call, a Result other than 200 is
+// returned as a *ResultError.
+func (c *Client) Invoke(ctx context.Context, name string, params map[string]interface{}) (interface{}, error) {
+	endpoint, err := LookupEndpoint(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var request interface{}
+	if endpoint.Request != nil {
+		if request, err = endpoint.Request(c, params); err != nil {
+			return nil, fmt.Errorf("failed to build %s request: %w", name, err)
+		}
+	} else {
+		body := make(map[string]interface{}, len(params)+2)
+		body["Blockchain"] = c.Blockchain
+		body["Version"] = c.CodeVersion
+		for key, value := range params {
+			body[key] = value
+		}
+		request = body
+	}
+
+	if endpoint.Response == nil {
+		return Call[map[string]interface{}](ctx, c, name, request)
+	}
+	raw, err := Call[json.RawMessage](ctx, c, name, request)
+	if err != nil {
+		return nil, err
+	}
+	response := endpoint.Response()
+	if err := json.Unmarshal(raw, response); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", name, err)
+	}
+	return response, nil
+}
+
+// InvokeAs is like Invoke but returns the response as T, which must be the
+// type returned by the endpoint's Response function.
+func InvokeAs[T any](ctx context.Context, c *Client, name string, params map[string]interface{}) (T, error) {
+	var zero T
+	response, err := c.Invoke(ctx, name, params)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := response.(T)
+	if !ok {
+		return zero, fmt.Errorf("endpoint %s returns %T, not %T", name, response, zero)
+	}
+	return typed, nil
+}
@@ -0,0 +1,113 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInvoke(t *testing.T) {
+	var path, authorization string
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get(HeaderAuthorization)
+		request = nil
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		if request["Account"] == "unknown" {
+			w.Write([]byte(`{"Result":118,"Response":"Account Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"Tier":"gold","Quota":500}}`))
+	}))
+	defer server.Close()
+
+	type quota struct {
+		Tier  string
+		Quota int
+	}
+	if err := RegisterEndpoint(Endpoint{
+		Name:     "GetQuota",
+		Response: func() interface{} { return &Envelope[quota]{} },
+	}); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	RegisterEndpoint(Endpoint{
+		Name: "GetQuotaRaw",
+		Request: func(c *Client, params map[string]interface{}) (interface{}, error) {
+			if params["Account"] == nil {
+				return nil, errors.New("account is required")
+			}
+			return map[string]interface{}{"Chain": c.Blockchain, "Account": params["Account"]}, nil
+		},
+	})
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithTokenProvider(StaticToken("abc")))
+	ctx := context.Background()
+
+	t.Run("Typed", func(t *testing.T) {
+		got, err := InvokeAs[*Envelope[quota]](ctx, c, "GetQuota", map[string]interface{}{"Account": "acme"})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if got.Response != (quota{Tier: "gold", Quota: 500}) {
+			t.Errorf("Expected the decoded quota, but got %+v", got.Response)
+		}
+		if path != "/Circular_GetQuota_" || authorization != "Bearer abc" {
+			t.Errorf("Expected the shared transport and auth, but got %s %q", path, authorization)
+		}
+		if request["Account"] != "acme" || request["Blockchain"] != DefaultChain || request["Version"] != LibVersion {
+			t.Errorf("Expected the default request body, but got %v", request)
+		}
+	})
+
+	t.Run("Custom Request", func(t *testing.T) {
+		got, err := c.Invoke(ctx, "GetQuotaRaw", map[string]interface{}{"Account": "acme"})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if response, ok := got.(map[string]interface{}); !ok || response["Result"] != float64(200) {
+			t.Errorf("Expected a map response, but got %#v", got)
+		}
+		if request["Chain"] != DefaultChain || request["Blockchain"] != nil {
+			t.Errorf("Expected the custom request body, but got %v", request)
+		}
+		if _, err := c.Invoke(ctx, "GetQuotaRaw", nil); err == nil || !strings.Contains(err.Error(), "account is required") {
+			t.Errorf("Expected the builder error, but got %v", err)
+		}
+	})
+
+	t.Run("Result Error", func(t *testing.T) {
+		_, err := c.Invoke(ctx, "GetQuota", map[string]interface{}{"Account": "unknown"})
+		var resultErr *ResultError
+		if !errors.As(err, &resultErr) || resultErr.Endpoint != "GetQuota" {
+			t.Errorf("Expected a *ResultError, but got %v", err)
+		}
+	})
+
+	t.Run("Wrong Type", func(t *testing.T) {
+		if _, err := InvokeAs[quota](ctx, c, "GetQuota", nil); err == nil {
+			t.Error("Expected a type mismatch error")
+		}
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		if _, err := c.Invoke(ctx, "Missing", nil); err == nil {
+			t.Error("Expected an unknown endpoint to fail")
+		}
+		for _, name := range []string{"", "Get/../Admin", "Get Quota"} {
+			if err := RegisterEndpoint(Endpoint{Name: name}); err == nil {
+				t.Errorf("Expected name %q to be rejected", name)
+			}
+		}
+		names := strings.Join(Endpoints(), ",")
+		if !strings.Contains(names, "GetQuota,GetQuotaRaw") {
+			t.Errorf("Expected the registered endpoints, but got %s", names)
+		}
+	})
+}
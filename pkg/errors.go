@@ -0,0 +1,116 @@
+package circular_enterprise_apis
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrThrottled is matched by errors.Is when the NAG rejected a request because
+// the client is sending too many. Use errors.As with *ThrottledError to obtain
+// the delay advised by the gateway.
+var ErrThrottled = errors.New("request throttled by the network")
+
+// ThrottledError reports an HTTP 429 response, or a 503 response carrying a
+// Retry-After header, from the NAG or a gateway in front of it.
+type ThrottledError struct {
+	// StatusCode is the HTTP status returned by the server.
+	StatusCode int
+	// RetryAfter is the delay advised by the server before retrying, or zero
+	// if the server did not say.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *ThrottledError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v (status %d, retry after %v)", ErrThrottled, e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("%v (status %d)", ErrThrottled, e.StatusCode)
+}
+
+// Is reports whether target is ErrThrottled.
+func (e *ThrottledError) Is(target error) bool {
+	return target == ErrThrottled
+}
+
+// throttledError inspects a response and returns a *ThrottledError if the
+// server signalled throttling, or nil otherwise.
+func throttledError(resp *http.Response, now time.Time) *ThrottledError {
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode == http.StatusServiceUnavailable && hasRetryAfter:
+	default:
+		return nil
+	}
+	return &ThrottledError{StatusCode: resp.StatusCode, RetryAfter: retryAfter}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// ResultError reports a NAG response whose Result code is not 200. The HTTP
+// exchange itself succeeded; the gateway rejected the request.
+type ResultError struct {
+	// Endpoint is the NAG method that was called, such as "GetWallet".
+	Endpoint string
+	// Result is the code returned by the NAG.
+	Result int
+	// Message is the Response returned alongside the code, if any.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("%s failed with result %d: %s", e.Endpoint, e.Result, e.Message)
+}
+
+// ErrTransactionDropped is returned by GetTransactionOutcome when the network
+// still does not know a transaction after the not-found grace period, which
+// usually means it was rejected or evicted before being included in a block.
+var ErrTransactionDropped = errors.New("transaction dropped")
+
+// ErrPayloadTooLarge is matched by errors.Is when a certificate payload is
+// larger than the client's MaxPayloadSize. Use errors.As with
+// *PayloadTooLargeError to obtain the sizes involved.
+var ErrPayloadTooLarge = errors.New("certificate payload too large")
+
+// PayloadTooLargeError reports a payload rejected before submission.
+type PayloadTooLargeError struct {
+	// Size is the length of the hex-encoded payload in bytes.
+	Size int
+	// Limit is the MaxPayloadSize in force.
+	Limit int
+}
+
+// Error implements the error interface.
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("%v: %d bytes exceeds the limit of %d", ErrPayloadTooLarge, e.Size, e.Limit)
+}
+
+// Is reports whether target is ErrPayloadTooLarge.
+func (e *PayloadTooLargeError) Is(target error) bool {
+	return target == ErrPayloadTooLarge
+}
@@ -0,0 +1,84 @@
+package circular_enterprise_apis
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		value         string
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{name: "Seconds", value: "7", expectedDelay: 7 * time.Second, expectedOK: true},
+		{name: "HTTP Date", value: now.Add(90 * time.Second).Format(http.TimeFormat), expectedDelay: 90 * time.Second, expectedOK: true},
+		{name: "Date In The Past", value: now.Add(-time.Minute).Format(http.TimeFormat), expectedDelay: 0, expectedOK: true},
+		{name: "Empty", value: "", expectedOK: false},
+		{name: "Negative", value: "-3", expectedOK: false},
+		{name: "Garbage", value: "soon", expectedOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tc.value, now)
+			if ok != tc.expectedOK || delay != tc.expectedDelay {
+				t.Errorf("Expected (%v, %v), but got (%v, %v)", tc.expectedDelay, tc.expectedOK, delay, ok)
+			}
+		})
+	}
+}
+
+func TestThrottledResponses(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		retryAfter      string
+		expectThrottled bool
+		expectedDelay   time.Duration
+	}{
+		{name: "429 With Retry-After", statusCode: http.StatusTooManyRequests, retryAfter: "7", expectThrottled: true, expectedDelay: 7 * time.Second},
+		{name: "429 Without Retry-After", statusCode: http.StatusTooManyRequests, expectThrottled: true},
+		{name: "503 With Retry-After", statusCode: http.StatusServiceUnavailable, retryAfter: "2", expectThrottled: true, expectedDelay: 2 * time.Second},
+		{name: "503 Without Retry-After", statusCode: http.StatusServiceUnavailable, expectThrottled: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+			acc.Open("0x123")
+
+			_, err := acc.UpdateAccount()
+			if err == nil {
+				t.Fatal("Expected an error but got nil")
+			}
+			if errors.Is(err, ErrThrottled) != tc.expectThrottled {
+				t.Fatalf("Expected errors.Is(err, ErrThrottled) to be %v, but got error %v", tc.expectThrottled, err)
+			}
+			if !tc.expectThrottled {
+				return
+			}
+			var throttled *ThrottledError
+			if !errors.As(err, &throttled) {
+				t.Fatalf("Expected a *ThrottledError, but got %v", err)
+			}
+			if throttled.RetryAfter != tc.expectedDelay {
+				t.Errorf("Expected RetryAfter %v, but got %v", tc.expectedDelay, throttled.RetryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// DefaultMaxPayloadSize is a suggested MaxPayloadSize: the largest
+// hex-encoded certificate payload, in bytes, that is submitted. The payload is
+// the hex encoding of {"data":...}, so it is a little over twice the size of
+// the certified data.
+//
+// The protocol does not fix a payload limit; each NAG rejects requests above
+// its own configured body size, and that rejection arrives as a bare HTTP or
+// Result error. No limit is enforced by default. Set MaxPayloadSize, with
+// WithMaxPayloadSize, to the limit of the gateway in use to fail early with
+// ErrPayloadTooLarge instead.
+const DefaultMaxPayloadSize = 1 << 20
+
+// WithMaxPayloadSize sets the largest hex-encoded payload SubmitCertificate
+// will send. Zero or a negative value disables the check.
+func WithMaxPayloadSize(size int) Option {
+	return func(c *Client) {
+		c.MaxPayloadSize = size
+	}
+}
+
+// FeeEstimator prices a certificate transaction from its projected request
+// size. It lets applications plug in the pricing of the network they use.
+type FeeEstimator func(ctx context.Context, requestSize int) (float64, error)
+
+// WithFeeEstimator sets the pricing used by EstimateCertificate.
+func WithFeeEstimator(estimator FeeEstimator) Option {
+	return func(c *Client) {
+		c.feeEstimator = estimator
+	}
+}
+
+// Estimate is the projected cost of submitting a certificate.
+type Estimate struct {
+	// DataSize is the length of the certified data in bytes.
+	DataSize int
+	// PayloadSize is the length of the hex-encoded Payload field.
+	PayloadSize int
+	// RequestSize is an upper bound on the size of the request body sent to
+	// the NAG.
+	RequestSize int
+	// Fee is the projected fee. It is only meaningful when FeeKnown is true,
+	// which requires a FeeEstimator.
+	Fee      float64
+	FeeKnown bool
+	// MaxPayloadSize is the limit PayloadSize was checked against; zero means
+	// no limit is enforced.
+	MaxPayloadSize int
+	// ExceedsLimit reports whether PayloadSize is over MaxPayloadSize.
+	ExceedsLimit bool
+}
+
+// EstimateCertificate projects the size and, when a FeeEstimator is
+// configured, the fee of submitting pdata, without signing or sending it. opts
+// are the options the certificate would be submitted with: the payload is
+// built as SubmitCertificate builds it, so the chain, codec, encryption and
+// recipient chosen are taken into account. Resolving a recipient given as a
+// domain name queries the network.
+func (a *CEPAccount) EstimateCertificate(ctx context.Context, pdata string, opts ...SubmitOption) (estimate *Estimate, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+
+	request, _, err := a.buildPayload(ctx, scratch, pdata, newSubmitOptions(opts), false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ID and signature are not known until the certificate is signed;
+	// placeholders of their largest length stand in for them.
+	curve := a.signingCurve()
+	request.Address = a.Address
+	request.Curve = curveMetadata(curve)
+	request.ID = strings.Repeat("0", 64)
+	request.Signature = strings.Repeat("0", 2*maxSignatureSize(curve))
+	request.Timestamp = utils.FormatTimestamp(a.now())
+
+	scratch.Reset()
+	if err := encodeJSON(scratch, request); err != nil {
+		return nil, fmt.Errorf("failed to marshal request data: %w", err)
+	}
+
+	estimate = &Estimate{
+		DataSize:       len(pdata),
+		PayloadSize:    len(request.Payload),
+		RequestSize:    scratch.Len(),
+		MaxPayloadSize: a.MaxPayloadSize,
+	}
+	estimate.ExceedsLimit = a.MaxPayloadSize > 0 && estimate.PayloadSize > a.MaxPayloadSize
+
+	if a.feeEstimator != nil {
+		fee, err := a.feeEstimator(ctx, estimate.RequestSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate fee: %w", err)
+		}
+		estimate.Fee = fee
+		estimate.FeeKnown = true
+	}
+	return estimate, nil
+}
+
+// maxSignatureSize returns the length in bytes of the longest signature
+// curve produces: that reported by its MaxSignatureSize method if it has one,
+// and the 72 bytes of a DER-encoded secp256k1 signature otherwise.
+func maxSignatureSize(curve Curve) int {
+	if sized, ok := curve.(interface{ MaxSignatureSize() int }); ok {
+		return sized.MaxSignatureSize()
+	}
+	return secp256k1Curve{}.MaxSignatureSize()
+}
@@ -0,0 +1,174 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEstimateCertificate(t *testing.T) {
+	var requestSize int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		json.NewDecoder(r.Body).Decode(&body)
+		requestSize = len(body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion,
+		WithFeeEstimator(func(ctx context.Context, size int) (float64, error) { return float64(size) * 0.001, nil }))
+	acc.Open("0x" + strings.Repeat("a", 64))
+
+	data := "hello world"
+	estimate, err := acc.EstimateCertificate(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	// {"data":"hello world"} is 22 bytes, hex-encoded to 44.
+	if estimate.DataSize != len(data) || estimate.PayloadSize != 44 {
+		t.Errorf("Expected sizes (%d, 44), but got (%d, %d)", len(data), estimate.DataSize, estimate.PayloadSize)
+	}
+	if !estimate.FeeKnown || estimate.Fee != float64(estimate.RequestSize)*0.001 {
+		t.Errorf("Expected fee from the estimator, but got %v (known %v)", estimate.Fee, estimate.FeeKnown)
+	}
+	if estimate.ExceedsLimit {
+		t.Error("Expected a small payload to be within the limit")
+	}
+
+	if _, err := acc.SubmitCertificate(data, strings.Repeat("1", 64)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if requestSize > estimate.RequestSize || requestSize < estimate.RequestSize-4 {
+		t.Errorf("Expected actual request size %d to be just under the estimate %d", requestSize, estimate.RequestSize)
+	}
+}
+
+func TestEstimateCertificateEncoding(t *testing.T) {
+	var body struct {
+		Payload string
+	}
+	var requestSize int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		json.NewDecoder(r.Body).Decode(&raw)
+		requestSize = len(raw)
+		json.Unmarshal(raw, &body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+
+	certificate := NewCertificate(LibVersion)
+	if err := certificate.SetValue(map[string]interface{}{"contract": 42, "parties": []string{"alice", "bob"}}, CBORCodec); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	data, err := certificate.GetJSONCertificate()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	opts := []SubmitOption{WithEncryption(testPublicKey), WithRecipient("0x" + strings.Repeat("b", 64))}
+	estimate, err := acc.EstimateCertificate(context.Background(), data, opts...)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if _, err := acc.SubmitCertificate(data, strings.Repeat("1", 64), opts...); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if estimate.PayloadSize != len(body.Payload) {
+		t.Errorf("Expected the estimated payload size %d to be the size submitted, %d", estimate.PayloadSize, len(body.Payload))
+	}
+	if requestSize > estimate.RequestSize || requestSize < estimate.RequestSize-4 {
+		t.Errorf("Expected actual request size %d to be just under the estimate %d", requestSize, estimate.RequestSize)
+	}
+}
+
+func TestEstimateCertificateLimits(t *testing.T) {
+	acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
+	acc.Open("0xabc")
+	data := strings.Repeat("x", DefaultMaxPayloadSize/2)
+
+	unlimited, err := acc.EstimateCertificate(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if unlimited.ExceedsLimit || unlimited.MaxPayloadSize != 0 {
+		t.Errorf("Expected no limit by default, but got %d", unlimited.MaxPayloadSize)
+	}
+
+	acc = NewCEPAccount(DefaultNAG, DefaultChain, LibVersion, WithMaxPayloadSize(DefaultMaxPayloadSize))
+	acc.Open("0xabc")
+	estimate, err := acc.EstimateCertificate(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !estimate.ExceedsLimit {
+		t.Errorf("Expected payload of %d bytes to exceed %d", estimate.PayloadSize, estimate.MaxPayloadSize)
+	}
+	if estimate.FeeKnown {
+		t.Error("Expected fee to be unknown without an estimator")
+	}
+
+	failing := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion,
+		WithFeeEstimator(func(ctx context.Context, size int) (float64, error) { return 0, errors.New("no pricing") }))
+	if _, err := failing.EstimateCertificate(context.Background(), "data"); err == nil {
+		t.Error("Expected estimator error to be returned")
+	}
+}
+
+func TestSubmitCertificatePayloadTooLarge(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name        string
+		limit       int
+		data        string
+		expectError bool
+	}{
+		{"Within Limit", 64, "small", false},
+		{"Over Limit", 64, strings.Repeat("x", 64), true},
+		{"Disabled", 0, strings.Repeat("x", 64), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = 0
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithMaxPayloadSize(tc.limit))
+			acc.Open("0xabc")
+
+			_, err := acc.SubmitCertificate(tc.data, strings.Repeat("1", 64))
+			if !tc.expectError {
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPayloadTooLarge) {
+				t.Fatalf("Expected ErrPayloadTooLarge, but got %v", err)
+			}
+			var tooLarge *PayloadTooLargeError
+			if !errors.As(err, &tooLarge) || tooLarge.Limit != tc.limit {
+				t.Errorf("Expected a *PayloadTooLargeError with limit %d, but got %v", tc.limit, err)
+			}
+			if requests != 0 {
+				t.Errorf("Expected no network request, but got %d", requests)
+			}
+		})
+	}
+}
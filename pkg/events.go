@@ -0,0 +1,185 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a lifecycle event published on an EventBus. The concrete types are
+// SubmittedEvent, BroadcastEvent, ConfirmedEvent, FailedEvent, RetryingEvent
+// and NonceResyncedEvent; subscribers tell them apart with a type switch.
+type Event interface {
+	// Info returns the fields shared by every event.
+	Info() EventInfo
+}
+
+// EventInfo holds the fields shared by every event.
+type EventInfo struct {
+	Time time.Time
+	// CorrelationID identifies the operation that produced the event.
+	CorrelationID string
+	// Labels are the labels of the client or account that produced the
+	// event; see WithLabels.
+	Labels map[string]string
+}
+
+// Info implements Event.
+func (e EventInfo) Info() EventInfo {
+	return e
+}
+
+// BroadcastEvent is published when a signed transaction is sent to a NAG,
+// before its answer is known.
+type BroadcastEvent struct {
+	EventInfo
+	TxID        string
+	NAGURL      string
+	Transaction SignedTransaction
+}
+
+// SubmittedEvent is published when a NAG accepts a certificate, whether
+// submitted by SubmitCertificate or Broadcast.
+type SubmittedEvent struct {
+	EventInfo
+	TxID       string
+	Address    string
+	Blockchain string
+	Response   map[string]interface{}
+}
+
+// ConfirmedEvent is published when GetOutcome or WaitForOutcome observes a
+// confirmed transaction.
+type ConfirmedEvent struct {
+	EventInfo
+	Outcome *Outcome
+}
+
+// FailedEvent is published when a certificate submission or broadcast fails
+// or is rejected, and when GetOutcome or WaitForOutcome observes a transaction that
+// failed, expired or was dropped. TxID is empty for submissions that failed
+// before signing. Outcome is set when the failure was observed on the
+// network.
+type FailedEvent struct {
+	EventInfo
+	TxID    string
+	Err     error
+	Outcome *Outcome
+}
+
+// RetryingEvent is published before a failed request is retried.
+type RetryingEvent struct {
+	EventInfo
+	Path string
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt int
+	Err     error
+	Wait    time.Duration
+}
+
+// NonceResyncedEvent is published when UpdateAccount reads the nonce of an
+// account from the network.
+type NonceResyncedEvent struct {
+	EventInfo
+	Address  string
+	Previous int
+	Nonce    int
+}
+
+// EventBus delivers lifecycle events to subscribers. Handlers run
+// synchronously, in the goroutine of the operation that published the event,
+// so they should return quickly and hand slow work off. A bus can be shared
+// by several clients. The zero value is ready to use.
+type EventBus struct {
+	mu       sync.RWMutex
+	next     int
+	handlers map[int]func(Event)
+}
+
+// NewEventBus returns an EventBus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe calls handler with every event published from now on, until the
+// returned function is called.
+func (b *EventBus) Subscribe(handler func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = make(map[int]func(Event))
+	}
+	id := b.next
+	b.next++
+	b.handlers[id] = handler
+	return func() {
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}
+}
+
+// Publish delivers event to every subscriber.
+func (b *EventBus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := make([]func(Event), 0, len(b.handlers))
+	for _, handler := range b.handlers {
+		handlers = append(handlers, handler)
+	}
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// WithEventBus publishes the client's lifecycle events on bus.
+func WithEventBus(bus *EventBus) Option {
+	return func(c *Client) {
+		c.events = bus
+	}
+}
+
+// eventInfo returns the shared fields of an event produced by c under ctx.
+func (c *Client) eventInfo(ctx context.Context) EventInfo {
+	id, _ := CorrelationIDFromContext(ctx)
+	return EventInfo{Time: time.Now(), CorrelationID: id, Labels: c.Labels()}
+}
+
+// emitSubmission publishes the event matching the result of the submission
+// of txID.
+func (c *Client) emitSubmission(ctx context.Context, txID, address, blockchain string, response map[string]interface{}, err error) {
+	if err == nil {
+		err = c.resultError("AddTransaction", response)
+	}
+	if err != nil {
+		c.events.Publish(FailedEvent{EventInfo: c.eventInfo(ctx), TxID: txID, Err: err})
+		return
+	}
+	c.events.Publish(SubmittedEvent{
+		EventInfo:  c.eventInfo(ctx),
+		TxID:       txID,
+		Address:    address,
+		Blockchain: blockchain,
+		Response:   response,
+	})
+}
+
+// emitOutcome publishes the event matching a final outcome.
+func (c *Client) emitOutcome(ctx context.Context, outcome *Outcome) {
+	if c.events == nil {
+		return
+	}
+	switch outcome.Status {
+	case TxConfirmed:
+		c.events.Publish(ConfirmedEvent{EventInfo: c.eventInfo(ctx), Outcome: outcome})
+	case TxFailed, TxExpired:
+		c.events.Publish(FailedEvent{
+			EventInfo: c.eventInfo(ctx),
+			TxID:      outcome.TxID,
+			Err:       fmt.Errorf("transaction %s %s", outcome.TxID, strings.ToLower(outcome.Status.String())),
+			Outcome:   outcome,
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventBus(t *testing.T) {
+	var mu sync.Mutex
+	submit := `{"Result":200,"Response":{"TxID":"abc"}}`
+	throttle := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.Contains(r.URL.Path, "GetWalletNonce"):
+			if throttle {
+				throttle = false
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{"Result":200,"Response":{"Nonce":41}}`))
+		case strings.Contains(r.URL.Path, "GetTransactionbyID"):
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","BlockID":"7","BlockTimestamp":"2025:01:01-00:00:00","Position":0,"NodeID":"n1"}}`))
+		default:
+			w.Write([]byte(submit))
+		}
+	}))
+	defer server.Close()
+
+	bus := NewEventBus()
+	var events []Event
+	unsubscribe := bus.Subscribe(func(event Event) { events = append(events, event) })
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithEventBus(bus),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+	ctx := ContextWithCorrelationID(context.Background(), "trace")
+
+	t.Run("Nonce", func(t *testing.T) {
+		events = nil
+		if _, err := acc.UpdateAccountContext(ctx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, but got %#v", events)
+		}
+		if retrying, ok := events[0].(RetryingEvent); !ok || retrying.Attempt != 1 || !errors.Is(retrying.Err, ErrThrottled) {
+			t.Errorf("Expected a RetryingEvent, but got %#v", events[0])
+		}
+		if resynced, ok := events[1].(NonceResyncedEvent); !ok || resynced.Previous != 0 || resynced.Nonce != 42 {
+			t.Errorf("Expected a NonceResyncedEvent, but got %#v", events[1])
+		}
+	})
+
+	t.Run("Submitted", func(t *testing.T) {
+		events = nil
+		acc.SubmitCertificateContext(ctx, "data", privateKey)
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, but got %#v", events)
+		}
+		broadcast, ok := events[0].(BroadcastEvent)
+		if !ok || broadcast.TxID != acc.LatestTxID || broadcast.CorrelationID != "trace" {
+			t.Errorf("Expected a BroadcastEvent for %s, but got %#v", acc.LatestTxID, events[0])
+		}
+		if submitted, ok := events[1].(SubmittedEvent); !ok || submitted.TxID != acc.LatestTxID || submitted.Info().Time.IsZero() {
+			t.Errorf("Expected a SubmittedEvent, but got %#v", events[1])
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		events = nil
+		mu.Lock()
+		submit = `{"Result":108,"Response":"Invalid signature"}`
+		mu.Unlock()
+		acc.SubmitCertificateContext(ctx, "data", privateKey)
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, but got %#v", events)
+		}
+		var resultErr *ResultError
+		if failed, ok := events[1].(FailedEvent); !ok || failed.TxID == "" || !errors.As(failed.Err, &resultErr) || resultErr.Result != 108 {
+			t.Errorf("Expected a FailedEvent with the result, but got %#v", events[1])
+		}
+	})
+
+	t.Run("Confirmed", func(t *testing.T) {
+		events = nil
+		if _, err := acc.GetOutcome(ctx, acc.LatestTxID); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %#v", events)
+		}
+		if confirmed, ok := events[0].(ConfirmedEvent); !ok || confirmed.Outcome.BlockID != "7" {
+			t.Errorf("Expected a ConfirmedEvent, but got %#v", events[0])
+		}
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		events = nil
+		unsubscribe()
+		acc.GetOutcome(ctx, acc.LatestTxID)
+		if len(events) != 0 {
+			t.Errorf("Expected no events after unsubscribing, but got %#v", events)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ExportColumn names a column of a certificate export.
+type ExportColumn string
+
+const (
+	ColumnTxID      ExportColumn = "tx_id"
+	ColumnBlock     ExportColumn = "block"
+	ColumnTimestamp ExportColumn = "timestamp"
+	ColumnFrom      ExportColumn = "from"
+	// ColumnDataHash is the hex SHA-256 of the certified data.
+	ColumnDataHash ExportColumn = "data_hash"
+	// ColumnSize is the size of the certified data in bytes.
+	ColumnSize ExportColumn = "size"
+)
+
+// DefaultExportColumns returns the columns exported when none are selected.
+func DefaultExportColumns() []ExportColumn {
+	return []ExportColumn{ColumnTxID, ColumnBlock, ColumnTimestamp, ColumnDataHash, ColumnSize}
+}
+
+// CertificateRow is one certificate transaction found on chain.
+type CertificateRow struct {
+	TxID      string
+	Block     int64
+	Timestamp string
+	From      string
+	DataHash  string
+	Size      int
+}
+
+// Value returns the row's value for column formatted as text.
+func (r CertificateRow) Value(column ExportColumn) string {
+	switch column {
+	case ColumnTxID:
+		return r.TxID
+	case ColumnBlock:
+		return strconv.FormatInt(r.Block, 10)
+	case ColumnTimestamp:
+		return r.Timestamp
+	case ColumnFrom:
+		return r.From
+	case ColumnDataHash:
+		return r.DataHash
+	case ColumnSize:
+		return strconv.Itoa(r.Size)
+	default:
+		return ""
+	}
+}
+
+// ValidateExportColumns returns a copy of columns, or DefaultExportColumns if
+// it is empty, after checking that every column is known.
+func ValidateExportColumns(columns []ExportColumn) ([]ExportColumn, error) {
+	if len(columns) == 0 {
+		return DefaultExportColumns(), nil
+	}
+	for _, column := range columns {
+		switch column {
+		case ColumnTxID, ColumnBlock, ColumnTimestamp, ColumnFrom, ColumnDataHash, ColumnSize:
+		default:
+			return nil, fmt.Errorf("unknown export column %q", column)
+		}
+	}
+	return append([]ExportColumn(nil), columns...), nil
+}
+
+// RowWriter receives the rows of an export. Implementations for other
+// formats, such as the Parquet writer in the export/parquet module, plug into
+// ExportCertificates through it.
+type RowWriter interface {
+	WriteRow(row CertificateRow) error
+}
+
+// certificateRow extracts a CertificateRow from a transaction in a block.
+// Transactions whose payload is not a certificate get an empty DataHash.
+func certificateRow(tx map[string]interface{}, block int64) CertificateRow {
+	row := CertificateRow{Block: block}
+	row.TxID, _ = tx["ID"].(string)
+	if row.TxID == "" {
+		row.TxID, _ = tx["TxID"].(string)
+	}
+	row.From, _ = tx["From"].(string)
+	row.Timestamp, _ = tx["Timestamp"].(string)
+	if payload, ok := tx["Payload"].(string); ok {
+		if data, err := decodePayload(payload); err == nil {
+			row.DataHash = payloadHash(data)
+			row.Size = len(data)
+		}
+	}
+	return row
+}
+
+// ExportCertificates streams the certificates sent by address in the blocks
+// from start to end (inclusive) to w, in chain order, and returns the number
+// of rows written. An empty address exports the certificates of every
+// account. Transactions that do not carry a certificate are skipped.
+func (c *Client) ExportCertificates(ctx context.Context, address string, start, end int64, w RowWriter) (n int, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	address = normalizeHex(address)
+	err = c.scanTransactions(ctx, start, end, func(tx map[string]interface{}, block int64) error {
+		row := certificateRow(tx, block)
+		if row.DataHash == "" || (address != "" && normalizeHex(row.From) != address) {
+			return nil
+		}
+		if err := w.WriteRow(row); err != nil {
+			return fmt.Errorf("failed to write row for %s: %w", row.TxID, err)
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// CSVExporter writes rows as CSV with a header line.
+type CSVExporter struct {
+	w             *csv.Writer
+	columns       []ExportColumn
+	headerWritten bool
+	record        []string
+}
+
+// NewCSVExporter returns an exporter writing the given columns, or
+// DefaultExportColumns if none are given, to w. Call Flush when done.
+func NewCSVExporter(w io.Writer, columns ...ExportColumn) (*CSVExporter, error) {
+	columns, err := ValidateExportColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+	return &CSVExporter{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}, nil
+}
+
+// WriteRow writes row, preceded by the header if it is the first.
+func (e *CSVExporter) WriteRow(row CertificateRow) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	for i, column := range e.columns {
+		e.record[i] = row.Value(column)
+	}
+	return e.w.Write(e.record)
+}
+
+// Flush writes the header if no row was written and flushes buffered data.
+func (e *CSVExporter) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// writeHeader writes the header line once.
+func (e *CSVExporter) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	for i, column := range e.columns {
+		e.record[i] = string(column)
+	}
+	return e.w.Write(e.record)
+}
@@ -0,0 +1,109 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+func TestExportCertificates(t *testing.T) {
+	tx := func(id, from, payload string) string {
+		return fmt.Sprintf(`{"ID":"%s","From":"%s","Payload":"%s","Timestamp":"2024:01:02-03:04:05"}`, id, from, payload)
+	}
+	certificate := utils.StringToHex(`{"data":"hello"}`)
+	body := fmt.Sprintf(`{"Result":200,"Response":{"Blocks":[{"BlockID":"7","Transactions":[%s,%s,%s]}]}}`,
+		tx("aa01", "0xme", certificate),
+		tx("aa02", "0xsomeone", certificate),
+		tx("aa03", "0xme", "not-a-certificate"),
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+
+	testCases := []struct {
+		name     string
+		columns  []ExportColumn
+		expected string
+	}{
+		{
+			name:     "Default Columns",
+			expected: "tx_id,block,timestamp,data_hash,size\naa01,7,2024:01:02-03:04:05," + payloadHash("hello") + ",5\n",
+		},
+		{
+			name:     "Selected Columns",
+			columns:  []ExportColumn{ColumnBlock, ColumnTxID},
+			expected: "block,tx_id\n7,aa01\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			exporter, err := NewCSVExporter(&buf, tc.columns...)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			n, err := c.ExportCertificates(context.Background(), "me", 7, 7, exporter)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if err := exporter.Flush(); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if n != 1 {
+				t.Errorf("Expected 1 row, but got %d", n)
+			}
+			if buf.String() != tc.expected {
+				t.Errorf("Expected %q, but got %q", tc.expected, buf.String())
+			}
+		})
+	}
+
+	t.Run("Unknown Column", func(t *testing.T) {
+		if _, err := NewCSVExporter(&bytes.Buffer{}, "nonce"); err == nil {
+			t.Error("Expected an error but got nil")
+		}
+	})
+
+	t.Run("Header Without Rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		exporter, _ := NewCSVExporter(&buf, ColumnTxID)
+		exporter.Flush()
+		if buf.String() != "tx_id\n" {
+			t.Errorf("Expected only the header, but got %q", buf.String())
+		}
+	})
+}
+
+func TestValidateExportColumns(t *testing.T) {
+	columns, err := ValidateExportColumns(nil)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	columns[0] = ColumnFrom
+	if DefaultExportColumns()[0] != ColumnTxID {
+		t.Errorf("Expected the default columns to be unaffected, but got %v", DefaultExportColumns())
+	}
+
+	selected := []ExportColumn{ColumnBlock}
+	columns, err = ValidateExportColumns(selected)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	columns[0] = ColumnFrom
+	if selected[0] != ColumnBlock {
+		t.Errorf("Expected the selected columns to be copied, but got %v", selected)
+	}
+
+	if _, err := ValidateExportColumns([]ExportColumn{"unknown"}); err == nil {
+		t.Error("Expected an error for an unknown column but got nil")
+	}
+}
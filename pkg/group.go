@@ -0,0 +1,100 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"sync"
+)
+
+// GroupMode selects how RunGroup and SubmitAll handle an item that fails.
+type GroupMode int
+
+const (
+	// CollectAll runs every item whatever the others return and reports all
+	// the failures in a *BatchError.
+	CollectAll GroupMode = iota
+	// FirstError cancels the context of the other items as soon as one
+	// fails and returns that first error. Items not started by then fail
+	// with an error matching ErrNotStarted.
+	FirstError
+)
+
+// RunGroup calls fn for each of n items, at most concurrency at a time, and
+// waits for all of them to return. Values of concurrency below one are
+// treated as one. fn must honour the context it is given: it is canceled
+// when ctx is done and, in FirstError mode, when another item fails.
+//
+// Items not started when the context is done are not passed to fn; they fail
+// with an error matching both ErrNotStarted and the context's error. In
+// CollectAll mode the error is a *BatchError if any item failed; in
+// FirstError mode it is the first error returned by fn, or by an item that
+// was not started.
+func RunGroup(ctx context.Context, n, concurrency int, mode GroupMode, fn func(ctx context.Context, i int) error) error {
+	errs, first := runGroup(ctx, n, concurrency, mode, fn)
+	if mode == FirstError {
+		return first
+	}
+	return newBatchError(errs)
+}
+
+// runGroup implements RunGroup on runBatch and returns the error of each item
+// along with the first one.
+func runGroup(ctx context.Context, n, concurrency int, mode GroupMode, fn func(ctx context.Context, i int) error) ([]error, error) {
+	// Only FirstError mode lets a failure cancel the others.
+	cancel := context.CancelFunc(func() {})
+	if mode == FirstError {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	errs := make([]error, n)
+	var (
+		once  sync.Once
+		first error
+	)
+	runBatch(ctx, n, concurrency, func(ctx context.Context, i int) {
+		errs[i] = fn(ctx, i)
+		if errs[i] != nil && mode == FirstError {
+			once.Do(func() {
+				first = errs[i]
+				cancel()
+			})
+		}
+	}, func(i int, err error) {
+		errs[i] = err
+	})
+	if first == nil {
+		for _, err := range errs {
+			if err != nil {
+				first = err
+				break
+			}
+		}
+	}
+	return errs, first
+}
+
+// SubmitAll submits each entry of data as a certificate, at most concurrency
+// at a time, and returns the results in the same order. Each certificate is
+// submitted as by SubmitBatch.
+//
+// In CollectAll mode SubmitAll behaves like SubmitBatch with its own
+// concurrency: every certificate is submitted and the error is a *BatchError
+// if any of them failed. In FirstError mode the first certificate to fail
+// cancels the submissions in flight and those not started yet, and its error
+// is returned as is. Either way the results describe every entry: those cut
+// off in flight fail with the context's error and those not started fail
+// with an error matching ErrNotStarted, and can be submitted again.
+func (a *CEPAccount) SubmitAll(ctx context.Context, data []string, privateKey string, concurrency int, mode GroupMode, opts ...SubmitOption) ([]SubmitResult, error) {
+	results := make([]SubmitResult, len(data))
+	errs, first := runGroup(ContextWithBulk(ctx), len(data), concurrency, mode, func(ctx context.Context, i int) error {
+		results[i] = a.submitItem(ctx, data[i], privateKey, opts)
+		return results[i].Err
+	})
+	for i, err := range errs {
+		results[i].Err = err
+	}
+	if mode == FirstError {
+		return results, first
+	}
+	return results, newBatchError(errs)
+}
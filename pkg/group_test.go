@@ -0,0 +1,153 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunGroup(t *testing.T) {
+	errBad := errors.New("bad")
+
+	t.Run("Collect All", func(t *testing.T) {
+		var inFlight, maxInFlight, calls int32
+		err := RunGroup(context.Background(), 10, 3, CollectAll, func(ctx context.Context, i int) error {
+			atomic.AddInt32(&calls, 1)
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			if i%2 == 0 {
+				return errBad
+			}
+			return nil
+		})
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || batchErr.Total != 10 || len(batchErr.Errors) != 5 {
+			t.Fatalf("Expected a *BatchError with 5 failures, but got %v", err)
+		}
+		if calls != 10 {
+			t.Errorf("Expected every item to run, but %d did", calls)
+		}
+		if max := atomic.LoadInt32(&maxInFlight); max > 3 {
+			t.Errorf("Expected at most 3 items in flight, but got %d", max)
+		}
+	})
+
+	t.Run("First Error", func(t *testing.T) {
+		var calls int32
+		err := RunGroup(context.Background(), 10, 1, FirstError, func(ctx context.Context, i int) error {
+			atomic.AddInt32(&calls, 1)
+			if i == 2 {
+				return errBad
+			}
+			return nil
+		})
+		if err != errBad {
+			t.Errorf("Expected the first error, but got %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("Expected the items after the failure not to run, but %d ran", calls)
+		}
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		started := make(chan struct{})
+		var cutOff error
+		err := RunGroup(context.Background(), 2, 2, FirstError, func(ctx context.Context, i int) error {
+			if i == 0 {
+				<-started
+				return errBad
+			}
+			close(started)
+			<-ctx.Done()
+			cutOff = ctx.Err()
+			return cutOff
+		})
+		if err != errBad {
+			t.Errorf("Expected the first error, but got %v", err)
+		}
+		if !errors.Is(cutOff, context.Canceled) {
+			t.Errorf("Expected the item in flight to be canceled, but got %v", cutOff)
+		}
+	})
+
+	t.Run("Done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, mode := range []GroupMode{CollectAll, FirstError} {
+			err := RunGroup(ctx, 3, 1, mode, func(ctx context.Context, i int) error {
+				t.Error("Expected no item to run")
+				return nil
+			})
+			if !errors.Is(err, ErrNotStarted) || !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected the items not to be started, but got %v", err)
+			}
+		}
+	})
+}
+
+func TestSubmitAll(t *testing.T) {
+	var submissions int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&submissions, 1)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := decodePayload(body["Payload"].(string))
+		if data == "bad" {
+			w.Write([]byte(`{"Result":108,"Response":"Invalid Payload"}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"ok"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+	data := []string{"good", "bad", "good", "good"}
+
+	t.Run("Collect All", func(t *testing.T) {
+		atomic.StoreInt32(&submissions, 0)
+		results, err := acc.SubmitAll(context.Background(), data, privateKey, 2, CollectAll)
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 {
+			t.Fatalf("Expected a *BatchError with one failure, but got %v", err)
+		}
+		if results[0].Err != nil || results[3].Err != nil || results[1].Response["Result"] != float64(108) {
+			t.Errorf("Expected the results to describe each item, but got %+v", results)
+		}
+		if n := atomic.LoadInt32(&submissions); n != 4 {
+			t.Errorf("Expected 4 submissions, but got %d", n)
+		}
+	})
+
+	t.Run("First Error", func(t *testing.T) {
+		atomic.StoreInt32(&submissions, 0)
+		results, err := acc.SubmitAll(context.Background(), data, privateKey, 1, FirstError)
+		var resultErr *ResultError
+		if !errors.As(err, &resultErr) || resultErr.Result != 108 {
+			t.Fatalf("Expected the *ResultError of the failed certificate, but got %v", err)
+		}
+		if results[0].Err != nil {
+			t.Errorf("Expected the first certificate to be accepted, but got %v", results[0].Err)
+		}
+		for _, result := range results[2:] {
+			if !errors.Is(result.Err, ErrNotStarted) {
+				t.Errorf("Expected the certificates after the failure not to be started, but got %v", result.Err)
+			}
+		}
+		if n := atomic.LoadInt32(&submissions); n != 2 {
+			t.Errorf("Expected 2 submissions, but got %d", n)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// LibName identifies this library in the User-Agent header.
+const LibName = "CEP-Go-APIs"
+
+// Header names sent with every request to the NAG.
+const (
+	HeaderUserAgent      = "User-Agent"
+	HeaderCircularClient = "X-Circular-Client"
+)
+
+// defaultUserAgent describes the library, its version and the platform, e.g.
+// "CEP-Go-APIs/1.0.13 (go1.24.4; linux/amd64)".
+var defaultUserAgent = fmt.Sprintf("%s/%s (%s; %s/%s)", LibName, LibVersion, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+// clientHeader is the value of X-Circular-Client, which gateway operators can
+// use to attribute traffic to an SDK and version without parsing User-Agent.
+var clientHeader = fmt.Sprintf("go/%s", LibVersion)
+
+// WithUserAgentSuffix appends an application identifier such as
+// "invoice-service/2.3.1" to the User-Agent sent with every request.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		c.userAgentSuffix = suffix
+	}
+}
+
+// WithHeaders adds static headers, such as a tenant ID, routing hints or a
+// gateway API key, to every request made by the client. Repeated options
+// accumulate, and a later value replaces an earlier one for the same header.
+// The headers the library sets itself (User-Agent, X-Circular-Client,
+// X-Correlation-ID and the content headers) take precedence; use
+// WithUserAgentSuffix to extend the User-Agent.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header, len(headers))
+		}
+		for name, value := range headers {
+			c.headers.Set(name, value)
+		}
+	}
+}
+
+// UserAgent returns the User-Agent header value used by the client.
+func (c *Client) UserAgent() string {
+	if c.userAgentSuffix == "" {
+		return defaultUserAgent
+	}
+	return defaultUserAgent + " " + c.userAgentSuffix
+}
+
+// applyHeaders sets the headers common to every request made by the client.
+func (c *Client) applyHeaders(ctx context.Context, req *http.Request) {
+	for name, values := range c.headers {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = append([]string(nil), values...)
+		}
+	}
+	req.Header.Set(HeaderUserAgent, c.UserAgent())
+	req.Header.Set(HeaderCircularClient, clientHeader)
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		req.Header.Set(HeaderCorrelationID, id)
+	}
+}
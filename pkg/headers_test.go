@@ -0,0 +1,83 @@
+package circular_enterprise_apis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRequestHeaders(t *testing.T) {
+	var userAgent, client string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get(HeaderUserAgent)
+		client = r.Header.Get(HeaderCircularClient)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name           string
+		opts           []Option
+		expectedSuffix string
+	}{
+		{name: "Default", opts: nil, expectedSuffix: ")"},
+		{name: "With App Suffix", opts: []Option{WithUserAgentSuffix("invoices/2.3.1")}, expectedSuffix: ") invoices/2.3.1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, tc.opts...)
+			acc.Open("0x123")
+			if _, err := acc.UpdateAccount(); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			if !strings.HasPrefix(userAgent, LibName+"/"+LibVersion+" (") {
+				t.Errorf("Expected User-Agent to start with library name and version, but got %s", userAgent)
+			}
+			if !strings.Contains(userAgent, runtime.Version()) || !strings.Contains(userAgent, runtime.GOOS) {
+				t.Errorf("Expected User-Agent to contain Go version and OS, but got %s", userAgent)
+			}
+			if !strings.HasSuffix(userAgent, tc.expectedSuffix) {
+				t.Errorf("Expected User-Agent to end with %q, but got %s", tc.expectedSuffix, userAgent)
+			}
+			if client != "go/"+LibVersion {
+				t.Errorf("Expected %s to be go/%s, but got %s", HeaderCircularClient, LibVersion, client)
+			}
+		})
+	}
+}
+
+func TestCustomHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion,
+		WithHeaders(map[string]string{"x-tenant-id": "acme", "X-Route": "eu"}),
+		WithHeaders(map[string]string{"X-Route": "us", "User-Agent": "spoofed", "Content-Type": "text/plain"}))
+	acc.Open("0x123")
+	if _, err := acc.UpdateAccount(); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if got := received.Get("X-Tenant-Id"); got != "acme" {
+		t.Errorf("Expected X-Tenant-Id acme, but got %q", got)
+	}
+	if got := received.Get("X-Route"); got != "us" {
+		t.Errorf("Expected the later X-Route to win, but got %q", got)
+	}
+	if got := received.Get(HeaderUserAgent); got != acc.UserAgent() {
+		t.Errorf("Expected the library User-Agent to take precedence, but got %q", got)
+	}
+	if got := received.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected the library Content-Type to take precedence, but got %q", got)
+	}
+}
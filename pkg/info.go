@@ -0,0 +1,122 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AccountInfo is the wallet information stored in CEPAccount.Info by
+// GetAccountInfo.
+type AccountInfo struct {
+	Address   string
+	PublicKey string
+	// Registered reports whether the wallet exists on the chain. The other
+	// fields are only populated for registered wallets.
+	Registered   bool
+	Nonce        int
+	DateCreation string
+	// Balances maps asset names to the amount held.
+	Balances map[string]float64
+	// Permissions are the wallet's roles and permissions on the chain.
+	Permissions Permissions
+	// LatestTxID is the ID of the account's latest successful submission made
+	// through this CEPAccount, or empty if there has been none.
+	LatestTxID string
+}
+
+// Balance returns the amount of asset held, or zero if none is held.
+func (i *AccountInfo) Balance(asset string) float64 {
+	return i.Balances[asset]
+}
+
+// walletResponse is the Response of the GetWallet endpoint.
+type walletResponse struct {
+	Address      string          `json:"Address"`
+	PublicKey    string          `json:"PublicKey"`
+	Nonce        int             `json:"Nonce"`
+	DateCreation string          `json:"DateCreation"`
+	Roles        json.RawMessage `json:"Roles"`
+	Role         json.RawMessage `json:"Role"`
+	Permissions  json.RawMessage `json:"Permissions"`
+	Assets       []struct {
+		Name   string          `json:"Name"`
+		Amount json.RawMessage `json:"Amount"`
+	} `json:"Assets"`
+}
+
+// GetAccountInfo fetches the wallet of the open account, stores it in Info as
+// an *AccountInfo and returns it. A wallet the NAG reports as not found is
+// returned with Registered set to false rather than as an error.
+func (a *CEPAccount) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
+	if a.Address == "" {
+		return nil, errors.New("Account is not open")
+	}
+
+	info, err := a.walletInfo(ctx, a.Blockchain, a.NAGURL)
+	if err != nil {
+		return nil, err
+	}
+	if info.PublicKey != "" {
+		a.PublicKey = info.PublicKey
+	}
+	a.Info = info
+	return info, nil
+}
+
+// walletInfo fetches the account's wallet on the given blockchain and NAG
+// without modifying the account.
+func (a *CEPAccount) walletInfo(ctx context.Context, blockchain, nagURL string) (*AccountInfo, error) {
+	a.txMu.Lock()
+	info := &AccountInfo{Address: a.Address, LatestTxID: a.LatestTxID, Permissions: Permissions{Address: a.Address}}
+	a.txMu.Unlock()
+
+	response, err := a.getWallet(ctx, blockchain, nagURL, a.Address)
+	var resultErr *ResultError
+	switch {
+	case errors.As(err, &resultErr) && strings.Contains(strings.ToLower(resultErr.Message), "not found"):
+		return info, nil
+	case err != nil:
+		return nil, err
+	}
+
+	// Re-encode the already validated Response into its typed form.
+	raw, err := json.Marshal(response["Response"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode wallet: %w", err)
+	}
+	var wallet walletResponse
+	if err := json.Unmarshal(raw, &wallet); err != nil {
+		return nil, fmt.Errorf("failed to decode wallet: %w", err)
+	}
+
+	info.Registered = true
+	info.PublicKey = wallet.PublicKey
+	info.Nonce = wallet.Nonce
+	info.DateCreation = wallet.DateCreation
+	info.Permissions = walletPermissions(a.Address, wallet.Roles, wallet.Role, wallet.Permissions)
+	info.Balances = make(map[string]float64, len(wallet.Assets))
+	for _, asset := range wallet.Assets {
+		amount, err := parseAmount(asset.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount for asset %s: %w", asset.Name, err)
+		}
+		info.Balances[asset.Name] += amount
+	}
+	return info, nil
+}
+
+// parseAmount accepts an amount encoded either as a JSON number or a string.
+func parseAmount(raw json.RawMessage) (float64, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	text := string(raw)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	return strconv.ParseFloat(strings.TrimSpace(text), 64)
+}
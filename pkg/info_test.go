@@ -0,0 +1,91 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAccountInfo(t *testing.T) {
+	testCases := []struct {
+		name               string
+		mockResponse       string
+		expectError        bool
+		expectedRegistered bool
+		expectedBalance    float64
+		expectedPublicKey  string
+	}{
+		{
+			name:               "Registered Wallet",
+			mockResponse:       `{"Result":200,"Response":{"Address":"0xabc","PublicKey":"04ff","Nonce":7,"DateCreation":"2024:01:02-03:04:05","Assets":[{"Name":"CIRX","Amount":"12.5"},{"Name":"USDC","Amount":3}]}}`,
+			expectedRegistered: true,
+			expectedBalance:    12.5,
+			expectedPublicKey:  "04ff",
+		},
+		{
+			name:         "Unregistered Wallet",
+			mockResponse: `{"Result":118,"Response":"Wallet Not Found"}`,
+		},
+		{
+			name:         "Other Failure",
+			mockResponse: `{"Result":108,"Response":"Invalid Blockchain"}`,
+			expectError:  true,
+		},
+		{
+			name:         "Invalid Amount",
+			mockResponse: `{"Result":200,"Response":{"Assets":[{"Name":"CIRX","Amount":"lots"}]}}`,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tc.mockResponse))
+			}))
+			defer server.Close()
+
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+			acc.Open("0xabc")
+			acc.LatestTxID = "tx1"
+
+			info, err := acc.GetAccountInfo(context.Background())
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("Expected an error but got nil")
+				}
+				if acc.Info != nil {
+					t.Errorf("Expected Info to remain unset, but got %v", acc.Info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if acc.Info != info {
+				t.Error("Expected Info to hold the returned *AccountInfo")
+			}
+			if info.Registered != tc.expectedRegistered {
+				t.Errorf("Expected Registered to be %v, but got %v", tc.expectedRegistered, info.Registered)
+			}
+			if info.Balance("CIRX") != tc.expectedBalance {
+				t.Errorf("Expected CIRX balance %v, but got %v", tc.expectedBalance, info.Balance("CIRX"))
+			}
+			if acc.PublicKey != tc.expectedPublicKey {
+				t.Errorf("Expected PublicKey %q, but got %q", tc.expectedPublicKey, acc.PublicKey)
+			}
+			if info.LatestTxID != "tx1" {
+				t.Errorf("Expected LatestTxID tx1, but got %s", info.LatestTxID)
+			}
+		})
+	}
+}
+
+func TestGetAccountInfoNotOpen(t *testing.T) {
+	acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
+	if _, err := acc.GetAccountInfo(context.Background()); err == nil {
+		t.Fatal("Expected an error but got nil")
+	}
+}
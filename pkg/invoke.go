@@ -0,0 +1,117 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Envelope is the shape of every NAG response: a Result code, 200 on
+// success, and a Response whose type depends on the endpoint. Use it as the
+// type argument of Call to decode the Response into a struct.
+type Envelope[T any] struct {
+	Result   int `json:"Result"`
+	Response T   `json:"Response"`
+}
+
+// Call posts request, marshalled as JSON, to the named NAG endpoint (for
+// example "GetWallet") on the client's gateway and decodes the response into
+// T. It applies the client's headers, retries, timeouts and parse mode like
+// the built-in methods, which send their requests the same way, and is the
+// way to reach endpoints the library does not wrap yet:
+//
+//	block, err := Call[Envelope[MyBlock]](ctx, client, "GetBlock", request)
+//
+// A response whose Result is not 200 is returned as a *ResultError carrying
+// the gateway's message, whatever T is. With T = map[string]interface{} the
+// whole response is returned as decoded by encoding/json.
+//
+// Two requests do not go through Call: transactions are posted to the NAG URL
+// itself rather than to a named endpoint, and SetNetwork and DiscoverNAG query
+// the client's NetworkURL rather than a gateway.
+func Call[T any](ctx context.Context, c *Client, endpoint string, request any) (T, error) {
+	return callAt[T](ctx, c, c.NAGURL, endpoint, request)
+}
+
+// callAt is like Call but sends the request to the given NAG, which lets
+// operations target a registered chain with its own gateway.
+func callAt[T any](ctx context.Context, c *Client, nagURL, endpoint string, request any) (out T, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	body, err := readAt(ctx, c, nagURL, endpoint, request)
+	if err != nil {
+		return out, err
+	}
+
+	// The generic form is needed to check the Result whatever T is, and is
+	// the result itself when T is a map.
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return out, fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+	}
+
+	if c.ParseMode == ParseStrict {
+		if err := validateFields(endpoint, response, fieldSpec{"Result", "number"}); err != nil {
+			return out, err
+		}
+	}
+
+	if err := c.resultError(endpoint, response); err != nil {
+		return out, err
+	}
+
+	if m, ok := any(&out).(*map[string]interface{}); ok {
+		*m = response
+		return out, nil
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return out, fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+	}
+	return out, nil
+}
+
+// readAt posts request to the named endpoint of nagURL, as callAt does, and
+// returns the body of the response without interpreting it. Methods whose
+// responses need their own parsing are built on it.
+func readAt(ctx context.Context, c *Client, nagURL, endpoint string, request any) ([]byte, error) {
+	resp, err := postAt(ctx, c, nagURL, endpoint, request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := c.readBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
+// postAt posts request, marshalled as JSON, to the named endpoint of nagURL
+// and returns the response once its HTTP status is checked. The caller must
+// close the response body; streaming methods decode it as it arrives.
+func postAt(ctx context.Context, c *Client, nagURL, endpoint string, request any) (*http.Response, error) {
+	if nagURL == "" {
+		return nil, fmt.Errorf("network is not set. Please call SetNetwork() first")
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request data: %w", err)
+	}
+
+	requestURL := fmt.Sprintf("%s/Circular_%s_%s", nagURL, endpoint, c.NetworkNode)
+
+	resp, err := c.postJSON(ctx, requestURL, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("http post request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("network request failed with status: %w", statusError(resp))
+	}
+	return resp, nil
+}
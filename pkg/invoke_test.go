@@ -0,0 +1,88 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	var path string
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		switch request["Name"] {
+		case "missing":
+			w.Write([]byte(`{"Result":118,"Response":"Asset Not Found"}`))
+		case "no-result":
+			w.Write([]byte(`{"Response":{"Name":"CIRX"}}`))
+		case "bad-type":
+			w.Write([]byte(`{"Result":200,"Response":{"Supply":"lots"}}`))
+		default:
+			w.Write([]byte(`{"Result":200,"Response":{"Name":"CIRX","Supply":1000}}`))
+		}
+	}))
+	defer server.Close()
+
+	type asset struct {
+		Name   string
+		Supply int64
+	}
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	ctx := context.Background()
+
+	t.Run("Typed", func(t *testing.T) {
+		got, err := Call[Envelope[asset]](ctx, c, "GetAssetInfo", map[string]string{"Name": "CIRX"})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if got.Result != 200 || got.Response != (asset{Name: "CIRX", Supply: 1000}) {
+			t.Errorf("Expected the decoded asset, but got %+v", got)
+		}
+		if path != "/Circular_GetAssetInfo_" || request["Name"] != "CIRX" {
+			t.Errorf("Expected the request to reach the endpoint, but got %s %v", path, request)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		got, err := Call[map[string]interface{}](ctx, c, "GetAssetInfo", map[string]string{"Name": "CIRX"})
+		if err != nil || got["Result"] != float64(200) {
+			t.Errorf("Expected the whole response, but got %v, %v", got, err)
+		}
+	})
+
+	t.Run("Result Error", func(t *testing.T) {
+		_, err := Call[Envelope[asset]](ctx, c, "GetAssetInfo", map[string]string{"Name": "missing"})
+		var resultErr *ResultError
+		if !errors.As(err, &resultErr) || resultErr.Endpoint != "GetAssetInfo" || resultErr.Message != "Asset Not Found" {
+			t.Errorf("Expected a *ResultError, but got %v", err)
+		}
+	})
+
+	t.Run("Decode Error", func(t *testing.T) {
+		if _, err := Call[Envelope[asset]](ctx, c, "GetAssetInfo", map[string]string{"Name": "bad-type"}); err == nil {
+			t.Error("Expected a mistyped response to fail decoding")
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		strict := NewClient(server.URL, DefaultChain, LibVersion)
+		strict.ParseMode = ParseStrict
+		_, err := Call[Envelope[asset]](ctx, strict, "GetAssetInfo", map[string]string{"Name": "no-result"})
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Errorf("Expected a *SchemaError, but got %v", err)
+		}
+	})
+
+	t.Run("No Network", func(t *testing.T) {
+		if _, err := Call[Envelope[asset]](ctx, NewClient("", DefaultChain, LibVersion), "GetAssetInfo", nil); err == nil {
+			t.Error("Expected an error without a NAG URL")
+		}
+	})
+}
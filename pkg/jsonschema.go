@@ -0,0 +1,461 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PayloadSchema validates certificate data against a JSON Schema before it
+// is signed. The common validation keywords are supported: type, enum,
+// const, properties, required, additionalProperties, items, minItems,
+// maxItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, allOf, anyOf, oneOf and not.
+// Annotations such as title, description and format are accepted and
+// ignored. Any other keyword, including $ref, is rejected when the schema is
+// compiled rather than silently skipped.
+type PayloadSchema struct {
+	root *schemaNode
+}
+
+// SchemaViolation is one way in which a payload fails its schema.
+type SchemaViolation struct {
+	// Path is the JSON Pointer of the offending value; empty for the root.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String formats the violation as "path: message".
+func (v SchemaViolation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + v.Message
+}
+
+// PayloadValidationError reports a payload rejected by a PayloadSchema.
+// Violations describe the constraint that failed, never the offending value,
+// so the error is safe to log.
+type PayloadValidationError struct {
+	Violations []SchemaViolation
+}
+
+// Error implements the error interface.
+func (e *PayloadValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.String()
+	}
+	return "payload does not match schema: " + strings.Join(messages, "; ")
+}
+
+// Retryable reports false: the payload will fail validation on every attempt.
+func (e *PayloadValidationError) Retryable() bool { return false }
+
+// WithPayloadSchema validates the data of every certificate submitted by the
+// client against schema.
+func WithPayloadSchema(schema *PayloadSchema) Option {
+	return func(c *Client) {
+		c.payloadSchema = schema
+	}
+}
+
+// WithSchema validates the certificate data against schema instead of the
+// client's payload schema.
+func WithSchema(schema *PayloadSchema) SubmitOption {
+	return func(o *submitOptions) {
+		o.schema = schema
+	}
+}
+
+// CompilePayloadSchema parses a JSON Schema document.
+func CompilePayloadSchema(schema string) (*PayloadSchema, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(schema), &doc); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	root, err := compileSchemaNode(doc, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &PayloadSchema{root: root}, nil
+}
+
+// Validate checks that data is JSON matching the schema. It returns a
+// *PayloadValidationError listing every violation.
+func (s *PayloadSchema) Validate(data string) error {
+	var value interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return &PayloadValidationError{Violations: []SchemaViolation{{Message: "payload is not valid JSON"}}}
+	}
+	var violations []SchemaViolation
+	s.root.validate(value, "", &violations)
+	if len(violations) > 0 {
+		return &PayloadValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// schemaNode is a compiled schema or subschema.
+type schemaNode struct {
+	reject bool // the schema "false"
+
+	types    []string
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	properties       map[string]*schemaNode
+	required         []string
+	additional       *schemaNode
+	items            *schemaNode
+	minItems         *int
+	maxItems         *int
+	minLength        *int
+	maxLength        *int
+	pattern          *regexp.Regexp
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+
+	allOf []*schemaNode
+	anyOf []*schemaNode
+	oneOf []*schemaNode
+	not   *schemaNode
+}
+
+// schemaAnnotations are keywords that do not affect validation.
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "format": true, "readOnly": true, "writeOnly": true,
+	"deprecated": true,
+}
+
+// compileSchemaNode compiles the schema v found at path in the document.
+func compileSchemaNode(v interface{}, path string) (*schemaNode, error) {
+	switch v := v.(type) {
+	case bool:
+		return &schemaNode{reject: !v}, nil
+	case map[string]interface{}:
+		node := &schemaNode{}
+		keywords := make([]string, 0, len(v))
+		for keyword := range v {
+			keywords = append(keywords, keyword)
+		}
+		sort.Strings(keywords)
+		for _, keyword := range keywords {
+			if err := node.compileKeyword(keyword, v[keyword], path+"/"+keyword); err != nil {
+				return nil, err
+			}
+		}
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", schemaPath(path))
+	}
+}
+
+// compileKeyword sets the constraint for one keyword of a schema object.
+func (n *schemaNode) compileKeyword(keyword string, value interface{}, path string) error {
+	var err error
+	switch keyword {
+	case "type":
+		n.types, err = schemaStrings(value, path)
+	case "enum":
+		values, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: must be an array", schemaPath(path))
+		}
+		n.enum = values
+	case "const":
+		n.constant, n.hasConst = value, true
+	case "properties":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: must be an object", schemaPath(path))
+		}
+		n.properties = make(map[string]*schemaNode, len(fields))
+		for name, sub := range fields {
+			if n.properties[name], err = compileSchemaNode(sub, path+"/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	case "required":
+		n.required, err = schemaStrings(value, path)
+	case "additionalProperties":
+		n.additional, err = compileSchemaNode(value, path)
+	case "items":
+		n.items, err = compileSchemaNode(value, path)
+	case "minItems":
+		n.minItems, err = schemaCount(value, path)
+	case "maxItems":
+		n.maxItems, err = schemaCount(value, path)
+	case "minLength":
+		n.minLength, err = schemaCount(value, path)
+	case "maxLength":
+		n.maxLength, err = schemaCount(value, path)
+	case "pattern":
+		expr, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: must be a string", schemaPath(path))
+		}
+		if n.pattern, err = regexp.Compile(expr); err != nil {
+			return fmt.Errorf("%s: %w", schemaPath(path), err)
+		}
+	case "minimum":
+		n.minimum, err = schemaNumber(value, path)
+	case "maximum":
+		n.maximum, err = schemaNumber(value, path)
+	case "exclusiveMinimum":
+		n.exclusiveMinimum, err = schemaNumber(value, path)
+	case "exclusiveMaximum":
+		n.exclusiveMaximum, err = schemaNumber(value, path)
+	case "allOf":
+		n.allOf, err = schemaList(value, path)
+	case "anyOf":
+		n.anyOf, err = schemaList(value, path)
+	case "oneOf":
+		n.oneOf, err = schemaList(value, path)
+	case "not":
+		n.not, err = compileSchemaNode(value, path)
+	default:
+		if !schemaAnnotations[keyword] {
+			return fmt.Errorf("%s: unsupported keyword %q", schemaPath(path), keyword)
+		}
+	}
+	return err
+}
+
+// validate appends the violations of value, found at path, to violations.
+func (n *schemaNode) validate(value interface{}, path string, violations *[]SchemaViolation) {
+	fail := func(format string, args ...interface{}) {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if n.reject {
+		fail("no value is allowed here")
+		return
+	}
+
+	if len(n.types) > 0 {
+		matched := false
+		for _, t := range n.types {
+			if jsonTypeMatches(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("expected %s, got %s", strings.Join(n.types, " or "), jsonTypeOf(value))
+			return
+		}
+	}
+	if n.enum != nil && !containsJSONValue(n.enum, value) {
+		fail("value is not one of the allowed values")
+	}
+	if n.hasConst && !reflect.DeepEqual(n.constant, value) {
+		fail("value does not equal the required constant")
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range n.required {
+			if _, ok := value[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			childPath := path + "/" + escapePointer(name)
+			if sub, ok := n.properties[name]; ok {
+				sub.validate(value[name], childPath, violations)
+			} else if n.additional != nil {
+				if n.additional.reject {
+					fail("property %q is not allowed", name)
+				} else {
+					n.additional.validate(value[name], childPath, violations)
+				}
+			}
+		}
+	case []interface{}:
+		if n.minItems != nil && len(value) < *n.minItems {
+			fail("expected at least %d items, got %d", *n.minItems, len(value))
+		}
+		if n.maxItems != nil && len(value) > *n.maxItems {
+			fail("expected at most %d items, got %d", *n.maxItems, len(value))
+		}
+		if n.items != nil {
+			for i, item := range value {
+				n.items.validate(item, path+"/"+strconv.Itoa(i), violations)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(value)
+		if n.minLength != nil && length < *n.minLength {
+			fail("expected at least %d characters, got %d", *n.minLength, length)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			fail("expected at most %d characters, got %d", *n.maxLength, length)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(value) {
+			fail("does not match pattern %s", n.pattern)
+		}
+	case float64:
+		if n.minimum != nil && value < *n.minimum {
+			fail("must be at least %v", *n.minimum)
+		}
+		if n.maximum != nil && value > *n.maximum {
+			fail("must be at most %v", *n.maximum)
+		}
+		if n.exclusiveMinimum != nil && value <= *n.exclusiveMinimum {
+			fail("must be greater than %v", *n.exclusiveMinimum)
+		}
+		if n.exclusiveMaximum != nil && value >= *n.exclusiveMaximum {
+			fail("must be less than %v", *n.exclusiveMaximum)
+		}
+	}
+
+	for _, sub := range n.allOf {
+		sub.validate(value, path, violations)
+	}
+	if len(n.anyOf) > 0 && countMatching(n.anyOf, value, path) == 0 {
+		fail("does not match any of the allowed schemas")
+	}
+	if len(n.oneOf) > 0 {
+		if matches := countMatching(n.oneOf, value, path); matches != 1 {
+			fail("must match exactly one schema, matched %d", matches)
+		}
+	}
+	if n.not != nil && countMatching([]*schemaNode{n.not}, value, path) == 1 {
+		fail("matches a disallowed schema")
+	}
+}
+
+// countMatching returns how many of schemas value satisfies.
+func countMatching(schemas []*schemaNode, value interface{}, path string) int {
+	matches := 0
+	for _, sub := range schemas {
+		var violations []SchemaViolation
+		sub.validate(value, path, &violations)
+		if len(violations) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+// jsonTypeMatches reports whether value has the JSON Schema type t.
+func jsonTypeMatches(t string, value interface{}) bool {
+	if t == "integer" {
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	}
+	return jsonTypeOf(value) == t || (t == "number" && jsonTypeOf(value) == "integer")
+}
+
+// jsonTypeOf returns the JSON Schema type name of a decoded JSON value.
+func jsonTypeOf(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// containsJSONValue reports whether values contains value.
+func containsJSONValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapePointer escapes name for use as a JSON Pointer token.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+// schemaPath formats a location in the schema document for errors.
+func schemaPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// schemaStrings reads a keyword given as a string or an array of strings.
+func schemaStrings(value interface{}, path string) ([]string, error) {
+	if s, ok := value.(string); ok {
+		return []string{s}, nil
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a string or an array of strings", schemaPath(path))
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		if out[i], ok = v.(string); !ok {
+			return nil, fmt.Errorf("%s: must be a string or an array of strings", schemaPath(path))
+		}
+	}
+	return out, nil
+}
+
+// schemaCount reads a keyword given as a non-negative integer.
+func schemaCount(value interface{}, path string) (*int, error) {
+	f, ok := value.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", schemaPath(path))
+	}
+	n := int(f)
+	return &n, nil
+}
+
+// schemaNumber reads a keyword given as a number.
+func schemaNumber(value interface{}, path string) (*float64, error) {
+	f, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a number", schemaPath(path))
+	}
+	return &f, nil
+}
+
+// schemaList reads a keyword given as a non-empty array of schemas.
+func schemaList(value interface{}, path string) ([]*schemaNode, error) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("%s: must be a non-empty array of schemas", schemaPath(path))
+	}
+	out := make([]*schemaNode, len(values))
+	for i, v := range values {
+		var err error
+		if out[i], err = compileSchemaNode(v, path+"/"+strconv.Itoa(i)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
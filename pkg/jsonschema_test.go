@@ -0,0 +1,171 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const diplomaSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Diploma",
+	"type": "object",
+	"required": ["student", "grade"],
+	"additionalProperties": false,
+	"properties": {
+		"student": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string", "minLength": 1},
+				"id": {"type": "string", "pattern": "^S[0-9]+$"}
+			}
+		},
+		"grade": {"type": "number", "minimum": 0, "maximum": 10},
+		"level": {"enum": ["bachelor", "master"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+	}
+}`
+
+func TestPayloadSchemaValidate(t *testing.T) {
+	schema, err := CompilePayloadSchema(diplomaSchema)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		data       string
+		violations []SchemaViolation
+	}{
+		{
+			name: "Valid",
+			data: `{"student":{"name":"Ada","id":"S42"},"grade":9.5,"level":"master","tags":["physics"]}`,
+		},
+		{
+			name:       "Not JSON",
+			data:       "hello",
+			violations: []SchemaViolation{{Message: "payload is not valid JSON"}},
+		},
+		{
+			name: "Missing And Wrong Types",
+			data: `{"student":{"id":"X1"},"grade":"A"}`,
+			violations: []SchemaViolation{
+				{Path: "/grade", Message: "expected number, got string"},
+				{Path: "/student", Message: `missing required property "name"`},
+				{Path: "/student/id", Message: "does not match pattern ^S[0-9]+$"},
+			},
+		},
+		{
+			name: "Constraints",
+			data: `{"student":{"name":""},"grade":11,"level":"phd","tags":["a","b",3],"extra":true}`,
+			violations: []SchemaViolation{
+				{Path: "", Message: `property "extra" is not allowed`},
+				{Path: "/grade", Message: "must be at most 10"},
+				{Path: "/level", Message: "value is not one of the allowed values"},
+				{Path: "/student/name", Message: "expected at least 1 characters, got 0"},
+				{Path: "/tags", Message: "expected at most 2 items, got 3"},
+				{Path: "/tags/2", Message: "expected string, got integer"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate(tc.data)
+			if tc.violations == nil {
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				return
+			}
+			var validationErr *PayloadValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a *PayloadValidationError, but got: %v", err)
+			}
+			if !reflect.DeepEqual(validationErr.Violations, tc.violations) {
+				t.Errorf("Expected violations %v, but got %v", tc.violations, validationErr.Violations)
+			}
+		})
+	}
+}
+
+func TestPayloadSchemaCombinators(t *testing.T) {
+	schema, err := CompilePayloadSchema(`{
+		"oneOf": [{"type": "integer"}, {"type": "string"}],
+		"not": {"const": "forbidden"}
+	}`)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	for data, valid := range map[string]bool{`7`: true, `"ok"`: true, `"forbidden"`: false, `7.5`: false, `null`: false} {
+		if err := schema.Validate(data); (err == nil) != valid {
+			t.Errorf("Expected %s valid=%v, but got error %v", data, valid, err)
+		}
+	}
+}
+
+func TestCompilePayloadSchemaErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		schema string
+	}{
+		{"Invalid JSON", `{"type":`},
+		{"Unsupported Keyword", `{"properties": {"a": {"$ref": "#/defs/a"}}}`},
+		{"Bad Pattern", `{"pattern": "("}`},
+		{"Bad Count", `{"minLength": -1}`},
+		{"Not A Schema", `{"items": 3}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := CompilePayloadSchema(tc.schema); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func TestSubmitCertificateSchema(t *testing.T) {
+	var submitted int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&submitted, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	schema, err := CompilePayloadSchema(diplomaSchema)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithPayloadSchema(schema))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+
+	_, err = acc.SubmitCertificateContext(context.Background(), `{"grade":3}`, privateKey)
+	var validationErr *PayloadValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *PayloadValidationError, but got: %v", err)
+	}
+	if IsRetryable(err) {
+		t.Error("Expected a validation error not to be retryable")
+	}
+	if atomic.LoadInt32(&submitted) != 0 {
+		t.Error("Expected the certificate not to be submitted")
+	}
+
+	anything, _ := CompilePayloadSchema(`true`)
+	if _, err := acc.SubmitCertificateContext(context.Background(), `{"grade":3}`, privateKey, WithSchema(anything)); err != nil {
+		t.Fatalf("Expected the per-call schema to override the account schema, but got: %v", err)
+	}
+	if atomic.LoadInt32(&submitted) != 1 {
+		t.Error("Expected the certificate to be submitted")
+	}
+}
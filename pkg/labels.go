@@ -0,0 +1,56 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// WithLabels attaches labels to a client or account, such as its tenant,
+// environment or cost center, so that operators running many of them can
+// attribute their activity. The labels are copied into the EventInfo of its
+// events, its Usage, and the AuditRecord and LedgerEntry of each of its
+// submissions, and passed to its Logger through the context; see
+// LabelsFromContext. Using the option several times merges the labels.
+func WithLabels(labels map[string]string) Option {
+	return func(c *Client) {
+		if c.labels == nil {
+			c.labels = make(map[string]string, len(labels))
+		}
+		maps.Copy(c.labels, labels)
+	}
+}
+
+// Labels returns a copy of the labels of the client, or nil if it has none.
+func (c *Client) Labels() map[string]string {
+	if len(c.labels) == 0 {
+		return nil
+	}
+	return maps.Clone(c.labels)
+}
+
+// labelsKey is the context key for the labels of the client logging.
+type labelsKey struct{}
+
+// LabelsFromContext returns the labels of the client whose Logger receives
+// ctx, if it has any.
+func LabelsFromContext(ctx context.Context) (map[string]string, bool) {
+	labels, ok := ctx.Value(labelsKey{}).(map[string]string)
+	return labels, ok && len(labels) > 0
+}
+
+// formatLabels renders labels as space-separated key=value pairs, sorted by
+// key.
+func formatLabels(labels map[string]string) string {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(labels[key])
+	}
+	return b.String()
+}
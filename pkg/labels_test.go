@@ -0,0 +1,87 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLabels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var events []Event
+	var logged []map[string]string
+	bus := NewEventBus()
+	bus.Subscribe(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+	ledger := NewMemoryLedger()
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion,
+		WithLabels(map[string]string{"tenant": "acme", "env": "test"}),
+		WithLabels(map[string]string{"env": "prod"}),
+		WithEventBus(bus), WithLedger(ledger),
+		WithLogger(func(ctx context.Context, message string) {
+			labels, _ := LabelsFromContext(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			logged = append(logged, labels)
+		}))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	ctx := context.Background()
+
+	labels := acc.Labels()
+	if len(labels) != 2 || labels["tenant"] != "acme" || labels["env"] != "prod" {
+		t.Fatalf("Expected the merged labels, but got %v", labels)
+	}
+	labels["tenant"] = "changed"
+	if acc.Labels()["tenant"] != "acme" {
+		t.Error("Expected Labels to return a copy")
+	}
+
+	if _, err := acc.SubmitCertificateContext(ctx, "data", strings.Repeat("1", 64)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	acc.logf(ctx, "message")
+
+	mu.Lock()
+	if len(events) == 0 {
+		t.Error("Expected events to be published")
+	}
+	for _, event := range events {
+		if event.Info().Labels["tenant"] != "acme" {
+			t.Errorf("Expected %T to carry the labels, but got %v", event, event.Info().Labels)
+		}
+	}
+	if len(logged) != 1 || logged[0]["env"] != "prod" {
+		t.Errorf("Expected the logger to receive the labels, but got %v", logged)
+	}
+	mu.Unlock()
+
+	if usage := acc.Usage(); usage.Labels["tenant"] != "acme" {
+		t.Errorf("Expected the usage to carry the labels, but got %v", usage.Labels)
+	}
+
+	entries, _ := ledger.Query(ctx, LedgerQuery{Labels: map[string]string{"tenant": "acme"}})
+	if len(entries) != 1 || entries[0].Labels["env"] != "prod" {
+		t.Errorf("Expected the ledger entry to carry the labels, but got %+v", entries)
+	}
+	if entries, _ := ledger.Query(ctx, LedgerQuery{Labels: map[string]string{"tenant": "other"}}); len(entries) != 0 {
+		t.Errorf("Expected no entries for another tenant, but got %+v", entries)
+	}
+
+	if labels, ok := LabelsFromContext(ctx); ok || labels != nil {
+		t.Errorf("Expected no labels in a plain context, but got %v", labels)
+	}
+	if got := formatLabels(map[string]string{"b": "2", "a": "1"}); got != "a=1 b=2" {
+		t.Errorf("Expected a=1 b=2, but got %q", got)
+	}
+}
@@ -0,0 +1,238 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrLedgerEntryNotFound is returned by a Ledger for a transaction it has no
+// entry for.
+var ErrLedgerEntryNotFound = errors.New("ledger entry not found")
+
+// LedgerStatus is the state of a submission recorded in a Ledger.
+type LedgerStatus string
+
+const (
+	// LedgerAccepted is a submission the NAG accepted, awaiting confirmation.
+	LedgerAccepted LedgerStatus = "accepted"
+	// LedgerRejected is a submission the NAG answered with a Result other
+	// than 200, or a transaction the network reported as failed.
+	LedgerRejected LedgerStatus = "rejected"
+	// LedgerFailed is a submission that was signed but failed before the
+	// NAG answered.
+	LedgerFailed LedgerStatus = "failed"
+	// LedgerConfirmed is a transaction included in a block.
+	LedgerConfirmed LedgerStatus = "confirmed"
+	// LedgerExpired is a transaction that was not processed in time.
+	LedgerExpired LedgerStatus = "expired"
+	// LedgerDropped is a transaction the network never indexed.
+	LedgerDropped LedgerStatus = "dropped"
+)
+
+// LedgerTransition is a change of status of a ledger entry.
+type LedgerTransition struct {
+	Status LedgerStatus
+	At     time.Time
+	// Detail is the error or result message behind the transition, if any.
+	Detail string
+}
+
+// LedgerEntry is the record of one signed submission.
+type LedgerEntry struct {
+	TxID        string
+	Address     string
+	Blockchain  string
+	PayloadHash string
+	// Size is the size of the certificate data in bytes.
+	Size          int
+	CorrelationID string
+	Status        LedgerStatus
+	// Result is the NAG result code of the submission, or zero.
+	Result    int
+	Submitted time.Time
+	Updated   time.Time
+	// History lists every status of the entry, oldest first, starting with
+	// the submission's.
+	History []LedgerTransition
+	// Labels are the labels of the account that submitted the transaction;
+	// see WithLabels.
+	Labels map[string]string
+}
+
+// LedgerQuery selects ledger entries. Zero fields match every entry.
+type LedgerQuery struct {
+	Status  LedgerStatus
+	Since   time.Time
+	Address string
+	// Labels selects the entries carrying every one of these labels.
+	Labels map[string]string
+	// Limit caps the number of entries returned; zero returns them all.
+	Limit int
+}
+
+// Matches reports whether entry is selected by q, ignoring Limit.
+func (q LedgerQuery) Matches(entry LedgerEntry) bool {
+	return (q.Status == "" || entry.Status == q.Status) &&
+		(q.Since.IsZero() || !entry.Submitted.Before(q.Since)) &&
+		(q.Address == "" || normalizeHex(entry.Address) == normalizeHex(q.Address)) &&
+		hasLabels(entry.Labels, q.Labels)
+}
+
+// hasLabels reports whether labels include every label of want.
+func hasLabels(labels, want map[string]string) bool {
+	for key, value := range want {
+		if got, ok := labels[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Ledger records every signed submission and its later status transitions,
+// as a local source of truth that can be joined against business data.
+// Implementations must be safe for concurrent use; MemoryLedger keeps
+// entries in memory and the ledger/sqlite module in a SQLite database.
+type Ledger interface {
+	// Record adds the entry of a new submission.
+	Record(ctx context.Context, entry LedgerEntry) error
+	// Transition moves the entry of txID to status, or returns an error
+	// matching ErrLedgerEntryNotFound.
+	Transition(ctx context.Context, txID string, transition LedgerTransition) error
+	// Query returns the entries selected by q, oldest submission first.
+	Query(ctx context.Context, q LedgerQuery) ([]LedgerEntry, error)
+}
+
+// WithLedger records every signed submission in ledger, and the final
+// statuses observed by GetOutcome and WaitForOutcome.
+func WithLedger(ledger Ledger) Option {
+	return func(c *Client) {
+		c.ledger = ledger
+	}
+}
+
+// recordLedger adds the entry of a finished submission attempt described by
+// its audit record. Attempts that failed before signing are not recorded.
+func (c *Client) recordLedger(ctx context.Context, record AuditRecord) {
+	if record.TxID == "" {
+		return
+	}
+	status := LedgerFailed
+	switch record.Outcome {
+	case AuditAccepted:
+		status = LedgerAccepted
+	case AuditRejected:
+		status = LedgerRejected
+	}
+	entry := LedgerEntry{
+		TxID:          record.TxID,
+		Address:       record.Address,
+		Blockchain:    record.Blockchain,
+		PayloadHash:   record.PayloadHash,
+		Size:          record.PayloadSize,
+		CorrelationID: record.CorrelationID,
+		Status:        status,
+		Result:        record.Result,
+		Submitted:     record.Started,
+		Updated:       record.Completed,
+		History:       []LedgerTransition{{Status: status, At: record.Completed, Detail: record.Error}},
+		Labels:        record.Labels,
+	}
+	if err := c.ledger.Record(ctx, entry); err != nil {
+		c.logf(ctx, "failed to record %s in the ledger: %v", record.TxID, err)
+	}
+}
+
+// transitionLedger records the final status of outcome in the ledger.
+// Transactions the ledger does not know, submitted elsewhere, are ignored.
+func (c *Client) transitionLedger(ctx context.Context, outcome *Outcome) {
+	if c.ledger == nil {
+		return
+	}
+	var status LedgerStatus
+	switch outcome.Status {
+	case TxConfirmed:
+		status = LedgerConfirmed
+	case TxFailed:
+		status = LedgerRejected
+	case TxExpired:
+		status = LedgerExpired
+	default:
+		return
+	}
+	c.transitionLedgerTo(ctx, outcome.TxID, LedgerTransition{Status: status, At: time.Now(), Detail: outcome.RawStatus})
+}
+
+// transitionLedgerTo records a transition of txID, ignoring unknown
+// transactions.
+func (c *Client) transitionLedgerTo(ctx context.Context, txID string, transition LedgerTransition) {
+	err := c.ledger.Transition(ctx, txID, transition)
+	if err != nil && !errors.Is(err, ErrLedgerEntryNotFound) {
+		c.logf(ctx, "failed to record the %s status of %s in the ledger: %v", transition.Status, txID, err)
+	}
+}
+
+// MemoryLedger is a Ledger kept in memory.
+type MemoryLedger struct {
+	mu      sync.Mutex
+	entries map[string]*LedgerEntry
+}
+
+// NewMemoryLedger returns an empty MemoryLedger.
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{entries: make(map[string]*LedgerEntry)}
+}
+
+// Record implements Ledger.
+func (l *MemoryLedger) Record(ctx context.Context, entry LedgerEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry.History = append([]LedgerTransition(nil), entry.History...)
+	entry.Labels = maps.Clone(entry.Labels)
+	l.entries[normalizeHex(entry.TxID)] = &entry
+	return nil
+}
+
+// Transition implements Ledger.
+func (l *MemoryLedger) Transition(ctx context.Context, txID string, transition LedgerTransition) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.entries[normalizeHex(txID)]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrLedgerEntryNotFound, txID)
+	}
+	entry.Status = transition.Status
+	entry.Updated = transition.At
+	entry.History = append(entry.History, transition)
+	return nil
+}
+
+// Query implements Ledger.
+func (l *MemoryLedger) Query(ctx context.Context, q LedgerQuery) ([]LedgerEntry, error) {
+	l.mu.Lock()
+	var entries []LedgerEntry
+	for _, entry := range l.entries {
+		if q.Matches(*entry) {
+			copied := *entry
+			copied.History = append([]LedgerTransition(nil), entry.History...)
+			copied.Labels = maps.Clone(entry.Labels)
+			entries = append(entries, copied)
+		}
+	}
+	l.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Submitted.Equal(entries[j].Submitted) {
+			return entries[i].Submitted.Before(entries[j].Submitted)
+		}
+		return entries[i].TxID < entries[j].TxID
+	})
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[:q.Limit]
+	}
+	return entries, nil
+}
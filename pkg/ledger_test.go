@@ -0,0 +1,104 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLedgerSubmissions(t *testing.T) {
+	submit := `{"Result":200,"Response":{"TxID":"abc"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.Contains(r.URL.Path, "GetTransactionbyID") {
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","BlockID":"7","BlockTimestamp":"2025:01:01-00:00:00","Position":0,"NodeID":"n1"}}`))
+			return
+		}
+		w.Write([]byte(submit))
+	}))
+	defer server.Close()
+
+	ledger := NewMemoryLedger()
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithLedger(ledger))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+	ctx := context.Background()
+
+	acc.SubmitCertificate("data", privateKey)
+	accepted := acc.LatestTxID
+	submit = `{"Result":108,"Response":"Invalid signature"}`
+	acc.SubmitCertificate("other", privateKey)
+	acc.SubmitCertificate("data", "not a key")
+
+	entries, _ := ledger.Query(ctx, LedgerQuery{})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, but got %d", len(entries))
+	}
+	if entries[0].TxID != accepted || entries[0].Status != LedgerAccepted || entries[0].PayloadHash != payloadHash("data") {
+		t.Errorf("Expected an accepted entry for %s, but got %+v", accepted, entries[0])
+	}
+	if entries[1].TxID == accepted || entries[1].Status != LedgerRejected || entries[1].Result != 108 {
+		t.Errorf("Expected a rejected entry, but got %+v", entries[1])
+	}
+
+	if _, err := acc.GetOutcome(ctx, accepted); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	// Transactions submitted elsewhere are ignored.
+	if _, err := acc.GetOutcome(ctx, "0x"+strings.Repeat("f", 64)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	confirmed, _ := ledger.Query(ctx, LedgerQuery{Status: LedgerConfirmed})
+	if len(confirmed) != 1 || confirmed[0].TxID != accepted {
+		t.Fatalf("Expected %s to be confirmed, but got %+v", accepted, confirmed)
+	}
+	history := confirmed[0].History
+	if len(history) != 2 || history[0].Status != LedgerAccepted || history[1].Detail != "Executed" {
+		t.Errorf("Expected the accepted and confirmed transitions, but got %+v", history)
+	}
+}
+
+func TestMemoryLedger(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	ledger := NewMemoryLedger()
+	ledger.Record(ctx, LedgerEntry{TxID: "0xAA", Address: "0xABC", Status: LedgerAccepted, Submitted: start})
+	ledger.Record(ctx, LedgerEntry{TxID: "bb", Address: "abc", Status: LedgerRejected, Submitted: start.Add(time.Second)})
+	ledger.Record(ctx, LedgerEntry{TxID: "cc", Address: "def", Status: LedgerAccepted, Submitted: start.Add(2 * time.Second)})
+
+	if err := ledger.Transition(ctx, "aa", LedgerTransition{Status: LedgerConfirmed, At: start}); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if err := ledger.Transition(ctx, "dd", LedgerTransition{Status: LedgerConfirmed}); !errors.Is(err, ErrLedgerEntryNotFound) {
+		t.Errorf("Expected ErrLedgerEntryNotFound, but got %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		query    LedgerQuery
+		expected []string
+	}{
+		{"All", LedgerQuery{}, []string{"0xAA", "bb", "cc"}},
+		{"Status", LedgerQuery{Status: LedgerAccepted}, []string{"cc"}},
+		{"Address", LedgerQuery{Address: "ABC"}, []string{"0xAA", "bb"}},
+		{"Since", LedgerQuery{Since: start.Add(time.Second)}, []string{"bb", "cc"}},
+		{"Limit", LedgerQuery{Limit: 2}, []string{"0xAA", "bb"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, _ := ledger.Query(ctx, tc.query)
+			var ids []string
+			for _, entry := range entries {
+				ids = append(ids, entry.TxID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Expected %v, but got %v", tc.expected, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+// Package loadtest measures the certification throughput of a NAG, or of the
+// simnag simulator, by submitting certificates from an account at a
+// configurable rate and reporting throughput, latency percentiles and a
+// breakdown of the errors met.
+//
+//	report, err := loadtest.Run(ctx, account, privateKey, loadtest.Config{
+//		Rate:     50,
+//		Duration: time.Minute,
+//		Confirm:  true,
+//	})
+//	fmt.Print(report)
+//
+// The cepload command runs the same load from the command line.
+package loadtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+const (
+	// DefaultConcurrency is how many submissions are kept in flight at most
+	// when Config.Concurrency is not set.
+	DefaultConcurrency = 16
+	// DefaultPayloadSize is the size in bytes of the generated certificate
+	// data when Config.PayloadSize is not set.
+	DefaultPayloadSize = 64
+	// DefaultConfirmTimeoutSec bounds the wait for each confirmation when
+	// Config.ConfirmTimeoutSec is not set.
+	DefaultConfirmTimeoutSec = 60
+)
+
+// Config describes the load to apply. At least one of Duration and Count
+// must be set; the run ends at whichever is reached first.
+type Config struct {
+	// Rate is how many submissions are started per second. Zero starts them
+	// as fast as Concurrency allows.
+	Rate float64
+	// Duration is how long submissions are started for.
+	Duration time.Duration
+	// Count is how many submissions are started.
+	Count int
+	// Concurrency caps the submissions in flight, including those waiting
+	// for confirmation. When it is reached, submissions fall behind Rate.
+	Concurrency int
+	// PayloadSize is the size in bytes of the random data certified by each
+	// submission.
+	PayloadSize int
+	// Payload, if set, returns the data of the i-th submission instead.
+	Payload func(i int) string
+	// Confirm also waits for every accepted certificate to leave the
+	// Pending state, for at most ConfirmTimeoutSec seconds, and reports the
+	// confirmation latency.
+	Confirm           bool
+	ConfirmTimeoutSec int
+}
+
+// Latencies summarises a set of durations.
+type Latencies struct {
+	Count int
+	Min   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// Report is the result of a load test.
+type Report struct {
+	// Elapsed is the time from the first submission to the end of the last.
+	Elapsed time.Duration
+	// Submitted counts the submissions started; Accepted those the NAG
+	// accepted and Failed the others.
+	Submitted int
+	Accepted  int
+	Failed    int
+	// Confirmed counts the accepted certificates observed as confirmed when
+	// Config.Confirm is set; the others are counted in Errors.
+	Confirmed int
+	// Throughput is the number of accepted submissions per second of
+	// Elapsed.
+	Throughput float64
+	// Submit is the latency of signing and submitting a certificate, for
+	// accepted submissions. Confirm is the latency from acceptance to
+	// confirmation.
+	Submit  Latencies
+	Confirm Latencies
+	// Errors counts failures by kind, such as "result 108", "http 503" or
+	// "timeout"; see ErrorKind.
+	Errors map[string]int
+
+	confirming bool
+}
+
+// notConfirmedError reports an accepted transaction that reached a final
+// status other than confirmed.
+type notConfirmedError struct {
+	status cep.TxStatus
+}
+
+func (e *notConfirmedError) Error() string {
+	return "transaction " + strings.ToLower(e.status.String())
+}
+
+// Run applies the load described by config with account, signing with
+// privateKey, and reports the results. It returns early, with the results so
+// far, when ctx is done; submissions in flight are then cut off and counted
+// as errors.
+func Run(ctx context.Context, account *cep.CEPAccount, privateKey string, config Config) (*Report, error) {
+	if config.Duration <= 0 && config.Count <= 0 {
+		return nil, errors.New("a duration or a count is required")
+	}
+	if config.Rate < 0 {
+		return nil, fmt.Errorf("invalid rate %v", config.Rate)
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	payload := config.Payload
+	if payload == nil {
+		size := config.PayloadSize
+		if size <= 0 {
+			size = DefaultPayloadSize
+		}
+		payload = func(int) string { return randomData(size) }
+	}
+	timeoutSec := config.ConfirmTimeoutSec
+	if timeoutSec <= 0 {
+		timeoutSec = DefaultConfirmTimeoutSec
+	}
+
+	var (
+		mu      sync.Mutex
+		submit  []time.Duration
+		confirm []time.Duration
+		report  = &Report{Errors: make(map[string]int), confirming: config.Confirm}
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		report.Errors[ErrorKind(err)]++
+	}
+
+	start := time.Now()
+	var deadline <-chan time.Time
+	if config.Duration > 0 {
+		timer := time.NewTimer(config.Duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+loop:
+	for i := 0; config.Count <= 0 || i < config.Count; i++ {
+		if config.Rate > 0 {
+			next := start.Add(time.Duration(float64(i) / config.Rate * float64(time.Second)))
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-deadline:
+				break loop
+			case <-time.After(time.Until(next)):
+			}
+		}
+		// A free slot must not win over a context that is already done.
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline:
+			break loop
+		case slots <- struct{}{}:
+		}
+
+		report.Submitted++
+		data := payload(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			began := time.Now()
+			tx, err := account.SignCertificate(ctx, data, privateKey)
+			if err == nil {
+				var response map[string]interface{}
+				response, err = account.Broadcast(ctx, tx)
+				if err == nil {
+					err = resultError(response)
+				}
+			}
+			if err != nil {
+				mu.Lock()
+				report.Failed++
+				mu.Unlock()
+				fail(err)
+				return
+			}
+			accepted := time.Now()
+			mu.Lock()
+			report.Accepted++
+			submit = append(submit, accepted.Sub(began))
+			mu.Unlock()
+
+			if !config.Confirm {
+				return
+			}
+			outcome, err := account.WaitForOutcome(ctx, tx.ID, timeoutSec)
+			switch {
+			case err != nil:
+				fail(err)
+			case outcome.Status != cep.TxConfirmed:
+				fail(&notConfirmedError{status: outcome.Status})
+			default:
+				mu.Lock()
+				report.Confirmed++
+				confirm = append(confirm, time.Since(accepted))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.Elapsed = time.Since(start)
+	if report.Elapsed > 0 {
+		report.Throughput = float64(report.Accepted) / report.Elapsed.Seconds()
+	}
+	report.Submit = summarize(submit)
+	report.Confirm = summarize(confirm)
+	return report, nil
+}
+
+// ErrorKind names the kind of err for the error breakdown of a Report: the
+// NAG result code, the HTTP status, "throttled", "timeout", "canceled",
+// "payload too large", the final status of a transaction that did not
+// confirm, such as "transaction failed", or "other".
+func ErrorKind(err error) string {
+	var resultErr *cep.ResultError
+	var statusErr *cep.StatusError
+	var throttled *cep.ThrottledError
+	var tooLarge *cep.PayloadTooLargeError
+	var notConfirmed *notConfirmedError
+	switch {
+	case errors.As(err, &resultErr):
+		return fmt.Sprintf("result %d", resultErr.Result)
+	case errors.As(err, &throttled):
+		return "throttled"
+	case errors.As(err, &statusErr):
+		return fmt.Sprintf("http %d", statusErr.StatusCode)
+	case errors.As(err, &tooLarge):
+		return "payload too large"
+	case errors.As(err, &notConfirmed):
+		return notConfirmed.Error()
+	// WaitForOutcome reports its own timeout without a sentinel error.
+	case errors.Is(err, context.DeadlineExceeded), strings.HasSuffix(err.Error(), "timeout exceeded"):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "other"
+	}
+}
+
+// resultError returns a *cep.ResultError for a submission response whose
+// Result is not 200.
+func resultError(response map[string]interface{}) error {
+	result, _ := response["Result"].(float64)
+	if result == 200 {
+		return nil
+	}
+	message, _ := response["Response"].(string)
+	return &cep.ResultError{Endpoint: "AddTransaction", Result: int(result), Message: message}
+}
+
+// summarize returns the Latencies of durations.
+func summarize(durations []time.Duration) Latencies {
+	if len(durations) == 0 {
+		return Latencies{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	percentile := func(p float64) time.Duration {
+		return durations[int(math.Ceil(p*float64(len(durations))))-1]
+	}
+	return Latencies{
+		Count: len(durations),
+		Min:   durations[0],
+		Mean:  total / time.Duration(len(durations)),
+		P50:   percentile(0.50),
+		P90:   percentile(0.90),
+		P99:   percentile(0.99),
+		Max:   durations[len(durations)-1],
+	}
+}
+
+// randomData returns size bytes of random hex text, so every certificate is
+// unique.
+func randomData(size int) string {
+	raw := make([]byte, (size+1)/2)
+	rand.Read(raw)
+	return hex.EncodeToString(raw)[:size]
+}
+
+// String formats the latencies on one line.
+func (l Latencies) String() string {
+	if l.Count == 0 {
+		return "n/a"
+	}
+	round := func(d time.Duration) time.Duration { return d.Round(time.Microsecond) }
+	return fmt.Sprintf("min %v  mean %v  p50 %v  p90 %v  p99 %v  max %v",
+		round(l.Min), round(l.Mean), round(l.P50), round(l.P90), round(l.P99), round(l.Max))
+}
+
+// String formats the report for a terminal.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "elapsed     %v\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(&b, "submitted   %d (accepted %d, failed %d)\n", r.Submitted, r.Accepted, r.Failed)
+	if r.confirming {
+		fmt.Fprintf(&b, "confirmed   %d\n", r.Confirmed)
+	}
+	fmt.Fprintf(&b, "throughput  %.2f/s\n", r.Throughput)
+	fmt.Fprintf(&b, "submit      %v\n", r.Submit)
+	if r.Confirm.Count > 0 {
+		fmt.Fprintf(&b, "confirm     %v\n", r.Confirm)
+	}
+	kinds := make([]string, 0, len(r.Errors))
+	for kind := range r.Errors {
+		kinds = append(kinds, kind)
+	}
+	// The most frequent errors come first.
+	sort.Slice(kinds, func(i, j int) bool {
+		if r.Errors[kinds[i]] != r.Errors[kinds[j]] {
+			return r.Errors[kinds[i]] > r.Errors[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+	for _, kind := range kinds {
+		fmt.Fprintf(&b, "error       %s: %d\n", kind, r.Errors[kind])
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/simnag"
+)
+
+var privateKey = strings.Repeat("1", 64)
+
+func newAccount(t *testing.T, config simnag.Config) *cep.CEPAccount {
+	t.Helper()
+	acc := cep.NewCEPAccount(simnag.NAGURL, cep.DefaultChain, cep.LibVersion, simnag.New(config).Option(),
+		cep.WithLogger(func(context.Context, string) {}),
+		cep.WithDefaultPollStrategy(cep.FixedPoll{Interval: time.Millisecond}))
+	if err := acc.Open("0x" + strings.Repeat("a", 64)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if _, err := acc.UpdateAccountContext(context.Background()); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	return acc
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Count", func(t *testing.T) {
+		acc := newAccount(t, simnag.Config{})
+		report, err := Run(ctx, acc, privateKey, Config{Count: 20, Concurrency: 1, Confirm: true})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if report.Submitted != 20 || report.Accepted != 20 || report.Confirmed != 20 || report.Failed != 0 {
+			t.Errorf("Expected 20 confirmed submissions, but got %+v", report)
+		}
+		if report.Submit.Count != 20 || report.Confirm.Count != 20 || report.Throughput <= 0 {
+			t.Errorf("Expected latencies and throughput for every submission, but got %+v", report)
+		}
+		if len(report.Errors) != 0 {
+			t.Errorf("Expected no errors, but got %v", report.Errors)
+		}
+		if s := report.String(); !strings.Contains(s, "confirmed   20") {
+			t.Errorf("Expected the report to show the confirmations, but got:\n%s", s)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		acc := newAccount(t, simnag.Config{RejectRate: 1, Seed: 1})
+		report, err := Run(ctx, acc, privateKey, Config{Count: 5})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if report.Accepted != 0 || report.Failed != 5 || report.Errors["result 108"] != 5 {
+			t.Errorf("Expected 5 rejections, but got %+v", report)
+		}
+		if s := report.String(); !strings.Contains(s, "error       result 108: 5") {
+			t.Errorf("Expected the report to break the errors down, but got:\n%s", s)
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		acc := newAccount(t, simnag.Config{FailRate: 1, Seed: 1})
+		report, err := Run(ctx, acc, privateKey, Config{Count: 3, Concurrency: 1, Confirm: true})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if report.Accepted != 3 || report.Confirmed != 0 || report.Errors["transaction failed"] != 3 {
+			t.Errorf("Expected 3 failed transactions, but got %+v", report)
+		}
+	})
+
+	t.Run("Duration", func(t *testing.T) {
+		acc := newAccount(t, simnag.Config{})
+		report, err := Run(ctx, acc, privateKey, Config{Rate: 100, Duration: 100 * time.Millisecond, Concurrency: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		// The rate allows 10 submissions, plus one started at time zero.
+		if report.Submitted == 0 || report.Submitted > 11 {
+			t.Errorf("Expected the rate to pace the submissions, but got %d", report.Submitted)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		acc := newAccount(t, simnag.Config{})
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		report, err := Run(cancelCtx, acc, privateKey, Config{Count: 5})
+		if err != nil || report.Submitted != 0 {
+			t.Errorf("Expected nothing to be submitted, but got %+v, %v", report, err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		acc := newAccount(t, simnag.Config{})
+		for _, config := range []Config{{}, {Count: 1, Rate: -1}} {
+			if _, err := Run(ctx, acc, privateKey, config); err == nil {
+				t.Errorf("Expected an error for %+v, but got nil", config)
+			}
+		}
+	})
+}
+
+func TestErrorKind(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Result", &cep.ResultError{Result: 108}, "result 108"},
+		{"Status", fmt.Errorf("request: %w", &cep.StatusError{StatusCode: 503}), "http 503"},
+		{"NotConfirmed", &notConfirmedError{status: cep.TxFailed}, "transaction failed"},
+		{"Deadline", context.DeadlineExceeded, "timeout"},
+		{"Canceled", context.Canceled, "canceled"},
+		{"Other", errors.New("boom"), "other"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if kind := ErrorKind(tc.err); kind != tc.expected {
+				t.Errorf("Expected %q, but got %q", tc.expected, kind)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	l := summarize(durations)
+	expected := Latencies{
+		Count: 100,
+		Min:   time.Millisecond,
+		Mean:  50500 * time.Microsecond,
+		P50:   50 * time.Millisecond,
+		P90:   90 * time.Millisecond,
+		P99:   99 * time.Millisecond,
+		Max:   100 * time.Millisecond,
+	}
+	if l != expected {
+		t.Errorf("Expected %+v, but got %+v", expected, l)
+	}
+	if summarize(nil).String() != "n/a" {
+		t.Errorf("Expected no latencies to print as n/a, but got %q", summarize(nil).String())
+	}
+}
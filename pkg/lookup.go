@@ -0,0 +1,72 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// DefaultSearchDepth is how many of the latest blocks GetTransactionByHash
+// searches.
+const DefaultSearchDepth = 32
+
+// ErrTransactionNotFound is returned by GetTransactionByHash when the
+// transaction is not in the searched blocks.
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// WithSearchDepth sets the client's SearchDepth.
+func WithSearchDepth(blocks int) Option {
+	return func(c *Client) {
+		c.SearchDepth = blocks
+	}
+}
+
+// GetTransactionByHash retrieves a transaction by its ID alone, without the
+// caller naming the block that holds it: the latest SearchDepth blocks are
+// searched with the GetTransactionbyID endpoint. It returns the transaction
+// itself, the Response of the NAG's answer. A transaction that is not in the
+// searched blocks fails with ErrTransactionNotFound; any other Result than 200
+// with a *ResultError.
+func (c *Client) GetTransactionByHash(ctx context.Context, txID string) (transaction map[string]interface{}, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	start, end, err := c.searchWindow(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine the blocks to search: %w", err)
+	}
+	data, err := c.GetTransactionByIDContext(ctx, txID, strconv.FormatInt(start, 10), strconv.FormatInt(end, 10))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.resultError("GetTransactionbyID", data); err != nil {
+		if newOutcome(txID, data).Status == TxNotFound {
+			return nil, fmt.Errorf("%w: %s in blocks %d to %d", ErrTransactionNotFound, txID, start, end)
+		}
+		return nil, err
+	}
+	transaction, ok := data["Response"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("GetTransactionbyID returned %T, not a transaction", data["Response"])
+	}
+	return transaction, nil
+}
+
+// searchWindow returns the first and last of the latest SearchDepth blocks,
+// or of the latest DefaultSearchDepth blocks when SearchDepth is not
+// positive.
+func (c *Client) searchWindow(ctx context.Context) (start, end int64, err error) {
+	depth := int64(c.SearchDepth)
+	if depth <= 0 {
+		depth = DefaultSearchDepth
+	}
+	height, err := c.GetBlockHeight(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if height <= 0 {
+		return 0, 0, errors.New("the blockchain has no blocks")
+	}
+	return max(height-depth, 0), height - 1, nil
+}
@@ -0,0 +1,72 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetTransactionByHash(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.Contains(r.URL.Path, "GetBlockCount") {
+			w.Write([]byte(`{"Result":200,"Response":{"Blocks":100}}`))
+			return
+		}
+		request = nil
+		json.NewDecoder(r.Body).Decode(&request)
+		switch request["TxID"] {
+		case "0xknown":
+			w.Write([]byte(`{"Result":200,"Response":{"ID":"known","Status":"Executed","BlockID":"97"}}`))
+		case "0xmissing":
+			w.Write([]byte(`{"Result":115,"Response":"Transaction Not Found"}`))
+		default:
+			w.Write([]byte(`{"Result":108,"Response":"Invalid Request"}`))
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name          string
+		txID          string
+		opts          []Option
+		expectedStart string
+		expectedErr   error
+		expectedCode  int
+	}{
+		{name: "Found", txID: "0xknown", expectedStart: "68"},
+		{name: "Custom Depth", txID: "0xknown", opts: []Option{WithSearchDepth(10)}, expectedStart: "90"},
+		{name: "Not Found", txID: "0xmissing", expectedStart: "68", expectedErr: ErrTransactionNotFound},
+		{name: "Result Error", txID: "0xbad", expectedStart: "68", expectedCode: 108},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewClient(server.URL, DefaultChain, LibVersion, tc.opts...)
+			tx, err := c.GetTransactionByHash(context.Background(), tc.txID)
+			if request["Start"] != tc.expectedStart || request["End"] != "99" {
+				t.Errorf("Expected blocks %s to 99 to be searched, but got %v to %v", tc.expectedStart, request["Start"], request["End"])
+			}
+			switch {
+			case tc.expectedCode != 0:
+				var resultErr *ResultError
+				if !errors.As(err, &resultErr) || resultErr.Result != tc.expectedCode {
+					t.Errorf("Expected a *ResultError, but got %v", err)
+				}
+			case tc.expectedErr != nil:
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("Expected %v, but got %v", tc.expectedErr, err)
+				}
+			case err != nil:
+				t.Fatalf("Expected no error, but got: %v", err)
+			case tx["BlockID"] != "97":
+				t.Errorf("Expected the transaction, but got %v", tx)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+// Package merkle anchors many records on-chain with a single certificate:
+// the payload hashes of the records are the leaves of a Merkle tree, only
+// the root is certified, and each record gets an inclusion proof tying it to
+// that root.
+//
+//	tree, err := merkle.NewTree(hashes)
+//	...
+//	anchor, err := merkle.Certify(ctx, account, tree, privateKey)
+//	...
+//	proof, err := anchor.Proof(i) // hand to the owner of record i
+//	...
+//	err = proof.Verify()
+//
+// Relying parties holding only a proof check it, and that its root is
+// anchored in a confirmed transaction, with VerifyInclusion.
+//
+// Leaves are hashed as SHA-256(0x00 || hash) and inner nodes as
+// SHA-256(0x01 || left || right), so a leaf can never pass for an inner node.
+// A node left without a sibling at the end of a level is promoted to the
+// next level unchanged rather than paired with itself.
+package merkle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+// RootType is the type field of the certificate data of an anchored root.
+const RootType = "circular.merkle-root"
+
+// Algorithm names the hashing scheme described in the package documentation.
+const Algorithm = "sha256"
+
+// ErrInvalidProof is matched by errors.Is when a proof does not lead from its
+// leaf to its root.
+var ErrInvalidProof = errors.New("invalid merkle proof")
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// HashData returns the hex SHA-256 of data, the payload hash recorded for a
+// certificate in audit records and ledgers, and the leaf value for it.
+func HashData(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// Tree is a Merkle tree over payload hashes.
+type Tree struct {
+	leaves []string
+	// levels holds the node hashes from the leaves up to the root.
+	levels [][][sha256.Size]byte
+}
+
+// NewTree builds the tree of hashes, each the hex SHA-256 of a record such as
+// returned by HashData, in the order given. Proofs are addressed by that
+// order.
+func NewTree(hashes []string) (*Tree, error) {
+	if len(hashes) == 0 {
+		return nil, errors.New("no hashes to build a tree of")
+	}
+	t := &Tree{leaves: make([]string, len(hashes))}
+	level := make([][sha256.Size]byte, len(hashes))
+	for i, hash := range hashes {
+		decoded, err := decodeHash(hash)
+		if err != nil {
+			return nil, fmt.Errorf("leaf %d: %w", i, err)
+		}
+		t.leaves[i] = hex.EncodeToString(decoded[:])
+		level[i] = hashLeaf(decoded)
+	}
+	t.levels = append(t.levels, level)
+	for len(level) > 1 {
+		next := make([][sha256.Size]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+			} else {
+				next = append(next, hashNode(level[i], level[i+1]))
+			}
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Len returns the number of leaves of the tree.
+func (t *Tree) Len() int {
+	return len(t.leaves)
+}
+
+// Root returns the hex root hash of the tree.
+func (t *Tree) Root() string {
+	root := t.levels[len(t.levels)-1][0]
+	return hex.EncodeToString(root[:])
+}
+
+// Proof returns the inclusion proof of leaf i.
+func (t *Tree) Proof(i int) (*Proof, error) {
+	if i < 0 || i >= len(t.leaves) {
+		return nil, fmt.Errorf("leaf %d out of range [0, %d)", i, len(t.leaves))
+	}
+	proof := &Proof{Leaf: t.leaves[i], Index: i, Size: len(t.leaves), Root: t.Root()}
+	index := i
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof.Path = append(proof.Path, hex.EncodeToString(level[sibling][:]))
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// Proof shows that a payload hash is a leaf of a tree with the given root.
+// It is self-contained and meant to be stored as JSON alongside the record.
+type Proof struct {
+	// Leaf is the payload hash of the record.
+	Leaf string `json:"leaf"`
+	// Index is the position of the leaf and Size the number of leaves.
+	Index int `json:"index"`
+	Size  int `json:"size"`
+	// Path holds the sibling hashes from the leaf up to the root. Levels
+	// where the node has no sibling contribute none.
+	Path []string `json:"path"`
+	Root string   `json:"root"`
+	// TxID is the transaction that certified Root, once anchored.
+	TxID string `json:"txId,omitempty"`
+}
+
+// Verify checks that the path leads from Leaf to Root. It does not check that
+// Root is anchored on-chain.
+func (p Proof) Verify() error {
+	leaf, err := decodeHash(p.Leaf)
+	if err != nil {
+		return fmt.Errorf("%w: leaf: %v", ErrInvalidProof, err)
+	}
+	root, err := decodeHash(p.Root)
+	if err != nil {
+		return fmt.Errorf("%w: root: %v", ErrInvalidProof, err)
+	}
+	if p.Index < 0 || p.Index >= p.Size {
+		return fmt.Errorf("%w: index %d out of range [0, %d)", ErrInvalidProof, p.Index, p.Size)
+	}
+
+	hash := hashLeaf(leaf)
+	path := p.Path
+	for index, size := p.Index, p.Size; size > 1; index, size = index/2, (size+1)/2 {
+		if index%2 == 0 && index+1 == size {
+			continue
+		}
+		if len(path) == 0 {
+			return fmt.Errorf("%w: path is too short", ErrInvalidProof)
+		}
+		sibling, err := decodeHash(path[0])
+		if err != nil {
+			return fmt.Errorf("%w: path: %v", ErrInvalidProof, err)
+		}
+		path = path[1:]
+		if index%2 == 0 {
+			hash = hashNode(hash, sibling)
+		} else {
+			hash = hashNode(sibling, hash)
+		}
+	}
+	if len(path) != 0 {
+		return fmt.Errorf("%w: path is too long", ErrInvalidProof)
+	}
+	if hash != root {
+		return fmt.Errorf("%w: path leads to %x, not to the root", ErrInvalidProof, hash)
+	}
+	return nil
+}
+
+// RootRecord is the certificate data of an anchored root.
+type RootRecord struct {
+	Type      string `json:"type"`
+	Algorithm string `json:"algorithm"`
+	Root      string `json:"root"`
+	Size      int    `json:"size"`
+}
+
+// ParseRootRecord decodes certificate data holding an anchored root. The
+// second result is false for any other data.
+func ParseRootRecord(data string) (*RootRecord, bool) {
+	var record RootRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, false
+	}
+	if record.Type != RootType || record.Root == "" {
+		return nil, false
+	}
+	return &record, true
+}
+
+// Anchor is a tree whose root was certified on-chain.
+type Anchor struct {
+	tree *Tree
+	// TxID is the transaction certifying the root, and Response the NAG's
+	// answer to its submission.
+	TxID     string
+	Response map[string]interface{}
+}
+
+// Root returns the anchored root hash.
+func (a *Anchor) Root() string {
+	return a.tree.Root()
+}
+
+// Proof returns the inclusion proof of leaf i, including the anchoring
+// transaction.
+func (a *Anchor) Proof(i int) (*Proof, error) {
+	proof, err := a.tree.Proof(i)
+	if err != nil {
+		return nil, err
+	}
+	proof.TxID = a.TxID
+	return proof, nil
+}
+
+// Certify certifies the root of tree as a RootRecord, signed by account with
+// privateKey, and returns the anchor from which proofs are issued. opts
+// adjust the signing as for SignCertificate; the transaction is broadcast to
+// the account's NAG.
+func Certify(ctx context.Context, account *cep.CEPAccount, tree *Tree, privateKey string, opts ...cep.SubmitOption) (*Anchor, error) {
+	data, err := json.Marshal(RootRecord{Type: RootType, Algorithm: Algorithm, Root: tree.Root(), Size: tree.Len()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal root record: %w", err)
+	}
+	tx, err := account.SignCertificate(ctx, string(data), privateKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	response, err := account.Broadcast(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if result, _ := response["Result"].(float64); result != 200 {
+		return nil, &cep.ResultError{Endpoint: "AddTransaction", Result: int(result), Message: fmt.Sprint(response["Response"])}
+	}
+	return &Anchor{tree: tree, TxID: tx.ID, Response: response}, nil
+}
+
+// decodeHash decodes a hex SHA-256 hash, with or without a 0x prefix.
+func decodeHash(hash string) ([sha256.Size]byte, error) {
+	var decoded [sha256.Size]byte
+	raw, err := hex.DecodeString(utils.HexFix(hash))
+	if err != nil {
+		return decoded, fmt.Errorf("invalid hash hex: %w", err)
+	}
+	if len(raw) != sha256.Size {
+		return decoded, fmt.Errorf("hash is %d bytes, not %d", len(raw), sha256.Size)
+	}
+	copy(decoded[:], raw)
+	return decoded, nil
+}
+
+func hashLeaf(leaf [sha256.Size]byte) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte{leafPrefix}, leaf[:]...))
+}
+
+func hashNode(left, right [sha256.Size]byte) [sha256.Size]byte {
+	input := make([]byte, 0, 1+2*sha256.Size)
+	input = append(input, nodePrefix)
+	input = append(input, left[:]...)
+	input = append(input, right[:]...)
+	return sha256.Sum256(input)
+}
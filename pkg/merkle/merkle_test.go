@@ -0,0 +1,131 @@
+package merkle
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+func hashes(n int) []string {
+	var out []string
+	for i := 0; i < n; i++ {
+		out = append(out, HashData(fmt.Sprintf("record %d", i)))
+	}
+	return out
+}
+
+func TestTree(t *testing.T) {
+	for size := 1; size <= 9; size++ {
+		t.Run(fmt.Sprintf("Size %d", size), func(t *testing.T) {
+			tree, err := NewTree(hashes(size))
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			for i := 0; i < size; i++ {
+				proof, err := tree.Proof(i)
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				if err := proof.Verify(); err != nil {
+					t.Errorf("Expected proof %d to verify, but got: %v", i, err)
+				}
+			}
+		})
+	}
+
+	tree, _ := NewTree(hashes(2))
+	left, right := hashLeaf([32]byte(mustDecode(hashes(2)[0]))), hashLeaf([32]byte(mustDecode(hashes(2)[1])))
+	root := hashNode(left, right)
+	if tree.Root() != hex.EncodeToString(root[:]) {
+		t.Errorf("Expected the root to hash both leaves, but got %s", tree.Root())
+	}
+
+	if _, err := NewTree(nil); err == nil {
+		t.Error("Expected an error for an empty tree, but got nil")
+	}
+	if _, err := NewTree([]string{"abcd"}); err == nil {
+		t.Error("Expected an error for a short hash, but got nil")
+	}
+	if _, err := tree.Proof(2); err == nil {
+		t.Error("Expected an error for a leaf out of range, but got nil")
+	}
+}
+
+func mustDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestProofTampering(t *testing.T) {
+	tree, _ := NewTree(hashes(5))
+	other := HashData("other")
+	testCases := []struct {
+		name   string
+		tamper func(p *Proof)
+	}{
+		{"Leaf", func(p *Proof) { p.Leaf = other }},
+		{"Root", func(p *Proof) { p.Root = other }},
+		{"Index", func(p *Proof) { p.Index = 3 }},
+		{"Size", func(p *Proof) { p.Size = 4 }},
+		{"Path", func(p *Proof) { p.Path[0] = other }},
+		{"Short Path", func(p *Proof) { p.Path = p.Path[1:] }},
+		{"Long Path", func(p *Proof) { p.Path = append(p.Path, other) }},
+		{"Out Of Range", func(p *Proof) { p.Index = 5 }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			proof, _ := tree.Proof(2)
+			tc.tamper(proof)
+			if err := proof.Verify(); !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("Expected ErrInvalidProof, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestCertify(t *testing.T) {
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&submitted)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	account := cep.NewCEPAccount(server.URL, cep.DefaultChain, cep.LibVersion)
+	account.Open("0x" + strings.Repeat("a", 64))
+	tree, _ := NewTree(hashes(3))
+	anchor, err := Certify(context.Background(), account, tree, strings.Repeat("1", 64))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if anchor.TxID == "" || anchor.TxID != submitted["ID"] {
+		t.Errorf("Expected the anchor to carry the submitted ID %v, but got %q", submitted["ID"], anchor.TxID)
+	}
+
+	payload, _ := submitted["Payload"].(string)
+	var object struct{ Data string }
+	json.Unmarshal(mustDecode(payload), &object)
+	record, ok := ParseRootRecord(object.Data)
+	if !ok || record.Root != tree.Root() || record.Size != 3 || record.Algorithm != Algorithm {
+		t.Errorf("Expected the root record to be certified, but got %q", object.Data)
+	}
+
+	proof, err := anchor.Proof(1)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if proof.TxID != anchor.TxID || proof.Verify() != nil {
+		t.Errorf("Expected a valid proof naming the anchor, but got %+v", proof)
+	}
+}
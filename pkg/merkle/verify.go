@@ -0,0 +1,67 @@
+package merkle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+// ErrNotAnchored is matched by errors.Is when the root of a proof is not
+// certified by a confirmed transaction.
+var ErrNotAnchored = errors.New("merkle root is not anchored")
+
+// VerifyInclusion checks that proof leads from its leaf to its root and that
+// the transaction rootTxID is confirmed and certifies that root, as Certify
+// does. rootTxID defaults to the proof's TxID; when both are set they must
+// match. Only the proof and network access are needed, not the batch.
+//
+// The returned outcome is that of the anchoring transaction: relying parties
+// should check its sender, in Transaction["From"], is the party they expect
+// to vouch for the batch.
+func VerifyInclusion(ctx context.Context, client cep.QueryAPI, proof Proof, rootTxID string) (*cep.Outcome, error) {
+	if err := proof.Verify(); err != nil {
+		return nil, err
+	}
+	switch {
+	case rootTxID == "":
+		rootTxID = proof.TxID
+	case proof.TxID != "" && !sameHex(proof.TxID, rootTxID):
+		return nil, fmt.Errorf("%w: proof names transaction %s, not %s", ErrInvalidProof, proof.TxID, rootTxID)
+	}
+	if rootTxID == "" {
+		return nil, errors.New("no anchoring transaction to verify against")
+	}
+
+	outcome, err := client.GetOutcome(ctx, rootTxID)
+	if err != nil {
+		return nil, err
+	}
+	if outcome.Status != cep.TxConfirmed {
+		return nil, fmt.Errorf("%w: transaction %s is %s", ErrNotAnchored, rootTxID, outcome.Status)
+	}
+	data, err := outcome.CertificateData()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotAnchored, err)
+	}
+	record, ok := ParseRootRecord(data)
+	if !ok {
+		return nil, fmt.Errorf("%w: transaction %s does not certify a merkle root", ErrNotAnchored, rootTxID)
+	}
+	if record.Algorithm != Algorithm {
+		return nil, fmt.Errorf("%w: transaction %s uses algorithm %q", ErrNotAnchored, rootTxID, record.Algorithm)
+	}
+	if !sameHex(record.Root, proof.Root) || record.Size != proof.Size {
+		return nil, fmt.Errorf("%w: transaction %s certifies root %s of %d leaves", ErrNotAnchored, rootTxID, record.Root, record.Size)
+	}
+	return outcome, nil
+}
+
+// sameHex reports whether two hex strings are equal, ignoring case and 0x
+// prefixes.
+func sameHex(a, b string) bool {
+	return strings.EqualFold(utils.HexFix(a), utils.HexFix(b))
+}
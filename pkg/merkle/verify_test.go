@@ -0,0 +1,81 @@
+package merkle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+func TestVerifyInclusion(t *testing.T) {
+	tree, _ := NewTree(hashes(4))
+	other, _ := NewTree(hashes(5))
+	rootData := func(tree *Tree) string {
+		data, _ := json.Marshal(RootRecord{Type: RootType, Algorithm: Algorithm, Root: tree.Root(), Size: tree.Len()})
+		payload, _ := json.Marshal(map[string]string{"data": string(data)})
+		return utils.StringToHex(string(payload))
+	}
+	transactions := map[string]string{
+		"anchored": `"Status":"Executed","Payload":"` + rootData(tree) + `"`,
+		"pending":  `"Status":"Pending","Payload":"` + rootData(tree) + `"`,
+		"other":    `"Status":"Executed","Payload":"` + rootData(other) + `"`,
+		"plain":    `"Status":"Executed","Payload":"` + utils.StringToHex(`{"data":"hello"}`) + `"`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct{ TxID string }
+		json.NewDecoder(r.Body).Decode(&request)
+		fields, ok := transactions[utils.HexFix(request.TxID)]
+		if !ok {
+			w.Write([]byte(`{"Result":118,"Response":"Transaction Not Found"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"Result":200,"Response":{"ID":"%s","From":"0xissuer","BlockID":"7","BlockTimestamp":"2025:01:01-00:00:00","Position":0,"NodeID":"n",%s}}`,
+			request.TxID, fields)
+	}))
+	defer server.Close()
+	client := cep.NewClient(server.URL, cep.DefaultChain, cep.LibVersion)
+
+	valid, _ := tree.Proof(3)
+	tampered, _ := tree.Proof(3)
+	tampered.Leaf = HashData("forged")
+	anchored := *valid
+	anchored.TxID = "anchored"
+
+	testCases := []struct {
+		name        string
+		proof       Proof
+		rootTxID    string
+		expectedErr error
+	}{
+		{name: "Anchored", proof: *valid, rootTxID: "anchored"},
+		{name: "TxID From Proof", proof: anchored},
+		{name: "Tampered", proof: *tampered, rootTxID: "anchored", expectedErr: ErrInvalidProof},
+		{name: "Mismatched TxID", proof: anchored, rootTxID: "other", expectedErr: ErrInvalidProof},
+		{name: "Pending", proof: *valid, rootTxID: "pending", expectedErr: ErrNotAnchored},
+		{name: "Other Root", proof: *valid, rootTxID: "other", expectedErr: ErrNotAnchored},
+		{name: "Not A Root", proof: *valid, rootTxID: "plain", expectedErr: ErrNotAnchored},
+		{name: "Not Found", proof: *valid, rootTxID: "missing", expectedErr: ErrNotAnchored},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outcome, err := VerifyInclusion(context.Background(), client, tc.proof, tc.rootTxID)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error %v, but got %v", tc.expectedErr, err)
+			}
+			if tc.expectedErr == nil && outcome.Transaction["From"] != "0xissuer" {
+				t.Errorf("Expected the anchoring transaction, but got %+v", outcome)
+			}
+		})
+	}
+
+	if _, err := VerifyInclusion(context.Background(), client, *valid, ""); err == nil || !strings.Contains(err.Error(), "no anchoring transaction") {
+		t.Errorf("Expected an error without an anchoring transaction, but got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// WithNetworkURL sets the client's NetworkURL, the discovery endpoint used by
+// SetNetwork and DiscoverNAG, such as a staging environment's or a mirror
+// reachable from an air-gapped network.
+func WithNetworkURL(networkURL string) Option {
+	return func(c *Client) {
+		c.NetworkURL = networkURL
+	}
+}
+
+// WithNetworks maps network identifiers to NAG URLs. SetNetwork and
+// DiscoverNAG use the mapping of a network when there is one, without
+// contacting the discovery endpoint, and discover the others as usual. Use
+// LoadNetworks to read the mapping from a file.
+func WithNetworks(networks map[string]string) Option {
+	return func(c *Client) {
+		if c.networks == nil {
+			c.networks = make(map[string]string, len(networks))
+		}
+		for network, nag := range networks {
+			c.networks[network] = nag
+		}
+	}
+}
+
+// LoadNetworks reads a static network-to-NAG mapping for WithNetworks from the
+// JSON file at path, an object whose keys are network identifiers and whose
+// values are NAG URLs:
+//
+//	{
+//		"staging": "https://nag.staging.internal/NAG.php?cep=",
+//		"testnet": "https://nag.mirror.internal/NAG.php?cep="
+//	}
+func LoadNetworks(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read networks file: %w", err)
+	}
+	var networks map[string]string
+	if err := json.Unmarshal(data, &networks); err != nil {
+		return nil, fmt.Errorf("failed to decode networks file %s: %w", path, err)
+	}
+	for network, nag := range networks {
+		nagURL, err := url.Parse(nag)
+		if err != nil || (nagURL.Scheme != "http" && nagURL.Scheme != "https") || nagURL.Host == "" {
+			return nil, fmt.Errorf("networks file %s: invalid NAG URL %q for network %q", path, nag, network)
+		}
+	}
+	return networks, nil
+}
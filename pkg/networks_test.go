@@ -0,0 +1,66 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoadNetworks(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		expectedErr bool
+	}{
+		{"Valid", `{"staging":"https://nag.staging.internal/NAG.php?cep="}`, false},
+		{"Not JSON", `staging=https://nag.staging.internal`, true},
+		{"Invalid URL", `{"staging":"nag.staging.internal"}`, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "networks.json")
+			os.WriteFile(path, []byte(tc.content), 0o600)
+			networks, err := LoadNetworks(path)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Expected error %v, but got %v", tc.expectedErr, err)
+			}
+			if !tc.expectedErr && networks["staging"] != "https://nag.staging.internal/NAG.php?cep=" {
+				t.Errorf("Expected the staging mapping, but got %v", networks)
+			}
+		})
+	}
+	if _, err := LoadNetworks(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file, but got nil")
+	}
+}
+
+func TestNetworkOverrides(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"status":"success","url":"https://discovered.example/NAG.php?cep="}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount("", DefaultChain, LibVersion,
+		WithNetworkURL(server.URL+"/getNAG?network="),
+		WithNetworks(map[string]string{"staging": "https://nag.staging.internal/NAG.php?cep="}))
+	ctx := context.Background()
+
+	if err := acc.SetNetworkContext(ctx, "staging"); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if acc.NAGURL != "https://nag.staging.internal/NAG.php?cep=" || requests.Load() != 0 {
+		t.Errorf("Expected the static mapping without discovery, but got %s after %d requests", acc.NAGURL, requests.Load())
+	}
+	if err := acc.SetNetworkContext(ctx, "testnet"); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if acc.NAGURL != "https://discovered.example/NAG.php?cep=" || requests.Load() != 1 {
+		t.Errorf("Expected unmapped networks to be discovered at the custom URL, but got %s", acc.NAGURL)
+	}
+}
@@ -0,0 +1,250 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// nodeErrorPenalty is added to a node's latency, scaled by its recent error
+// rate, when ranking nodes. A node failing every request ranks behind any
+// node that answers within this long.
+const nodeErrorPenalty = 2 * time.Second
+
+// nodeSmoothing is the weight of the newest sample in a node's moving
+// averages.
+const nodeSmoothing = 0.3
+
+// WithNAGNodes adds gateway nodes that serve the same network as the client's
+// NAG URL. Every request addressed to the NAG is then sent to the node with
+// the best recent latency and error rate, and a failed attempt that is
+// retried may move to another node. Requests for a registered chain with its
+// own NAG URL are not rerouted. See StartNodeProbing to keep the measurements
+// of idle nodes current.
+func WithNAGNodes(urls ...string) Option {
+	return func(c *Client) {
+		if c.nodes == nil {
+			c.nodes = &nodePool{stats: make(map[string]*nodeStats)}
+		}
+		for _, u := range urls {
+			c.nodes.extra = append(c.nodes.extra, strings.TrimRight(u, "/"))
+		}
+	}
+}
+
+// NodeStats describes what the client has measured about a gateway node.
+type NodeStats struct {
+	URL string
+	// Latency is the moving average time to response headers.
+	Latency time.Duration
+	// ErrorRate is the moving average share of failed requests, from 0 to 1.
+	ErrorRate float64
+	// Samples is the number of requests measured.
+	Samples int
+}
+
+// nodeStats holds the moving averages of a node.
+type nodeStats struct {
+	latency   time.Duration
+	errorRate float64
+	samples   int
+}
+
+// score ranks a node; lower is better. Unmeasured nodes score zero so they
+// are tried.
+func (s *nodeStats) score() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return s.latency + time.Duration(s.errorRate*float64(nodeErrorPenalty))
+}
+
+// nodePool tracks the gateway nodes of a client.
+type nodePool struct {
+	mu    sync.Mutex
+	extra []string
+	stats map[string]*nodeStats
+}
+
+// urls returns the client's NAG URL followed by the extra nodes.
+func (p *nodePool) urls(primary string) []string {
+	return append([]string{strings.TrimRight(primary, "/")}, p.extra...)
+}
+
+// match returns the node that requestURL is addressed to, or an empty string
+// if it targets none of them.
+func (p *nodePool) match(primary, requestURL string) string {
+	for _, n := range p.urls(primary) {
+		if n != "" && strings.HasPrefix(requestURL, n+"/") {
+			return n
+		}
+	}
+	return ""
+}
+
+// best returns the node with the lowest score, preferring earlier nodes on
+// ties.
+func (p *nodePool) best(primary string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	best := ""
+	for _, n := range p.urls(primary) {
+		if n != "" && (best == "" || p.stats[n].score() < p.stats[best].score()) {
+			best = n
+		}
+	}
+	return best
+}
+
+// observe records the outcome of a request to node.
+func (p *nodePool) observe(node string, latency time.Duration, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.stats[node]
+	if s == nil {
+		s = &nodeStats{}
+		p.stats[node] = s
+	}
+	failure := 0.0
+	if failed {
+		failure = 1
+	}
+	if s.samples == 0 {
+		s.latency, s.errorRate = latency, failure
+	} else {
+		s.latency = time.Duration(nodeSmoothing*float64(latency) + (1-nodeSmoothing)*float64(s.latency))
+		s.errorRate = nodeSmoothing*failure + (1-nodeSmoothing)*s.errorRate
+	}
+	s.samples++
+}
+
+// NodeStats returns the measurements of the client's NAG and the nodes added
+// with WithNAGNodes, in that order. It returns nil if no nodes were added.
+func (c *Client) NodeStats() []NodeStats {
+	if c.nodes == nil {
+		return nil
+	}
+	urls := c.nodes.urls(c.NAGURL)
+	c.nodes.mu.Lock()
+	defer c.nodes.mu.Unlock()
+	out := make([]NodeStats, 0, len(urls))
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		entry := NodeStats{URL: u}
+		if s := c.nodes.stats[u]; s != nil {
+			entry.Latency, entry.ErrorRate, entry.Samples = s.latency, s.errorRate, s.samples
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// routeRequest returns the request to send for req: a copy addressed to the
+// best node when nodes are configured and req targets one, or req itself.
+// node is the node the returned request targets, if any.
+func (c *Client) routeRequest(ctx context.Context, req *http.Request) (routed *http.Request, node string) {
+	if c.nodes == nil {
+		return req, ""
+	}
+	requestURL := req.URL.String()
+	node = c.nodes.match(c.NAGURL, requestURL)
+	if node == "" {
+		return req, ""
+	}
+	if pinned, _ := ctx.Value(pinnedNodeKey{}).(bool); pinned {
+		return req, node
+	}
+	best := c.nodes.best(c.NAGURL)
+	if best == node {
+		return req, node
+	}
+	target, err := req.URL.Parse(best + requestURL[len(node):])
+	if err != nil {
+		return req, node
+	}
+	routed = req.Clone(req.Context())
+	routed.URL = target
+	routed.Host = target.Host
+	return routed, best
+}
+
+// observeNode records the outcome of a request sent to node; exactly one of
+// resp and err is set. Failures are transport errors, throttling and 5xx
+// responses; a request abandoned because its caller's context ended says
+// nothing about the node and is not recorded.
+func (c *Client) observeNode(ctx context.Context, node string, latency time.Duration, resp *http.Response, err error) {
+	if node == "" || ctx.Err() != nil {
+		return
+	}
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	c.nodes.observe(node, latency, failed)
+}
+
+// pinnedNodeKey marks a context whose requests go to the node they are
+// addressed to, as probes must.
+type pinnedNodeKey struct{}
+
+// ProbeNodes measures every node once by reading its block count, so nodes
+// that receive no traffic because another ranks better are re-evaluated. The
+// errors of failed probes are joined.
+func (c *Client) ProbeNodes(ctx context.Context) error {
+	if c.nodes == nil {
+		return nil
+	}
+	ctx = context.WithValue(ctx, pinnedNodeKey{}, true)
+	urls := c.nodes.urls(c.NAGURL)
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		if u == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			_, err := callAt[map[string]interface{}](ctx, c, u, "GetBlockCount", map[string]interface{}{
+				"Blockchain": c.Blockchain,
+				"Version":    c.CodeVersion,
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("probe %s: %w", u, err)
+			}
+		}(i, u)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// StartNodeProbing runs ProbeNodes every interval in the background until ctx
+// is done or the client is shut down. Failed probes are logged.
+func (c *Client) StartNodeProbing(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("probe interval must be positive, got %v", interval)
+	}
+	ctx, done, err := c.startWorker(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.ProbeNodes(ctx); err != nil && ctx.Err() == nil {
+					c.logf(ctx, "node probe failed: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,160 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNodeSelection(t *testing.T) {
+	var slowHits, fastHits int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&slowHits, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":1}`))
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fastHits, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":2}`))
+	}))
+	defer fast.Close()
+
+	c := NewClient(slow.URL, DefaultChain, LibVersion, WithNAGNodes(fast.URL+"/"))
+	if err := c.ProbeNodes(context.Background()); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if slowHits != 1 || fastHits != 1 {
+		t.Fatalf("Expected one probe per node, but got %d and %d", slowHits, fastHits)
+	}
+
+	for i := 0; i < 5; i++ {
+		height, err := c.GetBlockHeight(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if height != 2 {
+			t.Errorf("Expected the request to be served by the fast node, but got height %d", height)
+		}
+	}
+	if slowHits != 1 || fastHits != 6 {
+		t.Errorf("Expected all requests on the fast node, but got %d slow and %d fast", slowHits, fastHits)
+	}
+
+	stats := c.NodeStats()
+	if len(stats) != 2 || stats[0].URL != slow.URL || stats[1].URL != fast.URL {
+		t.Fatalf("Expected stats for both nodes in order, but got %+v", stats)
+	}
+	if stats[0].Samples != 1 || stats[1].Samples != 6 || stats[0].Latency <= stats[1].Latency {
+		t.Errorf("Expected the slow node to measure slower, but got %+v", stats)
+	}
+}
+
+func TestNodeFailover(t *testing.T) {
+	var failing int32 = 1
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":1}`))
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":2}`))
+	}))
+	defer backup.Close()
+
+	c := NewClient(primary.URL, DefaultChain, LibVersion, WithNAGNodes(backup.URL))
+
+	// The primary is tried first as neither node has been measured. Its 503
+	// counts against it, so the next request goes to the backup.
+	if _, err := c.GetBlockHeight(context.Background()); err == nil {
+		t.Fatal("Expected the failing primary to return an error")
+	}
+	height, err := c.GetBlockHeight(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the backup to answer, but got: %v", err)
+	}
+	if height != 2 {
+		t.Errorf("Expected the backup to answer, but got height %d", height)
+	}
+
+	// Once the primary recovers, probing decays its error rate until it
+	// ranks ahead of the slower backup again.
+	atomic.StoreInt32(&failing, 0)
+	for i := 0; i < 30; i++ {
+		c.ProbeNodes(context.Background())
+	}
+	if height, _ := c.GetBlockHeight(context.Background()); height != 1 {
+		t.Errorf("Expected the recovered primary to be preferred, but got height %d", height)
+	}
+}
+
+func TestStartNodeProbing(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":1}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithNAGNodes(server.URL+"/other"))
+	if err := c.StartNodeProbing(context.Background(), 0); err == nil {
+		t.Error("Expected a zero interval to be rejected")
+	}
+	if err := c.StartNodeProbing(context.Background(), 5*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if atomic.LoadInt32(&hits) < 2 {
+		t.Errorf("Expected the nodes to be probed periodically, but got %d requests", hits)
+	}
+}
+
+func TestNodeRoutingSigned(t *testing.T) {
+	secret := []byte("shared-secret")
+	var rejected, routed int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !verifyHMAC(r, secret) {
+			atomic.AddInt32(&rejected, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/slow/") {
+			time.Sleep(50 * time.Millisecond)
+		} else {
+			atomic.AddInt32(&routed, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":1}`))
+	}))
+	defer server.Close()
+
+	// The nodes differ in path, which the signature covers.
+	c := NewClient(server.URL+"/slow", DefaultChain, LibVersion,
+		WithNAGNodes(server.URL+"/fast"),
+		WithRequestSigner(&HMACSigner{Secret: secret}))
+	if err := c.ProbeNodes(context.Background()); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if _, err := c.GetBlockHeight(context.Background()); err != nil {
+		t.Fatalf("Expected the routed request to be signed for its node, but got: %v", err)
+	}
+	if rejected != 0 || routed != 2 {
+		t.Errorf("Expected the request to be routed to the fast node with a valid signature, but got %d rejected and %d routed", rejected, routed)
+	}
+}
@@ -0,0 +1,307 @@
+// Package notify posts failures and stuck transactions reported on a
+// cep.EventBus to a webhook, so on-call engineers hear about failed
+// certifications before customers do.
+//
+// By default each notification is a JSON object with a single "text" field,
+// which Slack and Microsoft Teams incoming webhooks both accept:
+//
+//	bus := cep.NewEventBus()
+//	n := notify.New(webhookURL, notify.WithPrefix("[prod]"))
+//	defer n.Close(context.Background())
+//	n.Subscribe(bus)
+//	account := cep.NewCEPAccount(nagURL, chain, version, cep.WithEventBus(bus))
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+const (
+	// DefaultQueueSize is how many notifications wait to be posted before new
+	// ones are dropped.
+	DefaultQueueSize = 100
+	// DefaultTimeout bounds each webhook request.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Format is the shape of the JSON posted to the webhook.
+type Format int
+
+const (
+	// FormatText posts {"text": "..."}, as understood by Slack and Teams.
+	FormatText Format = iota
+	// FormatJSON posts a Payload, for webhooks that process events
+	// themselves.
+	FormatJSON
+)
+
+// Payload is the body posted in FormatJSON.
+type Payload struct {
+	Text          string    `json:"text"`
+	Event         string    `json:"event"`
+	TxID          string    `json:"txId,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// Option configures a Notifier.
+type Option func(*Notifier)
+
+// WithHTTPClient posts notifications with client instead of a client using
+// DefaultTimeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+// WithFormat selects the shape of the posted JSON.
+func WithFormat(format Format) Option {
+	return func(n *Notifier) {
+		n.format = format
+	}
+}
+
+// WithPrefix starts every message with prefix, such as the environment name.
+func WithPrefix(prefix string) Option {
+	return func(n *Notifier) {
+		n.prefix = prefix
+	}
+}
+
+// WithFilter only notifies events for which keep returns true. It is
+// consulted after the built-in selection of failures and stuck transactions.
+func WithFilter(keep func(cep.Event) bool) Option {
+	return func(n *Notifier) {
+		n.filter = keep
+	}
+}
+
+// WithQueueSize sets how many notifications wait to be posted before new ones
+// are dropped. See DefaultQueueSize.
+func WithQueueSize(size int) Option {
+	return func(n *Notifier) {
+		n.queueSize = size
+	}
+}
+
+// WithLogger reports failures to post notifications to fn instead of
+// standard output.
+func WithLogger(fn cep.LogFunc) Option {
+	return func(n *Notifier) {
+		n.logger = fn
+	}
+}
+
+// Notifier posts failure and stuck transaction events to a webhook. Events
+// are queued and posted by a background goroutine, so publishing never waits
+// for the webhook; when the queue is full, notifications are dropped and
+// counted.
+type Notifier struct {
+	url       string
+	client    *http.Client
+	format    Format
+	prefix    string
+	filter    func(cep.Event) bool
+	queueSize int
+	logger    cep.LogFunc
+
+	queue     chan cep.Event
+	done      chan struct{}
+	closeOnce sync.Once
+	mu        sync.RWMutex
+	closed    bool
+	dropped   atomic.Int64
+}
+
+// New returns a Notifier posting to url and starts its background goroutine.
+// Close stops it.
+func New(url string, opts ...Option) *Notifier {
+	n := &Notifier{
+		url:       url,
+		client:    &http.Client{Timeout: DefaultTimeout},
+		queueSize: DefaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	n.queue = make(chan cep.Event, max(n.queueSize, 1))
+	n.done = make(chan struct{})
+	go n.run()
+	return n
+}
+
+// Subscribe notifies the failures and stuck transactions published on bus
+// until the returned function is called.
+func (n *Notifier) Subscribe(bus *cep.EventBus) (unsubscribe func()) {
+	return bus.Subscribe(n.Handle)
+}
+
+// Handle queues event for posting if it is a failure or a stuck transaction.
+// It is the handler installed by Subscribe.
+func (n *Notifier) Handle(event cep.Event) {
+	if _, ok := Message(event); !ok || (n.filter != nil && !n.filter(event)) {
+		return
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		n.dropped.Add(1)
+		return
+	}
+	select {
+	case n.queue <- event:
+	default:
+		n.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many notifications were dropped because the queue was
+// full or the Notifier closed.
+func (n *Notifier) Dropped() int64 {
+	return n.dropped.Load()
+}
+
+// Close posts the notifications still queued and stops the background
+// goroutine, giving up when ctx is done.
+func (n *Notifier) Close(ctx context.Context) error {
+	n.closeOnce.Do(func() {
+		n.mu.Lock()
+		n.closed = true
+		close(n.queue)
+		n.mu.Unlock()
+	})
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run posts queued events until the queue is closed.
+func (n *Notifier) run() {
+	defer close(n.done)
+	for event := range n.queue {
+		ctx := context.Background()
+		if id := event.Info().CorrelationID; id != "" {
+			ctx = cep.ContextWithCorrelationID(ctx, id)
+		}
+		if err := n.Notify(ctx, event); err != nil {
+			n.logf(ctx, "failed to post notification: %v", err)
+		}
+	}
+}
+
+// Notify posts event to the webhook immediately, whether or not it is a
+// failure.
+func (n *Notifier) Notify(ctx context.Context, event cep.Event) error {
+	text, _ := Message(event)
+	if n.prefix != "" {
+		text = n.prefix + " " + text
+	}
+	var body interface{} = map[string]string{"text": text}
+	if n.format == FormatJSON {
+		payload := Payload{Text: text, Event: eventName(event), Time: event.Info().Time, CorrelationID: event.Info().CorrelationID}
+		switch e := event.(type) {
+		case cep.FailedEvent:
+			payload.TxID = e.TxID
+			if e.Err != nil {
+				payload.Error = e.Err.Error()
+			}
+		case cep.StuckEvent:
+			payload.TxID = e.TxID
+			if e.Err != nil {
+				payload.Error = e.Err.Error()
+			}
+		}
+		body = payload
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http post request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// Message describes event for a human and reports whether it is a failure or
+// a stuck transaction, which are worth notifying.
+func Message(event cep.Event) (string, bool) {
+	switch e := event.(type) {
+	case cep.FailedEvent:
+		if e.TxID == "" {
+			return fmt.Sprintf("Certificate submission failed: %v", e.Err), true
+		}
+		return fmt.Sprintf("Transaction %s failed: %v", e.TxID, e.Err), true
+	case cep.StuckEvent:
+		message := fmt.Sprintf("Transaction %s has been %s for %v", e.TxID, strings.ToLower(e.Status.String()), e.Pending.Round(time.Second))
+		switch {
+		case e.Resubmitted:
+			message += "; resubmitted"
+		case e.Err != nil:
+			message += fmt.Sprintf("; resubmission failed: %v", e.Err)
+		}
+		return message, true
+	default:
+		return fmt.Sprintf("%s event", eventName(event)), false
+	}
+}
+
+// eventName returns the name of the type of event without its Event suffix.
+func eventName(event cep.Event) string {
+	switch event.(type) {
+	case cep.BroadcastEvent:
+		return "Broadcast"
+	case cep.SubmittedEvent:
+		return "Submitted"
+	case cep.ConfirmedEvent:
+		return "Confirmed"
+	case cep.FailedEvent:
+		return "Failed"
+	case cep.RetryingEvent:
+		return "Retrying"
+	case cep.NonceResyncedEvent:
+		return "NonceResynced"
+	case cep.StuckEvent:
+		return "Stuck"
+	default:
+		return fmt.Sprintf("%T", event)
+	}
+}
+
+// logf formats a message and hands it to the notifier's logger.
+func (n *Notifier) logf(ctx context.Context, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if n.logger != nil {
+		n.logger(ctx, message)
+		return
+	}
+	fmt.Println(message)
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+func TestNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	failed := cep.FailedEvent{TxID: "abc", Err: errors.New("transaction abc failed")}
+	failed.CorrelationID = "trace"
+
+	testCases := []struct {
+		name     string
+		format   Format
+		expected map[string]interface{}
+	}{
+		{
+			name:     "Text",
+			format:   FormatText,
+			expected: map[string]interface{}{"text": "[prod] Transaction abc failed: transaction abc failed"},
+		},
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			expected: map[string]interface{}{
+				"text":          "[prod] Transaction abc failed: transaction abc failed",
+				"event":         "Failed",
+				"txId":          "abc",
+				"error":         "transaction abc failed",
+				"correlationId": "trace",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bodies = nil
+			bus := cep.NewEventBus()
+			n := New(server.URL, WithPrefix("[prod]"), WithFormat(tc.format))
+			n.Subscribe(bus)
+			bus.Publish(cep.SubmittedEvent{TxID: "abc"})
+			bus.Publish(failed)
+			if err := n.Close(context.Background()); err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			if len(bodies) != 1 {
+				t.Fatalf("Expected only the failure to be posted, but got %v", bodies)
+			}
+			for key, value := range tc.expected {
+				if bodies[0][key] != value {
+					t.Errorf("Expected %s to be %v, but got %v", key, value, bodies[0][key])
+				}
+			}
+			if tc.format == FormatText && len(bodies[0]) != 1 {
+				t.Errorf("Expected only a text field, but got %v", bodies[0])
+			}
+
+			bus.Publish(failed)
+			if n.Dropped() != 1 {
+				t.Errorf("Expected events after Close to be dropped, but got %d dropped", n.Dropped())
+			}
+		})
+	}
+
+	t.Run("Filter", func(t *testing.T) {
+		bodies = nil
+		n := New(server.URL, WithFilter(func(event cep.Event) bool {
+			return event.(cep.FailedEvent).TxID != ""
+		}))
+		n.Handle(cep.FailedEvent{Err: errors.New("invalid private key")})
+		n.Handle(failed)
+		n.Close(context.Background())
+		if len(bodies) != 1 || !strings.Contains(bodies[0]["text"].(string), "abc") {
+			t.Errorf("Expected only the filtered failure, but got %v", bodies)
+		}
+	})
+
+	t.Run("Stuck", func(t *testing.T) {
+		message, ok := Message(cep.StuckEvent{TxID: "abc", Status: cep.TxPending, Pending: 15 * time.Minute, Resubmitted: true})
+		if !ok || message != "Transaction abc has been pending for 15m0s; resubmitted" {
+			t.Errorf("Expected stuck transactions to be notified, but got %q", message)
+		}
+		if _, ok := Message(cep.ConfirmedEvent{}); ok {
+			t.Error("Expected confirmations not to be notified")
+		}
+	})
+
+	t.Run("Webhook Error", func(t *testing.T) {
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer broken.Close()
+		var logged []string
+		n := New(broken.URL, WithLogger(func(ctx context.Context, message string) {
+			logged = append(logged, message)
+		}))
+		n.Handle(failed)
+		n.Close(context.Background())
+		if len(logged) != 1 || !strings.Contains(logged[0], "403") {
+			t.Errorf("Expected the failure to be logged, but got %v", logged)
+		}
+	})
+}
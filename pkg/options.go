@@ -0,0 +1,116 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultRequestTimeout bounds a single HTTP exchange with the NAG, from
+// dialing until the response body has been read. It is independent of the
+// polling timeout passed to GetTransactionOutcome.
+const DefaultRequestTimeout = 30 * time.Second
+
+// Option configures a Client or CEPAccount at construction time.
+type Option func(*Client)
+
+// WithRequestTimeout sets the default timeout applied to every HTTP request
+// made by the client. A zero or negative value disables the timeout, leaving
+// only the deadline of the caller's context.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.RequestTimeout = timeout
+	}
+}
+
+// requestTimeoutKey is the context key for per-call timeout overrides.
+type requestTimeoutKey struct{}
+
+// ContextWithRequestTimeout returns a copy of ctx that overrides the
+// client's RequestTimeout for every HTTP request made with it. This is the
+// per-call counterpart of WithRequestTimeout; a zero value disables the
+// timeout for that call.
+func ContextWithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+// requestContext derives the context used for one HTTP request, applying the
+// per-call override if present and the client default otherwise.
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.RequestTimeout
+	if override, ok := ctx.Value(requestTimeoutKey{}).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// WithProxy routes the client's requests through the given proxy. HTTP,
+// HTTPS and SOCKS5 ("socks5://") proxies are supported; credentials embedded
+// in the URL (user:password@host) are used to authenticate with the proxy.
+// Without this option the standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+// environment variables are honoured.
+func WithProxy(proxyURL *url.URL) Option {
+	return withTransport(func(t *http.Transport) {
+		t.Proxy = http.ProxyURL(proxyURL)
+	})
+}
+
+// WithDialContext replaces the function used to open network connections,
+// for example to dial through a corporate tunnel or a fixed egress interface.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return withTransport(func(t *http.Transport) {
+		t.DialContext = dial
+	})
+}
+
+// WithTLSConfig sets the TLS configuration used for connections to the NAG,
+// for example to trust a private certificate authority or to present a client
+// certificate to a self-hosted gateway. The config is cloned, so later changes
+// to it do not affect the client.
+func WithTLSConfig(config *tls.Config) Option {
+	return withTransport(func(t *http.Transport) {
+		t.TLSClientConfig = config.Clone()
+	})
+}
+
+// WithRootCAs replaces the set of certificate authorities trusted when
+// verifying the NAG's certificate. It can be combined with WithTLSConfig and
+// WithClientCertificate; options are applied in order.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return withTransport(func(t *http.Transport) {
+		tlsConfig(t).RootCAs = pool
+	})
+}
+
+// WithClientCertificate presents the given certificate to NAG deployments that
+// require mutual TLS.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return withTransport(func(t *http.Transport) {
+		config := tlsConfig(t)
+		config.Certificates = append(config.Certificates, cert)
+	})
+}
+
+// tlsConfig returns the transport's TLS config, creating it if necessary.
+func tlsConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig
+}
+
+// withTransport records a customisation of the client's HTTP transport. Any
+// such option gives the client its own transport, cloned from the shared
+// defaults, instead of the shared one.
+func withTransport(configure func(*http.Transport)) Option {
+	return func(c *Client) {
+		c.transportOptions = append(c.transportOptions, configure)
+	}
+}
@@ -0,0 +1,239 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRequestTimeout(t *testing.T) {
+	acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
+	if acc.RequestTimeout != DefaultRequestTimeout {
+		t.Errorf("Expected default RequestTimeout %v, but got %v", DefaultRequestTimeout, acc.RequestTimeout)
+	}
+
+	acc = NewCEPAccount(DefaultNAG, DefaultChain, LibVersion, WithRequestTimeout(5*time.Second))
+	if acc.RequestTimeout != 5*time.Second {
+		t.Errorf("Expected RequestTimeout 5s, but got %v", acc.RequestTimeout)
+	}
+}
+
+func TestRequestTimeoutEnforced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	t.Run("Account Default", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRequestTimeout(50*time.Millisecond))
+		acc.Open("0x123")
+
+		_, err := acc.UpdateAccount()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected a deadline exceeded error, but got %v", err)
+		}
+	})
+
+	t.Run("Per-Call Override", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRequestTimeout(50*time.Millisecond))
+		acc.Open("0x123")
+
+		ctx := ContextWithRequestTimeout(context.Background(), 5*time.Second)
+		ok, err := acc.UpdateAccountContext(ctx)
+		if err != nil || !ok {
+			t.Fatalf("Expected the override to allow the request, but got ok=%v err=%v", ok, err)
+		}
+	})
+}
+
+func TestGetTransactionOutcomeContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Status":"Pending"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := acc.GetTransactionOutcomeContext(ctx, "0xabc", 30)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a deadline exceeded error, but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected polling to stop with the context, but it took %v", elapsed)
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxyAuth, requestedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		requestedURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":7}}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse proxy URL: %v", err)
+	}
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	acc := NewCEPAccount("http://nag.example.invalid", DefaultChain, LibVersion, WithProxy(proxyURL))
+	acc.Open("0x123")
+
+	if acc.httpClient() == sharedHTTPClient {
+		t.Fatal("Expected a dedicated client when a proxy is configured")
+	}
+	if _, err := acc.UpdateAccount(); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if !strings.HasPrefix(requestedURL, "http://nag.example.invalid/") {
+		t.Errorf("Expected the proxy to receive the NAG URL, but got %s", requestedURL)
+	}
+	if !strings.HasPrefix(proxyAuth, "Basic ") {
+		t.Errorf("Expected basic proxy credentials, but got %q", proxyAuth)
+	}
+}
+
+func TestWithDialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	dials := 0
+	dialer := &net.Dialer{}
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		// Every host resolves to the test server.
+		return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	acc := NewCEPAccount("http://nag.example.invalid", DefaultChain, LibVersion, WithDialContext(dial))
+	acc.Open("0x123")
+
+	if _, err := acc.UpdateAccount(); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if dials == 0 {
+		t.Error("Expected the custom dialer to be used")
+	}
+}
+
+// newClientCertificate creates a self-signed certificate usable for client
+// authentication, returning it along with a pool that trusts it.
+func newClientCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cep-test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestCustomRootCAs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	t.Run("Untrusted Gateway", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+		acc.Open("0x123")
+		if _, err := acc.UpdateAccount(); err == nil {
+			t.Fatal("Expected TLS verification to fail without the gateway CA")
+		}
+	})
+
+	t.Run("Trusted Gateway", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRootCAs(pool))
+		acc.Open("0x123")
+		if _, err := acc.UpdateAccount(); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	})
+}
+
+func TestMutualTLS(t *testing.T) {
+	clientCert, clientPool := newClientCertificate(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientPool}
+	server.StartTLS()
+	defer server.Close()
+
+	serverPool := x509.NewCertPool()
+	serverPool.AddCert(server.Certificate())
+
+	t.Run("Without Client Certificate", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithTLSConfig(&tls.Config{RootCAs: serverPool}))
+		acc.Open("0x123")
+		if _, err := acc.UpdateAccount(); err == nil {
+			t.Fatal("Expected the handshake to fail without a client certificate")
+		}
+	})
+
+	t.Run("With Client Certificate", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion,
+			WithTLSConfig(&tls.Config{RootCAs: serverPool}),
+			WithClientCertificate(clientCert),
+		)
+		acc.Open("0x123")
+		if _, err := acc.UpdateAccount(); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	})
+}
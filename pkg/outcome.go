@@ -0,0 +1,267 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TxStatus is the state of a transaction as reported by the network.
+type TxStatus int
+
+const (
+	// TxUnknown is a status the library does not recognise. The original
+	// string is kept in Outcome.RawStatus.
+	TxUnknown TxStatus = iota
+	// TxPending is a transaction accepted by the network but not yet final.
+	TxPending
+	// TxConfirmed is a transaction executed and included in a block.
+	TxConfirmed
+	// TxFailed is a transaction rejected by the network or failed on execution.
+	TxFailed
+	// TxNotFound is a transaction the network does not know about, either
+	// because it has not been indexed yet or because it was dropped.
+	TxNotFound
+	// TxExpired is a transaction that was not processed in time.
+	TxExpired
+)
+
+// String returns the name of the status.
+func (s TxStatus) String() string {
+	switch s {
+	case TxPending:
+		return "Pending"
+	case TxConfirmed:
+		return "Confirmed"
+	case TxFailed:
+		return "Failed"
+	case TxNotFound:
+		return "NotFound"
+	case TxExpired:
+		return "Expired"
+	default:
+		return "Unknown"
+	}
+}
+
+// Final reports whether the status will not change any more.
+func (s TxStatus) Final() bool {
+	return s == TxConfirmed || s == TxFailed || s == TxExpired
+}
+
+// ParseTxStatus converts a Status string returned by the network, such as
+// "Pending" or "Executed", into a TxStatus. The comparison ignores case.
+func ParseTxStatus(status string) TxStatus {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "pending":
+		return TxPending
+	case "executed", "confirmed":
+		return TxConfirmed
+	case "failed", "rejected":
+		return TxFailed
+	case "not found", "notfound", "transaction not found":
+		return TxNotFound
+	case "expired":
+		return TxExpired
+	default:
+		return TxUnknown
+	}
+}
+
+// Outcome is the typed form of a GetTransactionbyID response.
+type Outcome struct {
+	TxID   string
+	Status TxStatus
+	// RawStatus is the Status string as returned by the network.
+	RawStatus string
+	// Transaction is the transaction object returned by the network, or nil
+	// if the transaction was not found.
+	Transaction map[string]interface{}
+
+	// Inclusion details of a confirmed transaction. BlockID is the number
+	// of the block holding it, BlockTimestamp the block's timestamp and
+	// Position the index of the transaction among the block's transactions,
+	// or -1 when unknown. NodeID identifies the node that processed it.
+	// Details missing from the status response are looked up in the block by
+	// GetOutcome and WaitForOutcome; fields that still cannot be determined
+	// are left empty.
+	BlockID        string
+	BlockTimestamp string
+	Position       int
+	NodeID         string
+}
+
+// newOutcome builds the Outcome of txID from a GetTransactionbyID response.
+// A response carrying a "not found" message is reported as TxNotFound, and
+// any other non-200 Result as TxUnknown.
+func newOutcome(txID string, data map[string]interface{}) *Outcome {
+	outcome := &Outcome{TxID: txID, Position: -1}
+	if notFoundResponse(data) {
+		outcome.Status = TxNotFound
+		return outcome
+	}
+	if result, _ := data["Result"].(float64); result != 200 {
+		return outcome
+	}
+	outcome.Transaction, _ = data["Response"].(map[string]interface{})
+	outcome.RawStatus, _ = outcome.Transaction["Status"].(string)
+	outcome.Status = ParseTxStatus(outcome.RawStatus)
+	outcome.BlockID = firstField(outcome.Transaction, "BlockID", "BlockId", "BlockNumber", "Block")
+	outcome.NodeID = firstField(outcome.Transaction, "NodeID", "NodeId", "Node")
+	outcome.BlockTimestamp = firstField(outcome.Transaction, "BlockTimestamp")
+	if position, err := strconv.Atoi(firstField(outcome.Transaction, "Position", "Index")); err == nil && position >= 0 {
+		outcome.Position = position
+	}
+	return outcome
+}
+
+// notFoundResponse reports whether a GetTransactionbyID response says the
+// transaction is not known. Gateways report it with an error Result, but some
+// answer {"Result":200,"Response":"Transaction Not Found"}: the message,
+// rather than the Result, is what counts.
+func notFoundResponse(data map[string]interface{}) bool {
+	message, ok := data["Response"].(string)
+	return ok && strings.Contains(strings.ToLower(message), "not found")
+}
+
+// CertificateData decodes the certificate data carried by the outcome's
+// transaction, as submitted with SubmitCertificate.
+func (o *Outcome) CertificateData() (string, error) {
+	payload, ok := o.Transaction["Payload"].(string)
+	if !ok {
+		return "", fmt.Errorf("transaction %s has no payload", o.TxID)
+	}
+	return decodePayload(payload)
+}
+
+// firstField returns the first of the named fields of object that is set, as
+// a string. Gateways return identifiers as strings or numbers.
+func firstField(object map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		switch v := object[name].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// complete reports whether every inclusion detail of a confirmed outcome is
+// known.
+func (o *Outcome) complete() bool {
+	return o.BlockID != "" && o.BlockTimestamp != "" && o.Position >= 0 && o.NodeID != ""
+}
+
+// completeInclusion fills the inclusion details of a confirmed outcome that
+// the status response omitted from the block holding the transaction. When
+// the block is not named, the latest SearchDepth blocks are searched.
+// Failures are logged and leave the details empty: the transaction
+// is confirmed either way.
+func (c *Client) completeInclusion(ctx context.Context, outcome *Outcome) {
+	if outcome.Status != TxConfirmed || outcome.complete() {
+		return
+	}
+
+	var start, end int64
+	if outcome.BlockID != "" {
+		number, err := strconv.ParseInt(outcome.BlockID, 10, 64)
+		if err != nil {
+			c.logf(ctx, "cannot look up block %q of %s: %v", outcome.BlockID, outcome.TxID, err)
+			return
+		}
+		start, end = number, number
+	} else {
+		var err error
+		if start, end, err = c.searchWindow(ctx); err != nil {
+			c.logf(ctx, "cannot search the block of %s: %v", outcome.TxID, err)
+			return
+		}
+	}
+
+	txID := normalizeHex(outcome.TxID)
+	found := errors.New("found")
+	err := c.GetBlockRangeContext(ctx, start, end, func(block map[string]interface{}) error {
+		fields, _ := unwrapBlock(block).(map[string]interface{})
+		transactions, _ := fields["Transactions"].([]interface{})
+		for i, item := range transactions {
+			tx, _ := item.(map[string]interface{})
+			id, _ := item.(string)
+			if tx != nil {
+				id = certificateRow(tx, 0).TxID
+			}
+			if normalizeHex(id) != txID {
+				continue
+			}
+			if outcome.BlockID == "" {
+				outcome.BlockID = firstField(fields, "BlockID", "BlockNumber")
+			}
+			if outcome.BlockTimestamp == "" {
+				outcome.BlockTimestamp = firstField(fields, "Timestamp")
+			}
+			if outcome.Position < 0 {
+				outcome.Position = i
+			}
+			if outcome.NodeID == "" {
+				outcome.NodeID = firstField(tx, "NodeID", "NodeId", "Node")
+			}
+			if outcome.NodeID == "" {
+				outcome.NodeID = firstField(fields, "NodeID", "NodeId", "Node", "Miner")
+			}
+			return found
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, found):
+	case err != nil:
+		c.logf(ctx, "cannot look up the block of %s: %v", outcome.TxID, err)
+	default:
+		c.logf(ctx, "transaction %s not found in blocks %d to %d", outcome.TxID, start, end)
+	}
+}
+
+// GetOutcome looks up a transaction once and returns its current Outcome.
+// Unlike GetTransactionOutcome it does not wait for the transaction to leave
+// the Pending state.
+func (c *Client) GetOutcome(ctx context.Context, txID string) (*Outcome, error) {
+	data, err := c.GetTransactionByIDContext(ctx, txID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	outcome := newOutcome(txID, data)
+	c.completeInclusion(ctx, outcome)
+	c.transitionLedger(ctx, outcome)
+	c.confirmReceipts(ctx, outcome)
+	c.emitOutcome(ctx, outcome)
+	return outcome, nil
+}
+
+// WaitForOutcome is like GetTransactionOutcomeContext but returns a typed
+// Outcome.
+func (c *Client) WaitForOutcome(ctx context.Context, txID string, timeoutSec int, opts ...PollOption) (*Outcome, error) {
+	transaction, err := c.GetTransactionOutcomeContext(ctx, txID, timeoutSec, opts...)
+	if errors.Is(err, ErrTransactionDropped) {
+		if c.ledger != nil {
+			c.transitionLedgerTo(ctx, txID, LedgerTransition{Status: LedgerDropped, At: time.Now(), Detail: err.Error()})
+		}
+		if c.events != nil {
+			c.events.Publish(FailedEvent{EventInfo: c.eventInfo(ctx), TxID: txID, Err: err})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	outcome := newOutcome(txID, map[string]interface{}{"Result": float64(200), "Response": transaction})
+	c.completeInclusion(ctx, outcome)
+	c.transitionLedger(ctx, outcome)
+	c.confirmReceipts(ctx, outcome)
+	c.emitOutcome(ctx, outcome)
+	return outcome, nil
+}
@@ -0,0 +1,187 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseTxStatus(t *testing.T) {
+	testCases := []struct {
+		status   string
+		expected TxStatus
+		final    bool
+	}{
+		{"Pending", TxPending, false},
+		{"pending", TxPending, false},
+		{"Executed", TxConfirmed, true},
+		{"Confirmed", TxConfirmed, true},
+		{"Failed", TxFailed, true},
+		{"Transaction Not Found", TxNotFound, false},
+		{"Expired", TxExpired, true},
+		{"Mystery", TxUnknown, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.status, func(t *testing.T) {
+			got := ParseTxStatus(tc.status)
+			if got != tc.expected {
+				t.Errorf("Expected %v, but got %v", tc.expected, got)
+			}
+			if got.Final() != tc.final {
+				t.Errorf("Expected Final() to be %v for %v", tc.final, got)
+			}
+		})
+	}
+}
+
+func TestNewOutcome(t *testing.T) {
+	testCases := []struct {
+		name      string
+		response  string
+		expected  TxStatus
+		rawStatus string
+	}{
+		{"Executed", `{"Result":200,"Response":{"Status":"Executed"}}`, TxConfirmed, "Executed"},
+		{"Pending", `{"Result":200,"Response":{"Status":"Pending"}}`, TxPending, "Pending"},
+		{"Not Found", `{"Result":118,"Response":"Transaction Not Found"}`, TxNotFound, ""},
+		{"Not Found With Result 200", `{"Result":200,"Response":"Transaction Not Found"}`, TxNotFound, ""},
+		{"Other Error", `{"Result":108,"Response":"Invalid request"}`, TxUnknown, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var data map[string]interface{}
+			json.Unmarshal([]byte(tc.response), &data)
+			outcome := newOutcome("tx", data)
+			if outcome.Status != tc.expected || outcome.RawStatus != tc.rawStatus {
+				t.Errorf("Expected %v (%q), but got %v (%q)", tc.expected, tc.rawStatus, outcome.Status, outcome.RawStatus)
+			}
+		})
+	}
+}
+
+func TestOutcomeCertificateData(t *testing.T) {
+	outcome := &Outcome{TxID: "tx", Transaction: map[string]interface{}{"Payload": "7b2264617461223a2268656c6c6f227d"}}
+	data, err := outcome.CertificateData()
+	if err != nil || data != "hello" {
+		t.Errorf("Expected hello, but got %q (%v)", data, err)
+	}
+	if _, err := (&Outcome{TxID: "tx"}).CertificateData(); err == nil {
+		t.Error("Expected an error for a transaction without payload, but got nil")
+	}
+}
+
+func TestGetAndWaitForOutcome(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if atomic.AddInt32(&polls, 1) < 2 {
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Pending"}}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","BlockID":"12"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithLogger(func(ctx context.Context, message string) {}))
+
+	outcome, err := c.GetOutcome(context.Background(), "tx")
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if outcome.Status != TxPending {
+		t.Errorf("Expected %v, but got %v", TxPending, outcome.Status)
+	}
+
+	outcome, err = c.WaitForOutcome(context.Background(), "tx", 5, WithPollStrategy(FixedPoll{Interval: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if outcome.Status != TxConfirmed || outcome.Transaction["BlockID"] != "12" {
+		t.Errorf("Expected a confirmed outcome in block 12, but got %+v", outcome)
+	}
+}
+
+func TestOutcomeInclusion(t *testing.T) {
+	const txID = "0xAB12"
+	block := `{"BlockID":"12","Timestamp":"2024:05:01-10:00:00","NodeID":"node-b","Transactions":[{"ID":"ff00"},{"ID":"ab12","NodeID":"node-a"}]}`
+	type inclusion struct {
+		BlockID, BlockTimestamp string
+		Position                int
+		NodeID                  string
+	}
+	testCases := []struct {
+		name     string
+		status   string
+		expected inclusion
+		requests []string
+	}{
+		{
+			name:     "Complete Response",
+			status:   `{"Status":"Executed","BlockID":7,"BlockTimestamp":"t","Position":3,"NodeID":"n"}`,
+			expected: inclusion{BlockID: "7", BlockTimestamp: "t", Position: 3, NodeID: "n"},
+			requests: []string{"GetTransactionbyID"},
+		},
+		{
+			name:     "Block Named",
+			status:   `{"Status":"Executed","BlockID":"12"}`,
+			expected: inclusion{BlockID: "12", BlockTimestamp: "2024:05:01-10:00:00", Position: 1, NodeID: "node-a"},
+			requests: []string{"GetTransactionbyID", "GetBlockRange 12-12"},
+		},
+		{
+			name:     "Block Searched",
+			status:   `{"Status":"Executed"}`,
+			expected: inclusion{BlockID: "12", BlockTimestamp: "2024:05:01-10:00:00", Position: 1, NodeID: "node-a"},
+			requests: []string{"GetTransactionbyID", "GetBlockCount", "GetBlockRange 8-39"},
+		},
+		{
+			name:     "Pending",
+			status:   `{"Status":"Pending"}`,
+			expected: inclusion{Position: -1},
+			requests: []string{"GetTransactionbyID"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&request)
+				w.WriteHeader(http.StatusOK)
+				switch {
+				case strings.Contains(r.URL.Path, "GetTransactionbyID"):
+					requests = append(requests, "GetTransactionbyID")
+					w.Write([]byte(`{"Result":200,"Response":` + tc.status + `}`))
+				case strings.Contains(r.URL.Path, "GetBlockCount"):
+					requests = append(requests, "GetBlockCount")
+					w.Write([]byte(`{"Result":200,"Response":{"Blocks":40}}`))
+				case strings.Contains(r.URL.Path, "GetBlockRange"):
+					requests = append(requests, fmt.Sprintf("GetBlockRange %v-%v", request["Start"], request["End"]))
+					w.Write([]byte(`{"Result":200,"Response":{"Blocks":[` + block + `]}}`))
+				}
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL, DefaultChain, LibVersion)
+			outcome, err := c.GetOutcome(context.Background(), txID)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			got := inclusion{BlockID: outcome.BlockID, BlockTimestamp: outcome.BlockTimestamp, Position: outcome.Position, NodeID: outcome.NodeID}
+			if got != tc.expected {
+				t.Errorf("Expected %+v, but got %+v", tc.expected, got)
+			}
+			if strings.Join(requests, ",") != strings.Join(tc.requests, ",") {
+				t.Errorf("Expected requests %v, but got %v", tc.requests, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// PermissionSubmitCertificate is the permission a wallet needs to submit
+// certificates on chains that restrict submission.
+const PermissionSubmitCertificate = "SubmitCertificate"
+
+// Permissions describes what a wallet may do on a chain. Not every chain
+// reports permissions; when none are reported Known is false and every
+// action is assumed to be allowed, as the network will decide.
+type Permissions struct {
+	Address    string
+	Blockchain string
+	// Known reports whether the gateway returned roles or permissions for
+	// the wallet.
+	Known bool
+	Roles []string
+	// Granted lists the permissions held by the wallet.
+	Granted []string
+}
+
+// Allows reports whether the wallet holds permission, compared without
+// regard to case. It is true for every permission when none are Known.
+func (p *Permissions) Allows(permission string) bool {
+	if !p.Known {
+		return true
+	}
+	for _, granted := range p.Granted {
+		if strings.EqualFold(granted, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the wallet holds role, compared without regard to
+// case.
+func (p *Permissions) HasRole(role string) bool {
+	for _, held := range p.Roles {
+		if strings.EqualFold(held, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPermissions fetches the roles and permissions of the open account's
+// wallet on its default chain.
+func (a *CEPAccount) GetPermissions(ctx context.Context) (permissions *Permissions, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if a.Address == "" {
+		return nil, errors.New("Account is not open")
+	}
+	info, err := a.walletInfo(ctx, a.Blockchain, a.NAGURL)
+	if err != nil {
+		return nil, err
+	}
+	permissions = &info.Permissions
+	permissions.Blockchain = a.Blockchain
+	return permissions, nil
+}
+
+// walletPermissions extracts the permissions from the Roles, Role and
+// Permissions fields of a wallet. Each field may be a string, a list of
+// strings, or, for Permissions, an object mapping names to booleans.
+func walletPermissions(address string, roles, role, granted json.RawMessage) Permissions {
+	p := Permissions{Address: address}
+	for _, raw := range []json.RawMessage{roles, role} {
+		if names, ok := permissionNames(raw); ok {
+			p.Known = true
+			p.Roles = append(p.Roles, names...)
+		}
+	}
+	if names, ok := permissionNames(granted); ok {
+		p.Known = true
+		p.Granted = names
+	}
+	return p
+}
+
+// permissionNames decodes a string, a list of strings or an object of
+// booleans into names. It reports false when raw is absent or of another
+// shape.
+func permissionNames(raw json.RawMessage) ([]string, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, false
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		var names []string
+		for _, part := range strings.Split(name, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				names = append(names, part)
+			}
+		}
+		return names, true
+	}
+	var names []string
+	if err := json.Unmarshal(raw, &names); err == nil {
+		return names, true
+	}
+	var flags map[string]bool
+	if err := json.Unmarshal(raw, &flags); err == nil {
+		names = []string{}
+		for name, granted := range flags {
+			if granted {
+				names = append(names, name)
+			}
+		}
+		return names, true
+	}
+	return nil, false
+}
+
+// keyMatches reports whether privateKey is the key of publicKey on curve. The
+// public key may be in any encoding the curve verifies with, such as the
+// compressed or uncompressed forms of secp256k1.
+func keyMatches(curve Curve, privateKey, publicKey string) bool {
+	privateKeyBytes, err := hex.DecodeString(utils.HexFix(privateKey))
+	if err != nil || len(privateKeyBytes) == 0 {
+		return false
+	}
+	publicKeyBytes, err := hex.DecodeString(utils.HexFix(strings.ToLower(publicKey)))
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256([]byte("key match"))
+	signature, err := curve.Sign(privateKeyBytes, digest[:])
+	return err == nil && curve.Verify(publicKeyBytes, digest[:], signature) == nil
+}
@@ -0,0 +1,105 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testPublicKey is the compressed public key of the private key made of 64
+// ones used throughout the tests.
+const testPublicKey = "0x034f355bdcb7cc0af728ef3cceb9615d90684bb5b2ca5f859ab0f0b704075871aa"
+
+func TestGetPermissions(t *testing.T) {
+	testCases := []struct {
+		name            string
+		walletReply     string
+		expectedKnown   bool
+		expectedRoles   string
+		expectedGranted string
+		expectedAllowed bool
+	}{
+		{
+			name:            "Not Reported",
+			walletReply:     `{"Result":200,"Response":{"Address":"0xabc"}}`,
+			expectedAllowed: true,
+		},
+		{
+			name:            "Lists",
+			walletReply:     `{"Result":200,"Response":{"Roles":["Issuer","Auditor"],"Permissions":["SubmitCertificate","Read"]}}`,
+			expectedKnown:   true,
+			expectedRoles:   "Issuer,Auditor",
+			expectedGranted: "Read,SubmitCertificate",
+			expectedAllowed: true,
+		},
+		{
+			name:            "Single Role",
+			walletReply:     `{"Result":200,"Response":{"Role":"reader","Permissions":"Read, Transfer"}}`,
+			expectedKnown:   true,
+			expectedRoles:   "reader",
+			expectedGranted: "Read,Transfer",
+		},
+		{
+			name:            "Flags",
+			walletReply:     `{"Result":200,"Response":{"Permissions":{"SubmitCertificate":true,"Transfer":false}}}`,
+			expectedKnown:   true,
+			expectedGranted: "SubmitCertificate",
+			expectedAllowed: true,
+		},
+		{
+			name:            "Not Registered",
+			walletReply:     `{"Result":118,"Response":"Wallet Not Found"}`,
+			expectedAllowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tc.walletReply))
+			}))
+			defer server.Close()
+
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+			acc.Open("0xabc")
+			permissions, err := acc.GetPermissions(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			sort.Strings(permissions.Granted)
+			if permissions.Known != tc.expectedKnown ||
+				strings.Join(permissions.Roles, ",") != tc.expectedRoles ||
+				strings.Join(permissions.Granted, ",") != tc.expectedGranted {
+				t.Errorf("Expected known %t, roles %q and permissions %q, but got %+v", tc.expectedKnown, tc.expectedRoles, tc.expectedGranted, permissions)
+			}
+			if permissions.Address != "0xabc" || permissions.Blockchain != DefaultChain {
+				t.Errorf("Expected the account and chain to be set, but got %+v", permissions)
+			}
+			if allowed := permissions.Allows(PermissionSubmitCertificate); allowed != tc.expectedAllowed {
+				t.Errorf("Expected Allows to return %t, but got %t", tc.expectedAllowed, allowed)
+			}
+		})
+	}
+
+	t.Run("Account Not Open", func(t *testing.T) {
+		if _, err := NewCEPAccount("", DefaultChain, LibVersion).GetPermissions(context.Background()); err == nil {
+			t.Error("Expected an error for an account that is not open")
+		}
+	})
+}
+
+func TestKeyMatches(t *testing.T) {
+	privateKey := strings.Repeat("1", 64)
+	for _, publicKey := range []string{testPublicKey, strings.ToUpper(testPublicKey[2:])} {
+		if !keyMatches(Secp256k1Curve, privateKey, publicKey) {
+			t.Errorf("Expected %s to match the private key", publicKey)
+		}
+	}
+	if keyMatches(Secp256k1Curve, privateKey, "02ab") || keyMatches(Secp256k1Curve, "zz", testPublicKey) {
+		t.Error("Expected mismatched or invalid keys not to match")
+	}
+}
@@ -0,0 +1,84 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// PingResult describes a successful round trip to the NAG.
+type PingResult struct {
+	NAGURL string
+	// Latency is the time from sending the request to decoding the answer,
+	// including any connection setup.
+	Latency time.Duration
+	// BlockHeight is the block count reported by the NAG.
+	BlockHeight int64
+	// TLSVersion is the negotiated TLS version, such as "TLS 1.3", or empty
+	// for plain HTTP and reused connections.
+	TLSVersion string
+	// Reused reports whether an idle pooled connection was used.
+	Reused bool
+}
+
+// Ping checks that the NAG can be used before the first real submission. It
+// reads the block count of the client's blockchain, which exercises the
+// whole path a submission takes: DNS and dialing, the TLS handshake with the
+// client's certificate settings and pins, the client's request headers, and
+// the NAG's acceptance of the blockchain and CodeVersion. Any failure is
+// returned with the NAG URL for context.
+func (c *Client) Ping(ctx context.Context) (result *PingResult, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	result = &PingResult{NAGURL: c.NAGURL}
+	var mu sync.Mutex
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			result.Reused = info.Reused
+			mu.Unlock()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil {
+				mu.Lock()
+				result.TLSVersion = tls.VersionName(state.Version)
+				mu.Unlock()
+			}
+		},
+	})
+
+	start := time.Now()
+	height, err := c.GetBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ping %s: %w", c.NAGURL, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	result.Latency = time.Since(start)
+	result.BlockHeight = height
+	return result, nil
+}
+
+// Prewarm opens up to conns connections to the NAG ahead of time by sending
+// that many concurrent pings, so the first submissions do not pay for DNS,
+// dialing and TLS. Connections beyond the transport's idle limit
+// (DefaultMaxIdleConnsPerHost for the shared client) are closed again once
+// the pings complete. The errors of failed pings are joined.
+func (c *Client) Prewarm(ctx context.Context, conns int) error {
+	errs := make([]error, conns)
+	var wg sync.WaitGroup
+	for i := 0; i < conns; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.Ping(ctx)
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.Contains(r.URL.RawQuery, "old") {
+			w.Write([]byte(`{"Result":115,"Response":"Unsupported Version"}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"Blocks":42}}`))
+	}))
+	defer server.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	t.Run("Healthy", func(t *testing.T) {
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithRootCAs(pool))
+		result, err := c.Ping(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if result.BlockHeight != 42 || result.Latency <= 0 || result.TLSVersion == "" || result.Reused {
+			t.Errorf("Expected a fresh TLS connection reporting block 42, but got %+v", result)
+		}
+
+		again, err := c.Ping(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if !again.Reused {
+			t.Error("Expected the second ping to reuse the connection")
+		}
+	})
+
+	t.Run("Untrusted Certificate", func(t *testing.T) {
+		c := NewClient(server.URL, DefaultChain, LibVersion)
+		_, err := c.Ping(context.Background())
+		var unknownAuthority x509.UnknownAuthorityError
+		if !errors.As(err, &unknownAuthority) || !strings.Contains(err.Error(), server.URL) {
+			t.Errorf("Expected a certificate error naming the NAG, but got %v", err)
+		}
+	})
+
+	t.Run("Rejected Version", func(t *testing.T) {
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithRootCAs(pool))
+		c.NetworkNode = "?old"
+		_, err := c.Ping(context.Background())
+		var resultErr *ResultError
+		if !errors.As(err, &resultErr) || resultErr.Result != 115 {
+			t.Errorf("Expected the NAG's rejection, but got %v", err)
+		}
+	})
+}
+
+func TestPrewarm(t *testing.T) {
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":42}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	if err := c.Prewarm(context.Background(), 3); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if n := atomic.LoadInt32(&conns); n < 1 || n > 3 {
+		t.Errorf("Expected between 1 and 3 connections, but got %d", n)
+	}
+
+	c = NewClient("", DefaultChain, LibVersion)
+	if err := c.Prewarm(context.Background(), 2); err == nil {
+		t.Error("Expected prewarming without a network to fail")
+	}
+}
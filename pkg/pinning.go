@@ -0,0 +1,80 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrCertificatePinMismatch is returned when none of the certificates
+// presented by the server match a configured public key pin.
+var ErrCertificatePinMismatch = errors.New("server certificate does not match any pinned public key")
+
+// SPKIPin returns the pin for a certificate: the base64-encoded SHA-256 digest
+// of its DER-encoded SubjectPublicKeyInfo, prefixed with "sha256/". This is
+// the same format produced by
+//
+//	openssl x509 -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+func SPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// WithPinnedPublicKeys requires every TLS connection made by the client to
+// present a certificate chain containing at least one of the given public key
+// pins (see SPKIPin; the "sha256/" prefix is optional). Pinning is checked in
+// addition to normal certificate verification, so a compromised or
+// misbehaving CA cannot silently redirect submissions. Only the certificates
+// of the chains verified against the trusted roots are matched, not extra
+// certificates the server sends along; when InsecureSkipVerify is set no
+// chain is verified and only the server's own certificate is matched.
+//
+// Pins apply to all connections from the client, including the discovery
+// request made by SetNetwork. Apply this option after WithTLSConfig, which
+// replaces the whole TLS configuration; a VerifyConnection callback set there
+// is kept and runs before the pins are checked. Browser (js/wasm) builds
+// cannot pin, because TLS is handled by the browser.
+func WithPinnedPublicKeys(pins ...string) Option {
+	allowed := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		allowed[strings.TrimPrefix(pin, "sha256/")] = true
+	}
+	return withTransport(func(t *http.Transport) {
+		config := tlsConfig(t)
+		verifyConnection := config.VerifyConnection
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			if verifyConnection != nil {
+				if err := verifyConnection(cs); err != nil {
+					return err
+				}
+			}
+			return verifyPins(cs, allowed, config.InsecureSkipVerify)
+		}
+	})
+}
+
+// verifyPins checks the verified chain certificates against the pin set, or
+// the peer's leaf certificate when chains are not verified.
+func verifyPins(cs tls.ConnectionState, allowed map[string]bool, insecure bool) error {
+	var candidates []*x509.Certificate
+	if insecure {
+		if len(cs.PeerCertificates) > 0 {
+			candidates = cs.PeerCertificates[:1]
+		}
+	} else {
+		for _, chain := range cs.VerifiedChains {
+			candidates = append(candidates, chain...)
+		}
+	}
+	for _, cert := range candidates {
+		if allowed[strings.TrimPrefix(SPKIPin(cert), "sha256/")] {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w (host %q)", ErrCertificatePinMismatch, cs.ServerName)
+}
@@ -0,0 +1,165 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSPKIPin(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	pin := SPKIPin(server.Certificate())
+	if !strings.HasPrefix(pin, "sha256/") {
+		t.Errorf("Expected pin to start with sha256/, but got %s", pin)
+	}
+	if len(pin) != len("sha256/")+44 {
+		t.Errorf("Expected a base64 SHA-256 digest, but got %s", pin)
+	}
+}
+
+func TestWithPinnedPublicKeys(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	testCases := []struct {
+		name        string
+		pins        []string
+		expectError bool
+	}{
+		{
+			name: "Matching Pin",
+			pins: []string{SPKIPin(server.Certificate())},
+		},
+		{
+			name: "Matching Pin Without Prefix",
+			pins: []string{strings.TrimPrefix(SPKIPin(server.Certificate()), "sha256/")},
+		},
+		{
+			name:        "No Matching Pin",
+			pins:        []string{"sha256/AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRootCAs(pool), WithPinnedPublicKeys(tc.pins...))
+			acc.Open("0x123")
+
+			_, err := acc.UpdateAccount()
+			if tc.expectError {
+				if !errors.Is(err, ErrCertificatePinMismatch) {
+					t.Fatalf("Expected ErrCertificatePinMismatch, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithPinnedPublicKeysUnverifiedCertificate(t *testing.T) {
+	// The server presents a leaf trusted by the client followed by an
+	// unrelated certificate carrying the pinned key. The unrelated
+	// certificate is not part of the verified chain and must not satisfy the
+	// pin.
+	leaf, leafKey := selfSignedCertificate(t)
+	pinned, _ := selfSignedCertificate(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{leaf.Raw, pinned.Raw},
+		PrivateKey:  leafKey,
+	}}}
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRootCAs(pool), WithPinnedPublicKeys(SPKIPin(pinned)))
+	acc.Open("0x123")
+
+	if _, err := acc.UpdateAccount(); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Fatalf("Expected ErrCertificatePinMismatch, but got %v", err)
+	}
+}
+
+func TestWithPinnedPublicKeysKeepsVerifyConnection(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	errRejected := errors.New("rejected")
+	called := false
+	config := &tls.Config{
+		RootCAs: pool,
+		VerifyConnection: func(tls.ConnectionState) error {
+			called = true
+			return errRejected
+		},
+	}
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithTLSConfig(config), WithPinnedPublicKeys(SPKIPin(server.Certificate())))
+	acc.Open("0x123")
+
+	if _, err := acc.UpdateAccount(); !errors.Is(err, errRejected) {
+		t.Errorf("Expected the error of the existing VerifyConnection, but got %v", err)
+	}
+	if !called {
+		t.Error("Expected the existing VerifyConnection to be called")
+	}
+}
+
+// selfSignedCertificate returns a certificate for 127.0.0.1 and its key.
+func selfSignedCertificate(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	return cert, key
+}
@@ -0,0 +1,383 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+var (
+	// ErrPipelineFull is returned by TrySubmit when the pipeline's input
+	// queue has no room.
+	ErrPipelineFull = errors.New("pipeline is full")
+	// ErrPipelineClosed is returned by Submit and TrySubmit after the
+	// pipeline has been closed.
+	ErrPipelineClosed = errors.New("pipeline is closed")
+)
+
+const (
+	// DefaultPipelineCapacity is the number of certificates a Pipeline
+	// holds waiting to be signed.
+	DefaultPipelineCapacity = 256
+	// DefaultPipelineOutcomeTimeout is how many seconds the poll stage of a
+	// Pipeline waits for each transaction's outcome.
+	DefaultPipelineOutcomeTimeout = 60
+)
+
+// PipelineStage names a stage of a Pipeline.
+type PipelineStage string
+
+const (
+	StageSign   PipelineStage = "sign"
+	StageSubmit PipelineStage = "submit"
+	StagePoll   PipelineStage = "poll"
+)
+
+// PipelineOption configures a Pipeline.
+type PipelineOption func(*Pipeline)
+
+// WithPipelineCapacity sets how many certificates may wait to be signed.
+// Once they are all taken, Submit blocks and TrySubmit fails with
+// ErrPipelineFull. Values below one are treated as one.
+func WithPipelineCapacity(n int) PipelineOption {
+	return func(p *Pipeline) {
+		p.capacity = n
+	}
+}
+
+// WithStageWorkers sets the number of workers of each stage. By default
+// certificates are signed by one worker per CPU, and submitted and polled by
+// the client's BatchConcurrency workers each. A poll count of zero disables
+// the poll stage, so certificates complete once the NAG accepts them; other
+// values below one are treated as one.
+func WithStageWorkers(sign, submit, poll int) PipelineOption {
+	return func(p *Pipeline) {
+		p.signWorkers, p.submitWorkers, p.pollWorkers = sign, submit, poll
+	}
+}
+
+// WithPipelineOutcome sets how many seconds the poll stage waits for each
+// outcome, and the options it polls with.
+func WithPipelineOutcome(timeoutSec int, opts ...PollOption) PipelineOption {
+	return func(p *Pipeline) {
+		p.outcomeTimeout = timeoutSec
+		p.pollOptions = opts
+	}
+}
+
+// WithPipelineHandler calls fn with the result of every certificate as it
+// completes, so callers streaming many certificates need not keep their
+// PipelineSubmissions. fn runs on the worker of the last stage the
+// certificate reached, so it should return quickly.
+func WithPipelineHandler(fn func(PipelineResult)) PipelineOption {
+	return func(p *Pipeline) {
+		p.handler = fn
+	}
+}
+
+// Pipeline signs, submits and polls certificates in three stages, each with
+// a fixed number of workers connected by bounded queues. A stage that falls
+// behind fills the queue in front of it, which in turn holds back the stages
+// before it and finally Submit, so memory and goroutines stay bounded however
+// fast certificates arrive.
+//
+// Certificates are signed with SignCertificate and sent with Broadcast; a
+// failed certificate is dead-lettered as by SubmitBatch.
+type Pipeline struct {
+	account        *CEPAccount
+	privateKey     string
+	capacity       int
+	signWorkers    int
+	submitWorkers  int
+	pollWorkers    int
+	outcomeTimeout int
+	pollOptions    []PollOption
+	handler        func(PipelineResult)
+
+	// mu guards closed and sending on input, so Close never closes input
+	// under a sender.
+	mu     sync.RWMutex
+	closed bool
+
+	input     chan *PipelineSubmission
+	signed    chan *PipelineSubmission
+	submitted chan *PipelineSubmission
+	stopped   chan struct{}
+}
+
+// PipelineResult is the outcome of one certificate sent through a Pipeline.
+type PipelineResult struct {
+	Data string
+	// TxID is the ID of the signed transaction; empty if the certificate
+	// failed before it was signed.
+	TxID     string
+	Response map[string]interface{}
+	// Outcome is the transaction's outcome, unless the poll stage is
+	// disabled.
+	Outcome *Outcome
+	// Stage is the last stage the certificate reached, the one that failed
+	// if Err is set.
+	Stage PipelineStage
+	Err   error
+	// DeadLetterID is the ID under which a failed certificate was stored
+	// when the client has a dead-letter store.
+	DeadLetterID string
+}
+
+// PipelineSubmission is a certificate in flight through a Pipeline.
+type PipelineSubmission struct {
+	ctx    context.Context
+	opts   []SubmitOption
+	tx     *SignedTransaction
+	done   chan struct{}
+	result PipelineResult
+}
+
+// Done is closed once the certificate has completed or failed.
+func (s *PipelineSubmission) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait waits for the certificate to complete and returns its result, or
+// returns ctx's error if ctx is done first.
+func (s *PipelineSubmission) Wait(ctx context.Context) (PipelineResult, error) {
+	select {
+	case <-s.done:
+		return s.result, nil
+	case <-ctx.Done():
+		return PipelineResult{}, ctx.Err()
+	}
+}
+
+// NewPipeline starts a pipeline that signs certificates with privateKey. Its
+// workers run until Close is called, ctx is done or the client is shut down;
+// in the latter two cases certificates still in the pipeline fail with the
+// context's error.
+func (a *CEPAccount) NewPipeline(ctx context.Context, privateKey string, opts ...PipelineOption) (*Pipeline, error) {
+	p := &Pipeline{
+		account:        a,
+		privateKey:     privateKey,
+		capacity:       DefaultPipelineCapacity,
+		signWorkers:    runtime.NumCPU(),
+		submitWorkers:  a.BatchConcurrency,
+		pollWorkers:    a.BatchConcurrency,
+		outcomeTimeout: DefaultPipelineOutcomeTimeout,
+		stopped:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.capacity = max(p.capacity, 1)
+	p.signWorkers = max(p.signWorkers, 1)
+	p.submitWorkers = max(p.submitWorkers, 1)
+	if p.pollWorkers != 0 {
+		p.pollWorkers = max(p.pollWorkers, 1)
+	}
+
+	workerCtx, done, err := a.startWorker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.input = make(chan *PipelineSubmission, p.capacity)
+	p.signed = make(chan *PipelineSubmission, p.submitWorkers)
+	p.submitted = make(chan *PipelineSubmission, p.pollWorkers)
+
+	signing := p.stage(p.signWorkers, p.input, func(s *PipelineSubmission) { p.sign(workerCtx, s) })
+	submitting := p.stage(p.submitWorkers, p.signed, func(s *PipelineSubmission) { p.submit(workerCtx, s) })
+	polling := p.stage(p.pollWorkers, p.submitted, func(s *PipelineSubmission) { p.poll(workerCtx, s) })
+	go func() {
+		signing.Wait()
+		close(p.signed)
+		submitting.Wait()
+		close(p.submitted)
+		polling.Wait()
+		done()
+		close(p.stopped)
+	}()
+	// Once workerCtx is done the workers only fail what reaches them, so
+	// stop accepting certificates and let them drain.
+	go func() {
+		select {
+		case <-workerCtx.Done():
+			p.closeInput()
+		case <-p.stopped:
+		}
+	}()
+	return p, nil
+}
+
+// stage starts n workers that call run for each submission received from in,
+// until it is closed.
+func (p *Pipeline) stage(n int, in <-chan *PipelineSubmission, run func(*PipelineSubmission)) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range in {
+				run(s)
+			}
+		}()
+	}
+	return &wg
+}
+
+// Submit adds data to the pipeline, blocking while its input queue is full
+// until there is room or ctx is done. The certificate is processed with ctx,
+// so cancelling ctx abandons it at whichever stage it has reached.
+func (p *Pipeline) Submit(ctx context.Context, data string, opts ...SubmitOption) (*PipelineSubmission, error) {
+	return p.enqueue(ctx, data, opts, true)
+}
+
+// TrySubmit is like Submit but fails with ErrPipelineFull instead of
+// blocking when the input queue is full.
+func (p *Pipeline) TrySubmit(ctx context.Context, data string, opts ...SubmitOption) (*PipelineSubmission, error) {
+	return p.enqueue(ctx, data, opts, false)
+}
+
+// enqueue sends a new submission of data to the sign stage.
+func (p *Pipeline) enqueue(ctx context.Context, data string, opts []SubmitOption, block bool) (*PipelineSubmission, error) {
+	s := &PipelineSubmission{
+		ctx:    ctx,
+		opts:   opts,
+		done:   make(chan struct{}),
+		result: PipelineResult{Data: data, Stage: StageSign},
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return nil, ErrPipelineClosed
+	}
+	if !block {
+		select {
+		case p.input <- s:
+			return s, nil
+		default:
+			return nil, ErrPipelineFull
+		}
+	}
+	select {
+	case p.input <- s:
+		return s, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Len returns the number of certificates waiting to be signed.
+func (p *Pipeline) Len() int {
+	return len(p.input)
+}
+
+// Close stops accepting certificates and waits until those in the pipeline
+// have completed and the workers have exited, or until ctx is done.
+func (p *Pipeline) Close(ctx context.Context) error {
+	p.closeInput()
+	select {
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeInput stops accepting certificates.
+func (p *Pipeline) closeInput() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.input)
+	}
+}
+
+// sign signs s and passes it to the submit stage.
+func (p *Pipeline) sign(workerCtx context.Context, s *PipelineSubmission) {
+	ctx, cancel := itemContext(workerCtx, s)
+	defer cancel()
+	if p.abandoned(ctx, s) {
+		return
+	}
+	tx, err := p.account.SignCertificate(ctx, s.result.Data, p.privateKey, s.opts...)
+	if err != nil {
+		p.finish(ctx, s, err)
+		return
+	}
+	s.tx = tx
+	s.result.TxID = tx.ID
+	s.result.Stage = StageSubmit
+	p.signed <- s
+}
+
+// submit broadcasts the transaction of s and passes it to the poll stage,
+// if any.
+func (p *Pipeline) submit(workerCtx context.Context, s *PipelineSubmission) {
+	ctx, cancel := itemContext(workerCtx, s)
+	defer cancel()
+	if p.abandoned(ctx, s) {
+		return
+	}
+	response, err := p.account.Broadcast(ctx, s.tx)
+	s.result.Response = response
+	if err == nil {
+		err = p.account.resultError("AddTransaction", response)
+	}
+	if err != nil || p.pollWorkers == 0 {
+		p.finish(ctx, s, err)
+		return
+	}
+	s.result.Stage = StagePoll
+	p.submitted <- s
+}
+
+// poll waits for the outcome of the transaction of s.
+func (p *Pipeline) poll(workerCtx context.Context, s *PipelineSubmission) {
+	ctx, cancel := itemContext(workerCtx, s)
+	defer cancel()
+	if p.abandoned(ctx, s) {
+		return
+	}
+	outcome, err := p.account.WaitForOutcome(ctx, s.result.TxID, p.outcomeTimeout, p.pollOptions...)
+	s.result.Outcome = outcome
+	p.finish(ctx, s, err)
+}
+
+// itemContext returns the context s is processed with: its own, marked as
+// bulk traffic and also cancelled when the pipeline stops.
+func itemContext(workerCtx context.Context, s *PipelineSubmission) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ContextWithBulk(s.ctx))
+	if workerCtx.Err() != nil {
+		cancel()
+		return ctx, cancel
+	}
+	stop := context.AfterFunc(workerCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// abandoned fails s if ctx is done.
+func (p *Pipeline) abandoned(ctx context.Context, s *PipelineSubmission) bool {
+	if err := ctx.Err(); err != nil {
+		p.finish(ctx, s, err)
+		return true
+	}
+	return false
+}
+
+// finish completes s with err, dead-lettering it on failure unless it failed
+// only because ctx is done.
+func (p *Pipeline) finish(ctx context.Context, s *PipelineSubmission, err error) {
+	s.result.Err = err
+	a := p.account
+	if err != nil && a.deadLetters != nil && (ctx.Err() == nil || !errors.Is(err, ctx.Err())) {
+		s.result.DeadLetterID = a.deadLetter(context.WithoutCancel(ctx), s.result.Data, s.result.TxID, err)
+	}
+	close(s.done)
+	if p.handler != nil {
+		p.handler(s.result)
+	}
+}
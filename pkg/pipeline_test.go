@@ -0,0 +1,161 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pipelineServer accepts certificates, rejecting those whose data is
+// "reject" and holding "hold" until release is closed, and reports every
+// transaction as executed.
+func pipelineServer(t *testing.T) (server *httptest.Server, started, release chan struct{}) {
+	started = make(chan struct{})
+	release = make(chan struct{})
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		payload, ok := body["Payload"].(string)
+		if !ok {
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","BlockID":"7","BlockTimestamp":"t","Position":0,"NodeID":"n"}}`))
+			return
+		}
+		data, _ := decodePayload(payload)
+		switch data {
+		case "reject":
+			w.Write([]byte(`{"Result":108,"Response":"Invalid Signature"}`))
+			return
+		case "hold":
+			close(started)
+			<-release
+		}
+		fmt.Fprintf(w, `{"Result":200,"Response":{"TxID":%q}}`, body["ID"])
+	}))
+	t.Cleanup(server.Close)
+	return server, started, release
+}
+
+func TestPipeline(t *testing.T) {
+	server, started, release := pipelineServer(t)
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+	ctx := context.Background()
+
+	t.Run("Stages", func(t *testing.T) {
+		var mu sync.Mutex
+		handled := 0
+		p, err := acc.NewPipeline(ctx, privateKey,
+			WithStageWorkers(2, 2, 2),
+			WithPipelineOutcome(5, WithPollStrategy(FixedPoll{Interval: 10 * time.Millisecond})),
+			WithPipelineHandler(func(PipelineResult) {
+				mu.Lock()
+				defer mu.Unlock()
+				handled++
+			}))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		var submissions []*PipelineSubmission
+		for i := 0; i < 10; i++ {
+			s, err := p.Submit(ctx, fmt.Sprintf("certificate %d", i))
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			submissions = append(submissions, s)
+		}
+		rejected, _ := p.Submit(ctx, "reject")
+		if err := p.Close(ctx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+
+		for i, s := range submissions {
+			result, _ := s.Wait(ctx)
+			if result.Err != nil || result.Stage != StagePoll || result.Outcome == nil || result.Outcome.TxID != result.TxID || result.Data != fmt.Sprintf("certificate %d", i) {
+				t.Errorf("Expected certificate %d to complete, but got %+v", i, result)
+			}
+		}
+		result, _ := rejected.Wait(ctx)
+		var resultErr *ResultError
+		if !errors.As(result.Err, &resultErr) || result.Stage != StageSubmit || result.TxID == "" {
+			t.Errorf("Expected a *ResultError at the submit stage, but got %+v", result)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if handled != 11 {
+			t.Errorf("Expected 11 handled results, but got %d", handled)
+		}
+		if _, err := p.Submit(ctx, "late"); !errors.Is(err, ErrPipelineClosed) {
+			t.Errorf("Expected ErrPipelineClosed, but got %v", err)
+		}
+	})
+
+	t.Run("Backpressure", func(t *testing.T) {
+		p, err := acc.NewPipeline(ctx, privateKey, WithStageWorkers(1, 1, 0), WithPipelineCapacity(1))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		held, _ := p.Submit(ctx, "hold")
+		<-started
+		// With the submit worker held, one certificate waits to be
+		// submitted, the sign worker holds another and a third fills the
+		// input queue.
+		waitFor := func(cond func() bool) {
+			deadline := time.Now().Add(5 * time.Second)
+			for !cond() && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+		p.Submit(ctx, "a")
+		waitFor(func() bool { return len(p.signed) == 1 })
+		p.Submit(ctx, "b")
+		waitFor(func() bool { return p.Len() == 0 })
+		if _, err := p.Submit(ctx, "c"); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+
+		if _, err := p.TrySubmit(ctx, "full"); !errors.Is(err, ErrPipelineFull) {
+			t.Errorf("Expected ErrPipelineFull, but got %v", err)
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if _, err := p.Submit(timeoutCtx, "full"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected Submit to block until its context is done, but got %v", err)
+		}
+		if p.Len() != 1 {
+			t.Errorf("Expected 1 certificate waiting, but got %d", p.Len())
+		}
+
+		close(release)
+		if err := p.Close(ctx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if result, _ := held.Wait(ctx); result.Err != nil || result.Stage != StageSubmit || result.Outcome != nil {
+			t.Errorf("Expected the held certificate to complete without polling, but got %+v", result)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		pipelineCtx, cancel := context.WithCancel(ctx)
+		p, err := acc.NewPipeline(pipelineCtx, privateKey)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		cancel()
+		closeCtx, stop := context.WithTimeout(ctx, 5*time.Second)
+		defer stop()
+		if err := p.Close(closeCtx); err != nil {
+			t.Fatalf("Expected the pipeline to stop, but got: %v", err)
+		}
+		if _, err := p.Submit(ctx, "late"); !errors.Is(err, ErrPipelineClosed) {
+			t.Errorf("Expected ErrPipelineClosed, but got %v", err)
+		}
+	})
+}
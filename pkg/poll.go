@@ -0,0 +1,280 @@
+package circular_enterprise_apis
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// PollStrategy decides how long GetTransactionOutcome waits between polls.
+// Networks confirm at very different speeds, so the strategy can be chosen
+// per client with WithDefaultPollStrategy or per call with WithPollStrategy.
+type PollStrategy interface {
+	// Next returns the delay after the given poll (starting at 1), with
+	// elapsed being the time since polling started.
+	Next(attempt int, elapsed time.Duration) time.Duration
+}
+
+// ConfirmationObserver is implemented by strategies that learn from the time
+// transactions take to confirm. ObserveConfirmation is called with the time
+// from the first poll until an outcome was returned.
+type ConfirmationObserver interface {
+	ObserveConfirmation(latency time.Duration)
+}
+
+// FixedPoll waits the same Interval between every poll.
+type FixedPoll struct {
+	Interval time.Duration
+}
+
+// Next implements PollStrategy.
+func (p FixedPoll) Next(attempt int, elapsed time.Duration) time.Duration {
+	return p.Interval
+}
+
+// ExponentialPoll starts at Initial and multiplies the delay by Multiplier
+// after every poll, up to Max when it is positive. A Multiplier below 1 is
+// treated as 2.
+type ExponentialPoll struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// Next implements PollStrategy.
+func (p ExponentialPoll) Next(attempt int, elapsed time.Duration) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	delay := float64(p.Initial)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if p.Max > 0 && delay >= float64(p.Max) {
+			return p.Max
+		}
+	}
+	return time.Duration(delay)
+}
+
+// adaptiveWindow is the number of recent confirmations an AdaptivePoll keeps.
+const adaptiveWindow = 50
+
+// AdaptivePoll tunes its delays to the confirmation latency observed on the
+// network. Until a confirmation has been observed it behaves like Fallback;
+// afterwards it waits until the median observed latency before the second
+// poll and until the 90th percentile before the third, then continues with
+// Fallback. It is safe for concurrent use and is meant to be shared by every
+// call against the same network.
+type AdaptivePoll struct {
+	Fallback PollStrategy
+
+	mu        sync.Mutex
+	latencies []time.Duration
+}
+
+// NewAdaptivePoll returns an AdaptivePoll that uses fallback before any
+// confirmation has been observed and after its percentile-based delays.
+func NewAdaptivePoll(fallback PollStrategy) *AdaptivePoll {
+	return &AdaptivePoll{Fallback: fallback}
+}
+
+// ObserveConfirmation implements ConfirmationObserver.
+func (p *AdaptivePoll) ObserveConfirmation(latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.latencies = append(p.latencies, latency)
+	if len(p.latencies) > adaptiveWindow {
+		p.latencies = p.latencies[len(p.latencies)-adaptiveWindow:]
+	}
+}
+
+// Percentile returns the q-th quantile (0 to 1) of the observed latencies and
+// whether any have been observed.
+func (p *AdaptivePoll) Percentile(q float64) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.latencies) == 0 {
+		return 0, false
+	}
+	sorted := append([]time.Duration(nil), p.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(q * float64(len(sorted)-1))
+	return sorted[index], true
+}
+
+// Next implements PollStrategy.
+func (p *AdaptivePoll) Next(attempt int, elapsed time.Duration) time.Duration {
+	var target time.Duration
+	var ok bool
+	switch attempt {
+	case 1:
+		target, ok = p.Percentile(0.5)
+	case 2:
+		target, ok = p.Percentile(0.9)
+	}
+	if ok && target > elapsed {
+		return target - elapsed
+	}
+	fallbackAttempt := attempt
+	if _, observed := p.Percentile(0.5); observed && attempt > 2 {
+		fallbackAttempt = attempt - 2
+	}
+	return p.fallback().Next(fallbackAttempt, elapsed)
+}
+
+// Timeout returns how long to poll for a confirmation given the observed
+// latencies: three times the slowest of them, but at least
+// MinAdaptiveTimeout, or fallback if none have been observed.
+func (p *AdaptivePoll) Timeout(fallback time.Duration) time.Duration {
+	slowest, ok := p.Percentile(1)
+	if !ok {
+		return fallback
+	}
+	return max(3*slowest, MinAdaptiveTimeout)
+}
+
+// fallback returns the configured fallback or a one-second fixed interval.
+func (p *AdaptivePoll) fallback() PollStrategy {
+	if p.Fallback == nil {
+		return FixedPoll{Interval: time.Second}
+	}
+	return p.Fallback
+}
+
+const (
+	// DefaultAdaptiveTimeout is how long GetTransactionOutcome polls with an
+	// AdaptivePoll and no timeout before any confirmation was observed.
+	DefaultAdaptiveTimeout = 2 * time.Minute
+	// MinAdaptiveTimeout is the shortest timeout an AdaptivePoll derives
+	// from the observed latencies.
+	MinAdaptiveTimeout = 10 * time.Second
+)
+
+// WithAdaptivePolling polls every network with its own AdaptivePoll, so the
+// delays follow the confirmation latency observed on the network the client
+// is set to, falling back on fallback, or on the client's default strategy
+// when nil. Calls that choose a strategy with WithPollStrategy are not
+// affected. Combined with a timeoutSec of zero, the timeout of
+// GetTransactionOutcome follows the observed latency too.
+func WithAdaptivePolling(fallback PollStrategy) Option {
+	return func(c *Client) {
+		c.adaptivePolls = &adaptivePolls{fallback: fallback, polls: make(map[string]*AdaptivePoll)}
+	}
+}
+
+// adaptivePolls holds an AdaptivePoll per network, keyed by NAG URL.
+type adaptivePolls struct {
+	fallback PollStrategy
+
+	mu    sync.Mutex
+	polls map[string]*AdaptivePoll
+}
+
+// forNetwork returns the AdaptivePoll of the network at nagURL, creating it
+// with the given default fallback if needed.
+func (a *adaptivePolls) forNetwork(nagURL string, fallback PollStrategy) *AdaptivePoll {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p, ok := a.polls[nagURL]
+	if !ok {
+		if a.fallback != nil {
+			fallback = a.fallback
+		}
+		p = NewAdaptivePoll(fallback)
+		a.polls[nagURL] = p
+	}
+	return p
+}
+
+// AdaptivePoll returns the AdaptivePoll of the network the client is set to,
+// through which its observed confirmation latencies can be read, or nil
+// without WithAdaptivePolling.
+func (c *Client) AdaptivePoll() *AdaptivePoll {
+	if c.adaptivePolls == nil {
+		return nil
+	}
+	return c.adaptivePolls.forNetwork(c.NAGURL, c.defaultPollStrategy())
+}
+
+// WithDefaultPollStrategy sets the strategy used by GetTransactionOutcome
+// when a call does not choose one. Without it the client polls every
+// IntervalSec seconds.
+func WithDefaultPollStrategy(strategy PollStrategy) Option {
+	return func(c *Client) {
+		c.PollStrategy = strategy
+	}
+}
+
+// DefaultNotFoundGrace is a suggested NotFoundGrace: how long
+// GetTransactionOutcome keeps polling a transaction the network reports as
+// not found before treating it as dropped. Freshly submitted transactions can
+// take a few blocks to be indexed. Clients have no grace unless one is set
+// with WithDefaultNotFoundGrace or WithNotFoundGrace.
+const DefaultNotFoundGrace = 60 * time.Second
+
+// WithDefaultNotFoundGrace sets the client's NotFoundGrace.
+func WithDefaultNotFoundGrace(grace time.Duration) Option {
+	return func(c *Client) {
+		c.NotFoundGrace = grace
+	}
+}
+
+// PollOption adjusts a single call to GetTransactionOutcome.
+type PollOption func(*pollOptions)
+
+// pollOptions holds the per-call settings collected from PollOptions.
+type pollOptions struct {
+	strategy      PollStrategy
+	onAttempt     func(attempt int, status string, err error)
+	notFoundGrace time.Duration
+}
+
+// WithPollStrategy polls with strategy for this call only.
+func WithPollStrategy(strategy PollStrategy) PollOption {
+	return func(o *pollOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithNotFoundGrace overrides the client's NotFoundGrace for this call.
+func WithNotFoundGrace(grace time.Duration) PollOption {
+	return func(o *pollOptions) {
+		o.notFoundGrace = grace
+	}
+}
+
+// OnAttempt calls fn after every poll with the attempt number (starting at
+// 1), the transaction status reported by the NAG, or an empty string if the
+// transaction was not found yet, and the error of the poll, if any. fn runs on
+// the polling goroutine, so it should return quickly.
+func OnAttempt(fn func(attempt int, status string, err error)) PollOption {
+	return func(o *pollOptions) {
+		o.onAttempt = fn
+	}
+}
+
+// newPollOptions applies opts on top of the client's defaults.
+func (c *Client) newPollOptions(opts []PollOption) pollOptions {
+	o := pollOptions{notFoundGrace: c.NotFoundGrace}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.strategy == nil {
+		if adaptive := c.AdaptivePoll(); adaptive != nil {
+			o.strategy = adaptive
+		} else {
+			o.strategy = c.defaultPollStrategy()
+		}
+	}
+	return o
+}
+
+// defaultPollStrategy returns the client's PollStrategy, or a fixed interval
+// of IntervalSec seconds.
+func (c *Client) defaultPollStrategy() PollStrategy {
+	if c.PollStrategy != nil {
+		return c.PollStrategy
+	}
+	return FixedPoll{Interval: time.Duration(c.IntervalSec) * time.Second}
+}
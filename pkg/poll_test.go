@@ -0,0 +1,254 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPollStrategies(t *testing.T) {
+	exponential := ExponentialPoll{Initial: 100 * time.Millisecond, Max: time.Second}
+	testCases := []struct {
+		name     string
+		strategy PollStrategy
+		attempt  int
+		elapsed  time.Duration
+		expected time.Duration
+	}{
+		{"Fixed", FixedPoll{Interval: 2 * time.Second}, 5, 0, 2 * time.Second},
+		{"Exponential First", exponential, 1, 0, 100 * time.Millisecond},
+		{"Exponential Third", exponential, 3, 0, 400 * time.Millisecond},
+		{"Exponential Capped", exponential, 10, 0, time.Second},
+		{"Adaptive Without Observations", NewAdaptivePoll(FixedPoll{Interval: 3 * time.Second}), 1, 0, 3 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.strategy.Next(tc.attempt, tc.elapsed); got != tc.expected {
+				t.Errorf("Expected %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAdaptivePoll(t *testing.T) {
+	p := NewAdaptivePoll(FixedPoll{Interval: time.Second})
+	for _, latency := range []time.Duration{4, 5, 6, 7, 20} {
+		p.ObserveConfirmation(latency * time.Second)
+	}
+
+	if got := p.Next(1, time.Second); got != 5*time.Second {
+		t.Errorf("Expected to wait until the median (5s remaining), but got %v", got)
+	}
+	if got := p.Next(2, 6*time.Second); got != time.Second {
+		t.Errorf("Expected to wait until the 90th percentile (1s remaining), but got %v", got)
+	}
+	if got := p.Next(3, 7*time.Second); got != time.Second {
+		t.Errorf("Expected fallback interval, but got %v", got)
+	}
+
+	for i := 0; i < 2*adaptiveWindow; i++ {
+		p.ObserveConfirmation(time.Second)
+	}
+	if median, _ := p.Percentile(0.5); median != time.Second {
+		t.Errorf("Expected old observations to age out, but median is %v", median)
+	}
+}
+
+func TestAdaptivePollTimeout(t *testing.T) {
+	p := NewAdaptivePoll(nil)
+	if got := p.Timeout(time.Minute); got != time.Minute {
+		t.Errorf("Expected the fallback timeout without observations, but got %v", got)
+	}
+	p.ObserveConfirmation(time.Second)
+	if got := p.Timeout(time.Minute); got != MinAdaptiveTimeout {
+		t.Errorf("Expected the minimum timeout, but got %v", got)
+	}
+	p.ObserveConfirmation(20 * time.Second)
+	if got := p.Timeout(time.Minute); got != time.Minute {
+		t.Errorf("Expected three times the slowest latency, but got %v", got)
+	}
+}
+
+func TestAdaptivePolling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithAdaptivePolling(nil), WithDefaultPollStrategy(FixedPoll{Interval: time.Hour}))
+	adaptive := c.AdaptivePoll()
+	if adaptive == nil || adaptive.Fallback != (FixedPoll{Interval: time.Hour}) {
+		t.Fatalf("Expected an AdaptivePoll falling back on the client's strategy, but got %+v", adaptive)
+	}
+	if _, err := c.GetTransactionOutcomeContext(context.Background(), "tx", 0); err != nil {
+		t.Fatalf("Expected no error with an adaptive timeout, but got: %v", err)
+	}
+	if _, ok := adaptive.Percentile(0.5); !ok {
+		t.Error("Expected the confirmation to be observed by the network's AdaptivePoll")
+	}
+
+	strategy := &countingPoll{}
+	c.GetTransactionOutcomeContext(context.Background(), "tx", 5, WithPollStrategy(strategy))
+	if strategy.observed != 1 {
+		t.Error("Expected a per-call strategy to take precedence")
+	}
+
+	c.NAGURL = server.URL + "/other/"
+	if other := c.AdaptivePoll(); other == adaptive {
+		t.Error("Expected each network to have its own AdaptivePoll")
+	}
+	if NewClient(server.URL, DefaultChain, LibVersion).AdaptivePoll() != nil {
+		t.Error("Expected no AdaptivePoll without WithAdaptivePolling")
+	}
+}
+
+// countingPoll records how often it was consulted.
+type countingPoll struct {
+	calls    int32
+	observed int32
+}
+
+func (p *countingPoll) Next(attempt int, elapsed time.Duration) time.Duration {
+	atomic.AddInt32(&p.calls, 1)
+	return time.Millisecond
+}
+
+func (p *countingPoll) ObserveConfirmation(latency time.Duration) {
+	atomic.AddInt32(&p.observed, 1)
+}
+
+func TestGetTransactionOutcomeWithPollStrategy(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Pending"}}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion,
+		WithDefaultPollStrategy(FixedPoll{Interval: time.Hour}),
+		WithLogger(func(ctx context.Context, message string) {}))
+
+	strategy := &countingPoll{}
+	outcome, err := c.GetTransactionOutcomeContext(context.Background(), "tx", 5, WithPollStrategy(strategy))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if outcome["Status"] != "Executed" {
+		t.Errorf("Expected status Executed, but got %v", outcome["Status"])
+	}
+	if strategy.calls != 2 || strategy.observed != 1 {
+		t.Errorf("Expected the per-call strategy to be used twice and observe once, got %d and %d", strategy.calls, strategy.observed)
+	}
+}
+
+func TestGetTransactionOutcomeOnAttempt(t *testing.T) {
+	responses := []string{
+		`{"Result":108,"Response":"Transaction Not Found"}`,
+		`{"Result":200,"Response":{"Status":"Pending"}}`,
+		`{"Result":200,"Response":{"Status":"Executed"}}`,
+	}
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&polls, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(responses[n-2]))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithLogger(func(ctx context.Context, message string) {}))
+
+	type attemptRecord struct {
+		attempt int
+		status  string
+		failed  bool
+	}
+	var records []attemptRecord
+	_, err := c.GetTransactionOutcomeContext(context.Background(), "tx", 5,
+		WithPollStrategy(FixedPoll{Interval: time.Millisecond}),
+		OnAttempt(func(attempt int, status string, err error) {
+			records = append(records, attemptRecord{attempt, status, err != nil})
+		}))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	expected := []attemptRecord{{1, "", true}, {2, "", false}, {3, "Pending", false}, {4, "Executed", false}}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d attempts, but got %v", len(expected), records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("Expected attempt %+v, but got %+v", expected[i], records[i])
+		}
+	}
+}
+
+func TestGetTransactionOutcomeNotFoundGrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":118,"Response":"Transaction Not Found"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion,
+		WithDefaultPollStrategy(FixedPoll{Interval: 10 * time.Millisecond}),
+		WithLogger(func(ctx context.Context, message string) {}))
+
+	t.Run("Dropped After Grace", func(t *testing.T) {
+		start := time.Now()
+		_, err := c.GetTransactionOutcomeContext(context.Background(), "tx", 5, WithNotFoundGrace(50*time.Millisecond))
+		if !errors.Is(err, ErrTransactionDropped) {
+			t.Fatalf("Expected ErrTransactionDropped, but got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Expected polling to stop soon after the grace period, but it took %v", elapsed)
+		}
+	})
+
+	t.Run("No Default Grace", func(t *testing.T) {
+		if c.NotFoundGrace != 0 {
+			t.Errorf("Expected no grace unless one is set, but got %v", c.NotFoundGrace)
+		}
+	})
+
+	t.Run("Disabled Grace Polls Until Timeout", func(t *testing.T) {
+		_, err := c.GetTransactionOutcomeContext(context.Background(), "tx", 1, WithNotFoundGrace(0))
+		if err == nil || errors.Is(err, ErrTransactionDropped) {
+			t.Errorf("Expected a timeout error, but got %v", err)
+		}
+	})
+}
+
+func TestGetTransactionOutcomeNotFoundWithResult200(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":"Transaction Not Found"}`))
+	}))
+	defer server.Close()
+
+	for _, mode := range []ParseMode{ParseLenient, ParseStrict} {
+		c := NewClient(server.URL, DefaultChain, LibVersion,
+			WithDefaultPollStrategy(FixedPoll{Interval: 10 * time.Millisecond}),
+			WithDefaultNotFoundGrace(50*time.Millisecond),
+			WithLogger(func(ctx context.Context, message string) {}))
+		c.ParseMode = mode
+		_, err := c.GetTransactionOutcomeContext(context.Background(), "tx", 5)
+		if !errors.Is(err, ErrTransactionDropped) {
+			t.Errorf("Expected ErrTransactionDropped in parse mode %v, but got %v", mode, err)
+		}
+	}
+}
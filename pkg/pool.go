@@ -0,0 +1,73 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// maxPooledBufferSize caps the capacity of buffers returned to the pool so a
+// single very large certificate does not pin its memory for the lifetime of
+// the process.
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool holds scratch buffers reused across submissions.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns a buffer to the pool unless it has grown too large.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// appendHex writes the lowercase hexadecimal encoding of src to buf without
+// allocating an intermediate string.
+func appendHex(buf *bytes.Buffer, src []byte) {
+	n := hex.EncodedLen(len(src))
+	buf.Grow(n)
+	dst := buf.AvailableBuffer()[:n]
+	hex.Encode(dst, src)
+	buf.Write(dst)
+}
+
+// encodeJSON marshals v into buf using the same escaping rules as
+// json.Marshal, without the trailing newline added by json.Encoder.
+func encodeJSON(buf *bytes.Buffer, v interface{}) error {
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+// pooledBody is an HTTP request body backed by a pooled buffer. The buffer is
+// returned to the pool when the transport closes the body, which is the first
+// point at which it is guaranteed to no longer be read.
+type pooledBody struct {
+	*bytes.Reader
+	buf  *bytes.Buffer
+	once sync.Once
+}
+
+// newPooledBody wraps buf as a request body that releases it on Close.
+func newPooledBody(buf *bytes.Buffer) *pooledBody {
+	return &pooledBody{Reader: bytes.NewReader(buf.Bytes()), buf: buf}
+}
+
+// Close returns the underlying buffer to the pool.
+func (b *pooledBody) Close() error {
+	b.once.Do(func() { putBuffer(b.buf) })
+	return nil
+}
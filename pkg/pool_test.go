@@ -0,0 +1,101 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestEncodeJSONMatchesMarshal(t *testing.T) {
+	inputs := []string{"hello world", "", "<script>&</script>", "你好, 世界", "line\nbreak"}
+
+	for _, input := range inputs {
+		expected, err := json.Marshal(map[string]interface{}{"data": input})
+		if err != nil {
+			t.Fatalf("Failed to marshal expected payload: %v", err)
+		}
+
+		buf := getBuffer()
+		if err := encodeJSON(buf, certificatePayload{Data: input}); err != nil {
+			t.Fatalf("encodeJSON failed: %v", err)
+		}
+		if buf.String() != string(expected) {
+			t.Errorf("Expected %s, but got %s", expected, buf.String())
+		}
+		putBuffer(buf)
+	}
+}
+
+func TestAppendHex(t *testing.T) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteString("prefix:")
+	appendHex(buf, []byte("hello"))
+	if buf.String() != "prefix:68656c6c6f" {
+		t.Errorf("Expected prefix:68656c6c6f, but got %s", buf.String())
+	}
+}
+
+func TestPooledBodyCloseIsIdempotent(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("body")
+	body := newPooledBody(buf)
+
+	data, err := io.ReadAll(body)
+	if err != nil || string(data) != "body" {
+		t.Fatalf("Expected to read 'body', got %q (err=%v)", data, err)
+	}
+	if err := body.Close(); err != nil {
+		t.Errorf("Expected no error on first Close, got %v", err)
+	}
+	if err := body.Close(); err != nil {
+		t.Errorf("Expected no error on second Close, got %v", err)
+	}
+}
+
+func TestSubmitCertificateRequestBody(t *testing.T) {
+	var captured certificateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"0xabc"}}`))
+	}))
+	defer server.Close()
+
+	privateKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate private key: %v", err)
+	}
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x123")
+
+	if _, err := acc.SubmitCertificate("<payload & data>", hex.EncodeToString(privateKey.Serialize())); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	payloadObject, err := json.Marshal(map[string]interface{}{"data": "<payload & data>"})
+	if err != nil {
+		t.Fatalf("Failed to marshal payload object: %v", err)
+	}
+	if captured.Payload != hex.EncodeToString(payloadObject) {
+		t.Errorf("Expected Payload %s, but got %s", hex.EncodeToString(payloadObject), captured.Payload)
+	}
+
+	sum := sha256.Sum256([]byte(captured.Address + captured.Blockchain + captured.Payload + captured.Timestamp))
+	if captured.ID != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected ID %s, but got %s", hex.EncodeToString(sum[:]), captured.ID)
+	}
+	if captured.Signature == "" {
+		t.Error("Expected a non-empty signature")
+	}
+}
@@ -0,0 +1,126 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultPreflightAsset is the asset whose balance is checked when a
+// Preflight does not name one.
+const DefaultPreflightAsset = "CIRX"
+
+// Errors matched by errors.Is on a *PreflightError.
+var (
+	ErrNotRegistered       = errors.New("account is not registered")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrNotAuthorized       = errors.New("account is not authorized to submit certificates")
+	ErrKeyMismatch         = errors.New("signing key does not match the registered public key")
+)
+
+// Preflight describes the checks made before a certificate is signed and
+// submitted. Catching a doomed submission here avoids spending a nonce and a
+// round trip on a transaction the network will reject.
+type Preflight struct {
+	// Asset is the asset whose balance is checked. It defaults to
+	// DefaultPreflightAsset.
+	Asset string
+	// MinBalance is the smallest balance of Asset the account must hold.
+	// Zero only checks that the account is registered.
+	MinBalance float64
+	// CheckPermissions checks that the wallet holds
+	// PermissionSubmitCertificate on chains that report permissions and,
+	// when submitting, that the signing key is the one registered for the
+	// wallet.
+	CheckPermissions bool
+}
+
+// PreflightError reports a failed pre-flight check together with a hint on
+// how to resolve it.
+type PreflightError struct {
+	Address    string
+	Blockchain string
+	Err        error
+	Hint       string
+}
+
+// Error implements the error interface.
+func (e *PreflightError) Error() string {
+	return fmt.Sprintf("preflight failed for %s on chain %s: %v; %s", e.Address, e.Blockchain, e.Err, e.Hint)
+}
+
+// Unwrap returns the sentinel describing the failed check.
+func (e *PreflightError) Unwrap() error {
+	return e.Err
+}
+
+// WithPreflight runs the given checks before the certificate is signed.
+func WithPreflight(p Preflight) SubmitOption {
+	return func(o *submitOptions) {
+		o.preflight = &p
+	}
+}
+
+// Preflight runs the checks described by p against the account's default
+// chain and returns a *PreflightError if one fails.
+func (a *CEPAccount) Preflight(ctx context.Context, p Preflight) (err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	return a.preflight(ctx, p, a.Blockchain, a.NAGURL, "")
+}
+
+// preflight runs the checks against the given chain and NAG. The signing key
+// is only checked when privateKey is set.
+func (a *CEPAccount) preflight(ctx context.Context, p Preflight, blockchain, nagURL, privateKey string) error {
+	if a.Address == "" {
+		return errors.New("Account is not open")
+	}
+
+	info, err := a.walletInfo(ctx, blockchain, nagURL)
+	if err != nil {
+		return fmt.Errorf("preflight check could not fetch the wallet: %w", err)
+	}
+
+	if !info.Registered {
+		return &PreflightError{
+			Address:    a.Address,
+			Blockchain: blockchain,
+			Err:        ErrNotRegistered,
+			Hint:       "register the wallet on this chain before submitting",
+		}
+	}
+
+	if p.CheckPermissions {
+		if !info.Permissions.Allows(PermissionSubmitCertificate) {
+			return &PreflightError{
+				Address:    a.Address,
+				Blockchain: blockchain,
+				Err:        ErrNotAuthorized,
+				Hint:       fmt.Sprintf("the wallet has roles %v and permissions %v; ask the chain operator to grant %s", info.Permissions.Roles, info.Permissions.Granted, PermissionSubmitCertificate),
+			}
+		}
+		if privateKey != "" && info.PublicKey != "" && !keyMatches(a.signingCurve(), privateKey, info.PublicKey) {
+			return &PreflightError{
+				Address:    a.Address,
+				Blockchain: blockchain,
+				Err:        ErrKeyMismatch,
+				Hint:       "sign with the private key of the wallet's registered public key",
+			}
+		}
+	}
+
+	asset := p.Asset
+	if asset == "" {
+		asset = DefaultPreflightAsset
+	}
+	if balance := info.Balance(asset); balance < p.MinBalance {
+		return &PreflightError{
+			Address:    a.Address,
+			Blockchain: blockchain,
+			Err:        ErrInsufficientBalance,
+			Hint:       fmt.Sprintf("the account holds %g %s but at least %g is required", balance, asset, p.MinBalance),
+		}
+	}
+	return nil
+}
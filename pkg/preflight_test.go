@@ -0,0 +1,124 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPreflight(t *testing.T) {
+	testCases := []struct {
+		name          string
+		walletReply   string
+		preflight     Preflight
+		expectedError error
+	}{
+		{
+			name:        "Registered",
+			walletReply: `{"Result":200,"Response":{"Assets":[{"Name":"CIRX","Amount":"5"}]}}`,
+		},
+		{
+			name:        "Sufficient Balance",
+			walletReply: `{"Result":200,"Response":{"Assets":[{"Name":"CIRX","Amount":"5"}]}}`,
+			preflight:   Preflight{MinBalance: 5},
+		},
+		{
+			name:          "Insufficient Balance",
+			walletReply:   `{"Result":200,"Response":{"Assets":[{"Name":"CIRX","Amount":"5"}]}}`,
+			preflight:     Preflight{MinBalance: 10},
+			expectedError: ErrInsufficientBalance,
+		},
+		{
+			name:          "Other Asset",
+			walletReply:   `{"Result":200,"Response":{"Assets":[{"Name":"CIRX","Amount":"5"}]}}`,
+			preflight:     Preflight{Asset: "USDC", MinBalance: 1},
+			expectedError: ErrInsufficientBalance,
+		},
+		{
+			name:          "Not Registered",
+			walletReply:   `{"Result":118,"Response":"Wallet Not Found"}`,
+			expectedError: ErrNotRegistered,
+		},
+		{
+			name:        "Permissions Not Reported",
+			walletReply: `{"Result":200,"Response":{"Assets":[]}}`,
+			preflight:   Preflight{CheckPermissions: true},
+		},
+		{
+			name:        "Authorized",
+			walletReply: `{"Result":200,"Response":{"PublicKey":"` + testPublicKey + `","Roles":["issuer"],"Permissions":["submitcertificate"]}}`,
+			preflight:   Preflight{CheckPermissions: true},
+		},
+		{
+			name:          "Not Authorized",
+			walletReply:   `{"Result":200,"Response":{"Roles":"reader","Permissions":{"SubmitCertificate":false}}}`,
+			preflight:     Preflight{CheckPermissions: true},
+			expectedError: ErrNotAuthorized,
+		},
+		{
+			name:          "Key Mismatch",
+			walletReply:   `{"Result":200,"Response":{"PublicKey":"02ab","Permissions":["SubmitCertificate"]}}`,
+			preflight:     Preflight{CheckPermissions: true},
+			expectedError: ErrKeyMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var submitted int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				if strings.Contains(r.URL.Path, "Circular_GetWallet_") {
+					w.Write([]byte(tc.walletReply))
+					return
+				}
+				atomic.AddInt32(&submitted, 1)
+				w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+			}))
+			defer server.Close()
+
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+			acc.Open("0x" + strings.Repeat("a", 64))
+
+			_, err := acc.SubmitCertificateContext(context.Background(), "data", strings.Repeat("1", 64), WithPreflight(tc.preflight))
+			if tc.expectedError == nil {
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				if atomic.LoadInt32(&submitted) != 1 {
+					t.Error("Expected the certificate to be submitted")
+				}
+				return
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected %v, but got %v", tc.expectedError, err)
+			}
+			var preflightErr *PreflightError
+			if !errors.As(err, &preflightErr) || preflightErr.Hint == "" {
+				t.Errorf("Expected a *PreflightError with a hint, but got %v", err)
+			}
+			if atomic.LoadInt32(&submitted) != 0 {
+				t.Error("Expected the certificate not to be submitted")
+			}
+		})
+	}
+}
+
+func TestPreflightStandalone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":118,"Response":"Wallet Not Found"}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0xabc")
+	if err := acc.Preflight(context.Background(), Preflight{}); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered, but got %v", err)
+	}
+}
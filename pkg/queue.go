@@ -0,0 +1,431 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueClosed is returned by Enqueue after the queue has been closed.
+var ErrQueueClosed = errors.New("submit queue is closed")
+
+// Priority is the lane of a queued submission.
+type Priority int
+
+const (
+	// PriorityLow is for bulk traffic such as backfills and imports.
+	PriorityLow Priority = iota
+	// PriorityNormal is for regular interactive submissions.
+	PriorityNormal
+	// PriorityHigh is for urgent certificates, such as legal holds.
+	PriorityHigh
+
+	laneCount = int(PriorityHigh) + 1
+)
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// DefaultLaneWeights returns the dispatch weights of the low, normal and high
+// lanes. While all lanes are busy, each receives a share of the workers
+// proportional to its weight, so bulk traffic keeps moving behind urgent
+// certificates instead of starving.
+func DefaultLaneWeights() [laneCount]int {
+	return [laneCount]int{1, 4, 8}
+}
+
+// QueueOption configures a SubmitQueue.
+type QueueOption func(*SubmitQueue)
+
+// WithQueueWorkers sets how many certificates the queue submits at once.
+// The default is the client's BatchConcurrency.
+func WithQueueWorkers(n int) QueueOption {
+	return func(q *SubmitQueue) {
+		q.workers = n
+	}
+}
+
+// WithQueueStore persists waiting certificates in store, under the "queue/"
+// prefix, until they have been submitted. Certificates still waiting when the
+// queue stops because its context is done or the client is shut down stay in
+// the store, and are enqueued again by the next queue created with the same
+// store; see Recovered. Their SubmitOptions are not persisted, so they are
+// resubmitted without them. A store must not be shared by queues running at
+// the same time.
+func WithQueueStore(store Store) QueueOption {
+	return func(q *SubmitQueue) {
+		q.store = store
+	}
+}
+
+// WithLaneWeight sets the dispatch weight of the lane of priority p. Weights
+// below one are treated as one, so no lane is starved completely.
+func WithLaneWeight(p Priority, weight int) QueueOption {
+	return func(q *SubmitQueue) {
+		if p >= PriorityLow && p <= PriorityHigh {
+			q.weights[p] = weight
+		}
+	}
+}
+
+// SubmitQueue submits certificates in the background from one lane per
+// Priority. Within a lane certificates are submitted in order; across lanes
+// a waiting certificate of a higher priority is dispatched before lower ones,
+// and lanes then share the workers by weight (smooth weighted round-robin).
+// Each certificate is submitted as by SubmitBatch, including dead-lettering
+// of failures.
+type SubmitQueue struct {
+	account    *CEPAccount
+	privateKey string
+	workers    int
+	weights    [laneCount]int
+	store      Store
+	sequence   atomic.Int64
+	recovered  []*QueuedSubmission
+
+	mu      sync.Mutex
+	lanes   [laneCount][]*QueuedSubmission
+	current [laneCount]int
+	closed  bool
+
+	ready   chan struct{}
+	closing chan struct{}
+	stopped chan struct{}
+}
+
+// QueuedSubmission is a certificate waiting in or submitted by a SubmitQueue.
+type QueuedSubmission struct {
+	Priority Priority
+
+	ctx    context.Context
+	data   string
+	opts   []SubmitOption
+	done   chan struct{}
+	result SubmitResult
+	// key is the submission's key in the queue's store, if any.
+	key string
+}
+
+// queuePrefix is the key prefix of waiting certificates in a Store.
+const queuePrefix = "queue/"
+
+// queuedRecord is a waiting certificate as persisted in a Store.
+type queuedRecord struct {
+	Data     string    `json:"data"`
+	Priority Priority  `json:"priority"`
+	Enqueued time.Time `json:"enqueued"`
+}
+
+// Done is closed once the certificate has been submitted or has failed.
+func (s *QueuedSubmission) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait waits for the certificate to be submitted and returns its result, or
+// returns ctx's error if ctx is done first.
+func (s *QueuedSubmission) Wait(ctx context.Context) (SubmitResult, error) {
+	select {
+	case <-s.done:
+		return s.result, nil
+	case <-ctx.Done():
+		return SubmitResult{}, ctx.Err()
+	}
+}
+
+// finish records the result of the submission.
+func (s *QueuedSubmission) finish(result SubmitResult) {
+	s.result = result
+	close(s.done)
+}
+
+// NewSubmitQueue starts a queue that signs certificates with privateKey. Its
+// workers run until Close is called, ctx is done or the client is shut down;
+// in the latter two cases certificates still waiting fail with the context's
+// error.
+func (a *CEPAccount) NewSubmitQueue(ctx context.Context, privateKey string, opts ...QueueOption) (*SubmitQueue, error) {
+	q := &SubmitQueue{
+		account:    a,
+		privateKey: privateKey,
+		workers:    a.BatchConcurrency,
+		weights:    DefaultLaneWeights(),
+		ready:      make(chan struct{}, 1),
+		closing:    make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if q.workers < 1 {
+		q.workers = 1
+	}
+	for p := range q.weights {
+		if q.weights[p] < 1 {
+			q.weights[p] = 1
+		}
+	}
+
+	workerCtx, done, err := a.startWorker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if q.store != nil {
+		if err := q.recover(workerCtx); err != nil {
+			done()
+			return nil, fmt.Errorf("failed to recover queued certificates: %w", err)
+		}
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(workerCtx)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		done()
+		close(q.stopped)
+	}()
+	// Workers busy with a submission cannot notice ctx, so certificates
+	// waiting behind them are failed from here.
+	go func() {
+		select {
+		case <-workerCtx.Done():
+			q.abort(workerCtx.Err())
+		case <-q.stopped:
+		}
+	}()
+	return q, nil
+}
+
+// Enqueue adds data to the lane of priority. The certificate is submitted
+// with ctx, so cancelling ctx abandons it whether it is waiting or in flight.
+func (q *SubmitQueue) Enqueue(ctx context.Context, data string, priority Priority, opts ...SubmitOption) (*QueuedSubmission, error) {
+	if priority < PriorityLow || priority > PriorityHigh {
+		return nil, fmt.Errorf("invalid priority %d", priority)
+	}
+	s := &QueuedSubmission{
+		Priority: priority,
+		ctx:      ctx,
+		data:     data,
+		opts:     opts,
+		done:     make(chan struct{}),
+	}
+	if q.store != nil {
+		if err := q.persist(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		q.forget(s)
+		return nil, ErrQueueClosed
+	}
+	q.lanes[priority] = append(q.lanes[priority], s)
+	q.mu.Unlock()
+
+	q.notify()
+	return s, nil
+}
+
+// Len returns the number of certificates waiting in the lane of priority.
+func (q *SubmitQueue) Len(priority Priority) int {
+	if priority < PriorityLow || priority > PriorityHigh {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.lanes[priority])
+}
+
+// Close stops accepting certificates and waits until the waiting ones have
+// been submitted and the workers have exited, or until ctx is done.
+func (q *SubmitQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.closing)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work submits certificates until the queue is closed and drained, or until
+// ctx is done.
+func (q *SubmitQueue) work(ctx context.Context) {
+	for {
+		s, closed := q.next()
+		if s != nil {
+			q.submit(s)
+			continue
+		}
+		if closed {
+			return
+		}
+		select {
+		case <-q.ready:
+		case <-q.closing:
+		case <-ctx.Done():
+			q.abort(ctx.Err())
+			return
+		}
+	}
+}
+
+// submit submits s unless it was abandoned while waiting. Low-priority
+// certificates are submitted as bulk traffic.
+func (q *SubmitQueue) submit(s *QueuedSubmission) {
+	if err := s.ctx.Err(); err != nil {
+		q.forget(s)
+		s.finish(SubmitResult{Err: err})
+		return
+	}
+	ctx := s.ctx
+	if s.Priority == PriorityLow {
+		ctx = ContextWithBulk(ctx)
+	}
+	result := q.account.submitItem(ctx, s.data, q.privateKey, s.opts)
+	q.forget(s)
+	s.finish(result)
+}
+
+// Recovered returns the certificates that a previous queue left in the
+// queue's store and that this queue enqueued again when it started.
+func (q *SubmitQueue) Recovered() []*QueuedSubmission {
+	return q.recovered
+}
+
+// persist stores s under a new key that sorts after those of the
+// certificates enqueued before it.
+func (q *SubmitQueue) persist(ctx context.Context, s *QueuedSubmission) error {
+	value, err := json.Marshal(queuedRecord{Data: s.data, Priority: s.Priority, Enqueued: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to encode queued certificate: %w", err)
+	}
+	key := fmt.Sprintf("%s%020d-%010d", queuePrefix, time.Now().UnixNano(), q.sequence.Add(1))
+	if err := q.store.Put(ctx, key, value); err != nil {
+		return fmt.Errorf("failed to persist queued certificate: %w", err)
+	}
+	s.key = key
+	return nil
+}
+
+// forget removes s from the queue's store once it no longer waits.
+func (q *SubmitQueue) forget(s *QueuedSubmission) {
+	if s.key == "" {
+		return
+	}
+	if err := q.store.Delete(context.WithoutCancel(s.ctx), s.key); err != nil {
+		q.account.logf(s.ctx, "failed to remove queued certificate %s from the store: %v", s.key, err)
+	}
+}
+
+// recover enqueues the certificates left in the queue's store, in the order
+// they were first enqueued, to be submitted with ctx.
+func (q *SubmitQueue) recover(ctx context.Context) error {
+	return q.store.Iterate(ctx, queuePrefix, func(key string, value []byte) error {
+		var record queuedRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return fmt.Errorf("failed to decode queued certificate %s: %w", key, err)
+		}
+		if record.Priority < PriorityLow || record.Priority > PriorityHigh {
+			record.Priority = PriorityNormal
+		}
+		s := &QueuedSubmission{
+			Priority: record.Priority,
+			ctx:      ctx,
+			data:     record.Data,
+			done:     make(chan struct{}),
+			key:      key,
+		}
+		q.lanes[s.Priority] = append(q.lanes[s.Priority], s)
+		q.recovered = append(q.recovered, s)
+		return nil
+	})
+}
+
+// next removes the certificate to dispatch next, or returns nil and whether
+// the queue is closed when all lanes are empty. Each non-empty lane gains
+// its weight in credit, the lane with the most credit wins, ties going to the
+// higher priority, and the winner pays the total weight of the non-empty
+// lanes.
+func (q *SubmitQueue) next() (*QueuedSubmission, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lane, total, waiting := -1, 0, 0
+	for p := range q.lanes {
+		if len(q.lanes[p]) == 0 {
+			q.current[p] = 0
+			continue
+		}
+		q.current[p] += q.weights[p]
+		total += q.weights[p]
+		waiting += len(q.lanes[p])
+		if lane == -1 || q.current[p] >= q.current[lane] {
+			lane = p
+		}
+	}
+	if lane == -1 {
+		return nil, q.closed
+	}
+	q.current[lane] -= total
+
+	s := q.lanes[lane][0]
+	q.lanes[lane][0] = nil
+	q.lanes[lane] = q.lanes[lane][1:]
+	if waiting > 1 {
+		q.notify()
+	}
+	return s, false
+}
+
+// notify wakes an idle worker, if any.
+func (q *SubmitQueue) notify() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// abort closes the queue and fails every waiting certificate with err.
+func (q *SubmitQueue) abort(err error) {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.closing)
+	}
+	var waiting []*QueuedSubmission
+	for p := range q.lanes {
+		waiting = append(waiting, q.lanes[p]...)
+		q.lanes[p] = nil
+	}
+	q.mu.Unlock()
+
+	for _, s := range waiting {
+		s.finish(SubmitResult{Err: err})
+	}
+}
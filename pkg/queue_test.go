@@ -0,0 +1,154 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queueServer records the order in which certificates arrive and holds the
+// certificate "first" until release is closed.
+func queueServer(t *testing.T) (server *httptest.Server, order func() []string, started, release chan struct{}) {
+	var mu sync.Mutex
+	var received []string
+	started = make(chan struct{})
+	release = make(chan struct{})
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := decodePayload(body["Payload"].(string))
+		if data == "first" {
+			close(started)
+			<-release
+		} else {
+			mu.Lock()
+			received = append(received, data)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"ok"}}`))
+	}))
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}, started, release
+}
+
+func TestSubmitQueuePriority(t *testing.T) {
+	server, order, started, release := queueServer(t)
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	q, err := acc.NewSubmitQueue(context.Background(), strings.Repeat("1", 64), WithQueueWorkers(1))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	ctx := context.Background()
+	q.Enqueue(ctx, "first", PriorityLow)
+	<-started
+	var submissions []*QueuedSubmission
+	for i := 0; i < 5; i++ {
+		s, _ := q.Enqueue(ctx, fmt.Sprintf("bulk %d", i), PriorityLow)
+		submissions = append(submissions, s)
+	}
+	for i := 0; i < 2; i++ {
+		s, _ := q.Enqueue(ctx, fmt.Sprintf("urgent %d", i), PriorityHigh)
+		submissions = append(submissions, s)
+	}
+	if q.Len(PriorityLow) != 5 || q.Len(PriorityHigh) != 2 {
+		t.Errorf("Expected 5 low and 2 high certificates waiting, but got %d and %d", q.Len(PriorityLow), q.Len(PriorityHigh))
+	}
+	close(release)
+
+	for _, s := range submissions {
+		result, err := s.Wait(ctx)
+		if err != nil || result.Err != nil || result.TxID == "" {
+			t.Errorf("Expected the certificate to be submitted, but got %+v, %v", result, err)
+		}
+	}
+	got := order()
+	if got[0] != "urgent 0" || got[1] != "urgent 1" || got[2] != "bulk 0" {
+		t.Errorf("Expected urgent certificates to jump ahead of the backfill, but got %v", got)
+	}
+	if err := q.Close(ctx); err != nil {
+		t.Errorf("Expected no error, but got: %v", err)
+	}
+	if _, err := q.Enqueue(ctx, "late", PriorityHigh); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, but got %v", err)
+	}
+}
+
+func TestSubmitQueueFairness(t *testing.T) {
+	server, order, started, release := queueServer(t)
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	q, _ := acc.NewSubmitQueue(context.Background(), strings.Repeat("1", 64),
+		WithQueueWorkers(1), WithLaneWeight(PriorityHigh, 2), WithLaneWeight(PriorityLow, 1))
+
+	ctx := context.Background()
+	q.Enqueue(ctx, "first", PriorityHigh)
+	<-started
+	for i := 0; i < 3; i++ {
+		q.Enqueue(ctx, "low", PriorityLow)
+	}
+	for i := 0; i < 6; i++ {
+		q.Enqueue(ctx, "high", PriorityHigh)
+	}
+	close(release)
+	q.Close(ctx)
+
+	got := strings.Join(order(), " ")
+	if want := "high low high high low high high low high"; got != want {
+		t.Errorf("Expected lanes to share the worker 2:1 as %q, but got %q", want, got)
+	}
+}
+
+func TestSubmitQueueCancel(t *testing.T) {
+	server, _, started, release := queueServer(t)
+	defer server.Close()
+	defer close(release)
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	queueCtx, stop := context.WithCancel(context.Background())
+	q, _ := acc.NewSubmitQueue(queueCtx, strings.Repeat("1", 64), WithQueueWorkers(1))
+
+	q.Enqueue(context.Background(), "first", PriorityNormal)
+	<-started
+
+	t.Run("Abandoned", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s, _ := q.Enqueue(ctx, "abandoned", PriorityHigh)
+		cancel()
+		if _, err := s.Wait(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected Wait to return the context error, but got %v", err)
+		}
+	})
+
+	t.Run("Queue Stopped", func(t *testing.T) {
+		s, _ := q.Enqueue(context.Background(), "waiting", PriorityNormal)
+		stop()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		result, err := s.Wait(ctx)
+		if err != nil || !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected the waiting certificate to fail with the queue's context error, but got %+v, %v", result, err)
+		}
+	})
+
+	if _, err := q.Enqueue(context.Background(), "x", Priority(7)); err == nil {
+		t.Error("Expected an invalid priority to be rejected")
+	}
+}
@@ -0,0 +1,174 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrReceiptNotFound is matched by errors.Is when no receipt is indexed for a
+// payload hash.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+const (
+	// receiptPrefix starts the Store keys of receipts, followed by the
+	// payload hash.
+	receiptPrefix = "receipt/"
+	// receiptTxPrefix starts the keys mapping a transaction ID, then a
+	// payload hash, to that hash, so the receipts of a transaction can be
+	// found when it is confirmed.
+	receiptTxPrefix = "receipt-tx/"
+)
+
+// Receipt tells where a payload was certified: the transaction and, once it
+// is confirmed, the block holding it.
+type Receipt struct {
+	// PayloadHash is the hex SHA-256 of the certificate data.
+	PayloadHash string    `json:"payloadHash"`
+	TxID        string    `json:"txId"`
+	Address     string    `json:"address,omitempty"`
+	Blockchain  string    `json:"blockchain,omitempty"`
+	Timestamp   string    `json:"timestamp,omitempty"`
+	BlockID     string    `json:"blockId,omitempty"`
+	Recorded    time.Time `json:"recorded"`
+	// Proof is set for a payload anchored in a Merkle batch, rather than
+	// certified on its own: it is the JSON of the merkle.Proof tying
+	// PayloadHash to the root certified by TxID.
+	Proof json.RawMessage `json:"proof,omitempty"`
+}
+
+// ReceiptIndex maps payload hashes to their receipts, so the proof of a
+// certification can be returned long after the fact without scanning blocks.
+// It keeps its entries in a Store under keys starting with "receipt/" and
+// "receipt-tx/". It is safe for concurrent use if the Store is.
+type ReceiptIndex struct {
+	store Store
+}
+
+// NewReceiptIndex returns an index kept in store.
+func NewReceiptIndex(store Store) *ReceiptIndex {
+	return &ReceiptIndex{store: store}
+}
+
+// WithReceiptIndex indexes the receipt of every certificate the NAG accepts
+// in index, and records the block of those GetOutcome and WaitForOutcome see
+// confirmed.
+func WithReceiptIndex(index *ReceiptIndex) Option {
+	return func(c *Client) {
+		c.receipts = index
+	}
+}
+
+// Add indexes r. Only the first transaction certifying a payload is kept:
+// adding a receipt for another transaction with the same payload hash does
+// nothing, while adding one for the same transaction updates it.
+func (i *ReceiptIndex) Add(ctx context.Context, r Receipt) error {
+	hash := normalizeHex(r.PayloadHash)
+	if hash == "" || r.TxID == "" {
+		return errors.New("receipt needs a payload hash and a transaction ID")
+	}
+	existing, err := i.Find(ctx, hash)
+	switch {
+	case errors.Is(err, ErrReceiptNotFound):
+	case err != nil:
+		return err
+	case normalizeHex(existing.TxID) != normalizeHex(r.TxID):
+		return nil
+	case r.BlockID == "":
+		r.BlockID = existing.BlockID
+	}
+	if r.Recorded.IsZero() {
+		r.Recorded = time.Now()
+	}
+	value, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal receipt: %w", err)
+	}
+	if err := i.store.Put(ctx, receiptPrefix+hash, value); err != nil {
+		return fmt.Errorf("failed to store receipt of %s: %w", hash, err)
+	}
+	if err := i.store.Put(ctx, receiptTxPrefix+normalizeHex(r.TxID)+"/"+hash, []byte(hash)); err != nil {
+		return fmt.Errorf("failed to index receipt of %s: %w", hash, err)
+	}
+	return nil
+}
+
+// Find returns the receipt of the payload hash, or an error matching
+// ErrReceiptNotFound.
+func (i *ReceiptIndex) Find(ctx context.Context, payloadHash string) (*Receipt, error) {
+	value, err := i.store.Get(ctx, receiptPrefix+normalizeHex(payloadHash))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrReceiptNotFound, payloadHash)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt of %s: %w", payloadHash, err)
+	}
+	var receipt Receipt
+	if err := json.Unmarshal(value, &receipt); err != nil {
+		return nil, fmt.Errorf("failed to decode receipt of %s: %w", payloadHash, err)
+	}
+	return &receipt, nil
+}
+
+// Confirm records the block of txID in the receipts of every payload it
+// certified, and returns their number.
+func (i *ReceiptIndex) Confirm(ctx context.Context, txID, blockID string) (int, error) {
+	prefix := receiptTxPrefix + normalizeHex(txID) + "/"
+	var hashes []string
+	err := i.store.Iterate(ctx, prefix, func(_ string, value []byte) error {
+		hashes = append(hashes, string(value))
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list receipts of %s: %w", txID, err)
+	}
+	for _, hash := range hashes {
+		receipt, err := i.Find(ctx, hash)
+		if err != nil {
+			return 0, err
+		}
+		receipt.BlockID = blockID
+		if err := i.Add(ctx, *receipt); err != nil {
+			return 0, err
+		}
+	}
+	return len(hashes), nil
+}
+
+// FindReceipt returns the receipt of the payload hash from the client's
+// ReceiptIndex, or an error matching ErrReceiptNotFound.
+func (c *Client) FindReceipt(ctx context.Context, payloadHash string) (*Receipt, error) {
+	if c.receipts == nil {
+		return nil, errors.New("no receipt index configured; see WithReceiptIndex")
+	}
+	return c.receipts.Find(ctx, payloadHash)
+}
+
+// indexReceipt adds the receipt of an accepted submission to the client's
+// index.
+func (c *Client) indexReceipt(ctx context.Context, record AuditRecord) {
+	receipt := Receipt{
+		PayloadHash: record.PayloadHash,
+		TxID:        record.TxID,
+		Address:     record.Address,
+		Blockchain:  record.Blockchain,
+		Timestamp:   record.Timestamp,
+		Recorded:    record.Completed,
+	}
+	if err := c.receipts.Add(ctx, receipt); err != nil {
+		c.logf(ctx, "failed to index the receipt of %s: %v", record.TxID, err)
+	}
+}
+
+// confirmReceipts records the block of a confirmed outcome in the client's
+// index.
+func (c *Client) confirmReceipts(ctx context.Context, outcome *Outcome) {
+	if c.receipts == nil || outcome.Status != TxConfirmed || outcome.BlockID == "" {
+		return
+	}
+	if _, err := c.receipts.Confirm(ctx, outcome.TxID, outcome.BlockID); err != nil {
+		c.logf(ctx, "failed to record the block of %s in the receipt index: %v", outcome.TxID, err)
+	}
+}
@@ -0,0 +1,85 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReceiptIndex(t *testing.T) {
+	ctx := context.Background()
+	index := NewReceiptIndex(NewMemoryStore())
+	hash := payloadHash("data")
+
+	if _, err := index.Find(ctx, hash); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Expected ErrReceiptNotFound, but got %v", err)
+	}
+	if err := index.Add(ctx, Receipt{PayloadHash: hash}); err == nil {
+		t.Error("Expected an error for a receipt without transaction, but got nil")
+	}
+
+	index.Add(ctx, Receipt{PayloadHash: hash, TxID: "first"})
+	index.Add(ctx, Receipt{PayloadHash: "0x" + strings.ToUpper(hash), TxID: "second"})
+	index.Add(ctx, Receipt{PayloadHash: payloadHash("other"), TxID: "first"})
+	n, err := index.Confirm(ctx, "first", "42")
+	if err != nil || n != 2 {
+		t.Errorf("Expected 2 receipts to be confirmed, but got %d (%v)", n, err)
+	}
+
+	receipt, err := index.Find(ctx, "0x"+hash)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if receipt.TxID != "first" || receipt.BlockID != "42" || receipt.Recorded.IsZero() {
+		t.Errorf("Expected the first confirmed receipt, but got %+v", receipt)
+	}
+	index.Add(ctx, Receipt{PayloadHash: hash, TxID: "first", Address: "a"})
+	if receipt, _ := index.Find(ctx, hash); receipt.BlockID != "42" || receipt.Address != "a" {
+		t.Errorf("Expected an update to keep the block, but got %+v", receipt)
+	}
+}
+
+func TestClientReceipts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "GetTransactionbyID") {
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","BlockID":"7","BlockTimestamp":"t","Position":0,"NodeID":"n"}}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	plain := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	if _, err := plain.FindReceipt(ctx, payloadHash("data")); err == nil {
+		t.Error("Expected an error without receipt index, but got nil")
+	}
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithReceiptIndex(NewReceiptIndex(NewMemoryStore())))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	var txID string
+	if _, err := acc.SubmitCertificate("data", strings.Repeat("1", 64), CaptureTxID(&txID)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	receipt, err := acc.FindReceipt(ctx, payloadHash("data"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if receipt.TxID != txID || receipt.Address != acc.Address || receipt.Timestamp == "" || receipt.BlockID != "" {
+		t.Errorf("Expected the receipt of %s, but got %+v", txID, receipt)
+	}
+
+	if _, err := acc.GetOutcome(ctx, txID); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	receipt, _ = acc.FindReceipt(ctx, payloadHash("data"))
+	if receipt.BlockID != "7" {
+		t.Errorf("Expected the block of the confirmed transaction, but got %+v", receipt)
+	}
+	if _, err := acc.FindReceipt(ctx, payloadHash("missing")); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Expected ErrReceiptNotFound, but got %v", err)
+	}
+}
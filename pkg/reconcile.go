@@ -0,0 +1,151 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// ExpectedCertificate identifies a certificate that should be on chain, by
+// transaction ID, by the hex SHA-256 of its data (as recorded in
+// AuditRecord.PayloadHash), or both. When both are set either one matches.
+type ExpectedCertificate struct {
+	TxID        string `json:"txId,omitempty"`
+	PayloadHash string `json:"payloadHash,omitempty"`
+}
+
+// ReconciledCertificate is a certificate found on chain.
+type ReconciledCertificate struct {
+	TxID        string `json:"txId"`
+	PayloadHash string `json:"payloadHash,omitempty"`
+	From        string `json:"from"`
+	Timestamp   string `json:"timestamp,omitempty"`
+	Block       int64  `json:"block"`
+	// Expected is the entry the certificate was matched to, if any.
+	Expected *ExpectedCertificate `json:"expected,omitempty"`
+}
+
+// Reconciliation describes the certificates to look for.
+type Reconciliation struct {
+	Expected   []ExpectedCertificate
+	StartBlock int64
+	EndBlock   int64
+	// Address is the submitting account. Certificates it sent in the block
+	// range that match no expected entry are reported as unexpected. When
+	// empty, only matched and missing certificates are reported.
+	Address string
+}
+
+// ReconciliationReport is the result of Reconcile.
+type ReconciliationReport struct {
+	StartBlock int64 `json:"startBlock"`
+	EndBlock   int64 `json:"endBlock"`
+	// Matched are the certificates found for an expected entry.
+	Matched []ReconciledCertificate `json:"matched"`
+	// Missing are the expected entries with no certificate in the range.
+	Missing []ExpectedCertificate `json:"missing"`
+	// Unexpected are certificates sent by Address that match no expected
+	// entry, including second certificates for an already matched entry.
+	Unexpected []ReconciledCertificate `json:"unexpected"`
+}
+
+// Reconcile scans the blocks from r.StartBlock to r.EndBlock (inclusive) and
+// compares the certificates they contain with r.Expected. Blocks are streamed
+// as by GetBlockRange, so large ranges are processed in bounded memory.
+func (c *Client) Reconcile(ctx context.Context, r Reconciliation) (report *ReconciliationReport, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	byTxID := make(map[string]int)
+	byHash := make(map[string]int)
+	for i, expected := range r.Expected {
+		if expected.TxID != "" {
+			byTxID[normalizeHex(expected.TxID)] = i
+		}
+		if expected.PayloadHash != "" {
+			byHash[normalizeHex(expected.PayloadHash)] = i
+		}
+	}
+	matched := make([]bool, len(r.Expected))
+	address := normalizeHex(r.Address)
+
+	report = &ReconciliationReport{StartBlock: r.StartBlock, EndBlock: r.EndBlock}
+	err = c.scanTransactions(ctx, r.StartBlock, r.EndBlock, func(tx map[string]interface{}, block int64) error {
+		found := reconciledCertificate(tx, block)
+
+		index, ok := byTxID[normalizeHex(found.TxID)]
+		if !ok && found.PayloadHash != "" {
+			index, ok = byHash[found.PayloadHash]
+		}
+		if ok && !matched[index] {
+			matched[index] = true
+			found.Expected = &r.Expected[index]
+			report.Matched = append(report.Matched, found)
+			return nil
+		}
+		if address != "" && normalizeHex(found.From) == address {
+			report.Unexpected = append(report.Unexpected, found)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, expected := range r.Expected {
+		if !matched[i] {
+			report.Missing = append(report.Missing, expected)
+		}
+	}
+	return report, nil
+}
+
+// scanTransactions streams the blocks from start to end (inclusive) and calls
+// fn for every transaction object they contain, with the number of its block.
+// Blocks are numbered by their BlockID field, or by position in the range
+// when they have none. The blocks are requested as bulk traffic.
+func (c *Client) scanTransactions(ctx context.Context, start, end int64, fn func(tx map[string]interface{}, block int64) error) error {
+	ctx = ContextWithBulk(ctx)
+	number := start
+	return c.GetBlockRangeContext(ctx, start, end, func(block map[string]interface{}) error {
+		fields, _ := unwrapBlock(block).(map[string]interface{})
+		blockNumber := number
+		if id, err := strconv.ParseInt(fmt.Sprint(fields["BlockID"]), 10, 64); err == nil {
+			blockNumber = id
+		}
+		number++
+
+		transactions, _ := fields["Transactions"].([]interface{})
+		for _, item := range transactions {
+			if tx, ok := item.(map[string]interface{}); ok {
+				if err := fn(tx, blockNumber); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// reconciledCertificate extracts the fields used for reconciliation from a
+// transaction in a block. Transactions whose payload is not a certificate
+// get an empty PayloadHash.
+func reconciledCertificate(tx map[string]interface{}, block int64) ReconciledCertificate {
+	row := certificateRow(tx, block)
+	return ReconciledCertificate{
+		TxID:        row.TxID,
+		PayloadHash: row.DataHash,
+		From:        row.From,
+		Timestamp:   row.Timestamp,
+		Block:       row.Block,
+	}
+}
+
+// normalizeHex lowercases a hex value and strips any "0x" prefix so IDs and
+// addresses compare equal however they were written.
+func normalizeHex(value string) string {
+	return strings.ToLower(utils.HexFix(value))
+}
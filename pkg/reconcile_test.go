@@ -0,0 +1,62 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+func TestReconcile(t *testing.T) {
+	tx := func(id, from, data string) string {
+		payload := utils.StringToHex(fmt.Sprintf(`{"data":%q}`, data))
+		return fmt.Sprintf(`{"ID":"%s","From":"%s","Payload":"%s","Timestamp":"2024:01:02-03:04:05"}`, id, from, payload)
+	}
+	body := fmt.Sprintf(`{"Result":200,"Response":{"Blocks":[`+
+		`{"BlockID":"10","Transactions":[%s,%s]},`+
+		`{"Block":{"BlockID":"11","Transactions":[%s,%s]}}]}}`,
+		tx("aa01", "0xme", "invoice-1"),
+		tx("aa02", "0xsomeone", "other"),
+		tx("aa03", "0xme", "invoice-2"),
+		tx("aa04", "0xme", "surprise"),
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	report, err := c.Reconcile(context.Background(), Reconciliation{
+		Expected: []ExpectedCertificate{
+			{TxID: "0xaa01"},
+			{PayloadHash: payloadHash("invoice-2")},
+			{TxID: "ffff", PayloadHash: payloadHash("never-sent")},
+		},
+		StartBlock: 10,
+		EndBlock:   11,
+		Address:    "me",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if len(report.Matched) != 2 {
+		t.Fatalf("Expected 2 matched certificates, but got %+v", report.Matched)
+	}
+	if first := report.Matched[0]; first.TxID != "aa01" || first.Block != 10 || first.Expected.TxID != "0xaa01" {
+		t.Errorf("Expected aa01 in block 10 matched by ID, but got %+v", first)
+	}
+	if second := report.Matched[1]; second.TxID != "aa03" || second.Block != 11 {
+		t.Errorf("Expected aa03 in block 11 matched by payload hash, but got %+v", second)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].TxID != "ffff" {
+		t.Errorf("Expected the never-sent certificate to be missing, but got %+v", report.Missing)
+	}
+	if len(report.Unexpected) != 1 || report.Unexpected[0].TxID != "aa04" {
+		t.Errorf("Expected only aa04 to be unexpected, but got %+v", report.Unexpected)
+	}
+}
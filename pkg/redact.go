@@ -0,0 +1,42 @@
+package circular_enterprise_apis
+
+import "fmt"
+
+// maxMessageLength is the longest NAG response message quoted verbatim in
+// errors. Gateway error messages are short; anything longer may echo the
+// request.
+const maxMessageLength = 256
+
+// AllowSensitiveLogging lets error messages and log output include response
+// bodies and other content that may echo certificate payloads, signatures or
+// key material. It is meant for debugging against test networks.
+func AllowSensitiveLogging() Option {
+	return func(c *Client) {
+		c.sensitiveLogging = true
+	}
+}
+
+// Redact returns a placeholder that records the length of value without
+// revealing its content.
+func Redact(value string) string {
+	return fmt.Sprintf("[redacted %d bytes]", len(value))
+}
+
+// redact returns value unchanged when sensitive logging is allowed and a
+// placeholder otherwise.
+func (c *Client) redact(value string) string {
+	if c.sensitiveLogging {
+		return value
+	}
+	return Redact(value)
+}
+
+// responseMessage renders the Response of a failed NAG call for an error
+// message. Short strings are the gateway's own error messages and are kept;
+// objects and long strings may echo the request and are redacted.
+func (c *Client) responseMessage(response interface{}) string {
+	if message, ok := response.(string); ok && len(message) <= maxMessageLength {
+		return message
+	}
+	return c.redact(fmt.Sprint(response))
+}
@@ -0,0 +1,67 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedaction(t *testing.T) {
+	const secret = "certificate-content"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "GetWallet") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"Result":108,"Response":{"Echo":"` + secret + `"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(secret))
+	}))
+	defer server.Close()
+
+	privateKey := strings.Repeat("1", 64)
+	testCases := []struct {
+		name      string
+		opts      []Option
+		expectRaw bool
+	}{
+		{"Redacted By Default", nil, false},
+		{"Sensitive Logging Allowed", []Option{AllowSensitiveLogging()}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, tc.opts...)
+			acc.Open("0x" + strings.Repeat("a", 64))
+
+			_, submitErr := acc.SubmitCertificate("data", privateKey)
+			_, walletErr := acc.GetWallet(context.Background(), acc.Address)
+			_, keyErr := acc.SignData([]byte("data"), "zz"+privateKey[2:])
+
+			for name, err := range map[string]error{"submit": submitErr, "wallet": walletErr} {
+				if err == nil {
+					t.Fatalf("Expected a %s error but got nil", name)
+				}
+				if got := strings.Contains(err.Error(), secret); got != tc.expectRaw {
+					t.Errorf("Expected %s error to include the body: %v, but got %q", name, tc.expectRaw, err)
+				}
+			}
+			if got := strings.Contains(keyErr.Error(), "'z'"); got != tc.expectRaw {
+				t.Errorf("Expected key error to quote the key: %v, but got %q", tc.expectRaw, keyErr)
+			}
+		})
+	}
+}
+
+func TestResponseMessage(t *testing.T) {
+	c := NewClient("", DefaultChain, LibVersion)
+	if got := c.responseMessage("Wallet Not Found"); got != "Wallet Not Found" {
+		t.Errorf("Expected short gateway messages to be kept, but got %q", got)
+	}
+	long := strings.Repeat("x", maxMessageLength+1)
+	if got := c.responseMessage(long); got != Redact(long) {
+		t.Errorf("Expected long messages to be redacted, but got %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package circular_enterprise_apis
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultReorgDepth is how many of the latest blocks a ReorgDetector
+// remembers.
+const DefaultReorgDepth = 64
+
+// ReorgEvent reports that blocks seen earlier are no longer part of the
+// chain. Derived state built from the Replaced blocks should be rolled back
+// and the blocks from Number onwards processed again.
+type ReorgEvent struct {
+	// Number is the first block that was replaced.
+	Number int64
+	// Replaced are the headers seen earlier for Number and the blocks after
+	// it, in order.
+	Replaced []BlockHeader
+	// Header is the block whose hash or parent revealed the reorg.
+	Header BlockHeader
+}
+
+// ReorgDetector remembers the hashes of the latest blocks it observed and
+// reports a ReorgEvent when a block is observed with a different hash, or
+// with a parent whose hash differs from the one observed. A fork deeper than
+// one block is reported block by block as the replaced blocks are observed
+// again. It is safe for concurrent use.
+type ReorgDetector struct {
+	mu      sync.Mutex
+	depth   int64
+	highest int64
+	seen    map[int64]BlockHeader
+}
+
+// NewReorgDetector returns a ReorgDetector that remembers the latest depth
+// blocks, or DefaultReorgDepth blocks when depth is not positive. Reorgs
+// deeper than that go unnoticed.
+func NewReorgDetector(depth int) *ReorgDetector {
+	if depth <= 0 {
+		depth = DefaultReorgDepth
+	}
+	return &ReorgDetector{depth: int64(depth), highest: -1, seen: make(map[int64]BlockHeader)}
+}
+
+// Observe records header and returns the reorg it reveals, or nil. Headers
+// without a hash are ignored.
+func (d *ReorgDetector) Observe(header BlockHeader) *ReorgEvent {
+	if header.Hash == "" {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var event *ReorgEvent
+	if seen, ok := d.seen[header.Number]; ok && !sameHash(seen.Hash, header.Hash) {
+		event = d.rollback(header.Number, header)
+	} else if parent, ok := d.seen[header.Number-1]; ok && header.PreviousHash != "" && !sameHash(parent.Hash, header.PreviousHash) {
+		event = d.rollback(header.Number-1, header)
+	}
+
+	d.seen[header.Number] = header
+	if header.Number > d.highest {
+		d.highest = header.Number
+	}
+	for number := range d.seen {
+		if number <= d.highest-d.depth {
+			delete(d.seen, number)
+		}
+	}
+	return event
+}
+
+// rollback forgets the blocks from number onwards and returns the event
+// reporting them.
+func (d *ReorgDetector) rollback(number int64, header BlockHeader) *ReorgEvent {
+	event := &ReorgEvent{Number: number, Header: header}
+	for n, seen := range d.seen {
+		if n >= number {
+			event.Replaced = append(event.Replaced, seen)
+			delete(d.seen, n)
+		}
+	}
+	sort.Slice(event.Replaced, func(i, j int) bool { return event.Replaced[i].Number < event.Replaced[j].Number })
+	d.highest = number - 1
+	return event
+}
+
+// sameHash reports whether two block hashes are equal, ignoring case and a
+// 0x prefix.
+func sameHash(a, b string) bool {
+	return normalizeHex(a) == normalizeHex(b)
+}
@@ -0,0 +1,115 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReorgDetector(t *testing.T) {
+	header := func(number int64, hash, previous string) BlockHeader {
+		return BlockHeader{Number: number, Hash: hash, PreviousHash: previous}
+	}
+
+	t.Run("Replaced Block", func(t *testing.T) {
+		d := NewReorgDetector(0)
+		for _, h := range []BlockHeader{header(1, "a1", "a0"), header(2, "a2", "a1"), header(3, "a3", "a2")} {
+			if event := d.Observe(h); event != nil {
+				t.Fatalf("Expected no reorg, but got %+v", event)
+			}
+		}
+		event := d.Observe(header(2, "0xB2", "a1"))
+		if event == nil {
+			t.Fatal("Expected a reorg")
+		}
+		if event.Number != 2 || len(event.Replaced) != 2 || event.Replaced[0].Hash != "a2" || event.Replaced[1].Hash != "a3" {
+			t.Errorf("Expected blocks 2 and 3 to be replaced, but got %+v", event)
+		}
+		if event := d.Observe(header(3, "b3", "b2")); event != nil {
+			t.Errorf("Expected the new branch to be accepted, but got %+v", event)
+		}
+	})
+
+	t.Run("Replaced Parent", func(t *testing.T) {
+		d := NewReorgDetector(0)
+		d.Observe(header(1, "a1", "a0"))
+		d.Observe(header(2, "a2", "a1"))
+		event := d.Observe(header(3, "b3", "b2"))
+		if event == nil || event.Number != 2 || len(event.Replaced) != 1 {
+			t.Fatalf("Expected block 2 to be replaced, but got %+v", event)
+		}
+		if event := d.Observe(header(2, "b2", "b1")); event == nil || event.Number != 1 {
+			t.Errorf("Expected the deeper fork to be reported next, but got %+v", event)
+		}
+	})
+
+	t.Run("Same Hash Formats", func(t *testing.T) {
+		d := NewReorgDetector(0)
+		d.Observe(header(1, "0xAB", ""))
+		if event := d.Observe(header(1, "ab", "")); event != nil {
+			t.Errorf("Expected equal hashes to match, but got %+v", event)
+		}
+	})
+
+	t.Run("Depth", func(t *testing.T) {
+		d := NewReorgDetector(2)
+		d.Observe(header(1, "a1", ""))
+		d.Observe(header(2, "a2", "a1"))
+		d.Observe(header(3, "a3", "a2"))
+		if event := d.Observe(header(1, "b1", "")); event != nil {
+			t.Errorf("Expected blocks beyond the depth to be forgotten, but got %+v", event)
+		}
+	})
+}
+
+func TestIterateBlocksReorg(t *testing.T) {
+	branch := "a"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var blocks []string
+		for i := 0; i < 4; i++ {
+			hash, previous := fmt.Sprintf("a%d", i), fmt.Sprintf("a%d", i-1)
+			if branch == "b" && i >= 2 {
+				hash = fmt.Sprintf("b%d", i)
+			}
+			if branch == "b" && i >= 3 {
+				previous = fmt.Sprintf("b%d", i-1)
+			}
+			blocks = append(blocks, fmt.Sprintf(`{"BlockID":%d,"Hash":%q,"PreviousHash":%q}`, i, hash, previous))
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"Result":200,"Response":{"Blocks":[%s]}}`, strings.Join(blocks, ","))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	detector := NewReorgDetector(0)
+	rollback := errors.New("rollback")
+	var events []ReorgEvent
+	onReorg := func(event ReorgEvent) error {
+		events = append(events, event)
+		return rollback
+	}
+
+	if err := c.IterateBlocks(context.Background(), 0, 3, func(Block) error { return nil }, WithReorgDetection(detector, onReorg)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	branch = "b"
+	var processed []int64
+	err := c.IterateBlocks(context.Background(), 0, 3, func(b Block) error {
+		processed = append(processed, b.Number)
+		return nil
+	}, WithReorgDetection(detector, onReorg))
+	if !errors.Is(err, rollback) {
+		t.Errorf("Expected the handler's error, but got %v", err)
+	}
+	if len(events) != 1 || events[0].Number != 2 || len(events[0].Replaced) != 2 {
+		t.Errorf("Expected blocks 2 and 3 to be reported replaced, but got %+v", events)
+	}
+	if fmt.Sprint(processed) != "[0 1]" {
+		t.Errorf("Expected iteration to stop at the replaced block, but got %v", processed)
+	}
+}
@@ -0,0 +1,274 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// ParseMode controls how strictly responses from the Network Access Gateway
+// (NAG) are validated before they are used.
+type ParseMode int
+
+const (
+	// ParseLenient accepts whatever the NAG returns and treats missing or
+	// mistyped fields as "not there yet". This is the default and matches the
+	// historical behaviour of the library.
+	ParseLenient ParseMode = iota
+
+	// ParseStrict rejects responses that are missing required fields or that
+	// carry values of an unexpected type, returning a *SchemaError.
+	ParseStrict
+)
+
+// String returns the name of the parse mode.
+func (m ParseMode) String() string {
+	switch m {
+	case ParseLenient:
+		return "lenient"
+	case ParseStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("ParseMode(%d)", int(m))
+	}
+}
+
+// SchemaError describes a NAG response that does not have the shape the
+// library expects. It is only returned when the account uses ParseStrict.
+type SchemaError struct {
+	// Endpoint is the NAG method whose response was rejected.
+	Endpoint string
+	// Field is the dotted path of the offending field, e.g. "Response.Status".
+	Field string
+	// Expected is the JSON type the field should have.
+	Expected string
+	// Got is the JSON type that was found, or "missing".
+	Got string
+}
+
+// Error implements the error interface.
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("invalid %s response: field %q expected %s, got %s", e.Endpoint, e.Field, e.Expected, e.Got)
+}
+
+// fieldSpec names a required field of a response and its expected JSON type.
+type fieldSpec struct {
+	path string
+	kind string
+}
+
+// validateFields checks that every field in specs is present in the decoded
+// response and has the expected JSON type. It returns a *SchemaError for the
+// first field that does not match.
+func validateFields(endpoint string, response interface{}, specs ...fieldSpec) error {
+	for _, spec := range specs {
+		value, found := lookupField(response, spec.path)
+		got := "missing"
+		if found {
+			got = jsonKind(value)
+		}
+		if got != spec.kind {
+			return &SchemaError{Endpoint: endpoint, Field: spec.path, Expected: spec.kind, Got: got}
+		}
+	}
+	return nil
+}
+
+// validateBody decodes a raw response body and validates it with validateFields.
+func validateBody(endpoint string, body []byte, specs ...fieldSpec) error {
+	var response interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return &SchemaError{Endpoint: endpoint, Field: "", Expected: "object", Got: "invalid JSON"}
+	}
+	return validateFields(endpoint, response, specs...)
+}
+
+// lookupField walks a dotted path through nested JSON objects.
+func lookupField(response interface{}, path string) (interface{}, bool) {
+	current := response
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonKind returns the JSON type name of a value produced by encoding/json.
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// DefaultMaxResponseSize is the default MaxResponseSize. NAG answers are a
+// few kilobytes; the limit only stops a misbehaving or compromised gateway
+// from exhausting memory.
+const DefaultMaxResponseSize = 16 << 20
+
+// WithMaxResponseSize sets the largest response body, after decompression,
+// that the client reads into memory. Zero or a negative value disables the
+// limit. Streamed block ranges are not subject to it.
+func WithMaxResponseSize(size int64) Option {
+	return func(c *Client) {
+		c.MaxResponseSize = size
+	}
+}
+
+// ResponseTooLargeError reports a response body larger than the client's
+// MaxResponseSize.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds %d bytes", e.Limit)
+}
+
+// Retryable reports false: the gateway will send the same oversized answer.
+func (e *ResponseTooLargeError) Retryable() bool { return false }
+
+// readBody reads a response body, failing with a *ResponseTooLargeError once
+// it exceeds the client's MaxResponseSize.
+func (c *Client) readBody(body io.Reader) ([]byte, error) {
+	return readLimited(body, c.MaxResponseSize)
+}
+
+// readLimited reads r to the end, or up to limit bytes when limit is positive.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, &ResponseTooLargeError{Limit: limit}
+	}
+	return data, nil
+}
+
+// quoteMessage returns a message from a gateway for use in an error, replaced
+// by its length if it is too long to be a genuine error message.
+func quoteMessage(message string) string {
+	if len(message) > maxMessageLength {
+		return Redact(message)
+	}
+	return message
+}
+
+// parseNAGResponse decodes the answer of the NAG discovery service and
+// returns the NAG URL it names.
+func parseNAGResponse(body []byte, strict bool) (string, error) {
+	// The response body is expected to be a JSON object containing the
+	// status and the specific NAG URL for the requested network.
+	var result struct {
+		Status  string `json:"status"`
+		URL     string `json:"url"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to decode network response: %w", err)
+	}
+
+	if strict {
+		specs := []fieldSpec{{"status", "string"}}
+		if result.Status == "success" {
+			specs = append(specs, fieldSpec{"url", "string"})
+		}
+		if err := validateBody("getNAG", body, specs...); err != nil {
+			return "", err
+		}
+	}
+
+	// The 'message' field provides context for a failure.
+	if result.Status != "success" || result.URL == "" {
+		return "", fmt.Errorf("failed to set network: %s", quoteMessage(result.Message))
+	}
+	nagURL, err := url.Parse(result.URL)
+	if err != nil || (nagURL.Scheme != "http" && nagURL.Scheme != "https") || nagURL.Host == "" {
+		return "", fmt.Errorf("failed to set network: invalid NAG URL %q", quoteMessage(result.URL))
+	}
+	return result.URL, nil
+}
+
+// parseNonceResponse decodes a GetWalletNonce response and returns the
+// current nonce of the wallet.
+func parseNonceResponse(body []byte, strict bool) (int, error) {
+	// In strict mode the nonce must be present whenever the call succeeded,
+	// otherwise a missing field would silently reset the nonce to zero.
+	if strict {
+		if err := validateBody("GetWalletNonce", body, fieldSpec{"Result", "number"}); err != nil {
+			return 0, err
+		}
+		var probe struct {
+			Result int `json:"Result"`
+		}
+		if err := json.Unmarshal(body, &probe); err == nil && probe.Result == 200 {
+			if err := validateBody("GetWalletNonce", body, fieldSpec{"Response.Nonce", "number"}); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var responseData struct {
+		Result   int `json:"Result"`
+		Response struct {
+			Nonce int `json:"Nonce"`
+		} `json:"Response"`
+	}
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		return 0, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	if responseData.Result != 200 {
+		return 0, errors.New("failed to update account, invalid response from server")
+	}
+	// The next nonce is one more, which must not overflow.
+	if nonce := responseData.Response.Nonce; nonce < 0 || nonce == math.MaxInt {
+		return 0, fmt.Errorf("invalid nonce %d", nonce)
+	}
+	return responseData.Response.Nonce, nil
+}
+
+// parseTransactionResponse decodes a GetTransactionbyID response.
+func parseTransactionResponse(body []byte, strict bool) (map[string]interface{}, error) {
+	// A map gives flexible access to transaction data, which can have a
+	// variable schema.
+	var transactionDetails map[string]interface{}
+	if err := json.Unmarshal(body, &transactionDetails); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction JSON: %w", err)
+	}
+	if transactionDetails == nil {
+		return nil, fmt.Errorf("failed to decode transaction JSON: response is null")
+	}
+	if strict {
+		if err := validateFields("GetTransactionbyID", transactionDetails, fieldSpec{"Result", "number"}); err != nil {
+			return nil, err
+		}
+	}
+	return transactionDetails, nil
+}
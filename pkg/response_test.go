@@ -0,0 +1,258 @@
+package circular_enterprise_apis
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateFields(t *testing.T) {
+	response := map[string]interface{}{
+		"Result":   float64(200),
+		"Response": map[string]interface{}{"Status": "Confirmed"},
+	}
+
+	testCases := []struct {
+		name        string
+		spec        fieldSpec
+		expectError bool
+		expectedGot string
+	}{
+		{name: "Present Number", spec: fieldSpec{"Result", "number"}},
+		{name: "Present Nested String", spec: fieldSpec{"Response.Status", "string"}},
+		{name: "Missing Field", spec: fieldSpec{"Response.BlockID", "string"}, expectError: true, expectedGot: "missing"},
+		{name: "Wrong Type", spec: fieldSpec{"Response.Status", "number"}, expectError: true, expectedGot: "string"},
+		{name: "Path Through Non-Object", spec: fieldSpec{"Result.Value", "number"}, expectError: true, expectedGot: "missing"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateFields("Test", response, tc.spec)
+			if !tc.expectError {
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				return
+			}
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("Expected a *SchemaError, but got %v", err)
+			}
+			if schemaErr.Field != tc.spec.path || schemaErr.Got != tc.expectedGot {
+				t.Errorf("Expected field %s with got=%s, but got field %s with got=%s", tc.spec.path, tc.expectedGot, schemaErr.Field, schemaErr.Got)
+			}
+		})
+	}
+}
+
+func TestUpdateAccountParseModes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{}}`))
+	}))
+	defer server.Close()
+
+	t.Run("Lenient", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+		acc.Open("0x123")
+
+		ok, err := acc.UpdateAccount()
+		if err != nil || !ok {
+			t.Fatalf("Expected lenient update to succeed, but got ok=%v err=%v", ok, err)
+		}
+		if acc.Nonce != 1 {
+			t.Errorf("Expected Nonce to be 1, but got %d", acc.Nonce)
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+		acc.ParseMode = ParseStrict
+		acc.Open("0x123")
+
+		_, err := acc.UpdateAccount()
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("Expected a *SchemaError, but got %v", err)
+		}
+		if schemaErr.Field != "Response.Nonce" {
+			t.Errorf("Expected field Response.Nonce, but got %s", schemaErr.Field)
+		}
+	})
+}
+
+func TestGetTransactionOutcomeStrictFailsFast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Status":42}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.ParseMode = ParseStrict
+
+	start := time.Now()
+	_, err := acc.GetTransactionOutcome("0xabc", 10)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected a *SchemaError, but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected strict mode to fail fast, but it took %v", elapsed)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":"` + strings.Repeat("x", 1000) + `"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion, WithMaxResponseSize(100))
+	_, err := c.GetTransactionByID("tx", "", "")
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 100 {
+		t.Errorf("Expected a *ResponseTooLargeError, but got %v", err)
+	}
+	if IsRetryable(err) {
+		t.Error("Expected an oversized response not to be retryable")
+	}
+
+	c = NewClient(server.URL, DefaultChain, LibVersion, WithMaxResponseSize(0))
+	if _, err := c.GetTransactionByID("tx", "", ""); err != nil {
+		t.Errorf("Expected no limit, but got: %v", err)
+	}
+}
+
+func TestParseNAGResponse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		body        string
+		expectedURL string
+		expectError bool
+	}{
+		{"Success", `{"status":"success","url":"https://nag.example/NAG.php?cep="}`, "https://nag.example/NAG.php?cep=", false},
+		{"Failure", `{"status":"error","message":"unknown network"}`, "", true},
+		{"Not A URL", `{"status":"success","url":"javascript:alert(1)"}`, "", true},
+		{"No Host", `{"status":"success","url":"https:///path"}`, "", true},
+		{"Null", `null`, "", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url, err := parseNAGResponse([]byte(tc.body), false)
+			if (err != nil) != tc.expectError || url != tc.expectedURL {
+				t.Errorf("Expected %q (error %v), but got %q, %v", tc.expectedURL, tc.expectError, url, err)
+			}
+		})
+	}
+}
+
+func TestParseNonceResponse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		body        string
+		expected    int
+		expectError bool
+	}{
+		{"Success", `{"Result":200,"Response":{"Nonce":7}}`, 7, false},
+		{"Rejected", `{"Result":118,"Response":"Wallet Not Found"}`, 0, true},
+		{"Negative", `{"Result":200,"Response":{"Nonce":-1}}`, 0, true},
+		{"Overflow", `{"Result":200,"Response":{"Nonce":` + strconv.Itoa(math.MaxInt) + `}}`, 0, true},
+		{"Out Of Range", `{"Result":200,"Response":{"Nonce":1e400}}`, 0, true},
+		{"Wrong Type", `{"Result":200,"Response":[1]}`, 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nonce, err := parseNonceResponse([]byte(tc.body), false)
+			if (err != nil) != tc.expectError || nonce != tc.expected {
+				t.Errorf("Expected %d (error %v), but got %d, %v", tc.expected, tc.expectError, nonce, err)
+			}
+		})
+	}
+}
+
+// adversarialSeeds are malformed and hostile bodies shared by the fuzz
+// targets of the response parsers.
+var adversarialSeeds = []string{
+	``,
+	`null`,
+	`[]`,
+	`"string"`,
+	`{"Result":`,
+	`{"Result":"200"}`,
+	`{"Result":1e400}`,
+	`{"Result":-200,"Response":null}`,
+	`{"Result":200,"Response":{"Nonce":1.5}}`,
+	`{"Result":200,"Response":{"Status":{"Status":"Executed"}}}`,
+	`{"status":"success","url":"%zz"}`,
+	`{"status":"success","url":"http://[::1"}`,
+	`{"Result":200,"Response":"` + strings.Repeat("\\u0000", 100) + `"}`,
+	strings.Repeat("[", 20000) + strings.Repeat("]", 20000),
+	strings.Repeat(`{"a":`, 5000) + `1` + strings.Repeat(`}`, 5000),
+}
+
+func FuzzParseNonceResponse(f *testing.F) {
+	f.Add(`{"Result":200,"Response":{"Nonce":7}}`)
+	for _, seed := range adversarialSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		for _, strict := range []bool{false, true} {
+			nonce, err := parseNonceResponse([]byte(body), strict)
+			if err == nil && (nonce < 0 || nonce == math.MaxInt) {
+				t.Errorf("Expected a usable nonce, but got %d", nonce)
+			}
+		}
+	})
+}
+
+func FuzzParseNAGResponse(f *testing.F) {
+	f.Add(`{"status":"success","url":"https://nag.example/NAG.php?cep="}`)
+	for _, seed := range adversarialSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		for _, strict := range []bool{false, true} {
+			nagURL, err := parseNAGResponse([]byte(body), strict)
+			if err != nil {
+				if len(err.Error()) > 2*maxMessageLength {
+					t.Errorf("Expected a bounded error message, but got %d bytes", len(err.Error()))
+				}
+				continue
+			}
+			if u, err := url.Parse(nagURL); err != nil || u.Host == "" {
+				t.Errorf("Expected a valid NAG URL, but got %q", nagURL)
+			}
+		}
+	})
+}
+
+func FuzzParseTransactionResponse(f *testing.F) {
+	f.Add(`{"Result":200,"Response":{"Status":"Executed","ID":"tx"}}`)
+	f.Add(`{"Result":118,"Response":"Transaction Not Found"}`)
+	for _, seed := range adversarialSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		for _, strict := range []bool{false, true} {
+			response, err := parseTransactionResponse([]byte(body), strict)
+			if err != nil {
+				continue
+			}
+			if response == nil {
+				t.Fatal("Expected a response without an error")
+			}
+			// The outcome and result error are derived from every decoded
+			// transaction and must cope with any shape.
+			newOutcome("tx", response)
+			NewClient("", DefaultChain, LibVersion).resultError("GetTransactionbyID", response)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultRetryBackoff is the delay before the first retry when a RetryPolicy
+// does not set one.
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// RetryPolicy controls how often a failed request is attempted again.
+// Throttling responses and transport failures classified as transient by
+// IsRetryable are retried; responses that reached the NAG are returned to the
+// caller as they are. Requests whose body
+// cannot be replayed, such as certificate submissions, are never retried, so
+// a retry can never submit the same certificate twice.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles on each
+	// subsequent retry. A Retry-After advised by the server takes precedence.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts when positive.
+	MaxBackoff time.Duration
+}
+
+// WithRetryPolicy sets the retry policy used for the client's requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.Retry = policy
+	}
+}
+
+// attempts returns the total number of attempts allowed by the policy.
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay returns how long to wait after the given failed attempt.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var throttled *ThrottledError
+	if errors.As(err, &throttled) && throttled.RetryAfter > 0 {
+		return throttled.RetryAfter
+	}
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// retryable reports whether a failed attempt is worth repeating. Errors caused
+// by the caller's context are final.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return IsRetryable(err)
+}
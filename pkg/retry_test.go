@@ -0,0 +1,86 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		err      error
+		expected time.Duration
+	}{
+		{"Default Backoff", RetryPolicy{}, 1, fmt.Errorf("boom"), DefaultRetryBackoff},
+		{"Doubles", RetryPolicy{Backoff: time.Second}, 3, fmt.Errorf("boom"), 4 * time.Second},
+		{"Capped", RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}, 5, fmt.Errorf("boom"), 3 * time.Second},
+		{"Retry-After Wins", RetryPolicy{Backoff: time.Second}, 1, &ThrottledError{StatusCode: 429, RetryAfter: 7 * time.Second}, 7 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.delay(tc.attempt, tc.err); got != tc.expected {
+				t.Errorf("Expected delay %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRetryThrottledRequest(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Blocks":1}}`))
+	}))
+	defer server.Close()
+
+	t.Run("Retries Replayable Request", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		c := NewClient(server.URL, DefaultChain, LibVersion,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+			WithLogger(func(ctx context.Context, message string) {}))
+		if _, err := c.GetBlockCount(context.Background()); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if got := atomic.LoadInt32(&calls); got != 3 {
+			t.Errorf("Expected 3 attempts, but got %d", got)
+		}
+	})
+
+	t.Run("No Retries By Default", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		c := NewClient(server.URL, DefaultChain, LibVersion)
+		if _, err := c.GetBlockCount(context.Background()); err == nil {
+			t.Fatal("Expected an error but got nil")
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("Expected 1 attempt, but got %d", got)
+		}
+	})
+
+	t.Run("Submission Not Retried", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+		acc.Open("0x" + strings.Repeat("a", 64))
+		_, err := acc.SubmitCertificate("data", strings.Repeat("1", 64))
+		if err == nil {
+			t.Fatal("Expected an error but got nil")
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("Expected 1 attempt, but got %d", got)
+		}
+	})
+}
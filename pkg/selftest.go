@@ -0,0 +1,168 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// The steps of a self-test, in order.
+const (
+	SelfTestSubmit  = "submit"
+	SelfTestConfirm = "confirm"
+	SelfTestFetch   = "fetch"
+	SelfTestDecode  = "decode"
+	SelfTestCompare = "compare"
+)
+
+// SelfTestStep is the result of one step of a self-test.
+type SelfTestStep struct {
+	Name     string
+	Duration time.Duration
+	// Err is nil when the step passed.
+	Err error
+}
+
+// SelfTestReport describes a round trip of a canary certificate through the
+// network, as run by SelfTest.
+type SelfTestReport struct {
+	NAGURL     string
+	Blockchain string
+	Address    string
+	// TxID is the ID of the canary transaction, once it is signed.
+	TxID string
+	// Steps are the steps run, in order, up to the first that failed.
+	Steps []SelfTestStep
+	// Outcome is the outcome of the canary once it left the Pending state.
+	Outcome *Outcome
+	// SubmittedHash and RetrievedHash are the SHA-256 hashes, in hex, of the
+	// canary data as submitted and as decoded from the fetched transaction.
+	SubmittedHash string
+	RetrievedHash string
+	Elapsed       time.Duration
+}
+
+// Healthy reports whether every step of the self-test passed.
+func (r *SelfTestReport) Healthy() bool {
+	return len(r.Steps) > 0 && r.Steps[len(r.Steps)-1].Name == SelfTestCompare && r.Err() == nil
+}
+
+// Err returns the error of the step that failed, or nil.
+func (r *SelfTestReport) Err() error {
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return fmt.Errorf("self-test %s failed: %w", step.Name, step.Err)
+		}
+	}
+	return nil
+}
+
+// SelfTest checks that the account can certify data end to end, typically
+// after a deployment or a key rotation. It submits a canary certificate
+// signed with privateKey, waits at most timeoutSec seconds for it to be
+// confirmed, fetches it back from the block that holds it, decodes it with
+// DecodeCertificatePayload and compares the hash of the data retrieved with
+// the hash of the data submitted. The canary is a real transaction: each call
+// costs one submitted transaction and its fee.
+//
+// The report describes every step run; the error is that of the step that
+// failed, as returned by the report's Err, and is nil when the account is
+// healthy.
+func (a *CEPAccount) SelfTest(ctx context.Context, privateKey string, timeoutSec int) (report *SelfTestReport, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	report = &SelfTestReport{NAGURL: a.NAGURL, Blockchain: a.Blockchain, Address: a.Address}
+	start := time.Now()
+	defer func() { report.Elapsed = time.Since(start) }()
+
+	data := canaryData()
+	report.SubmittedHash = payloadHash(data)
+	var transaction map[string]interface{}
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{SelfTestSubmit, func() error {
+			canary := NewCertificate(LibVersion)
+			canary.SetData(data)
+			certificate, err := canary.GetJSONCertificate()
+			if err != nil {
+				return err
+			}
+			response, err := a.SubmitCertificateContext(ctx, certificate, privateKey, CaptureTxID(&report.TxID))
+			if err != nil {
+				return err
+			}
+			return a.resultError("AddTransaction", response)
+		}},
+		{SelfTestConfirm, func() error {
+			outcome, err := a.WaitForOutcome(ctx, report.TxID, timeoutSec)
+			if err != nil {
+				return err
+			}
+			report.Outcome = outcome
+			if outcome.Status != TxConfirmed {
+				return fmt.Errorf("transaction %s is %s", report.TxID, outcome.Status)
+			}
+			return nil
+		}},
+		{SelfTestFetch, func() error {
+			// The block is known once the transaction is confirmed; the
+			// latest blocks are searched otherwise.
+			block := report.Outcome.BlockID
+			if block == "" {
+				var err error
+				transaction, err = a.GetTransactionByHash(ctx, report.TxID)
+				return err
+			}
+			response, err := a.GetTransactionByIDContext(ctx, report.TxID, block, block)
+			if err != nil {
+				return err
+			}
+			if err := a.resultError("GetTransactionbyID", response); err != nil {
+				return err
+			}
+			transaction, _ = response["Response"].(map[string]interface{})
+			return nil
+		}},
+		{SelfTestDecode, func() error {
+			certificate, err := DecodeCertificatePayload(NewTransaction(transaction))
+			if err != nil {
+				return err
+			}
+			retrieved, err := certificate.GetData()
+			if err != nil {
+				return err
+			}
+			report.RetrievedHash = payloadHash(retrieved)
+			return nil
+		}},
+		{SelfTestCompare, func() error {
+			if report.RetrievedHash != report.SubmittedHash {
+				return fmt.Errorf("retrieved data hash %s does not match submitted hash %s", report.RetrievedHash, report.SubmittedHash)
+			}
+			return nil
+		}},
+	}
+	for _, step := range steps {
+		began := time.Now()
+		err := step.run()
+		report.Steps = append(report.Steps, SelfTestStep{Name: step.name, Duration: time.Since(began), Err: err})
+		if err != nil {
+			break
+		}
+	}
+	return report, report.Err()
+}
+
+// canaryData returns the data of a self-test canary, unique to the call.
+func canaryData() string {
+	nonce := make([]byte, 8)
+	rand.Read(nonce)
+	return fmt.Sprintf("circular self-test %s %s", utils.GetFormattedTimestamp(), hex.EncodeToString(nonce))
+}
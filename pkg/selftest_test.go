@@ -0,0 +1,83 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSelfTest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		submitResult  int
+		tamper        bool
+		expectedSteps int
+		expectedErr   string
+	}{
+		{name: "Healthy", submitResult: 200, expectedSteps: 5},
+		{name: "Rejected", submitResult: 108, expectedSteps: 1, expectedErr: "self-test submit failed"},
+		{name: "Tampered", submitResult: 200, tamper: true, expectedSteps: 5, expectedErr: "self-test compare failed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var payload, fetchedBlock string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				var body map[string]string
+				json.NewDecoder(r.Body).Decode(&body)
+				if !strings.Contains(r.URL.Path, "GetTransactionbyID") {
+					payload = body["Payload"]
+					if tc.tamper {
+						// Alter the hex of the certificate data inside the
+						// payload, so that it still decodes.
+						decoded, _ := hex.DecodeString(payload)
+						payload = hex.EncodeToString(bytes.Replace(decoded, []byte("636972"), []byte("646972"), 1))
+					}
+					fmt.Fprintf(w, `{"Result":%d,"Response":{"TxID":%q}}`, tc.submitResult, body["ID"])
+					return
+				}
+				fetchedBlock = body["Start"]
+				fmt.Fprintf(w, `{"Result":200,"Response":{"ID":%q,"Payload":%q,"Status":"Executed","BlockID":"3","BlockTimestamp":"t","Position":0,"NodeID":"n"}}`, body["TxID"], payload)
+			}))
+			defer server.Close()
+
+			acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithDefaultPollStrategy(FixedPoll{Interval: 10 * time.Millisecond}))
+			acc.Open("0x" + strings.Repeat("a", 64))
+			report, err := acc.SelfTest(context.Background(), strings.Repeat("1", 64), 5)
+
+			if len(report.Steps) != tc.expectedSteps {
+				t.Fatalf("Expected %d steps, but got %+v", tc.expectedSteps, report.Steps)
+			}
+			if tc.expectedErr == "" {
+				if err != nil || !report.Healthy() {
+					t.Fatalf("Expected a healthy report, but got %+v, %v", report, err)
+				}
+				if report.TxID == "" || report.Outcome.Status != TxConfirmed || report.RetrievedHash != report.SubmittedHash {
+					t.Errorf("Expected the canary to round-trip, but got %+v", report)
+				}
+				if fetchedBlock != "3" {
+					t.Errorf("Expected the canary to be fetched from block 3, but got %q", fetchedBlock)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) || report.Healthy() {
+				t.Errorf("Expected an error containing %q, but got %v", tc.expectedErr, err)
+			}
+			if !errors.Is(err, report.Steps[len(report.Steps)-1].Err) {
+				t.Errorf("Expected the error to wrap the failed step's error, but got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrShutdown is returned by operations started after Shutdown.
+var ErrShutdown = errors.New("client is shut down")
+
+// lifecycle tracks the background workers and in-flight submissions of a
+// client so Shutdown can stop the former and wait for the latter.
+type lifecycle struct {
+	mu       sync.Mutex
+	shutdown bool
+	nextID   uint64
+	cancels  map[uint64]context.CancelFunc
+	workers  sync.WaitGroup
+	inflight sync.WaitGroup
+}
+
+// startWorker registers a background worker. The returned context is
+// cancelled when ctx is done or the client is shut down, and done must be
+// called when the worker exits.
+func (c *Client) startWorker(ctx context.Context) (workerCtx context.Context, done func(), err error) {
+	l := &c.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.shutdown {
+		return nil, nil, ErrShutdown
+	}
+	if l.cancels == nil {
+		l.cancels = make(map[uint64]context.CancelFunc)
+	}
+	workerCtx, cancel := context.WithCancel(ctx)
+	id := l.nextID
+	l.nextID++
+	l.cancels[id] = cancel
+	l.workers.Add(1)
+
+	return workerCtx, func() {
+		l.mu.Lock()
+		delete(l.cancels, id)
+		l.mu.Unlock()
+		cancel()
+		l.workers.Done()
+	}, nil
+}
+
+// beginSubmit registers an in-flight submission, which Shutdown waits for.
+// The returned function must be called when the submission completes.
+func (c *Client) beginSubmit() (done func(), err error) {
+	l := &c.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.shutdown {
+		return nil, ErrShutdown
+	}
+	l.inflight.Add(1)
+	return l.inflight.Done, nil
+}
+
+// Shutdown stops the client's background workers, such as the goroutines
+// started by WatchBlocks, and waits for them and for in-flight submissions to
+// finish. Operations started afterwards fail with ErrShutdown. If ctx expires
+// first, Shutdown returns its error; the workers have still been told to stop
+// and in-flight submissions continue in the background.
+func (c *Client) Shutdown(ctx context.Context) error {
+	l := &c.lifecycle
+	l.mu.Lock()
+	l.shutdown = true
+	for _, cancel := range l.cancels {
+		cancel()
+	}
+	l.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		l.workers.Wait()
+		l.inflight.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
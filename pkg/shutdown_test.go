@@ -0,0 +1,87 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdownStopsWatchers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Blocks":1}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, DefaultChain, LibVersion)
+	headers, err := c.WatchBlocks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if _, ok := <-headers; ok {
+		t.Error("Expected the watcher channel to be closed after Shutdown")
+	}
+
+	if _, err := c.WatchBlocks(context.Background()); !errors.Is(err, ErrShutdown) {
+		t.Errorf("Expected ErrShutdown, but got %v", err)
+	}
+}
+
+func TestShutdownWaitsForSubmissions(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+
+	submitted := make(chan error, 1)
+	go func() {
+		_, err := acc.SubmitCertificateContext(context.Background(), "data", privateKey)
+		submitted <- err
+	}()
+	<-received
+
+	t.Run("Context Expires First", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := acc.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, but got %v", err)
+		}
+	})
+
+	t.Run("New Submissions Rejected", func(t *testing.T) {
+		if _, err := acc.SubmitCertificateContext(context.Background(), "data", privateKey); !errors.Is(err, ErrShutdown) {
+			t.Errorf("Expected ErrShutdown, but got %v", err)
+		}
+	})
+
+	t.Run("Waits For In-Flight Submission", func(t *testing.T) {
+		close(release)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := acc.Shutdown(ctx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if err := <-submitted; err != nil {
+			t.Errorf("Expected the in-flight submission to succeed, but got %v", err)
+		}
+	})
+}
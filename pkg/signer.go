@@ -0,0 +1,117 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultSigningPollInterval is how often PollingSigner asks a SessionSigner
+// for the result of a signing session when no interval is given.
+const DefaultSigningPollInterval = time.Second
+
+// ErrSigningPending is returned by SessionSigner.SigningResult while a
+// session has not produced its signature yet.
+var ErrSigningPending = errors.New("signing pending")
+
+// SigningRequest describes a transaction to be signed by a Signer.
+type SigningRequest struct {
+	Address    string
+	Blockchain string
+	// TxID is the ID of the transaction, the hex encoding of Digest.
+	TxID string
+	// Digest is the SHA-256 digest to sign.
+	Digest []byte
+	// Curve names the curve the signature must be made with.
+	Curve string
+}
+
+// Signer signs transactions on behalf of an account whose key is held
+// elsewhere, such as in an HSM or by a threshold (MPC) signing service. Sign
+// returns the signature in the curve's wire encoding, DER for secp256k1. It
+// may block for as long as the provider needs and must return when ctx is
+// done.
+type Signer interface {
+	Sign(ctx context.Context, request SigningRequest) ([]byte, error)
+}
+
+// SignerFunc adapts a function to the Signer interface.
+type SignerFunc func(ctx context.Context, request SigningRequest) ([]byte, error)
+
+// Sign implements Signer.
+func (f SignerFunc) Sign(ctx context.Context, request SigningRequest) ([]byte, error) {
+	return f(ctx, request)
+}
+
+// SessionSigner is implemented by asynchronous providers, typically
+// threshold signing services, that sign in a session started by one call and
+// completed later, once enough parties have contributed. Wrap one with
+// PollingSigner to use it as a Signer.
+type SessionSigner interface {
+	// StartSigning starts a session for request and returns its ID.
+	StartSigning(ctx context.Context, request SigningRequest) (sessionID string, err error)
+	// SigningResult returns the aggregate signature of a finished session,
+	// or ErrSigningPending while the session is in progress.
+	SigningResult(ctx context.Context, sessionID string) ([]byte, error)
+}
+
+// SessionCanceler is optionally implemented by a SessionSigner to abandon a
+// session whose result is no longer wanted.
+type SessionCanceler interface {
+	CancelSigning(ctx context.Context, sessionID string) error
+}
+
+// PollingSigner returns a Signer that starts a session with provider and
+// polls it every interval, or every DefaultSigningPollInterval when interval
+// is not positive, until it yields a signature or ctx is done. Sessions
+// abandoned because ctx is done are canceled when the provider implements
+// SessionCanceler. Errors name the session, for correlation with the
+// provider's logs.
+func PollingSigner(provider SessionSigner, interval time.Duration) Signer {
+	if interval <= 0 {
+		interval = DefaultSigningPollInterval
+	}
+	return SignerFunc(func(ctx context.Context, request SigningRequest) ([]byte, error) {
+		sessionID, err := provider.StartSigning(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start signing session: %w", err)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			signature, err := provider.SigningResult(ctx, sessionID)
+			switch {
+			case err == nil:
+				return signature, nil
+			case !errors.Is(err, ErrSigningPending):
+				return nil, fmt.Errorf("signing session %s failed: %w", sessionID, err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				if canceler, ok := provider.(SessionCanceler); ok {
+					// The session is abandoned, so cancel it without the
+					// expired context.
+					cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interval)
+					canceler.CancelSigning(cancelCtx, sessionID)
+					cancel()
+				}
+				return nil, fmt.Errorf("signing session %s: %w", sessionID, ctx.Err())
+			}
+		}
+	})
+}
+
+// WithSigner signs the account's transactions with signer instead of a
+// private key; the privateKey arguments of the submission methods are then
+// ignored and may be empty. The transaction timestamp is taken before
+// signing, so slow signers should finish well within the network's
+// tolerance for stale timestamps.
+func WithSigner(signer Signer) Option {
+	return func(c *Client) {
+		c.signer = signer
+	}
+}
@@ -0,0 +1,122 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMPC is a SessionSigner that completes a session after a number of
+// polls, signing with a local key.
+type fakeMPC struct {
+	mu       sync.Mutex
+	key      []byte
+	polls    int
+	sessions map[string]SigningRequest
+	canceled []string
+	fail     error
+}
+
+func (m *fakeMPC) StartSigning(ctx context.Context, request SigningRequest) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions == nil {
+		m.sessions = make(map[string]SigningRequest)
+	}
+	id := "session-" + request.TxID[:8]
+	m.sessions[id] = request
+	return id, nil
+}
+
+func (m *fakeMPC) SigningResult(ctx context.Context, sessionID string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail != nil {
+		return nil, m.fail
+	}
+	if m.polls > 0 {
+		m.polls--
+		return nil, ErrSigningPending
+	}
+	return Secp256k1Curve.Sign(m.key, m.sessions[sessionID].Digest)
+}
+
+func (m *fakeMPC) CancelSigning(ctx context.Context, sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.canceled = append(m.canceled, sessionID)
+	return nil
+}
+
+func TestPollingSigner(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = nil
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	key, _ := hex.DecodeString(strings.Repeat("1", 64))
+	publicKey, _ := Secp256k1Curve.PublicKey(key)
+
+	t.Run("Aggregate Signature", func(t *testing.T) {
+		mpc := &fakeMPC{key: key, polls: 2}
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithSigner(PollingSigner(mpc, time.Millisecond)))
+		acc.Open("0xabc")
+		if _, err := acc.SubmitCertificateContext(context.Background(), "hello", ""); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		id, _ := hex.DecodeString(request["ID"].(string))
+		signature, _ := hex.DecodeString(request["Signature"].(string))
+		if err := Secp256k1Curve.Verify(publicKey, id, signature); err != nil {
+			t.Errorf("Expected the aggregate signature to be submitted, but got: %v", err)
+		}
+		for _, session := range mpc.sessions {
+			if session.Address != "0xabc" || session.Blockchain != DefaultChain || session.TxID != request["ID"] || session.Curve != "secp256k1" {
+				t.Errorf("Expected the signing request to describe the transaction, but got %+v", session)
+			}
+		}
+	})
+
+	t.Run("Session Failed", func(t *testing.T) {
+		mpc := &fakeMPC{key: key, fail: errors.New("quorum not reached")}
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithSigner(PollingSigner(mpc, time.Millisecond)))
+		acc.Open("0xabc")
+		_, err := acc.SubmitCertificateContext(context.Background(), "hello", "")
+		if err == nil || !strings.Contains(err.Error(), "quorum not reached") || !strings.Contains(err.Error(), "session-") {
+			t.Errorf("Expected the session error, but got %v", err)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		mpc := &fakeMPC{key: key, polls: 1 << 30}
+		signer := PollingSigner(mpc, time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := signer.Sign(ctx, SigningRequest{TxID: strings.Repeat("ab", 32), Digest: make([]byte, 32)})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the context error, but got %v", err)
+		}
+		if len(mpc.canceled) != 1 {
+			t.Errorf("Expected the abandoned session to be canceled, but got %v", mpc.canceled)
+		}
+	})
+
+	t.Run("Empty Signature", func(t *testing.T) {
+		empty := SignerFunc(func(context.Context, SigningRequest) ([]byte, error) { return nil, nil })
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithSigner(empty))
+		acc.Open("0xabc")
+		if _, err := acc.SubmitCertificateContext(context.Background(), "hello", ""); err == nil {
+			t.Error("Expected an empty signature to be rejected")
+		}
+	})
+}
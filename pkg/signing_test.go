@@ -0,0 +1,101 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// verifyHMAC checks a request the way a gateway would.
+func verifyHMAC(r *http.Request, secret []byte) bool {
+	body, _ := io.ReadAll(r.Body)
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:])
+	if r.Header.Get(HeaderContentSHA256) != digest {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	io.WriteString(mac, HMACCanonicalString(r.Method, r.URL.RequestURI(), r.Header.Get(HeaderSignatureTimestamp), digest))
+	signature, _ := hex.DecodeString(r.Header.Get(HeaderSignature))
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+func TestHMACSigning(t *testing.T) {
+	secret := []byte("shared-secret")
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !verifyHMAC(r, secret) || r.Header.Get(HeaderSignatureKeyID) != "key-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderSignatureTimestamp), 10, 64)
+		if time.Since(time.Unix(timestamp, 0)) > time.Minute {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Fail the first submission to check that the retried body is
+		// signed too.
+		if !strings.Contains(r.URL.Path, "Circular_") && atomic.AddInt32(&attempts, 1) == 1 {
+			hj, _ := w.(http.Hijacker)
+			conn, _, _ := hj.Hijack()
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc","Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	signer := &HMACSigner{KeyID: "key-1", Secret: secret}
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion,
+		WithRequestSigner(signer),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	acc.Open("0x" + strings.Repeat("a", 64))
+
+	if _, err := acc.UpdateAccount(); err != nil {
+		t.Fatalf("Expected the signed request to be accepted, but got: %v", err)
+	}
+	response, err := acc.SubmitCertificateContext(context.Background(), "data", strings.Repeat("1", 64))
+	if err != nil {
+		t.Fatalf("Expected the signed submission to be accepted, but got: %v", err)
+	}
+	if response["Result"] != float64(200) || atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("Expected the retried submission to succeed, but got %v after %d attempts", response, attempts)
+	}
+
+	wrong := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithRequestSigner(&HMACSigner{KeyID: "key-1", Secret: []byte("other")}))
+	wrong.Open("0x123")
+	if _, err := wrong.UpdateAccount(); err == nil {
+		t.Error("Expected a request signed with the wrong secret to be rejected")
+	}
+}
+
+func TestHMACSignerHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "https://nag.example/Circular_GetBlockCount_?x=1", nil)
+	signer := &HMACSigner{Secret: []byte("k"), TimestampHeader: "X-Ts", SignatureHeader: "X-Sig"}
+	if err := signer.SignRequest(req, []byte("{}"), time.Unix(1700000000, 0)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("k"))
+	sum := sha256.Sum256([]byte("{}"))
+	io.WriteString(mac, "POST\n/Circular_GetBlockCount_?x=1\n1700000000\n"+hex.EncodeToString(sum[:]))
+	if got := req.Header.Get("X-Sig"); got != hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("Expected the signature over the canonical string, but got %s", got)
+	}
+	if req.Header.Get("X-Ts") != "1700000000" || req.Header.Get(HeaderSignatureKeyID) != "" {
+		t.Errorf("Expected custom header names and no key ID, but got %v", req.Header)
+	}
+	if err := (&HMACSigner{}).SignRequest(req, nil, time.Now()); err == nil {
+		t.Error("Expected a signer without a secret to fail")
+	}
+}
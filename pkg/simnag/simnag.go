@@ -0,0 +1,478 @@
+// Package simnag simulates a Network Access Gateway in process, so that
+// applications can be tested end to end against real clients and accounts
+// without any HTTP server or network.
+//
+// A Gateway answers the requests of the clients it is installed in as a NAG
+// would: it accepts validly signed certificates and transactions, keeps the
+// nonce of every wallet, reports accepted transactions as pending and then,
+// after a configurable delay, seals them in blocks, and serves the resulting
+// transactions, blocks and wallets. It can also inject errors, either at
+// random rates or on demand with Inject.
+//
+//	gateway := simnag.New(simnag.Config{ConfirmAfter: time.Second})
+//	account := cep.NewCEPAccount(simnag.NAGURL, cep.DefaultChain, cep.LibVersion, gateway.Option())
+//
+// Signatures are not verified, since transactions do not carry the public key
+// they were signed with; the transaction ID is.
+package simnag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+// NAGURL is a NAG URL for clients using a Gateway. Any URL works, since a
+// Gateway answers every request sent through it; this one cannot reach a
+// real network by mistake.
+const NAGURL = "https://simnag.invalid/NAG.php?cep="
+
+// NodeID is the NodeID of the transactions and blocks of a Gateway.
+const NodeID = "simnag"
+
+// ErrInjected is the cause of the connection failures injected by a Gateway.
+var ErrInjected = errors.New("simnag: injected connection failure")
+
+// Config shapes the behaviour of a Gateway. Rates are probabilities between
+// 0 and 1, drawn independently for each request.
+type Config struct {
+	// Latency delays every answer.
+	Latency time.Duration
+	// ConfirmAfter is how long an accepted transaction stays pending before
+	// it is sealed in a block. Zero seals it on the next request.
+	ConfirmAfter time.Duration
+	// ErrorRate answers requests with 503 Service Unavailable without
+	// processing them.
+	ErrorRate float64
+	// RejectRate rejects valid submissions with a nonce conflict.
+	RejectRate float64
+	// FailRate seals accepted transactions with a Failed status instead of
+	// Executed.
+	FailRate float64
+	// Seed makes the random draws reproducible; zero uses a random seed.
+	Seed int64
+}
+
+// Fault is an error injected with Inject. A Fault with Err set, such as
+// ErrInjected, fails the request with that error, as a broken connection
+// would; otherwise the request is answered with Status and Body.
+type Fault struct {
+	Status int
+	Body   string
+	Err    error
+}
+
+// Gateway is an in-process NAG. It is safe for concurrent use, and its
+// state can be shared by any number of clients.
+type Gateway struct {
+	mu           sync.Mutex
+	config       Config
+	rng          *rand.Rand
+	nonces       map[string]int
+	created      map[string]time.Time
+	transactions map[string]*transaction
+	pending      []*transaction
+	blocks       []*block
+	faults       map[string][]Fault
+}
+
+// transaction is a transaction accepted by a Gateway.
+type transaction struct {
+	tx       cep.SignedTransaction
+	accepted time.Time
+	status   string
+	block    *block
+	position int
+}
+
+// block is a block sealed by a Gateway.
+type block struct {
+	number       int64
+	hash         string
+	previousHash string
+	timestamp    string
+	transactions []*transaction
+}
+
+// New returns a Gateway with no wallets, transactions or blocks.
+func New(config Config) *Gateway {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Gateway{
+		config:       config,
+		rng:          rand.New(rand.NewSource(seed)),
+		nonces:       make(map[string]int),
+		created:      make(map[string]time.Time),
+		transactions: make(map[string]*transaction),
+		faults:       make(map[string][]Fault),
+	}
+}
+
+// Option routes the requests of a client or account through g.
+func (g *Gateway) Option() cep.Option {
+	return func(c *cep.Client) {
+		c.HTTPClient = g.HTTPClient()
+	}
+}
+
+// HTTPClient returns an HTTP client whose requests are answered by g.
+func (g *Gateway) HTTPClient() *http.Client {
+	return &http.Client{Transport: g}
+}
+
+// SetNonce registers the wallet at address, if needed, and sets its nonce.
+func (g *Gateway) SetNonce(address string, nonce int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.register(normalize(address))
+	g.nonces[normalize(address)] = nonce
+}
+
+// Inject makes the next request to the NAG method fail with fault, for
+// example "AddTransaction" or "GetTransactionbyID". An empty method matches
+// the next request of any method. Faults injected several times apply to
+// successive requests.
+func (g *Gateway) Inject(method string, fault Fault) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.faults[method] = append(g.faults[method], fault)
+}
+
+// BlockCount returns the number of blocks sealed so far, sealing the
+// transactions that are due first.
+func (g *Gateway) BlockCount() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.seal(time.Now())
+	return int64(len(g.blocks))
+}
+
+// Seal seals every pending transaction in a new block at once, regardless
+// of ConfirmAfter, and returns the number of transactions sealed.
+func (g *Gateway) Seal() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := len(g.pending)
+	g.sealBlock(g.pending)
+	g.pending = nil
+	return n
+}
+
+// RoundTrip implements http.RoundTripper by answering req as a NAG would.
+func (g *Gateway) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if g.config.Latency > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(g.config.Latency):
+		}
+	}
+
+	method := method(req)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.seal(time.Now())
+
+	if fault, ok := g.nextFault(method); ok {
+		if fault.Err != nil {
+			return nil, fault.Err
+		}
+		return respond(req, fault.Status, fault.Body), nil
+	}
+	if g.hit(g.config.ErrorRate) {
+		return respond(req, http.StatusServiceUnavailable, "Service Unavailable"), nil
+	}
+
+	var params map[string]interface{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &params); err != nil {
+			return respond(req, http.StatusBadRequest, "invalid JSON"), nil
+		}
+	}
+	var answer interface{}
+	switch method {
+	case "AddTransaction":
+		answer = g.addTransaction(body)
+	case "GetWalletNonce":
+		answer = g.walletNonce(str(params["Address"]))
+	case "GetWallet", "CheckWallet":
+		answer = g.wallet(str(params["Address"]))
+	case "GetTransactionbyID":
+		answer = g.transaction(str(params["TxID"]))
+	case "GetBlockCount":
+		answer = success(map[string]interface{}{"Blocks": len(g.blocks)})
+	case "GetBlock":
+		answer = g.block(str(params["BlockNumber"]))
+	case "GetBlockRange":
+		answer = g.blockRange(str(params["Start"]), str(params["End"]))
+	default:
+		answer = map[string]interface{}{"Result": 404, "Response": fmt.Sprintf("Unknown method %s", method)}
+	}
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return nil, err
+	}
+	return respond(req, http.StatusOK, string(data)), nil
+}
+
+// addTransaction accepts a signed transaction whose ID matches its contents
+// and, when it has one, whose nonce follows the wallet's.
+func (g *Gateway) addTransaction(body []byte) interface{} {
+	tx, err := cep.ParseSignedTransaction(body)
+	if err != nil {
+		return rejected(err.Error())
+	}
+	id := normalize(tx.ID)
+	if _, ok := g.transactions[id]; ok {
+		return rejected("Duplicate Transaction")
+	}
+	address := normalize(tx.Address)
+	if tx.Nonce != "" {
+		nonce, err := strconv.Atoi(tx.Nonce)
+		switch {
+		case err != nil || nonce > g.nonces[address]+1:
+			return rejected("Invalid Nonce")
+		case nonce <= g.nonces[address]:
+			return rejected("Duplicate Nonce")
+		}
+	}
+	if g.hit(g.config.RejectRate) {
+		return rejected("Duplicate Nonce")
+	}
+
+	g.register(address)
+	g.nonces[address]++
+	accepted := &transaction{tx: *tx, accepted: time.Now(), status: "Pending", position: -1}
+	g.transactions[id] = accepted
+	g.pending = append(g.pending, accepted)
+	return success(map[string]interface{}{"TxID": tx.ID, "Timestamp": tx.Timestamp})
+}
+
+// walletNonce answers GetWalletNonce.
+func (g *Gateway) walletNonce(address string) interface{} {
+	return success(map[string]interface{}{"Nonce": g.nonces[normalize(address)]})
+}
+
+// wallet answers GetWallet and CheckWallet for the wallets that submitted a
+// transaction or were given a nonce with SetNonce.
+func (g *Gateway) wallet(address string) interface{} {
+	created, ok := g.created[normalize(address)]
+	if !ok {
+		return notFound("Wallet Not Found")
+	}
+	return success(map[string]interface{}{
+		"Address":      address,
+		"Nonce":        g.nonces[normalize(address)],
+		"DateCreation": utils.FormatTimestamp(created),
+		"Assets":       []interface{}{},
+	})
+}
+
+// transaction answers GetTransactionbyID.
+func (g *Gateway) transaction(txID string) interface{} {
+	tx, ok := g.transactions[normalize(txID)]
+	if !ok {
+		return notFound("Transaction Not Found")
+	}
+	return success(tx.fields())
+}
+
+// block answers GetBlock.
+func (g *Gateway) block(number string) interface{} {
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 || n >= int64(len(g.blocks)) {
+		return notFound("Block Not Found")
+	}
+	return success(map[string]interface{}{"Block": g.blocks[n].fields()})
+}
+
+// blockRange answers GetBlockRange with the blocks from start to end
+// (inclusive) that exist.
+func (g *Gateway) blockRange(start, end string) interface{} {
+	first, err1 := strconv.ParseInt(start, 10, 64)
+	last, err2 := strconv.ParseInt(end, 10, 64)
+	if err1 != nil || err2 != nil || first > last {
+		return rejected("Invalid Range")
+	}
+	blocks := []interface{}{}
+	for n := max(first, 0); n <= last && n < int64(len(g.blocks)); n++ {
+		blocks = append(blocks, g.blocks[n].fields())
+	}
+	return success(map[string]interface{}{"Blocks": blocks})
+}
+
+// seal seals the pending transactions accepted at least ConfirmAfter before
+// now in a new block.
+func (g *Gateway) seal(now time.Time) {
+	var due, waiting []*transaction
+	for _, tx := range g.pending {
+		if now.Sub(tx.accepted) >= g.config.ConfirmAfter {
+			due = append(due, tx)
+		} else {
+			waiting = append(waiting, tx)
+		}
+	}
+	g.pending = waiting
+	g.sealBlock(due)
+}
+
+// sealBlock appends a block holding transactions, if there are any.
+func (g *Gateway) sealBlock(transactions []*transaction) {
+	if len(transactions) == 0 {
+		return
+	}
+	b := &block{number: int64(len(g.blocks)), timestamp: utils.FormatTimestamp(time.Now()), transactions: transactions}
+	if len(g.blocks) > 0 {
+		b.previousHash = g.blocks[len(g.blocks)-1].hash
+	}
+	hash := sha256.New()
+	hash.Write([]byte(b.previousHash))
+	for i, tx := range transactions {
+		hash.Write([]byte(tx.tx.ID))
+		tx.block = b
+		tx.position = i
+		tx.status = "Executed"
+		if g.hit(g.config.FailRate) {
+			tx.status = "Failed"
+		}
+	}
+	b.hash = hex.EncodeToString(hash.Sum(nil))
+	g.blocks = append(g.blocks, b)
+}
+
+// fields returns the transaction as a NAG reports it.
+func (t *transaction) fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"ID":         t.tx.ID,
+		"From":       t.tx.Address,
+		"To":         t.tx.To,
+		"Blockchain": t.tx.Blockchain,
+		"Payload":    t.tx.Payload,
+		"Nonce":      t.tx.Nonce,
+		"Signature":  t.tx.Signature,
+		"Timestamp":  t.tx.Timestamp,
+		"Type":       string(t.tx.Type),
+		"Status":     t.status,
+	}
+	if t.block != nil {
+		fields["BlockID"] = strconv.FormatInt(t.block.number, 10)
+		fields["BlockTimestamp"] = t.block.timestamp
+		fields["Position"] = t.position
+		fields["NodeID"] = NodeID
+	}
+	return fields
+}
+
+// fields returns the block as a NAG reports it.
+func (b *block) fields() map[string]interface{} {
+	transactions := make([]interface{}, len(b.transactions))
+	for i, tx := range b.transactions {
+		transactions[i] = tx.fields()
+	}
+	return map[string]interface{}{
+		"BlockID":      strconv.FormatInt(b.number, 10),
+		"Hash":         b.hash,
+		"PreviousHash": b.previousHash,
+		"Timestamp":    b.timestamp,
+		"NodeID":       NodeID,
+		"Transactions": transactions,
+	}
+}
+
+// register records the creation of the wallet at address, if it is new.
+func (g *Gateway) register(address string) {
+	if _, ok := g.created[address]; !ok {
+		g.created[address] = time.Now()
+	}
+}
+
+// nextFault removes and returns the next fault injected for method, or for
+// any method.
+func (g *Gateway) nextFault(method string) (Fault, bool) {
+	for _, key := range []string{method, ""} {
+		if faults := g.faults[key]; len(faults) > 0 {
+			g.faults[key] = faults[1:]
+			return faults[0], true
+		}
+	}
+	return Fault{}, false
+}
+
+// hit draws an event of probability rate.
+func (g *Gateway) hit(rate float64) bool {
+	return rate > 0 && g.rng.Float64() < rate
+}
+
+// method returns the NAG method requested by req. Certificates are posted
+// to the NAG URL itself, without a method.
+func method(req *http.Request) string {
+	_, rest, found := strings.Cut(req.URL.String(), "Circular_")
+	if !found {
+		return "AddTransaction"
+	}
+	name, _, _ := strings.Cut(rest, "_")
+	return name
+}
+
+func success(response interface{}) map[string]interface{} {
+	return map[string]interface{}{"Result": 200, "Response": response}
+}
+
+func rejected(message string) map[string]interface{} {
+	return map[string]interface{}{"Result": 108, "Response": message}
+}
+
+func notFound(message string) map[string]interface{} {
+	return map[string]interface{}{"Result": 118, "Response": message}
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// normalize lowercases a hex value and strips its 0x prefix.
+func normalize(value string) string {
+	return strings.TrimPrefix(strings.ToLower(value), "0x")
+}
+
+// respond builds the response to req.
+func respond(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}, "Date": {time.Now().UTC().Format(http.TimeFormat)}},
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+var _ http.RoundTripper = (*Gateway)(nil)
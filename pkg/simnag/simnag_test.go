@@ -0,0 +1,191 @@
+package simnag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+var privateKey = strings.Repeat("1", 64)
+
+func newAccount(t *testing.T, g *Gateway, opts ...cep.Option) *cep.CEPAccount {
+	t.Helper()
+	acc := cep.NewCEPAccount(NAGURL, cep.DefaultChain, cep.LibVersion, append([]cep.Option{g.Option()}, opts...)...)
+	if err := acc.Open("0x" + strings.Repeat("a", 64)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	return acc
+}
+
+func TestGatewayEndToEnd(t *testing.T) {
+	g := New(Config{})
+	acc := newAccount(t, g)
+	ctx := context.Background()
+
+	if _, err := acc.UpdateAccountContext(ctx); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if acc.Nonce != 1 {
+		t.Errorf("Expected the nonce of a new wallet to be 1, but got %d", acc.Nonce)
+	}
+
+	response, err := acc.SubmitCertificateContext(ctx, "data", privateKey)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if result, _ := response["Result"].(float64); result != 200 {
+		t.Fatalf("Expected the certificate to be accepted, but got %v", response)
+	}
+
+	outcome, err := acc.WaitForOutcome(ctx, acc.LatestTxID, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if outcome.Status != cep.TxConfirmed || outcome.NodeID != NodeID || outcome.BlockID != "0" || outcome.Position != 0 {
+		t.Errorf("Expected the certificate in block 0, but got %+v", outcome)
+	}
+	if data, err := outcome.CertificateData(); err != nil || data != "data" {
+		t.Errorf("Expected the certificate data to round-trip, but got %q, %v", data, err)
+	}
+
+	if height, err := acc.GetBlockHeight(ctx); err != nil || height != 1 {
+		t.Errorf("Expected one block, but got %d, %v", height, err)
+	}
+	var blocks []cep.Block
+	if err := acc.IterateBlocks(ctx, 0, 0, func(block cep.Block) error {
+		blocks = append(blocks, block)
+		return nil
+	}); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].TransactionCount != 1 {
+		t.Errorf("Expected one block holding the certificate, but got %+v", blocks)
+	}
+
+	if _, err := acc.UpdateAccountContext(ctx); err != nil || acc.Nonce != 2 {
+		t.Errorf("Expected the nonce to advance to 2, but got %d, %v", acc.Nonce, err)
+	}
+	info, err := acc.GetAccountInfo(ctx)
+	if err != nil || !info.Registered || info.Nonce != 1 {
+		t.Errorf("Expected a registered wallet with nonce 1, but got %+v, %v", info, err)
+	}
+}
+
+func TestGatewayConfirmation(t *testing.T) {
+	g := New(Config{ConfirmAfter: time.Hour})
+	acc := newAccount(t, g)
+	ctx := context.Background()
+
+	if _, err := acc.SubmitCertificateContext(ctx, "data", privateKey); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	outcome, err := acc.GetOutcome(ctx, acc.LatestTxID)
+	if err != nil || outcome.Status != cep.TxPending {
+		t.Fatalf("Expected the certificate to be pending, but got %+v, %v", outcome, err)
+	}
+	if n := g.Seal(); n != 1 {
+		t.Errorf("Expected one transaction to be sealed, but got %d", n)
+	}
+	outcome, err = acc.GetOutcome(ctx, acc.LatestTxID)
+	if err != nil || outcome.Status != cep.TxConfirmed {
+		t.Errorf("Expected the certificate to be confirmed, but got %+v, %v", outcome, err)
+	}
+
+	outcome, err = acc.GetOutcome(ctx, "0x"+strings.Repeat("f", 64))
+	if err != nil || outcome.Status != cep.TxNotFound {
+		t.Errorf("Expected an unknown transaction not to be found, but got %+v, %v", outcome, err)
+	}
+}
+
+func TestGatewayNonces(t *testing.T) {
+	g := New(Config{})
+	acc := newAccount(t, g)
+	ctx := context.Background()
+	g.SetNonce(acc.Address, 5)
+
+	testCases := []struct {
+		name     string
+		nonce    int
+		expected float64
+	}{
+		{"Next", 6, 200},
+		{"Duplicate", 6, 108},
+		{"Gap", 9, 108},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, err := cep.NewTransactionBuilder(cep.TxTypeCertificate).Certificate(tc.name).Nonce(tc.nonce).Sign(ctx, acc, privateKey)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			response, err := acc.Broadcast(ctx, tx)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if result, _ := response["Result"].(float64); result != tc.expected {
+				t.Errorf("Expected Result %v, but got %v", tc.expected, response)
+			}
+		})
+	}
+}
+
+func TestGatewayFaults(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Inject", func(t *testing.T) {
+		g := New(Config{})
+		acc := newAccount(t, g)
+		g.Inject("AddTransaction", Fault{Status: http.StatusServiceUnavailable, Body: "busy"})
+		g.Inject("", Fault{Err: ErrInjected})
+
+		if _, err := acc.SubmitCertificateContext(ctx, "data", privateKey); err == nil {
+			t.Error("Expected the injected status to fail the submission, but got nil")
+		}
+		if _, err := acc.GetOutcome(ctx, "0xabc"); !errors.Is(err, ErrInjected) {
+			t.Errorf("Expected ErrInjected, but got %v", err)
+		}
+		if _, err := acc.SubmitCertificateContext(ctx, "data", privateKey); err != nil {
+			t.Errorf("Expected the faults to be used up, but got: %v", err)
+		}
+	})
+
+	t.Run("Rates", func(t *testing.T) {
+		g := New(Config{RejectRate: 1, Seed: 1})
+		acc := newAccount(t, g)
+		response, err := acc.SubmitCertificateContext(ctx, "data", privateKey)
+		if result, _ := response["Result"].(float64); err != nil || result != 108 {
+			t.Errorf("Expected the submission to be rejected, but got %v, %v", response, err)
+		}
+
+		g = New(Config{FailRate: 1, Seed: 1})
+		acc = newAccount(t, g)
+		if _, err := acc.SubmitCertificateContext(ctx, "data", privateKey); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		outcome, err := acc.WaitForOutcome(ctx, acc.LatestTxID, 5)
+		if err != nil || outcome.Status != cep.TxFailed {
+			t.Errorf("Expected the transaction to fail, but got %+v, %v", outcome, err)
+		}
+
+		g = New(Config{ErrorRate: 1, Seed: 1})
+		acc = newAccount(t, g)
+		if _, err := acc.GetBlockHeight(ctx); err == nil {
+			t.Error("Expected the request to fail, but got nil")
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		acc := newAccount(t, New(Config{Latency: time.Hour}))
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if _, err := acc.GetBlockHeight(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the latency to outlast the context, but got %v", err)
+		}
+	})
+}
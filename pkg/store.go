@@ -0,0 +1,166 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrKeyNotFound is returned by Store.Get for a key that is not stored.
+var ErrKeyNotFound = errors.New("key not found")
+
+// Store is a key-value store shared by the subsystems that persist state,
+// such as the submit queue (WithQueueStore) and dead letters
+// (NewStoreDeadLetterStore), so one backend is configured for all of them.
+// Subsystems keep their keys under distinct prefixes. MemoryStore is the
+// in-memory implementation and the store/bolt module a persistent one;
+// others, such as Redis or SQL, only need these four methods.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value of key, or an error matching ErrKeyNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores value under key, replacing any previous value.
+	Put(ctx context.Context, key string, value []byte) error
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// Iterate calls fn for every key starting with prefix, in ascending key
+	// order, and stops at the first error fn returns, returning it. fn must
+	// not modify the store.
+	Iterate(ctx context.Context, prefix string, fn func(key string, value []byte) error) error
+}
+
+// MemoryStore is a Store kept in memory.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: make(map[string][]byte)}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+// Iterate implements Store. It iterates over a snapshot of the keys, so fn
+// sees the values as of the call.
+func (s *MemoryStore) Iterate(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.values))
+	values := make(map[string][]byte)
+	for key, value := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+			values[key] = value
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(key, append([]byte(nil), values[key]...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deadLetterPrefix is the key prefix of dead letters in a Store.
+const deadLetterPrefix = "deadletter/"
+
+// storeDeadLetters is a DeadLetterStore keeping letters as JSON in a Store.
+type storeDeadLetters struct {
+	store Store
+}
+
+// NewStoreDeadLetterStore returns a DeadLetterStore that keeps letters as
+// JSON in store, under the "deadletter/" prefix.
+func NewStoreDeadLetterStore(store Store) DeadLetterStore {
+	return &storeDeadLetters{store: store}
+}
+
+// Put implements DeadLetterStore.
+func (s *storeDeadLetters) Put(ctx context.Context, letter DeadLetter) error {
+	value, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+	return s.store.Put(ctx, deadLetterPrefix+letter.ID, value)
+}
+
+// Get implements DeadLetterStore.
+func (s *storeDeadLetters) Get(ctx context.Context, id string) (DeadLetter, error) {
+	value, err := s.store.Get(ctx, deadLetterPrefix+id)
+	if errors.Is(err, ErrKeyNotFound) {
+		return DeadLetter{}, fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	var letter DeadLetter
+	if err := json.Unmarshal(value, &letter); err != nil {
+		return DeadLetter{}, fmt.Errorf("failed to decode dead letter %s: %w", id, err)
+	}
+	return letter, nil
+}
+
+// List implements DeadLetterStore.
+func (s *storeDeadLetters) List(ctx context.Context) ([]DeadLetter, error) {
+	var letters []DeadLetter
+	err := s.store.Iterate(ctx, deadLetterPrefix, func(key string, value []byte) error {
+		var letter DeadLetter
+		if err := json.Unmarshal(value, &letter); err != nil {
+			return fmt.Errorf("failed to decode dead letter %s: %w", strings.TrimPrefix(key, deadLetterPrefix), err)
+		}
+		letters = append(letters, letter)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortDeadLetters(letters)
+	return letters, nil
+}
+
+// Delete implements DeadLetterStore.
+func (s *storeDeadLetters) Delete(ctx context.Context, id string) error {
+	if _, err := s.store.Get(ctx, deadLetterPrefix+id); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+		}
+		return err
+	}
+	return s.store.Delete(ctx, deadLetterPrefix+id)
+}
@@ -0,0 +1,129 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	value := []byte("v")
+	s.Put(ctx, "b/2", value)
+	s.Put(ctx, "b/1", []byte("w"))
+	s.Put(ctx, "a/1", []byte("x"))
+	value[0] = 'z'
+
+	got, err := s.Get(ctx, "b/2")
+	if err != nil || string(got) != "v" {
+		t.Errorf("Expected a copy of the stored value, but got %q (%v)", got, err)
+	}
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, but got %v", err)
+	}
+
+	var keys []string
+	s.Iterate(ctx, "b/", func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if strings.Join(keys, ",") != "b/1,b/2" {
+		t.Errorf("Expected the prefixed keys in order, but got %v", keys)
+	}
+	stop := errors.New("stop")
+	if err := s.Iterate(ctx, "", func(string, []byte) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("Expected the callback error, but got %v", err)
+	}
+
+	if err := s.Delete(ctx, "b/2"); err != nil {
+		t.Errorf("Expected no error, but got: %v", err)
+	}
+	if err := s.Delete(ctx, "b/2"); err != nil {
+		t.Errorf("Expected deleting a missing key to succeed, but got: %v", err)
+	}
+}
+
+func TestStoreDeadLetterStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	letters := NewStoreDeadLetterStore(store)
+	now := time.Now()
+	letters.Put(ctx, DeadLetter{ID: "new", Data: "b", FirstFailed: now})
+	letters.Put(ctx, DeadLetter{ID: "old", Data: "a", FirstFailed: now.Add(-time.Hour)})
+
+	list, err := letters.List(ctx)
+	if err != nil || len(list) != 2 || list[0].ID != "old" || list[1].ID != "new" {
+		t.Errorf("Expected the letters oldest first, but got %+v (%v)", list, err)
+	}
+	if letter, err := letters.Get(ctx, "new"); err != nil || letter.Data != "b" {
+		t.Errorf("Expected the stored letter, but got %+v (%v)", letter, err)
+	}
+	if err := letters.Delete(ctx, "new"); err != nil {
+		t.Errorf("Expected no error, but got: %v", err)
+	}
+	if _, err := letters.Get(ctx, "new"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("Expected ErrDeadLetterNotFound, but got %v", err)
+	}
+	if err := letters.Delete(ctx, "new"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("Expected ErrDeadLetterNotFound, but got %v", err)
+	}
+	if _, err := store.Get(ctx, "deadletter/old"); err != nil {
+		t.Errorf("Expected the letter under the deadletter/ prefix, but got %v", err)
+	}
+}
+
+func TestSubmitQueueStore(t *testing.T) {
+	server, order, started, release := queueServer(t)
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+	store := NewMemoryStore()
+
+	// The first queue stops while certificates are still waiting.
+	ctx, cancel := context.WithCancel(context.Background())
+	q, err := acc.NewSubmitQueue(ctx, privateKey, WithQueueWorkers(1), WithQueueStore(store))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	first, _ := q.Enqueue(context.Background(), "first", PriorityNormal)
+	<-started
+	q.Enqueue(context.Background(), "low", PriorityLow)
+	q.Enqueue(context.Background(), "high", PriorityHigh)
+	cancel()
+	close(release)
+	first.Wait(context.Background())
+	q.Close(context.Background())
+
+	// The next queue recovers and submits them.
+	q, err = acc.NewSubmitQueue(context.Background(), privateKey, WithQueueWorkers(1), WithQueueStore(store))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	recovered := q.Recovered()
+	if len(recovered) != 2 {
+		t.Fatalf("Expected two recovered certificates, but got %d", len(recovered))
+	}
+	for _, s := range recovered {
+		if result, err := s.Wait(context.Background()); err != nil || result.Err != nil {
+			t.Errorf("Expected the recovered certificate to be submitted, but got %v %v", err, result.Err)
+		}
+	}
+	q.Close(context.Background())
+
+	if got := strings.Join(order(), ","); got != "high,low" {
+		t.Errorf("Expected the recovered certificates by priority, but got %s", got)
+	}
+	var left []string
+	store.Iterate(context.Background(), "", func(key string, value []byte) error {
+		left = append(left, key)
+		return nil
+	})
+	if len(left) != 0 {
+		t.Errorf("Expected submitted certificates to be removed from the store, but got %v", left)
+	}
+}
@@ -0,0 +1,171 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// streamResponseArray incrementally decodes a NAG response of the form
+//
+//	{"Result": 200, "Response": {"<arrayKey>": [ ... ]}}
+//
+// calling fn with each array element as soon as it has been read. Only one
+// element is held in memory at a time, which keeps large range queries from
+// buffering the entire body. A Response that is itself an array is streamed in
+// the same way. In strict mode a missing Result or array is a *SchemaError.
+func streamResponseArray(r io.Reader, endpoint, arrayKey string, strict bool, fn func(json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+	}
+
+	result := -1
+	message := ""
+	sawArray := false
+
+	for dec.More() {
+		key, err := readKey(dec)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+		}
+
+		switch key {
+		case "Result":
+			var n json.Number
+			if err := dec.Decode(&n); err != nil {
+				return fmt.Errorf("failed to decode %s result: %w", endpoint, err)
+			}
+			code, err := n.Int64()
+			if err != nil {
+				return fmt.Errorf("failed to decode %s result: %w", endpoint, err)
+			}
+			result = int(code)
+		case "Response":
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+			}
+			switch v := tok.(type) {
+			case json.Delim:
+				if v == '[' {
+					sawArray = true
+					if err := streamElements(dec, fn); err != nil {
+						return err
+					}
+					continue
+				}
+				if v != '{' {
+					return fmt.Errorf("failed to decode %s response: unexpected %v", endpoint, v)
+				}
+				for dec.More() {
+					field, err := readKey(dec)
+					if err != nil {
+						return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+					}
+					if field != arrayKey {
+						if err := skipValue(dec); err != nil {
+							return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+						}
+						continue
+					}
+					if err := expectDelim(dec, '['); err != nil {
+						return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+					}
+					sawArray = true
+					if err := streamElements(dec, fn); err != nil {
+						return err
+					}
+				}
+				if err := expectDelim(dec, '}'); err != nil {
+					return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+				}
+			case string:
+				message = v
+			}
+		default:
+			if err := skipValue(dec); err != nil {
+				return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+			}
+		}
+	}
+
+	if result != -1 && result != 200 {
+		if len(message) > maxMessageLength {
+			message = Redact(message)
+		}
+		return fmt.Errorf("network returned result %d: %s", result, message)
+	}
+	if strict {
+		if result == -1 {
+			return &SchemaError{Endpoint: endpoint, Field: "Result", Expected: "number", Got: "missing"}
+		}
+		if !sawArray {
+			return &SchemaError{Endpoint: endpoint, Field: "Response." + arrayKey, Expected: "array", Got: "missing"}
+		}
+	}
+	return nil
+}
+
+// streamElements decodes array elements one at a time until the closing bracket.
+func streamElements(dec *json.Decoder, fn func(json.RawMessage) error) error {
+	for dec.More() {
+		var element json.RawMessage
+		if err := dec.Decode(&element); err != nil {
+			return fmt.Errorf("failed to decode array element: %w", err)
+		}
+		if err := fn(element); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// readKey reads the next object key from the decoder.
+func readKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("expected object key, got %v", tok)
+	}
+	return key, nil
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+	return nil
+}
+
+// skipValue consumes the next value without retaining it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamResponseArray(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          string
+		strict        bool
+		expectedCount int
+		expectError   bool
+		expectSchema  bool
+	}{
+		{
+			name:          "Blocks Inside Response Object",
+			body:          `{"Result":200,"Response":{"Count":2,"Blocks":[{"BlockID":"1"},{"BlockID":"2"}]}}`,
+			expectedCount: 2,
+		},
+		{
+			name:          "Response Is An Array",
+			body:          `{"Result":200,"Response":[{"BlockID":"1"}]}`,
+			expectedCount: 1,
+		},
+		{
+			name:          "Result After Response",
+			body:          `{"Node":"n1","Response":{"Blocks":[{"BlockID":"1"}]},"Result":200}`,
+			expectedCount: 1,
+		},
+		{
+			name:        "Error Result",
+			body:        `{"Result":108,"Response":"Invalid range"}`,
+			expectError: true,
+		},
+		{
+			name:        "Truncated Body",
+			body:        `{"Result":200,"Response":{"Blocks":[{"BlockID":"1"},`,
+			expectError: true,
+		},
+		{
+			name:          "Lenient Missing Blocks",
+			body:          `{"Result":200,"Response":{}}`,
+			expectedCount: 0,
+		},
+		{
+			name:         "Strict Missing Blocks",
+			body:         `{"Result":200,"Response":{}}`,
+			strict:       true,
+			expectError:  true,
+			expectSchema: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			count := 0
+			err := streamResponseArray(strings.NewReader(tc.body), "GetBlockRange", "Blocks", tc.strict, func(raw json.RawMessage) error {
+				count++
+				return nil
+			})
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("Expected an error but got nil")
+				}
+				var schemaErr *SchemaError
+				if tc.expectSchema && !errors.As(err, &schemaErr) {
+					t.Errorf("Expected a *SchemaError, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if count != tc.expectedCount {
+				t.Errorf("Expected %d elements, but got %d", tc.expectedCount, count)
+			}
+		})
+	}
+}
+
+func TestGetBlockRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "Circular_GetBlockRange_") {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Blocks":[{"BlockID":"10"},{"BlockID":"11"},{"BlockID":"12"}]}}`))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+
+	t.Run("Visits Every Block", func(t *testing.T) {
+		var ids []string
+		err := acc.GetBlockRange(10, 12, func(block map[string]interface{}) error {
+			ids = append(ids, block["BlockID"].(string))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if strings.Join(ids, ",") != "10,11,12" {
+			t.Errorf("Expected blocks 10,11,12, but got %v", ids)
+		}
+	})
+
+	t.Run("Callback Error Stops Decoding", func(t *testing.T) {
+		stop := errors.New("stop")
+		visited := 0
+		err := acc.GetBlockRange(10, 12, func(block map[string]interface{}) error {
+			visited++
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Fatalf("Expected callback error, but got %v", err)
+		}
+		if visited != 1 {
+			t.Errorf("Expected 1 block to be visited, but got %d", visited)
+		}
+	})
+
+	t.Run("Invalid Range", func(t *testing.T) {
+		err := acc.GetBlockRange(12, 10, func(block map[string]interface{}) error { return nil })
+		if err == nil {
+			t.Fatal("Expected an error but got nil")
+		}
+	})
+}
+
+func FuzzStreamResponseArray(f *testing.F) {
+	f.Add(`{"Result":200,"Response":{"Blocks":[{"a":1},{"b":2}]}}`)
+	f.Add(`{"Result":200,"Response":[1,2,3]}`)
+	for _, seed := range adversarialSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		for _, strict := range []bool{false, true} {
+			streamResponseArray(strings.NewReader(body), "GetBlockRange", "Blocks", strict, func(element json.RawMessage) error {
+				if !json.Valid(element) {
+					t.Errorf("Expected only valid elements, but got %q", element)
+				}
+				return nil
+			})
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package circular_enterprise_apis
+
+// SubmitOption adjusts a single call to SubmitCertificate.
+type SubmitOption func(*submitOptions)
+
+// submitOptions holds the per-call settings collected from SubmitOptions.
+type submitOptions struct {
+	chain     string
+	preflight *Preflight
+	schema    *PayloadSchema
+	codec     Codec
+	recipient string
+	encryptTo string
+	// txID, when set, receives the ID of the signed transaction.
+	txID *string
+}
+
+// newSubmitOptions applies opts to the default settings.
+func newSubmitOptions(opts []SubmitOption) submitOptions {
+	var o submitOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithChain submits the certificate to the chain registered under name
+// instead of the account's default Blockchain.
+func WithChain(name string) SubmitOption {
+	return func(o *submitOptions) {
+		o.chain = name
+	}
+}
+
+// WithRecipient addresses the certificate to a recipient wallet, recorded
+// on-chain as the transaction's To. recipient is either an address or a
+// Circular domain name, which is resolved with ResolveDomain on the chain the
+// certificate is submitted to before signing.
+func WithRecipient(recipient string) SubmitOption {
+	return func(o *submitOptions) {
+		o.recipient = recipient
+	}
+}
+
+// CaptureTxID stores the ID of the signed transaction in id, so callers that
+// share an account between goroutines learn the ID of their own submission
+// rather than reading LatestTxID.
+func CaptureTxID(id *string) SubmitOption {
+	return func(o *submitOptions) {
+		o.txID = id
+	}
+}
@@ -0,0 +1,167 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// SummarySource tells where an ActivitySummary was computed from.
+type SummarySource string
+
+const (
+	// SummaryFromLedger is a summary computed from the client's Ledger,
+	// which knows about failures and pending certificates.
+	SummaryFromLedger SummarySource = "ledger"
+	// SummaryFromChain is a summary computed from the blocks, which only
+	// hold confirmed certificates.
+	SummaryFromChain SummarySource = "chain"
+)
+
+// ActivitySummary aggregates the certificates an account submitted since a
+// point in time.
+type ActivitySummary struct {
+	Address string
+	Since   time.Time
+	Source  SummarySource
+
+	// Submitted counts every signed submission, and Confirmed, Failed and
+	// Pending split them by their latest status. Failed includes rejected,
+	// expired and dropped transactions. From the chain, Submitted equals
+	// Confirmed.
+	Submitted int
+	Confirmed int
+	Failed    int
+	Pending   int
+
+	// SubmittedBytes and ConfirmedBytes are the sizes of the certificate
+	// data of the submitted and confirmed certificates.
+	SubmittedBytes int64
+	ConfirmedBytes int64
+
+	// AverageConfirmation is the mean delay between submission and
+	// confirmation, or zero when no confirmation time is known. From the
+	// chain it is measured from the signed timestamp to the block's, so it
+	// has a resolution of one second.
+	AverageConfirmation time.Duration
+}
+
+// latency accumulates confirmation delays.
+type latency struct {
+	total time.Duration
+	count int
+}
+
+func (l *latency) add(d time.Duration) {
+	if d >= 0 {
+		l.total += d
+		l.count++
+	}
+}
+
+func (l *latency) average() time.Duration {
+	if l.count == 0 {
+		return 0
+	}
+	return l.total / time.Duration(l.count)
+}
+
+// Summary counts the certificates the account submitted since since. With a
+// Ledger configured the summary is computed from it, without network access.
+// Otherwise the blocks are scanned from the latest back to the first one
+// older than since, which only finds confirmed certificates.
+func (a *CEPAccount) Summary(ctx context.Context, since time.Time) (summary *ActivitySummary, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if a.Address == "" {
+		return nil, errors.New("Account is not open")
+	}
+	if a.ledger != nil {
+		return a.ledgerSummary(ctx, since)
+	}
+	return a.chainSummary(ctx, since)
+}
+
+// ledgerSummary computes a summary from the client's ledger.
+func (a *CEPAccount) ledgerSummary(ctx context.Context, since time.Time) (*ActivitySummary, error) {
+	entries, err := a.ledger.Query(ctx, LedgerQuery{Address: a.Address, Since: since})
+	if err != nil {
+		return nil, err
+	}
+	summary := &ActivitySummary{Address: a.Address, Since: since, Source: SummaryFromLedger}
+	var delays latency
+	for _, entry := range entries {
+		summary.Submitted++
+		summary.SubmittedBytes += int64(entry.Size)
+		switch entry.Status {
+		case LedgerConfirmed:
+			summary.Confirmed++
+			summary.ConfirmedBytes += int64(entry.Size)
+			for _, transition := range entry.History {
+				if transition.Status == LedgerConfirmed {
+					delays.add(transition.At.Sub(entry.Submitted))
+					break
+				}
+			}
+		case LedgerAccepted:
+			summary.Pending++
+		default:
+			summary.Failed++
+		}
+	}
+	summary.AverageConfirmation = delays.average()
+	return summary, nil
+}
+
+// chainSummary computes a summary from the blocks, scanning BlockBatchSize
+// blocks at a time from the latest one back.
+func (a *CEPAccount) chainSummary(ctx context.Context, since time.Time) (*ActivitySummary, error) {
+	height, err := a.GetBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	size := int64(a.BlockBatchSize)
+	if size <= 0 {
+		size = DefaultBlockBatchSize
+	}
+
+	summary := &ActivitySummary{Address: a.Address, Since: since, Source: SummaryFromChain}
+	address := normalizeHex(a.Address)
+	var delays latency
+	for end := height - 1; end >= 0; end -= size {
+		start := max(end-size+1, 0)
+		older := false
+		err := a.IterateBlocks(ctx, start, end, func(block Block) error {
+			blockTime, err := utils.ParseTimestamp(block.Timestamp)
+			if err == nil && blockTime.Before(since) {
+				older = true
+				return nil
+			}
+			for _, tx := range block.Transactions {
+				row := certificateRow(tx.Fields, block.Number)
+				if row.DataHash == "" || normalizeHex(row.From) != address {
+					continue
+				}
+				summary.Confirmed++
+				summary.ConfirmedBytes += int64(row.Size)
+				if signed, err := utils.ParseTimestamp(row.Timestamp); err == nil && !blockTime.IsZero() {
+					delays.add(blockTime.Sub(signed))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if older {
+			break
+		}
+	}
+	summary.Submitted = summary.Confirmed
+	summary.SubmittedBytes = summary.ConfirmedBytes
+	summary.AverageConfirmation = delays.average()
+	return summary, nil
+}
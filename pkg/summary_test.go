@@ -0,0 +1,111 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+func TestSummaryFromLedger(t *testing.T) {
+	ctx := context.Background()
+	address := "0x" + strings.Repeat("a", 64)
+	since := time.Now().Add(-time.Hour)
+	ledger := NewMemoryLedger()
+	for _, entry := range []LedgerEntry{
+		{TxID: "1", Address: address, Status: LedgerConfirmed, Size: 10, Submitted: since.Add(time.Minute),
+			History: []LedgerTransition{{Status: LedgerAccepted}, {Status: LedgerConfirmed, At: since.Add(time.Minute + 2*time.Second)}}},
+		{TxID: "2", Address: address, Status: LedgerConfirmed, Size: 20, Submitted: since.Add(2 * time.Minute),
+			History: []LedgerTransition{{Status: LedgerConfirmed, At: since.Add(2*time.Minute + 4*time.Second)}}},
+		{TxID: "3", Address: address, Status: LedgerAccepted, Size: 30, Submitted: since.Add(3 * time.Minute)},
+		{TxID: "4", Address: address, Status: LedgerRejected, Size: 40, Submitted: since.Add(4 * time.Minute)},
+		{TxID: "5", Address: address, Status: LedgerDropped, Size: 50, Submitted: since.Add(5 * time.Minute)},
+		{TxID: "6", Address: address, Status: LedgerConfirmed, Size: 60, Submitted: since.Add(-time.Minute)},
+		{TxID: "7", Address: "0xother", Status: LedgerConfirmed, Size: 70, Submitted: since.Add(time.Minute)},
+	} {
+		ledger.Record(ctx, entry)
+	}
+
+	acc := NewCEPAccount("", DefaultChain, LibVersion, WithLedger(ledger))
+	acc.Open(address)
+	summary, err := acc.Summary(ctx, since)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	expected := ActivitySummary{
+		Address:             address,
+		Since:               since,
+		Source:              SummaryFromLedger,
+		Submitted:           5,
+		Confirmed:           2,
+		Failed:              2,
+		Pending:             1,
+		SubmittedBytes:      150,
+		ConfirmedBytes:      30,
+		AverageConfirmation: 3 * time.Second,
+	}
+	if *summary != expected {
+		t.Errorf("Expected %+v, but got %+v", expected, *summary)
+	}
+}
+
+func TestSummaryFromChain(t *testing.T) {
+	address := strings.Repeat("a", 64)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	payload := utils.StringToHex(`{"data":"hello"}`)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.Contains(r.URL.Path, "GetBlockCount") {
+			w.Write([]byte(`{"Result":200,"Response":{"Blocks":10}}`))
+			return
+		}
+		var request struct{ Start, End string }
+		json.NewDecoder(r.Body).Decode(&request)
+		ranges = append(ranges, request.Start+"-"+request.End)
+		start, _ := strconv.Atoi(request.Start)
+		end, _ := strconv.Atoi(request.End)
+		var blocks []string
+		for i := start; i <= end; i++ {
+			blockTime := base.Add(time.Duration(i) * time.Minute)
+			signed := utils.FormatTimestamp(blockTime.Add(-30 * time.Second))
+			blocks = append(blocks, fmt.Sprintf(`{"Block":{"BlockID":"%d","Timestamp":"%s","Transactions":[`+
+				`{"ID":"a%d","From":"0x%s","Timestamp":"%s","Payload":"%s"},`+
+				`{"ID":"b%d","From":"other","Timestamp":"%s","Payload":"%s"}]}}`,
+				i, utils.FormatTimestamp(blockTime), i, address, signed, payload, i, signed, payload))
+		}
+		fmt.Fprintf(w, `{"Result":200,"Response":{"Blocks":[%s]}}`, strings.Join(blocks, ","))
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithBlockBatchSize(3))
+	acc.Open(address)
+	since := base.Add(5 * time.Minute)
+	summary, err := acc.Summary(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	expected := ActivitySummary{
+		Address:             address,
+		Since:               since,
+		Source:              SummaryFromChain,
+		Submitted:           5,
+		Confirmed:           5,
+		SubmittedBytes:      25,
+		ConfirmedBytes:      25,
+		AverageConfirmation: 30 * time.Second,
+	}
+	if *summary != expected {
+		t.Errorf("Expected %+v, but got %+v", expected, *summary)
+	}
+	if strings.Join(ranges, ",") != "7-9,4-6" {
+		t.Errorf("Expected the scan to stop at the first older block, but got ranges %v", ranges)
+	}
+}
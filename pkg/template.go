@@ -0,0 +1,182 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// placeholderPattern matches a {{field}} placeholder. Field names are letters,
+// digits, underscores, dashes and dots.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// TemplateError reports input that does not fit a CertificateTemplate.
+type TemplateError struct {
+	Template string
+	// Missing lists placeholders without a value.
+	Missing []string
+	// Unknown lists input fields the template does not use.
+	Unknown []string
+}
+
+// Error implements the error interface.
+func (e *TemplateError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing fields "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Unknown) > 0 {
+		problems = append(problems, "unknown fields "+strings.Join(e.Unknown, ", "))
+	}
+	return fmt.Sprintf("template %s: %s", e.Template, strings.Join(problems, "; "))
+}
+
+// CertificateTemplate renders certificate data from structured input. The
+// template is a JSON document whose string values may contain {{field}}
+// placeholders. A string that is exactly one placeholder is replaced by the
+// input value as JSON, so numbers, booleans and objects keep their type; a
+// placeholder inside a longer string is replaced by the value's text.
+//
+// Every placeholder is required, and input fields that the template does not
+// use are rejected, so certificates issued from one template stay consistent.
+type CertificateTemplate struct {
+	Name   string
+	body   interface{}
+	fields []string
+}
+
+// NewCertificateTemplate parses body as a template.
+func NewCertificateTemplate(name, body string) (*CertificateTemplate, error) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("template %s: invalid JSON: %w", name, err)
+	}
+
+	seen := make(map[string]bool)
+	walkTemplateStrings(parsed, func(s string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			seen[match[1]] = true
+		}
+	})
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return &CertificateTemplate{Name: name, body: parsed, fields: fields}, nil
+}
+
+// Fields returns the placeholders used by the template, sorted.
+func (t *CertificateTemplate) Fields() []string {
+	return append([]string(nil), t.fields...)
+}
+
+// Render substitutes input into the template and returns the certificate
+// data as compact JSON with object keys sorted. A *TemplateError lists every
+// missing and unknown field.
+func (t *CertificateTemplate) Render(input map[string]interface{}) (string, error) {
+	templateErr := &TemplateError{Template: t.Name}
+	for _, field := range t.fields {
+		if _, ok := input[field]; !ok {
+			templateErr.Missing = append(templateErr.Missing, field)
+		}
+	}
+	for field := range input {
+		if i := sort.SearchStrings(t.fields, field); i == len(t.fields) || t.fields[i] != field {
+			templateErr.Unknown = append(templateErr.Unknown, field)
+		}
+	}
+	if len(templateErr.Missing) > 0 || len(templateErr.Unknown) > 0 {
+		sort.Strings(templateErr.Unknown)
+		return "", templateErr
+	}
+
+	rendered, err := renderTemplateValue(t.body, input)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, rendered); err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// renderTemplateValue returns a copy of v with placeholders substituted.
+func renderTemplateValue(v interface{}, input map[string]interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			rendered, err := renderTemplateValue(value, input)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			rendered, err := renderTemplateValue(value, input)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	case string:
+		if match := placeholderPattern.FindStringSubmatchIndex(v); match != nil && match[0] == 0 && match[1] == len(v) {
+			return input[v[match[2]:match[3]]], nil
+		}
+		var renderErr error
+		rendered := placeholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			field := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			switch value := input[field].(type) {
+			case string:
+				return value
+			case map[string]interface{}, []interface{}:
+				renderErr = fmt.Errorf("field %s: objects and arrays cannot be embedded in text", field)
+				return ""
+			default:
+				return fmt.Sprint(value)
+			}
+		})
+		return rendered, renderErr
+	default:
+		return v, nil
+	}
+}
+
+// walkTemplateStrings calls fn for every string value in v.
+func walkTemplateStrings(v interface{}, fn func(string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, value := range v {
+			walkTemplateStrings(value, fn)
+		}
+	case []interface{}:
+		for _, value := range v {
+			walkTemplateStrings(value, fn)
+		}
+	case string:
+		fn(v)
+	}
+}
+
+// SubmitTemplate renders t with input and submits the result as the
+// certificate data. Nothing is signed or sent if the input does not fit the
+// template.
+func (a *CEPAccount) SubmitTemplate(ctx context.Context, t *CertificateTemplate, input map[string]interface{}, privateKey string, opts ...SubmitOption) (map[string]interface{}, error) {
+	data, err := t.Render(input)
+	if err != nil {
+		return nil, err
+	}
+	return a.SubmitCertificateContext(ctx, data, privateKey, opts...)
+}
@@ -0,0 +1,86 @@
+package circular_enterprise_apis
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCertificateTemplateRender(t *testing.T) {
+	tmpl, err := NewCertificateTemplate("diploma", `{
+		"type": "diploma",
+		"student": {"name": "{{name}}", "id": "{{ studentId }}"},
+		"grade": "{{grade}}",
+		"title": "Diploma of {{course}} ({{year}})"
+	}`)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if fields := tmpl.Fields(); !reflect.DeepEqual(fields, []string{"course", "grade", "name", "studentId", "year"}) {
+		t.Errorf("Expected sorted placeholders, but got %v", fields)
+	}
+
+	testCases := []struct {
+		name     string
+		input    map[string]interface{}
+		expected string
+		missing  []string
+		unknown  []string
+	}{
+		{
+			name: "All Fields",
+			input: map[string]interface{}{
+				"name": "Ada", "studentId": 42, "grade": 9.5, "course": "Physics", "year": 2024,
+			},
+			expected: `{"grade":9.5,"student":{"id":42,"name":"Ada"},"title":"Diploma of Physics (2024)","type":"diploma"}`,
+		},
+		{
+			name:    "Missing Fields",
+			input:   map[string]interface{}{"name": "Ada", "course": "Physics"},
+			missing: []string{"grade", "studentId", "year"},
+		},
+		{
+			name: "Unknown Fields",
+			input: map[string]interface{}{
+				"name": "Ada", "studentId": 42, "grade": 9.5, "course": "Physics", "year": 2024, "nickname": "A",
+			},
+			unknown: []string{"nickname"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tmpl.Render(tc.input)
+			if tc.missing == nil && tc.unknown == nil {
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				if data != tc.expected {
+					t.Errorf("Expected %s, but got %s", tc.expected, data)
+				}
+				return
+			}
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("Expected a *TemplateError, but got: %v", err)
+			}
+			if !reflect.DeepEqual(templateErr.Missing, tc.missing) || !reflect.DeepEqual(templateErr.Unknown, tc.unknown) {
+				t.Errorf("Expected missing %v and unknown %v, but got %v and %v", tc.missing, tc.unknown, templateErr.Missing, templateErr.Unknown)
+			}
+		})
+	}
+}
+
+func TestCertificateTemplateInvalid(t *testing.T) {
+	if _, err := NewCertificateTemplate("broken", `{"name": "{{name}}"`); err == nil {
+		t.Error("Expected an error for invalid JSON, but got nil")
+	}
+
+	tmpl, err := NewCertificateTemplate("embedded", `{"title": "Award: {{award}}"}`)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if _, err := tmpl.Render(map[string]interface{}{"award": map[string]interface{}{"a": 1}}); err == nil {
+		t.Error("Expected an error when embedding an object in text, but got nil")
+	}
+}
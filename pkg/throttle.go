@@ -0,0 +1,144 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Throttle caps the bulk traffic of a client; see WithBulkThrottle. A zero
+// field leaves that dimension unlimited.
+type Throttle struct {
+	// RequestsPerSecond caps the rate of bulk requests, retries included.
+	RequestsPerSecond float64
+	// BytesPerSecond caps the bandwidth of bulk requests, counting both
+	// request and response bodies as they cross the wire.
+	BytesPerSecond int64
+}
+
+// bulkKey is the context key marking bulk traffic.
+type bulkKey struct{}
+
+// ContextWithBulk returns a copy of ctx whose requests count as bulk traffic,
+// throttled according to WithBulkThrottle. SubmitBatch, GetTransactionsByIDs,
+// low-priority SubmitQueue certificates, Pipeline certificates, block
+// iteration, exports, reconciliations and tombstone indexing mark their
+// requests this way already; use it for other background jobs.
+func ContextWithBulk(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bulkKey{}, true)
+}
+
+// isBulk reports whether ctx carries bulk traffic.
+func isBulk(ctx context.Context) bool {
+	bulk, _ := ctx.Value(bulkKey{}).(bool)
+	return bulk
+}
+
+// WithBulkThrottle limits the client's bulk traffic to t, so background jobs
+// such as imports and exports leave room for interactive requests to the
+// same NAG. Requests that are not bulk are never delayed. The limits apply to
+// the client as a whole, however many bulk operations run at once.
+func WithBulkThrottle(t Throttle) Option {
+	return func(c *Client) {
+		c.bulkThrottle = &throttle{
+			requests: newTokenBucket(t.RequestsPerSecond),
+			bytes:    newTokenBucket(float64(t.BytesPerSecond)),
+		}
+	}
+}
+
+// throttle holds the token buckets of a Throttle. A nil bucket is unlimited.
+type throttle struct {
+	requests *tokenBucket
+	bytes    *tokenBucket
+}
+
+// tokenBucket refills at rate tokens per second, holding at most a second's
+// worth. Taking more tokens than it holds runs it into debt, which later
+// callers wait out, so a request larger than the burst is delayed rather
+// than refused.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket refilling at rate, or nil if rate is
+// not positive.
+func newTokenBucket(rate float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: rate, tokens: max(rate, 1), last: time.Now()}
+}
+
+// wait takes n tokens, waiting until the bucket is out of debt or ctx is
+// done.
+func (b *tokenBucket) wait(ctx context.Context, n int64) error {
+	if b == nil || n <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, max(b.rate, 1))
+	b.last = now
+	b.tokens -= float64(n)
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttleRequest delays a bulk request until the client's throttle admits
+// it and its body.
+func (c *Client) throttleRequest(ctx context.Context, contentLength int64) error {
+	if c.bulkThrottle == nil || !isBulk(ctx) {
+		return nil
+	}
+	if err := c.bulkThrottle.requests.wait(ctx, 1); err != nil {
+		return err
+	}
+	return c.bulkThrottle.bytes.wait(ctx, contentLength)
+}
+
+// throttleResponse paces the reading of the body of a bulk response by the
+// client's throttle.
+func (c *Client) throttleResponse(ctx context.Context, body io.ReadCloser) io.ReadCloser {
+	if c.bulkThrottle == nil || c.bulkThrottle.bytes == nil || !isBulk(ctx) {
+		return body
+	}
+	return &throttledBody{ReadCloser: body, ctx: ctx, bucket: c.bulkThrottle.bytes}
+}
+
+// throttledBody takes a token from bucket for every byte read.
+type throttledBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	bucket *tokenBucket
+}
+
+// Read implements io.Reader.
+func (b *throttledBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := b.bucket.wait(b.ctx, int64(n)); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,64 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBulkThrottle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 1500)))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	get := func(c *Client, ctx context.Context) error {
+		resp, err := c.get(ctx, server.URL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+
+	t.Run("Requests", func(t *testing.T) {
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithBulkThrottle(Throttle{RequestsPerSecond: 1}))
+		if err := get(c, ContextWithBulk(ctx)); err != nil {
+			t.Fatalf("Expected the first bulk request to pass, but got: %v", err)
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		if err := get(c, ContextWithBulk(timeoutCtx)); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the second bulk request to wait, but got %v", err)
+		}
+		interactiveCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		if err := get(c, interactiveCtx); err != nil {
+			t.Errorf("Expected an interactive request not to be throttled, but got: %v", err)
+		}
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithBulkThrottle(Throttle{BytesPerSecond: 1000}))
+		start := time.Now()
+		if err := get(c, ContextWithBulk(ctx)); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		// The bucket holds 1000 bytes, so the remaining 500 take half a
+		// second.
+		if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+			t.Errorf("Expected reading 1500 bytes to be paced, but it took %v", elapsed)
+		}
+	})
+
+	if !isBulk(ContextWithBulk(ctx)) || isBulk(ctx) {
+		t.Error("Expected only contexts from ContextWithBulk to be bulk")
+	}
+}
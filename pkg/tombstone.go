@@ -0,0 +1,255 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// TombstoneType is the type field of the certificate data of a tombstone.
+const TombstoneType = "circular.tombstone"
+
+// TombstoneAction tells what a tombstone asks of the data it references.
+type TombstoneAction string
+
+const (
+	// TombstoneRevocation withdraws a certificate: its data stays readable
+	// but is no longer vouched for by the issuer.
+	TombstoneRevocation TombstoneAction = "revocation"
+	// TombstoneErasure records a request to erase the data of a
+	// certificate, such as under the GDPR right to erasure. GetCertificate
+	// withholds the data of an erased certificate.
+	TombstoneErasure TombstoneAction = "erasure"
+)
+
+// tombstonePrefix starts the Store keys of tombstones, followed by the
+// normalized ID of the transaction they reference and of their sender.
+const tombstonePrefix = "tombstone/"
+
+// Tombstone is a certificate that revokes, or requests the erasure of, an
+// earlier certificate. Chain data is immutable, so rather than removing the
+// original, a tombstone referencing it is certified after it, and readers
+// going through GetCertificate are told about it.
+type Tombstone struct {
+	Type    string          `json:"type"`
+	Revokes string          `json:"revokes"`
+	Action  TombstoneAction `json:"action"`
+	Reason  string          `json:"reason,omitempty"`
+	Issued  string          `json:"issued"`
+
+	// TxID and From are the ID and sender of the tombstone's own
+	// transaction. They are not part of the certificate data.
+	TxID string `json:"txID,omitempty"`
+	From string `json:"from,omitempty"`
+}
+
+// ParseTombstone decodes certificate data holding a tombstone. The second
+// result is false for any other data.
+func ParseTombstone(data string) (*Tombstone, bool) {
+	var tombstone Tombstone
+	if err := json.Unmarshal([]byte(data), &tombstone); err != nil {
+		return nil, false
+	}
+	if tombstone.Type != TombstoneType || tombstone.Revokes == "" {
+		return nil, false
+	}
+	return &tombstone, true
+}
+
+// WithTombstoneStore keeps the tombstones known to the client in store, under
+// keys starting with "tombstone/", so they survive restarts. Without it they
+// are kept in memory.
+func WithTombstoneStore(store Store) Option {
+	return func(c *Client) {
+		c.tombstones = store
+	}
+}
+
+// IssueTombstone certifies a tombstone for the certificate txID, which
+// should have been sent by this account: GetCertificate ignores tombstones
+// from other senders. reason is recorded on-chain, so it must not itself
+// contain the data being erased. Once accepted by the network, the tombstone
+// is added to the client's tombstone store. opts adjust the submission as for
+// SubmitCertificate.
+func (a *CEPAccount) IssueTombstone(ctx context.Context, txID string, action TombstoneAction, reason, privateKey string, opts ...SubmitOption) (tombstone *Tombstone, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	switch action {
+	case TombstoneRevocation, TombstoneErasure:
+	default:
+		return nil, fmt.Errorf("unknown tombstone action %q", action)
+	}
+	if normalizeHex(txID) == "" {
+		return nil, errors.New("transaction ID is empty")
+	}
+	tombstone = &Tombstone{
+		Type:    TombstoneType,
+		Revokes: txID,
+		Action:  action,
+		Reason:  reason,
+		Issued:  utils.FormatTimestamp(a.now()),
+	}
+	data, err := json.Marshal(tombstone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+
+	var tombstoneID string
+	opts = append(opts[:len(opts):len(opts)], CaptureTxID(&tombstoneID))
+	response, err := a.SubmitCertificateContext(ctx, string(data), privateKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.resultError("AddTransaction", response); err != nil {
+		return nil, err
+	}
+	tombstone.TxID = tombstoneID
+	tombstone.From = a.Address
+	if err := a.putTombstone(ctx, tombstone); err != nil {
+		return tombstone, err
+	}
+	return tombstone, nil
+}
+
+// IndexTombstones scans the blocks from start to end (inclusive) for
+// tombstones and adds them to the client's tombstone store, so tombstones
+// issued by other clients are surfaced by GetCertificate too. It returns the
+// number of tombstones found.
+func (c *Client) IndexTombstones(ctx context.Context, start, end int64) (n int, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	err = c.scanTransactions(ctx, start, end, func(tx map[string]interface{}, block int64) error {
+		payload, _ := tx["Payload"].(string)
+		data, err := decodePayload(payload)
+		if err != nil {
+			return nil
+		}
+		tombstone, ok := ParseTombstone(data)
+		if !ok {
+			return nil
+		}
+		row := certificateRow(tx, block)
+		tombstone.TxID = row.TxID
+		tombstone.From = row.From
+		if err := c.putTombstone(ctx, tombstone); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// LookupTombstone returns the tombstone sent by from for the certificate
+// txID that is known to the client, or nil if there is none.
+func (c *Client) LookupTombstone(ctx context.Context, txID, from string) (*Tombstone, error) {
+	value, err := c.tombstones.Get(ctx, tombstoneKey(txID, from))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tombstone of %s: %w", txID, err)
+	}
+	var tombstone Tombstone
+	if err := json.Unmarshal(value, &tombstone); err != nil {
+		return nil, fmt.Errorf("failed to decode tombstone of %s: %w", txID, err)
+	}
+	return &tombstone, nil
+}
+
+// tombstoneKey returns the Store key of the tombstone sent by from for txID.
+// Keying by sender keeps a tombstone from another account from hiding the
+// one the certificate's sender issued.
+func tombstoneKey(txID, from string) string {
+	return tombstonePrefix + normalizeHex(txID) + "/" + normalizeHex(from)
+}
+
+// putTombstone stores a tombstone under the ID it references and its sender.
+// An erasure replaces a revocation, but not the other way around.
+func (c *Client) putTombstone(ctx context.Context, tombstone *Tombstone) error {
+	if tombstone.Action != TombstoneErasure {
+		existing, err := c.LookupTombstone(ctx, tombstone.Revokes, tombstone.From)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.Action == TombstoneErasure {
+			return nil
+		}
+	}
+	value, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+	if err := c.tombstones.Put(ctx, tombstoneKey(tombstone.Revokes, tombstone.From), value); err != nil {
+		return fmt.Errorf("failed to store tombstone of %s: %w", tombstone.Revokes, err)
+	}
+	return nil
+}
+
+// RetrievedCertificate is a certificate fetched by GetCertificate.
+type RetrievedCertificate struct {
+	TxID string
+	From string
+	// Data is the certified data. It is empty when the certificate was
+	// erased.
+	Data string
+	// Transaction is the transaction object returned by the network,
+	// without its Payload when the certificate was erased.
+	Transaction map[string]interface{}
+	// Tombstone is the tombstone issued by the certificate's sender for it,
+	// or nil if none is known.
+	Tombstone *Tombstone
+}
+
+// Revoked reports whether the certificate was revoked or erased.
+func (r *RetrievedCertificate) Revoked() bool {
+	return r.Tombstone != nil
+}
+
+// Erased reports whether the erasure of the certificate was requested, in
+// which case its data is withheld.
+func (r *RetrievedCertificate) Erased() bool {
+	return r.Tombstone != nil && r.Tombstone.Action == TombstoneErasure
+}
+
+// GetCertificate fetches the certificate txID, searched for like
+// GetTransactionByHash, and decodes its data. When the client knows a
+// tombstone for it, from IssueTombstone or IndexTombstones, that was issued
+// by the certificate's own sender, it is returned in Tombstone, and the data
+// of an erased certificate is left out.
+func (c *Client) GetCertificate(ctx context.Context, txID string) (certificate *RetrievedCertificate, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	transaction, err := c.GetTransactionByHash(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+	certificate = &RetrievedCertificate{TxID: txID, Transaction: transaction}
+	certificate.From, _ = transaction["From"].(string)
+
+	if certificate.Tombstone, err = c.LookupTombstone(ctx, txID, certificate.From); err != nil {
+		return nil, err
+	}
+	if certificate.Erased() {
+		withheld := make(map[string]interface{}, len(transaction))
+		for key, value := range transaction {
+			if key != "Payload" {
+				withheld[key] = value
+			}
+		}
+		certificate.Transaction = withheld
+		return certificate, nil
+	}
+
+	payload, _ := transaction["Payload"].(string)
+	if certificate.Data, err = decodePayload(payload); err != nil {
+		return nil, fmt.Errorf("transaction %s does not hold a certificate: %w", txID, err)
+	}
+	return certificate, nil
+}
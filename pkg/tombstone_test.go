@@ -0,0 +1,138 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+func TestParseTombstone(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected bool
+	}{
+		{"Tombstone", `{"type":"circular.tombstone","revokes":"abc","action":"erasure"}`, true},
+		{"Other Type", `{"type":"invoice","revokes":"abc"}`, false},
+		{"No Reference", `{"type":"circular.tombstone","action":"erasure"}`, false},
+		{"Not JSON", "hello", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tombstone, ok := ParseTombstone(tc.data)
+			if ok != tc.expected {
+				t.Fatalf("Expected %v, but got %v", tc.expected, ok)
+			}
+			if ok && (tombstone.Revokes != "abc" || tombstone.Action != TombstoneErasure) {
+				t.Errorf("Expected a tombstone for abc, but got %+v", tombstone)
+			}
+		})
+	}
+}
+
+func TestTombstones(t *testing.T) {
+	address := "0x" + strings.Repeat("a", 64)
+	original := strings.Repeat("1", 64)
+	tombstoneData := func(action TombstoneAction) string {
+		data, _ := json.Marshal(Tombstone{Type: TombstoneType, Revokes: original, Action: action})
+		payload, _ := encodePayload(new(bytes.Buffer), string(data))
+		return payload
+	}
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		switch {
+		case strings.Contains(r.URL.Path, "GetBlockCount"):
+			w.Write([]byte(`{"Result":200,"Response":{"Blocks":10}}`))
+		case strings.Contains(r.URL.Path, "GetTransactionbyID"):
+			fmt.Fprintf(w, `{"Result":200,"Response":{"ID":"%s","From":"%s","Status":"Executed","Payload":"%s"}}`,
+				original, address, utils.StringToHex(`{"data":"personal data"}`))
+		case strings.Contains(r.URL.Path, "GetBlockRange"):
+			fmt.Fprintf(w, `{"Result":200,"Response":{"Blocks":[{"Block":{"BlockID":"9","Transactions":[`+
+				`{"ID":"t1","From":"0x%s","Payload":"%s"},`+
+				`{"ID":"t2","From":"%s","Payload":"%s"},`+
+				`{"ID":"t3","From":"%s","Payload":"%s"}]}}]}}`,
+				strings.Repeat("b", 64), tombstoneData(TombstoneErasure),
+				address, utils.StringToHex(`{"data":"unrelated"}`),
+				strings.TrimPrefix(address, "0x"), tombstoneData(TombstoneErasure))
+		default:
+			submitted = request
+			w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+		}
+	}))
+	defer server.Close()
+
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.Open(address)
+	ctx := context.Background()
+
+	certificate, err := acc.GetCertificate(ctx, original)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if certificate.Revoked() || certificate.Data != "personal data" {
+		t.Errorf("Expected the certificate data without tombstone, but got %+v", certificate)
+	}
+
+	t.Run("Issue", func(t *testing.T) {
+		if _, err := acc.IssueTombstone(ctx, original, "delete", "", strings.Repeat("1", 64)); err == nil {
+			t.Error("Expected an error for an unknown action, but got nil")
+		}
+		tombstone, err := acc.IssueTombstone(ctx, original, TombstoneRevocation, "superseded", strings.Repeat("1", 64))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		payload, _ := submitted["Payload"].(string)
+		data, _ := decodePayload(payload)
+		onChain, ok := ParseTombstone(data)
+		if !ok || onChain.Revokes != original || onChain.Reason != "superseded" {
+			t.Errorf("Expected a tombstone to be submitted, but got %q", data)
+		}
+		if tombstone.TxID != submitted["ID"] || tombstone.From != address {
+			t.Errorf("Expected the tombstone's transaction to be recorded, but got %+v", tombstone)
+		}
+
+		certificate, err := acc.GetCertificate(ctx, original)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if !certificate.Revoked() || certificate.Erased() || certificate.Data != "personal data" {
+			t.Errorf("Expected a revoked certificate with its data, but got %+v", certificate)
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		n, err := acc.IndexTombstones(ctx, 9, 9)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("Expected 2 tombstones, but got %d", n)
+		}
+		certificate, err := acc.GetCertificate(ctx, original)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if !certificate.Erased() || certificate.Tombstone.TxID != "t3" {
+			t.Errorf("Expected the sender's erasure, but got %+v", certificate.Tombstone)
+		}
+		if certificate.Data != "" || certificate.Transaction["Payload"] != nil {
+			t.Errorf("Expected the data of an erased certificate to be withheld, but got %+v", certificate)
+		}
+	})
+
+	t.Run("Other Sender", func(t *testing.T) {
+		tombstone, err := acc.LookupTombstone(ctx, original, strings.Repeat("b", 64))
+		if err != nil || tombstone == nil || tombstone.TxID != "t1" {
+			t.Errorf("Expected the other sender's tombstone to be kept apart, but got %+v, %v", tombstone, err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package circular_enterprise_apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Transaction is the typed form of a transaction object returned by the
+// gateway, such as the transactions of a Block.
+type Transaction struct {
+	ID         string
+	From       string
+	To         string
+	Blockchain string
+	Type       TxType
+	// Payload is the hex-encoded payload; see CertificateData.
+	Payload   string
+	Nonce     string
+	Signature string
+	Timestamp string
+
+	Status TxStatus
+	// RawStatus is the Status string as returned by the network.
+	RawStatus string
+	// BlockID is the number of the block holding the transaction and
+	// Position its index among the block's transactions, or -1 when unknown.
+	BlockID  string
+	Position int
+	NodeID   string
+
+	// Fields is the transaction object as returned by the gateway.
+	Fields map[string]interface{}
+}
+
+// NewTransaction builds a Transaction from a transaction object returned by
+// the gateway. Gateways return identifiers and nonces as strings or numbers,
+// and some name the sender Address rather than From; both forms are accepted.
+func NewTransaction(fields map[string]interface{}) Transaction {
+	tx := Transaction{
+		ID:         firstField(fields, "ID", "TxID"),
+		From:       firstField(fields, "From", "Address"),
+		To:         firstField(fields, "To"),
+		Blockchain: firstField(fields, "Blockchain"),
+		Type:       TxType(firstField(fields, "Type")),
+		Payload:    firstField(fields, "Payload"),
+		Nonce:      firstField(fields, "Nonce"),
+		Signature:  firstField(fields, "Signature"),
+		Timestamp:  firstField(fields, "Timestamp"),
+		RawStatus:  firstField(fields, "Status"),
+		BlockID:    firstField(fields, "BlockID", "BlockId", "BlockNumber", "Block"),
+		Position:   -1,
+		NodeID:     firstField(fields, "NodeID", "NodeId", "Node"),
+		Fields:     fields,
+	}
+	tx.Status = ParseTxStatus(tx.RawStatus)
+	if position, err := strconv.Atoi(firstField(fields, "Position", "Index")); err == nil && position >= 0 {
+		tx.Position = position
+	}
+	return tx
+}
+
+// CertificateData decodes the certificate data carried by the transaction,
+// as submitted with SubmitCertificate.
+func (t Transaction) CertificateData() (string, error) {
+	if t.Payload == "" {
+		return "", fmt.Errorf("transaction %s has no payload", t.ID)
+	}
+	return decodePayload(t.Payload)
+}
+
+// DecodeCertificatePayload reverses the encoding of a certificate submitted
+// with SubmitCertificate. The payload of the transaction is the hex encoding
+// of a JSON object whose data field holds the submitted string, which is
+// usually itself a Certificate serialized by GetJSONCertificate, with the
+// application data in hex. Such a certificate is returned as it was
+// submitted, metadata included; GetData returns its original data. Any other
+// submitted string is returned as the data of a Certificate without
+// metadata.
+func DecodeCertificatePayload(tx Transaction) (*Certificate, error) {
+	data, err := tx.CertificateData()
+	if err != nil {
+		return nil, err
+	}
+	if certificate, ok := parseCertificate(data); ok {
+		return certificate, nil
+	}
+	certificate := &Certificate{}
+	certificate.SetData(data)
+	return certificate, nil
+}
+
+// parseCertificate decodes data as a Certificate serialized by
+// GetJSONCertificate. It reports false for data that merely happens to be
+// JSON: unknown fields, a missing data field or data that does not decode in
+// the certificate's encoding.
+func parseCertificate(data string) (*Certificate, bool) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var fields struct {
+		Certificate
+		Data *string `json:"data"`
+	}
+	if err := decoder.Decode(&fields); err != nil || fields.Data == nil || decoder.More() {
+		return nil, false
+	}
+	certificate := fields.Certificate
+	certificate.Data = *fields.Data
+	if _, err := certificate.rawData(); err != nil {
+		return nil, false
+	}
+	return &certificate, true
+}
@@ -0,0 +1,111 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestNewTransaction(t *testing.T) {
+	payload, err := encodePayload(new(bytes.Buffer), "hello")
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		fields   map[string]interface{}
+		expected Transaction
+	}{
+		{
+			name: "Confirmed",
+			fields: map[string]interface{}{
+				"ID": "0xabc", "From": "0x1", "To": "0x2", "Blockchain": "0xchain", "Type": "C_TYPE_CERTIFICATE",
+				"Payload": payload, "Nonce": float64(7), "Signature": "sig", "Timestamp": "2024:01:01-00:00:00",
+				"Status": "Executed", "BlockID": float64(12), "Position": float64(3), "NodeID": "node",
+			},
+			expected: Transaction{
+				ID: "0xabc", From: "0x1", To: "0x2", Blockchain: "0xchain", Type: TxTypeCertificate,
+				Payload: payload, Nonce: "7", Signature: "sig", Timestamp: "2024:01:01-00:00:00",
+				Status: TxConfirmed, RawStatus: "Executed", BlockID: "12", Position: 3, NodeID: "node",
+			},
+		},
+		{
+			name:     "Alternative Names",
+			fields:   map[string]interface{}{"TxID": "0xdef", "Address": "0x1", "Status": "Pending"},
+			expected: Transaction{ID: "0xdef", From: "0x1", Status: TxPending, RawStatus: "Pending", Position: -1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := NewTransaction(tc.fields)
+			tx.Fields = nil
+			if !reflect.DeepEqual(tx, tc.expected) {
+				t.Errorf("Expected %+v, but got %+v", tc.expected, tx)
+			}
+		})
+	}
+
+	t.Run("Certificate Data", func(t *testing.T) {
+		if data, err := NewTransaction(map[string]interface{}{"Payload": payload}).CertificateData(); err != nil || data != "hello" {
+			t.Errorf("Expected the certificate data to round-trip, but got %q, %v", data, err)
+		}
+		if _, err := NewTransaction(map[string]interface{}{"ID": "0xabc"}).CertificateData(); err == nil {
+			t.Error("Expected an error for a transaction without a payload, but got nil")
+		}
+	})
+}
+
+func TestDecodeCertificatePayload(t *testing.T) {
+	certificate := NewCertificate(LibVersion)
+	certificate.SetData("hello")
+	certificate.PreviousTxID = "0xabc"
+	certificate.PreviousBlock = "12"
+	certificateJSON, _ := certificate.GetJSONCertificate()
+	base64Certificate := NewCertificate(LibVersion)
+	base64Certificate.SetEncoding(Base64Encoding)
+	base64Certificate.SetData("hello")
+	base64JSON, _ := base64Certificate.GetJSONCertificate()
+
+	testCases := []struct {
+		name     string
+		data     string
+		expected *Certificate
+	}{
+		{"Certificate", certificateJSON, certificate},
+		{"Base64", base64JSON, base64Certificate},
+		{"Plain", "hello", &Certificate{Data: "68656c6c6f"}},
+		{"Other JSON", `{"data":"hello","extra":1}`, &Certificate{Data: hex.EncodeToString([]byte(`{"data":"hello","extra":1}`))}},
+		{"Invalid Data", `{"data":"zz"}`, &Certificate{Data: hex.EncodeToString([]byte(`{"data":"zz"}`))}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := encodePayload(new(bytes.Buffer), tc.data)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			decoded, err := DecodeCertificatePayload(NewTransaction(map[string]interface{}{"ID": "0x1", "Payload": payload}))
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tc.expected) {
+				t.Errorf("Expected %+v, but got %+v", tc.expected, decoded)
+			}
+		})
+	}
+
+	payload, _ := encodePayload(new(bytes.Buffer), certificateJSON)
+	decoded, err := DecodeCertificatePayload(Transaction{Payload: payload})
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if data, err := decoded.GetData(); err != nil || data != "hello" {
+		t.Errorf("Expected the original data, but got %q, %v", data, err)
+	}
+	if _, err := DecodeCertificatePayload(Transaction{ID: "0x1", Payload: "zz"}); err == nil {
+		t.Error("Expected an error for an invalid payload, but got nil")
+	}
+}
@@ -0,0 +1,183 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Transport defaults used by the shared HTTP client. They bound every phase of
+// a connection that can otherwise hang indefinitely when a NAG stops
+// responding: dialing, the TLS handshake and waiting for response headers.
+const (
+	DefaultDialTimeout           = 10 * time.Second
+	DefaultKeepAlive             = 30 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 30 * time.Second
+	DefaultIdleConnTimeout       = 90 * time.Second
+	DefaultMaxIdleConnsPerHost   = 16
+)
+
+// sharedHTTPClient is used by every client that has not been given its own
+// HTTPClient, so connections to the same NAG are pooled across clients.
+var sharedHTTPClient = &http.Client{Transport: newTransport()}
+
+// newCustomClient builds a client with its own transport, starting from the
+// library defaults and applying each customisation in order.
+func newCustomClient(options []func(*http.Transport)) *http.Client {
+	transport := newTransport()
+	for _, configure := range options {
+		configure(transport)
+	}
+	return &http.Client{Transport: transport}
+}
+
+// httpClient returns the client used for the client's network requests.
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return sharedHTTPClient
+}
+
+// postJSON sends a JSON body to requestURL using the client's HTTP client.
+// The request is bounded by the client's request timeout; the timeout stays
+// in force until the caller closes the response body.
+func (c *Client) postJSON(ctx context.Context, requestURL string, body io.Reader) (*http.Response, error) {
+	if c.shouldCompress(body) {
+		return c.postCompressed(ctx, requestURL, body)
+	}
+	return c.sendJSON(ctx, requestURL, body, "")
+}
+
+// sendJSON posts body with the given Content-Encoding, if any.
+func (c *Client) sendJSON(ctx context.Context, requestURL string, body io.Reader, encoding string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, requestURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if sized, ok := body.(interface{ Len() int }); ok && req.ContentLength == 0 {
+		req.ContentLength = int64(sized.Len())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	return c.do(ctx, req)
+}
+
+// get performs a GET request using the client's HTTP client.
+func (c *Client) get(ctx context.Context, requestURL string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req)
+}
+
+// do sends req with the client's headers under a context derived from ctx
+// and the request timeout. Throttling responses are returned as a
+// *ThrottledError. Failed attempts are retried according to the client's
+// retry policy when the request body can be replayed.
+func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req)
+		if err == nil || attempt >= c.Retry.attempts() || !retryable(ctx, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		wait := c.Retry.delay(attempt, err)
+		c.logf(ctx, "request to %s failed: %v; retrying in %v", req.URL.Path, err, wait)
+		if c.events != nil {
+			c.events.Publish(RetryingEvent{EventInfo: c.eventInfo(ctx), Path: req.URL.Path, Attempt: attempt, Err: err, Wait: wait})
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// send performs a single attempt of req.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	// Routing comes first so the headers, authorization and signature are
+	// those of the node the request is actually sent to.
+	req, node := c.routeRequest(ctx, req)
+	c.applyHeaders(ctx, req)
+	// Asking for gzip explicitly disables the transport's own transparent
+	// decompression, so it is handled in decompressResponse regardless of the
+	// client in use. Browsers negotiate and decode compression themselves.
+	if !platformDecompresses {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	if err := c.authorize(ctx, req); err != nil {
+		return nil, err
+	}
+	// Signing comes last so signers that cover headers see all of them.
+	if err := c.sign(req); err != nil {
+		return nil, err
+	}
+	if err := c.throttleRequest(ctx, req.ContentLength); err != nil {
+		return nil, err
+	}
+	reqCtx, cancel := c.requestContext(ctx)
+	c.countCall(ctx)
+	var tracer *connTracer
+	if c.connStats != nil {
+		tracer = &connTracer{}
+		reqCtx = tracer.trace(reqCtx)
+	}
+	start := time.Now()
+	resp, err := c.httpClient().Do(req.WithContext(reqCtx))
+	if tracer != nil {
+		c.reportConnStats(ctx, tracer, req, resp, err)
+	}
+	if err != nil {
+		cancel()
+		c.observeNode(ctx, node, time.Since(start), nil, err)
+		return nil, err
+	}
+	c.observeClock(ctx, resp, start, time.Now())
+	if throttled := throttledError(resp, time.Now()); throttled != nil {
+		resp.Body.Close()
+		cancel()
+		c.observeNode(ctx, node, time.Since(start), nil, throttled)
+		return nil, throttled
+	}
+	c.observeNode(ctx, node, time.Since(start), resp, nil)
+	c.observeAuth(resp)
+	resp.Body = c.throttleResponse(reqCtx, resp.Body)
+	if !platformDecompresses {
+		if err := decompressResponse(resp); err != nil {
+			resp.Body.Close()
+			cancel()
+			return nil, err
+		}
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request context once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context.
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,19 @@
+//go:build js && wasm
+
+package circular_enterprise_apis
+
+import "net/http"
+
+// platformDecompresses reports whether responses arrive already decoded. In
+// the browser, requests go through the Fetch API, which negotiates and
+// decodes compression itself and does not let callers set Accept-Encoding.
+const platformDecompresses = true
+
+// newTransport returns an http.Transport that sends requests with the Fetch
+// API. net/http only uses fetch when no dial function is set, so the dialer
+// and connection settings of native builds are deliberately left out; the
+// browser manages connections, TLS and proxies, and transport options such
+// as WithTLSConfig and WithPinnedPublicKeys have no effect.
+func newTransport() *http.Transport {
+	return &http.Transport{}
+}
@@ -0,0 +1,15 @@
+//go:build js && wasm
+
+package circular_enterprise_apis
+
+import "testing"
+
+func TestFetchTransport(t *testing.T) {
+	transport := newTransport()
+	if transport.DialContext != nil || transport.Dial != nil {
+		t.Error("Expected no dial function, so that requests use the Fetch API")
+	}
+	if !platformDecompresses {
+		t.Error("Expected responses to be decoded by the browser")
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !(js && wasm)
+
+package circular_enterprise_apis
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// platformDecompresses reports whether responses arrive already decoded.
+// Native builds request and decode gzip themselves.
+const platformDecompresses = false
+
+// newTransport returns an http.Transport configured with the library defaults.
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   DefaultDialTimeout,
+		KeepAlive: DefaultKeepAlive,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:       DefaultIdleConnTimeout,
+		TLSHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		ResponseHeaderTimeout: DefaultResponseHeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,69 @@
+package circular_enterprise_apis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// countingRoundTripper records how many requests pass through it.
+type countingRoundTripper struct {
+	next  http.RoundTripper
+	count int
+}
+
+func (c *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.count++
+	return c.next.RoundTrip(req)
+}
+
+func TestSharedTransportDefaults(t *testing.T) {
+	acc := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
+	if acc.httpClient() != sharedHTTPClient {
+		t.Fatal("Expected accounts without an HTTPClient to use the shared client")
+	}
+
+	other := NewCEPAccount(DefaultNAG, DefaultChain, LibVersion)
+	if acc.httpClient() != other.httpClient() {
+		t.Error("Expected accounts to share one HTTP client")
+	}
+
+	transport, ok := sharedHTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected shared transport to be *http.Transport, got %T", sharedHTTPClient.Transport)
+	}
+	if transport.ResponseHeaderTimeout != DefaultResponseHeaderTimeout {
+		t.Errorf("Expected ResponseHeaderTimeout %v, but got %v", DefaultResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+	}
+	if transport.TLSHandshakeTimeout != DefaultTLSHandshakeTimeout {
+		t.Errorf("Expected TLSHandshakeTimeout %v, but got %v", DefaultTLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+	}
+	if transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Errorf("Expected MaxIdleConnsPerHost %d, but got %d", DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+}
+
+func TestCustomHTTPClient(t *testing.T) {
+	var contentLength int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Result":200,"Response":{"Nonce":1}}`))
+	}))
+	defer server.Close()
+
+	counter := &countingRoundTripper{next: http.DefaultTransport}
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion)
+	acc.HTTPClient = &http.Client{Transport: counter}
+	acc.Open("0x123")
+
+	if _, err := acc.UpdateAccount(); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if counter.count != 1 {
+		t.Errorf("Expected 1 request through the custom client, but got %d", counter.count)
+	}
+	if contentLength <= 0 {
+		t.Errorf("Expected a known Content-Length, but got %d", contentLength)
+	}
+}
@@ -0,0 +1,219 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// TxType is the Type field of a transaction, telling the network how to
+// process it.
+type TxType string
+
+// The transaction types known to the network.
+const (
+	// TxTypeCertificate certifies arbitrary data. SubmitCertificate sends
+	// certificates without a Type, which the NAG treats as this type.
+	TxTypeCertificate TxType = "C_TYPE_CERTIFICATE"
+	// TxTypeRegisterWallet registers a wallet and its public key.
+	TxTypeRegisterWallet TxType = "C_TYPE_REGISTERWALLET"
+	// TxTypeCoin transfers the blockchain's native coin to To.
+	TxTypeCoin TxType = "C_TYPE_COIN"
+	// TxTypeToken transfers a token to To.
+	TxTypeToken TxType = "C_TYPE_TOKEN"
+	// TxTypeAsset transfers an asset to To.
+	TxTypeAsset TxType = "C_TYPE_ASSET"
+)
+
+// ErrInvalidTransaction is matched by errors.Is when a TransactionBuilder
+// describes a transaction that cannot be signed.
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// txTypePattern is the form of transaction types: C_TYPE_ followed by
+// uppercase words.
+var txTypePattern = regexp.MustCompile(`^C_TYPE_[A-Z0-9]+(_[A-Z0-9]+)*$`)
+
+// Valid reports whether t is of the C_TYPE_NAME form used by the network.
+// Types other than the constants above are valid as long as they have that
+// form, so new types can be sent before the library knows them.
+func (t TxType) Valid() bool {
+	return txTypePattern.MatchString(string(t))
+}
+
+// Transfer reports whether t moves value to a recipient, and so requires To.
+func (t TxType) Transfer() bool {
+	return t == TxTypeCoin || t == TxTypeToken || t == TxTypeAsset
+}
+
+// TransactionBuilder assembles a typed transaction. Setters return the
+// builder so calls can be chained, and the first invalid value is reported
+// by Validate and Sign:
+//
+//	tx, err := cep.NewTransactionBuilder(cep.TxTypeCoin).
+//		To(recipient).
+//		Nonce(account.Nonce).
+//		PayloadJSON(map[string]interface{}{"Amount": 10}).
+//		Sign(ctx, account, privateKey)
+//	...
+//	response, err := account.Broadcast(ctx, tx)
+type TransactionBuilder struct {
+	tx        SignedTransaction
+	timestamp time.Time
+	err       error
+}
+
+// NewTransactionBuilder returns a builder for a transaction of type t.
+func NewTransactionBuilder(t TxType) *TransactionBuilder {
+	return &TransactionBuilder{tx: SignedTransaction{Type: t}}
+}
+
+// fail records the first error of the builder.
+func (b *TransactionBuilder) fail(format string, args ...interface{}) {
+	if b.err == nil {
+		b.err = fmt.Errorf("%w: %s", ErrInvalidTransaction, fmt.Sprintf(format, args...))
+	}
+}
+
+// Blockchain sets the blockchain of the transaction. The signing account's
+// blockchain is used when it is not set.
+func (b *TransactionBuilder) Blockchain(blockchain string) *TransactionBuilder {
+	if !isHex(blockchain) {
+		b.fail("blockchain %q is not hex", blockchain)
+	}
+	b.tx.Blockchain = blockchain
+	return b
+}
+
+// To sets the recipient of the transaction, which transfers require.
+func (b *TransactionBuilder) To(address string) *TransactionBuilder {
+	if !isHex(address) {
+		b.fail("recipient %q is not hex", address)
+	}
+	b.tx.To = address
+	return b
+}
+
+// Nonce sets the nonce of the transaction, such as the account's Nonce after
+// UpdateAccount.
+func (b *TransactionBuilder) Nonce(nonce int) *TransactionBuilder {
+	if nonce < 0 {
+		b.fail("nonce %d is negative", nonce)
+	}
+	b.tx.Nonce = strconv.Itoa(nonce)
+	return b
+}
+
+// Payload sets the payload of the transaction to data, hex-encoded.
+func (b *TransactionBuilder) Payload(data []byte) *TransactionBuilder {
+	b.tx.Payload = hex.EncodeToString(data)
+	return b
+}
+
+// PayloadHex sets the payload of the transaction to an already hex-encoded
+// value.
+func (b *TransactionBuilder) PayloadHex(payload string) *TransactionBuilder {
+	if !isHex(payload) {
+		b.fail("payload is not hex")
+	}
+	b.tx.Payload = payload
+	return b
+}
+
+// PayloadJSON sets the payload of the transaction to the JSON encoding of v,
+// hex-encoded.
+func (b *TransactionBuilder) PayloadJSON(v interface{}) *TransactionBuilder {
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+	if err := encodeJSON(scratch, v); err != nil {
+		b.fail("failed to marshal payload: %v", err)
+		return b
+	}
+	return b.Payload(scratch.Bytes())
+}
+
+// Certificate sets the payload to pdata encoded as SubmitCertificate encodes
+// certificates.
+func (b *TransactionBuilder) Certificate(pdata string) *TransactionBuilder {
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+	payload, err := encodePayload(scratch, pdata)
+	if err != nil {
+		b.fail("%v", err)
+		return b
+	}
+	b.tx.Payload = payload
+	return b
+}
+
+// Timestamp sets the time of the transaction. The signing account's clock,
+// corrected for skew when enabled, is used when it is not set.
+func (b *TransactionBuilder) Timestamp(t time.Time) *TransactionBuilder {
+	b.timestamp = t
+	return b
+}
+
+// Validate reports the first invalid value given to the builder, or what the
+// transaction still lacks.
+func (b *TransactionBuilder) Validate() error {
+	if b.err != nil {
+		return b.err
+	}
+	switch {
+	case !b.tx.Type.Valid():
+		return fmt.Errorf("%w: type %q is not of the form C_TYPE_NAME", ErrInvalidTransaction, b.tx.Type)
+	case b.tx.Payload == "":
+		return fmt.Errorf("%w: payload is empty", ErrInvalidTransaction)
+	case b.tx.Type.Transfer() && b.tx.To == "":
+		return fmt.Errorf("%w: %s requires a recipient", ErrInvalidTransaction, b.tx.Type)
+	}
+	return nil
+}
+
+// Sign validates the transaction and signs it as account, with privateKey or
+// the account's Signer. The result is submitted with Broadcast.
+func (b *TransactionBuilder) Sign(ctx context.Context, account *CEPAccount, privateKey string) (tx *SignedTransaction, err error) {
+	ctx, correlationID := withCorrelationID(ctx)
+	defer annotateError(&err, correlationID)
+
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	if account.Address == "" {
+		return nil, errors.New("Account is not open")
+	}
+	request := certificateRequest(b.tx)
+	if request.Blockchain == "" {
+		request.Blockchain = account.Blockchain
+	}
+	timestamp := b.timestamp
+	if timestamp.IsZero() {
+		timestamp = account.now()
+	}
+	request.Timestamp = utils.FormatTimestamp(timestamp)
+
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+	request, err = account.signTransaction(ctx, scratch, request, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signed := SignedTransaction(request)
+	return &signed, nil
+}
+
+// isHex reports whether value, without a 0x prefix, is a non-empty string of
+// hex digits.
+func isHex(value string) bool {
+	value = utils.HexFix(value)
+	if value == "" || len(value)%2 != 0 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
@@ -0,0 +1,106 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransactionBuilder(t *testing.T) {
+	ctx := context.Background()
+	privateKey := strings.Repeat("1", 64)
+	recipient := "0x" + strings.Repeat("b", 64)
+	acc := NewCEPAccount("", DefaultChain, LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+
+	t.Run("Transfer", func(t *testing.T) {
+		at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		tx, err := NewTransactionBuilder(TxTypeCoin).
+			To(recipient).
+			Nonce(7).
+			PayloadJSON(map[string]interface{}{"Amount": 10}).
+			Timestamp(at).
+			Sign(ctx, acc, privateKey)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if tx.Type != TxTypeCoin || tx.To != recipient || tx.Nonce != "7" || tx.Timestamp != "2025:01:02-03:04:05" || tx.Blockchain != DefaultChain {
+			t.Errorf("Expected the builder's fields, but got %+v", tx)
+		}
+		if expected := ComputeTransactionID(DefaultChain, acc.Address, recipient, tx.Payload, "7", tx.Timestamp); tx.ID != expected {
+			t.Errorf("Expected ID %s, but got %s", expected, tx.ID)
+		}
+		if err := tx.Validate(); err != nil {
+			t.Errorf("Expected the transaction to validate, but got: %v", err)
+		}
+		item, _ := SignedItemFromTransaction(tx, testPublicKey)
+		if err := VerifySignature(item); err != nil {
+			t.Errorf("Expected a valid signature, but got: %v", err)
+		}
+	})
+
+	t.Run("Certificate", func(t *testing.T) {
+		tx, err := NewTransactionBuilder(TxTypeCertificate).Certificate("hello").Sign(ctx, acc, privateKey)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if data, err := decodePayload(tx.Payload); err != nil || data != "hello" {
+			t.Errorf("Expected the certificate payload, but got %q, %v", data, err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			builder *TransactionBuilder
+		}{
+			{"Unknown Type Form", NewTransactionBuilder("coin").Payload([]byte("x"))},
+			{"Missing Payload", NewTransactionBuilder(TxTypeCertificate)},
+			{"Transfer Without Recipient", NewTransactionBuilder(TxTypeToken).Payload([]byte("x"))},
+			{"Recipient Not Hex", NewTransactionBuilder(TxTypeCoin).To("bob").Payload([]byte("x"))},
+			{"Payload Not Hex", NewTransactionBuilder(TxTypeCertificate).PayloadHex("zz")},
+			{"Negative Nonce", NewTransactionBuilder(TxTypeCertificate).Nonce(-1).Payload([]byte("x"))},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := tc.builder.Sign(ctx, acc, privateKey); !errors.Is(err, ErrInvalidTransaction) {
+					t.Errorf("Expected ErrInvalidTransaction, but got %v", err)
+				}
+			})
+		}
+		if err := NewTransactionBuilder("C_TYPE_HC_REQUEST").Payload([]byte("x")).Validate(); err != nil {
+			t.Errorf("Expected types of the C_TYPE_ form to be accepted, but got: %v", err)
+		}
+	})
+
+	t.Run("Broadcast", func(t *testing.T) {
+		var body map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+		}))
+		defer server.Close()
+
+		tx, _ := NewTransactionBuilder(TxTypeAsset).To(recipient).Payload([]byte("x")).Sign(ctx, acc, privateKey)
+		c := NewClient(server.URL, DefaultChain, LibVersion)
+		if _, err := c.Broadcast(ctx, tx); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if body["Type"] != string(TxTypeAsset) || body["To"] != recipient || body["ID"] != tx.ID {
+			t.Errorf("Expected the typed transaction to be posted, but got %v", body)
+		}
+
+		signed, _ := acc.SignCertificate(ctx, "data", privateKey)
+		data, _ := json.Marshal(signed)
+		for _, field := range []string{`"Type"`, `"To"`, `"Nonce"`} {
+			if strings.Contains(string(data), field) {
+				t.Errorf("Expected certificates to be sent without %s, but got %s", field, data)
+			}
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package circular_enterprise_apis
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ComputeTransactionID returns the ID of a transaction: the hex SHA-256 of
+// from, blockchain, to, payload, nonce and timestamp concatenated in that
+// order. Values are used verbatim, so addresses keep or omit their "0x"
+// prefix exactly as they were submitted.
+//
+// Certificates submitted by SubmitCertificate carry no nonce, and no
+// recipient unless WithRecipient is used; pass an empty nonce, and the
+// recipient address or an empty string for to, to compute their ID ahead of
+// time from the account address, the blockchain, the hex-encoded payload and
+// the timestamp.
+func ComputeTransactionID(blockchain, from, to, payload, nonce, timestamp string) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeTransactionIDInput(buf, blockchain, from, to, payload, nonce, timestamp)
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// writeTransactionIDInput writes the string that is hashed into a transaction
+// ID, and signed, to buf.
+func writeTransactionIDInput(buf *bytes.Buffer, blockchain, from, to, payload, nonce, timestamp string) {
+	buf.WriteString(from)
+	buf.WriteString(blockchain)
+	buf.WriteString(to)
+	buf.WriteString(payload)
+	buf.WriteString(nonce)
+	buf.WriteString(timestamp)
+}
@@ -0,0 +1,32 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/compat"
+)
+
+func TestComputeTransactionID(t *testing.T) {
+	t.Run("Concatenation Order", func(t *testing.T) {
+		sum := sha256.Sum256([]byte("0xfrom" + "0xchain" + "0xto" + "7061796c6f6164" + "3" + "2024:01:02-03:04:05"))
+		expected := hex.EncodeToString(sum[:])
+		got := ComputeTransactionID("0xchain", "0xfrom", "0xto", "7061796c6f6164", "3", "2024:01:02-03:04:05")
+		if got != expected {
+			t.Errorf("Expected %s, but got %s", expected, got)
+		}
+	})
+
+	t.Run("Matches Submitted Certificates", func(t *testing.T) {
+		vectors, err := compat.Load()
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		for _, v := range vectors.Certificates {
+			if got := ComputeTransactionID(v.Blockchain, v.Address, "", v.Payload, "", v.Timestamp); got != v.ID {
+				t.Errorf("%s: expected %s, but got %s", v.Name, v.ID, got)
+			}
+		}
+	})
+}
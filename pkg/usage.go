@@ -0,0 +1,140 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Usage is a snapshot of the activity of a client since Since.
+type Usage struct {
+	Since time.Time
+	// Submissions counts certificates sent to the NAG, whatever the answer.
+	Submissions int64
+	// Accepted counts certificates the NAG accepted with Result 200.
+	Accepted int64
+	// BytesCertified is the total size of the data of accepted certificates,
+	// before encoding.
+	BytesCertified int64
+	// Calls counts HTTP requests to the NAG, including retries, probes and
+	// polling.
+	Calls int64
+	// Labels are the labels of the client; see WithLabels.
+	Labels map[string]string
+}
+
+// UsageMetric names a counter of Usage.
+type UsageMetric string
+
+const (
+	MetricSubmissions    UsageMetric = "submissions"
+	MetricAccepted       UsageMetric = "accepted"
+	MetricBytesCertified UsageMetric = "bytes_certified"
+	MetricCalls          UsageMetric = "calls"
+)
+
+// UsageLimits are soft limits on a client's usage. Zero leaves a metric
+// unlimited. Crossing a limit does not block anything; it only notifies the
+// callback given to WithUsageLimits.
+type UsageLimits struct {
+	Submissions    int64
+	Accepted       int64
+	BytesCertified int64
+	Calls          int64
+}
+
+// UsageAlert reports that a metric reached its limit.
+type UsageAlert struct {
+	Metric UsageMetric
+	Limit  int64
+	Value  int64
+	Usage  Usage
+}
+
+// WithUsageLimits calls fn once for each metric of limits when the client's
+// usage reaches it, until ResetUsage starts a new period. fn runs
+// synchronously on the goroutine of the call that crossed the limit, so it
+// should return quickly; it may cancel work through its own means, for
+// example by shutting the client down.
+func WithUsageLimits(limits UsageLimits, fn func(ctx context.Context, alert UsageAlert)) Option {
+	return func(c *Client) {
+		c.usage.limits = limits
+		c.usage.onLimit = fn
+	}
+}
+
+// usageCounters tracks the usage of a client.
+type usageCounters struct {
+	mu             sync.Mutex
+	since          time.Time
+	submissions    atomic.Int64
+	accepted       atomic.Int64
+	bytesCertified atomic.Int64
+	calls          atomic.Int64
+
+	limits  UsageLimits
+	onLimit func(ctx context.Context, alert UsageAlert)
+}
+
+// Usage returns the client's usage since it was created or last reset.
+func (c *Client) Usage() Usage {
+	u := &c.usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	usage := u.snapshot()
+	usage.Labels = c.Labels()
+	return usage
+}
+
+// ResetUsage starts a new usage period, for example at the start of each
+// billing month, and returns the usage of the period that ended. Limits that
+// were reached can fire again in the new period.
+func (c *Client) ResetUsage() Usage {
+	u := &c.usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	previous := u.snapshot()
+	previous.Labels = c.Labels()
+	u.since = time.Now()
+	u.submissions.Store(0)
+	u.accepted.Store(0)
+	u.bytesCertified.Store(0)
+	u.calls.Store(0)
+	return previous
+}
+
+// snapshot returns the current counters; u.mu must be held.
+func (u *usageCounters) snapshot() Usage {
+	return Usage{
+		Since:          u.since,
+		Submissions:    u.submissions.Load(),
+		Accepted:       u.accepted.Load(),
+		BytesCertified: u.bytesCertified.Load(),
+		Calls:          u.calls.Load(),
+	}
+}
+
+// countUsage adds delta to counter and alerts if that crossed limit.
+func (c *Client) countUsage(ctx context.Context, metric UsageMetric, counter *atomic.Int64, limit, delta int64) {
+	value := counter.Add(delta)
+	if limit > 0 && c.usage.onLimit != nil && value >= limit && value-delta < limit {
+		c.usage.onLimit(ctx, UsageAlert{Metric: metric, Limit: limit, Value: value, Usage: c.Usage()})
+	}
+}
+
+// countCall records an HTTP request to the NAG.
+func (c *Client) countCall(ctx context.Context) {
+	c.countUsage(ctx, MetricCalls, &c.usage.calls, c.usage.limits.Calls, 1)
+}
+
+// countSubmission records a certificate sent to the NAG.
+func (c *Client) countSubmission(ctx context.Context) {
+	c.countUsage(ctx, MetricSubmissions, &c.usage.submissions, c.usage.limits.Submissions, 1)
+}
+
+// countAccepted records a certificate of size bytes accepted by the NAG.
+func (c *Client) countAccepted(ctx context.Context, size int) {
+	c.countUsage(ctx, MetricAccepted, &c.usage.accepted, c.usage.limits.Accepted, 1)
+	c.countUsage(ctx, MetricBytesCertified, &c.usage.bytesCertified, c.usage.limits.BytesCertified, int64(size))
+}
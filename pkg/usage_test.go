@@ -0,0 +1,67 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.Contains(r.URL.Path, "Circular_") {
+			w.Write([]byte(`{"Result":200,"Response":1}`))
+			return
+		}
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+	}))
+	defer server.Close()
+
+	var alerts []UsageAlert
+	acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithUsageLimits(
+		UsageLimits{Submissions: 2, BytesCertified: 8},
+		func(ctx context.Context, alert UsageAlert) { alerts = append(alerts, alert) },
+	))
+	acc.Open("0x" + strings.Repeat("a", 64))
+	privateKey := strings.Repeat("1", 64)
+
+	for _, data := range []string{"abcd", "efgh", "ijkl"} {
+		if _, err := acc.SubmitCertificateContext(context.Background(), data, privateKey); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	}
+	if _, err := acc.GetBlockCount(context.Background()); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	usage := acc.Usage()
+	if usage.Submissions != 3 || usage.Accepted != 3 || usage.BytesCertified != 12 || usage.Calls != 4 {
+		t.Errorf("Expected 3 submissions, 3 accepted, 12 bytes and 4 calls, but got %+v", usage)
+	}
+	if usage.Since.IsZero() {
+		t.Error("Expected the usage period to have a start time")
+	}
+
+	if len(alerts) != 2 {
+		t.Fatalf("Expected one alert per limit, but got %+v", alerts)
+	}
+	if alerts[0].Metric != MetricSubmissions || alerts[0].Value != 2 || alerts[0].Limit != 2 {
+		t.Errorf("Expected the submissions alert at 2, but got %+v", alerts[0])
+	}
+	if alerts[1].Metric != MetricBytesCertified || alerts[1].Value != 8 {
+		t.Errorf("Expected the bytes alert at 8, but got %+v", alerts[1])
+	}
+
+	previous := acc.ResetUsage()
+	if previous.Submissions != 3 || acc.Usage().Submissions != 0 || !acc.Usage().Since.After(previous.Since) {
+		t.Errorf("Expected the reset to start a new period, but got %+v then %+v", previous, acc.Usage())
+	}
+	for _, data := range []string{"abcd", "efgh"} {
+		acc.SubmitCertificateContext(context.Background(), data, privateKey)
+	}
+	if len(alerts) != 4 {
+		t.Errorf("Expected the limits to fire again after a reset, but got %d alerts", len(alerts))
+	}
+}
@@ -0,0 +1,92 @@
+package circular_enterprise_apis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lessuselesss/CEP-Go-APIs/internal/utils"
+)
+
+// ErrInvalidSignature is matched by errors.Is when VerifySignature or
+// VerifyBatch rejects a signature.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// SignedItem is a signature to verify with VerifySignature or VerifyBatch.
+type SignedItem struct {
+	// Curve names the curve of the signature; empty for secp256k1.
+	Curve string
+	// PublicKey and Signature are hex-encoded, with or without 0x.
+	PublicKey string
+	Signature string
+	// Digest is the SHA-256 digest that was signed. When nil, it is computed
+	// from Data.
+	Digest []byte
+	Data   []byte
+}
+
+// SignedItemFromTransaction returns the SignedItem of a signed transaction,
+// to be verified against the public key of its sender.
+func SignedItemFromTransaction(tx *SignedTransaction, publicKey string) (SignedItem, error) {
+	digest, err := hex.DecodeString(utils.HexFix(tx.ID))
+	if err != nil {
+		return SignedItem{}, fmt.Errorf("%w: ID is not hex: %v", ErrInvalidSignedTransaction, err)
+	}
+	return SignedItem{Curve: tx.Curve, PublicKey: publicKey, Signature: tx.Signature, Digest: digest}, nil
+}
+
+// VerifySignature returns nil if item holds a valid signature, and an error
+// matching ErrInvalidSignature otherwise.
+func VerifySignature(item SignedItem) error {
+	curve, err := LookupCurve(item.Curve)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	publicKey, err := hex.DecodeString(utils.HexFix(item.PublicKey))
+	if err != nil {
+		return fmt.Errorf("%w: public key is not hex: %v", ErrInvalidSignature, err)
+	}
+	signature, err := hex.DecodeString(utils.HexFix(item.Signature))
+	if err != nil {
+		return fmt.Errorf("%w: signature is not hex: %v", ErrInvalidSignature, err)
+	}
+	digest := item.Digest
+	if digest == nil {
+		sum := sha256.Sum256(item.Data)
+		digest = sum[:]
+	}
+	if err := curve.Verify(publicKey, digest, signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// VerifyBatch verifies every item and returns their errors in the same
+// order, nil for the valid ones. Verification is CPU-bound, so the items are
+// shared among one worker per available CPU rather than started one
+// goroutine each.
+func VerifyBatch(items []SignedItem) []error {
+	errs := make([]error, len(items))
+	workers := min(runtime.GOMAXPROCS(0), len(items))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(items) {
+					return
+				}
+				errs[i] = VerifySignature(items[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
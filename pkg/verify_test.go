@@ -0,0 +1,75 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	key, _ := hex.DecodeString(strings.Repeat("1", 64))
+	items := make([]SignedItem, 0, 200)
+	for i := 0; i < 100; i++ {
+		for _, curve := range []Curve{Secp256k1Curve, Ed25519Curve} {
+			data := []byte(fmt.Sprintf("certificate %d", i))
+			digest := sha256.Sum256(data)
+			publicKey, _ := curve.PublicKey(key)
+			signature, _ := curve.Sign(key, digest[:])
+			item := SignedItem{Curve: curve.Name(), PublicKey: hex.EncodeToString(publicKey), Signature: hex.EncodeToString(signature), Data: data}
+			if curve == Secp256k1Curve {
+				item.Curve = ""
+			}
+			items = append(items, item)
+		}
+	}
+	invalid := map[int]func(*SignedItem){
+		3:  func(item *SignedItem) { item.Data = []byte("tampered") },
+		10: func(item *SignedItem) { item.Signature = "zz" },
+		57: func(item *SignedItem) { item.Curve = "p256" },
+		98: func(item *SignedItem) { item.PublicKey = items[1].PublicKey },
+	}
+	for i, modify := range invalid {
+		modify(&items[i])
+	}
+
+	errs := VerifyBatch(items)
+	if len(errs) != len(items) {
+		t.Fatalf("Expected %d results, but got %d", len(items), len(errs))
+	}
+	for i, err := range errs {
+		if _, bad := invalid[i]; bad {
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected item %d to be rejected, but got %v", i, err)
+			}
+		} else if err != nil {
+			t.Errorf("Expected item %d to verify, but got %v", i, err)
+		}
+	}
+	if errs := VerifyBatch(nil); len(errs) != 0 {
+		t.Errorf("Expected no results for no items, but got %v", errs)
+	}
+}
+
+func TestSignedItemFromTransaction(t *testing.T) {
+	acc := NewCEPAccount("", DefaultChain, LibVersion, WithCurve(Ed25519Curve))
+	acc.Open("0xabc")
+	privateKey := strings.Repeat("1", 64)
+	tx, err := acc.SignCertificate(context.Background(), "hello", privateKey)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	key, _ := hex.DecodeString(privateKey)
+	publicKey, _ := Ed25519Curve.PublicKey(key)
+
+	item, err := SignedItemFromTransaction(tx, hex.EncodeToString(publicKey))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if err := VerifySignature(item); err != nil {
+		t.Errorf("Expected the transaction signature to verify, but got: %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultStuckAfter is how long a transaction may stay pending before a
+	// Watchdog flags it.
+	DefaultStuckAfter = 10 * time.Minute
+	// DefaultWatchdogInterval is how often a Watchdog checks the pending
+	// transactions.
+	DefaultWatchdogInterval = time.Minute
+)
+
+// StuckEvent is published by a Watchdog for a transaction still pending, or
+// still unknown to the network, long after it was submitted.
+type StuckEvent struct {
+	EventInfo
+	TxID      string
+	Submitted time.Time
+	// Pending is how long the transaction has been pending.
+	Pending time.Duration
+	// Status is the status last reported by the network.
+	Status TxStatus
+	// Resubmitted is set when the transaction was broadcast again, with the
+	// same ID, after being flagged. Err holds the reason a resubmission
+	// failed.
+	Resubmitted bool
+	Err         error
+}
+
+// WatchdogOption configures a Watchdog.
+type WatchdogOption func(*Watchdog)
+
+// WithStuckAfter sets how long a transaction may stay pending before it is
+// flagged. See DefaultStuckAfter.
+func WithStuckAfter(d time.Duration) WatchdogOption {
+	return func(w *Watchdog) {
+		w.stuckAfter = d
+	}
+}
+
+// WithWatchdogInterval sets how often the pending transactions are checked.
+// Zero or negative disables the background checks, leaving them to Check.
+// See DefaultWatchdogInterval.
+func WithWatchdogInterval(d time.Duration) WatchdogOption {
+	return func(w *Watchdog) {
+		w.interval = d
+	}
+}
+
+// WithResubmit broadcasts a flagged transaction again, unchanged and so with
+// the same ID, up to maxAttempts times. Only transactions whose signed form
+// was seen on the client's event bus can be resubmitted.
+func WithResubmit(maxAttempts int) WatchdogOption {
+	return func(w *Watchdog) {
+		w.maxResubmits = maxAttempts
+	}
+}
+
+// Watchdog flags transactions that stay pending beyond a configurable
+// duration. It tracks the transactions submitted through a client with an
+// event bus, those added with Track, and, when the client has a Ledger, the
+// entries still accepted there, which covers submissions made before a
+// restart. Each stuck transaction is looked up with GetOutcome, so the
+// transactions that turn out to be final are recorded as such, and those
+// still pending are reported as a StuckEvent on the client's event bus and
+// returned by Check. A transaction is flagged again every StuckAfter for as
+// long as it stays pending.
+type Watchdog struct {
+	client       *Client
+	stuckAfter   time.Duration
+	interval     time.Duration
+	maxResubmits int
+	unsubscribe  func()
+
+	mu      sync.Mutex
+	sent    map[string]SignedTransaction
+	pending map[string]*watchedTransaction
+
+	stopped chan struct{}
+	cancel  context.CancelFunc
+}
+
+// watchedTransaction is a transaction tracked by a Watchdog.
+type watchedTransaction struct {
+	txID        string
+	submitted   time.Time
+	flagged     time.Time
+	transaction *SignedTransaction
+	resubmits   int
+}
+
+// NewWatchdog starts a Watchdog for the client's transactions. It runs until
+// Close is called, ctx is done or the client is shut down.
+func (c *Client) NewWatchdog(ctx context.Context, opts ...WatchdogOption) (*Watchdog, error) {
+	w := &Watchdog{
+		client:     c,
+		stuckAfter: DefaultStuckAfter,
+		interval:   DefaultWatchdogInterval,
+		sent:       make(map[string]SignedTransaction),
+		pending:    make(map[string]*watchedTransaction),
+		stopped:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	workerCtx, done, err := c.startWorker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workerCtx, w.cancel = context.WithCancel(workerCtx)
+	if c.events != nil {
+		w.unsubscribe = c.events.Subscribe(w.observe)
+	}
+	go func() {
+		defer close(w.stopped)
+		defer done()
+		w.run(workerCtx)
+	}()
+	return w, nil
+}
+
+// Track watches a transaction submitted at submitted. When tx is the signed
+// transaction rather than nil, it can be resubmitted.
+func (w *Watchdog) Track(txID string, submitted time.Time, tx *SignedTransaction) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.track(txID, submitted, tx)
+}
+
+// track adds a transaction unless it is already watched. w.mu must be held.
+func (w *Watchdog) track(txID string, submitted time.Time, tx *SignedTransaction) {
+	key := normalizeHex(txID)
+	if _, ok := w.pending[key]; ok {
+		return
+	}
+	w.pending[key] = &watchedTransaction{txID: txID, submitted: submitted, transaction: tx}
+}
+
+// Pending returns the number of transactions being watched.
+func (w *Watchdog) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending)
+}
+
+// Close stops the background checks and waits for a check in progress to
+// finish, or until ctx is done.
+func (w *Watchdog) Close(ctx context.Context) error {
+	if w.unsubscribe != nil {
+		w.unsubscribe()
+	}
+	w.cancel()
+	select {
+	case <-w.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observe follows the client's transactions on its event bus.
+func (w *Watchdog) observe(event Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch e := event.(type) {
+	case BroadcastEvent:
+		key := normalizeHex(e.TxID)
+		if _, ok := w.pending[key]; !ok {
+			w.sent[key] = e.Transaction
+		}
+	case SubmittedEvent:
+		key := normalizeHex(e.TxID)
+		tx, ok := w.sent[key]
+		delete(w.sent, key)
+		if ok {
+			w.track(e.TxID, e.Time, &tx)
+		} else {
+			w.track(e.TxID, e.Time, nil)
+		}
+	case FailedEvent:
+		delete(w.sent, normalizeHex(e.TxID))
+		delete(w.pending, normalizeHex(e.TxID))
+	case ConfirmedEvent:
+		delete(w.pending, normalizeHex(e.Outcome.TxID))
+	}
+}
+
+// run checks the pending transactions every interval until ctx is done.
+func (w *Watchdog) run(ctx context.Context) {
+	if w.interval <= 0 {
+		<-ctx.Done()
+		return
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Check(ctx); err != nil && ctx.Err() == nil {
+				w.client.logf(ctx, "watchdog check failed: %v", err)
+			}
+		}
+	}
+}
+
+// Check looks up every transaction pending for longer than StuckAfter and
+// returns the ones still pending, oldest first, after resubmitting them if
+// enabled. Transactions that turn out to be final stop being watched.
+// Failures to look up a transaction are logged and the transaction is
+// checked again next time.
+func (w *Watchdog) Check(ctx context.Context) ([]StuckEvent, error) {
+	now := time.Now()
+	if err := w.loadLedger(ctx, now); err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	var due []*watchedTransaction
+	for _, watched := range w.pending {
+		since := watched.submitted
+		if watched.flagged.After(since) {
+			since = watched.flagged
+		}
+		if now.Sub(since) >= w.stuckAfter {
+			due = append(due, watched)
+		}
+	}
+	w.mu.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i].submitted.Before(due[j].submitted) })
+
+	var stuck []StuckEvent
+	for _, watched := range due {
+		if err := ctx.Err(); err != nil {
+			return stuck, err
+		}
+		outcome, err := w.client.GetOutcome(ctx, watched.txID)
+		if err != nil {
+			w.client.logf(ctx, "watchdog cannot look up %s: %v", watched.txID, err)
+			continue
+		}
+		if outcome.Status.Final() {
+			w.mu.Lock()
+			delete(w.pending, normalizeHex(watched.txID))
+			w.mu.Unlock()
+			continue
+		}
+
+		event := StuckEvent{
+			EventInfo: w.client.eventInfo(ctx),
+			TxID:      watched.txID,
+			Submitted: watched.submitted,
+			Pending:   now.Sub(watched.submitted),
+			Status:    outcome.Status,
+		}
+		w.mu.Lock()
+		watched.flagged = now
+		resubmit := watched.transaction != nil && watched.resubmits < w.maxResubmits
+		if resubmit {
+			watched.resubmits++
+		}
+		w.mu.Unlock()
+		if resubmit {
+			event.Resubmitted, event.Err = w.resubmit(ctx, watched)
+		}
+		if w.client.events != nil {
+			w.client.events.Publish(event)
+		}
+		stuck = append(stuck, event)
+	}
+	return stuck, nil
+}
+
+// resubmit broadcasts a stuck transaction again, unchanged.
+func (w *Watchdog) resubmit(ctx context.Context, watched *watchedTransaction) (bool, error) {
+	response, err := w.client.postTransaction(ctx, w.client.NAGURL, certificateRequest(*watched.transaction))
+	if err == nil {
+		err = w.client.resultError("AddTransaction", response)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// loadLedger watches the ledger entries still accepted that were submitted
+// long enough ago to be stuck.
+func (w *Watchdog) loadLedger(ctx context.Context, now time.Time) error {
+	if w.client.ledger == nil {
+		return nil
+	}
+	entries, err := w.client.ledger.Query(ctx, LedgerQuery{Status: LedgerAccepted})
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, entry := range entries {
+		if now.Sub(entry.Submitted) >= w.stuckAfter {
+			w.track(entry.TxID, entry.Submitted, nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package circular_enterprise_apis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	var mu sync.Mutex
+	status := "Pending"
+	var submitted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.Contains(r.URL.Path, "GetTransactionbyID") {
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"` + status + `","BlockID":"7","BlockTimestamp":"t","Position":0,"NodeID":"n1"}}`))
+			return
+		}
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		id, _ := request["ID"].(string)
+		submitted = append(submitted, id)
+		w.Write([]byte(`{"Result":200,"Response":{"TxID":"` + id + `"}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("Resubmit", func(t *testing.T) {
+		bus := NewEventBus()
+		var stuckEvents []StuckEvent
+		bus.Subscribe(func(event Event) {
+			if stuck, ok := event.(StuckEvent); ok {
+				stuckEvents = append(stuckEvents, stuck)
+			}
+		})
+		acc := NewCEPAccount(server.URL, DefaultChain, LibVersion, WithEventBus(bus))
+		acc.Open("0x" + strings.Repeat("a", 64))
+		w, err := acc.NewWatchdog(ctx, WithStuckAfter(0), WithWatchdogInterval(0), WithResubmit(1))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		defer w.Close(ctx)
+
+		if _, err := acc.SubmitCertificate("data", strings.Repeat("1", 64)); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if w.Pending() != 1 {
+			t.Fatalf("Expected the submission to be watched, but got %d", w.Pending())
+		}
+
+		stuck, err := w.Check(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(stuck) != 1 || stuck[0].TxID != acc.LatestTxID || stuck[0].Status != TxPending || !stuck[0].Resubmitted {
+			t.Fatalf("Expected %s to be flagged and resubmitted, but got %+v", acc.LatestTxID, stuck)
+		}
+		if len(submitted) != 2 || submitted[0] != submitted[1] {
+			t.Errorf("Expected the transaction to be resubmitted with the same ID, but got %v", submitted)
+		}
+		if len(stuckEvents) != 1 {
+			t.Errorf("Expected a StuckEvent, but got %d", len(stuckEvents))
+		}
+
+		stuck, _ = w.Check(ctx)
+		if len(stuck) != 1 || stuck[0].Resubmitted || len(submitted) != 2 {
+			t.Errorf("Expected no more than one resubmission, but got %+v and %d submissions", stuck, len(submitted))
+		}
+
+		mu.Lock()
+		status = "Executed"
+		mu.Unlock()
+		stuck, _ = w.Check(ctx)
+		if len(stuck) != 0 || w.Pending() != 0 {
+			t.Errorf("Expected the confirmed transaction to be forgotten, but got %+v and %d pending", stuck, w.Pending())
+		}
+	})
+
+	t.Run("Ledger", func(t *testing.T) {
+		mu.Lock()
+		status = "Pending"
+		mu.Unlock()
+		ledger := NewMemoryLedger()
+		ledger.Record(ctx, LedgerEntry{TxID: "old", Status: LedgerAccepted, Submitted: time.Now().Add(-time.Hour)})
+		ledger.Record(ctx, LedgerEntry{TxID: "recent", Status: LedgerAccepted, Submitted: time.Now()})
+		ledger.Record(ctx, LedgerEntry{TxID: "done", Status: LedgerConfirmed, Submitted: time.Now().Add(-time.Hour)})
+
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithLedger(ledger))
+		w, err := c.NewWatchdog(ctx, WithWatchdogInterval(0))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		defer w.Close(ctx)
+
+		stuck, err := w.Check(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if len(stuck) != 1 || stuck[0].TxID != "old" || stuck[0].Pending < time.Hour || stuck[0].Resubmitted {
+			t.Errorf("Expected only the old entry to be flagged, but got %+v", stuck)
+		}
+		if stuck, _ := w.Check(ctx); len(stuck) != 0 {
+			t.Errorf("Expected a flagged transaction to wait StuckAfter before being flagged again, but got %+v", stuck)
+		}
+	})
+
+	t.Run("Background", func(t *testing.T) {
+		bus := NewEventBus()
+		flagged := make(chan StuckEvent, 10)
+		bus.Subscribe(func(event Event) {
+			if stuck, ok := event.(StuckEvent); ok {
+				flagged <- stuck
+			}
+		})
+		c := NewClient(server.URL, DefaultChain, LibVersion, WithEventBus(bus))
+		w, err := c.NewWatchdog(ctx, WithStuckAfter(0), WithWatchdogInterval(time.Millisecond))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		w.Track("abc", time.Now(), nil)
+		select {
+		case stuck := <-flagged:
+			if stuck.TxID != "abc" {
+				t.Errorf("Expected abc to be flagged, but got %+v", stuck)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("Expected the background check to flag the transaction")
+		}
+		if err := c.Shutdown(ctx); err != nil {
+			t.Errorf("Expected the watchdog to stop on shutdown, but got: %v", err)
+		}
+	})
+}
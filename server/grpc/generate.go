@@ -0,0 +1,5 @@
+package grpcserver
+
+// The certificationv1 package is generated from proto/certification.proto
+// with protoc, protoc-gen-go and protoc-gen-go-grpc.
+//go:generate protoc --go_out=. --go_opt=module=github.com/lessuselesss/CEP-Go-APIs/server/grpc --go-grpc_out=. --go-grpc_opt=module=github.com/lessuselesss/CEP-Go-APIs/server/grpc proto/certification.proto
@@ -0,0 +1,20 @@
+module github.com/lessuselesss/CEP-Go-APIs/server/grpc
+
+go 1.24.4
+
+require (
+	github.com/lessuselesss/CEP-Go-APIs v0.0.0
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.34.1
+)
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+)
+
+replace github.com/lessuselesss/CEP-Go-APIs => ../..
@@ -0,0 +1,173 @@
+// Package grpcserver exposes certificate submission, outcome polling and
+// verification as a gRPC service backed by a single CEPAccount. Running it as
+// a sidecar lets services written in any language certify data without
+// embedding the SDK or handling the account's private key.
+//
+// The package lives in its own module so the core SDK does not depend on
+// gRPC. The service definition is in proto/certification.proto and the
+// certificationv1 package generated from it is committed; run go generate
+// after changing the definition.
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/server/grpc/certificationv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultOutcomeTimeoutSec bounds GetOutcome when the request does not set a
+// timeout.
+const DefaultOutcomeTimeoutSec = 60
+
+// Server implements certificationv1.CertificationServiceServer.
+type Server struct {
+	certificationv1.UnimplementedCertificationServiceServer
+
+	account           *cep.CEPAccount
+	privateKey        string
+	outcomeTimeoutSec int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithOutcomeTimeout sets the timeout used by GetOutcome when the request
+// does not specify one.
+func WithOutcomeTimeout(seconds int) Option {
+	return func(s *Server) {
+		s.outcomeTimeoutSec = seconds
+	}
+}
+
+// New returns a Server that signs certificates for account with privateKey.
+// The account must already be opened and have its network set.
+func New(account *cep.CEPAccount, privateKey string, opts ...Option) *Server {
+	s := &Server{
+		account:           account,
+		privateKey:        privateKey,
+		outcomeTimeoutSec: DefaultOutcomeTimeoutSec,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register registers the service with r, typically a *grpc.Server.
+func (s *Server) Register(r grpc.ServiceRegistrar) {
+	certificationv1.RegisterCertificationServiceServer(r, s)
+}
+
+// SubmitCertificate signs and submits a certificate carrying req.Data.
+func (s *Server) SubmitCertificate(ctx context.Context, req *certificationv1.SubmitCertificateRequest) (*certificationv1.SubmitCertificateResponse, error) {
+	var opts []cep.SubmitOption
+	if req.GetChain() != "" {
+		opts = append(opts, cep.WithChain(req.GetChain()))
+	}
+
+	var txID string
+	opts = append(opts, cep.CaptureTxID(&txID))
+	response, err := s.account.SubmitCertificateContext(ctx, req.GetData(), s.privateKey, opts...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+
+	result, _ := response["Result"].(float64)
+	out := &certificationv1.SubmitCertificateResponse{
+		Result:       int32(result),
+		ResponseJson: string(encoded),
+	}
+	if result == 200 {
+		out.TxId = txID
+	}
+	return out, nil
+}
+
+// GetOutcome polls until the transaction leaves the Pending state or the
+// timeout expires.
+func (s *Server) GetOutcome(ctx context.Context, req *certificationv1.GetOutcomeRequest) (*certificationv1.GetOutcomeResponse, error) {
+	if req.GetTxId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "tx_id is required")
+	}
+	timeout := int(req.GetTimeoutSec())
+	if timeout <= 0 {
+		timeout = s.outcomeTimeoutSec
+	}
+
+	outcome, err := s.account.GetTransactionOutcomeContext(ctx, req.GetTxId(), timeout)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	encoded, err := json.Marshal(outcome)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode outcome: %v", err)
+	}
+	txStatus, _ := outcome["Status"].(string)
+	return &certificationv1.GetOutcomeResponse{Status: txStatus, OutcomeJson: string(encoded)}, nil
+}
+
+// Verify looks the transaction up and compares the data it certifies with
+// req.Data.
+func (s *Server) Verify(ctx context.Context, req *certificationv1.VerifyRequest) (*certificationv1.VerifyResponse, error) {
+	if req.GetTxId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "tx_id is required")
+	}
+
+	data, err := s.account.GetTransactionByIDContext(ctx, req.GetTxId(), "", "")
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if result, _ := data["Result"].(float64); result != 200 {
+		return &certificationv1.VerifyResponse{Found: false}, nil
+	}
+	transaction, _ := data["Response"].(map[string]interface{})
+	txStatus, _ := transaction["Status"].(string)
+
+	certified, err := cep.NewTransaction(transaction).CertificateData()
+	if err != nil {
+		return nil, status.Errorf(codes.DataLoss, "transaction %s: %v", req.GetTxId(), err)
+	}
+	return &certificationv1.VerifyResponse{
+		Found:  true,
+		Match:  certified == req.GetData(),
+		Status: txStatus,
+		Data:   certified,
+	}, nil
+}
+
+// toStatus maps an SDK error to the gRPC status code closest to its cause.
+func toStatus(err error) error {
+	var (
+		resultErr    *cep.ResultError
+		preflightErr *cep.PreflightError
+		schemaErr    *cep.SchemaError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, cep.ErrUnknownChain), errors.Is(err, cep.ErrPayloadTooLarge):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &preflightErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, cep.ErrTransactionDropped):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, cep.ErrThrottled):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.As(err, &resultErr), errors.As(err, &schemaErr):
+		return status.Error(codes.Internal, err.Error())
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
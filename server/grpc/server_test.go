@@ -0,0 +1,85 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/circularutil"
+	"github.com/lessuselesss/CEP-Go-APIs/server/grpc/certificationv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestVerify(t *testing.T) {
+	txID := strings.Repeat("ab", 32)
+	nag := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request["TxID"] != txID {
+			w.Write([]byte(`{"Result":118,"Response":"Transaction Not Found"}`))
+			return
+		}
+		payload := circularutil.StringToHex(`{"data":"hello"}`)
+		w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","Payload":"` + payload + `"}}`))
+	}))
+	defer nag.Close()
+
+	acc := cep.NewCEPAccount(nag.URL, cep.DefaultChain, cep.LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	s := New(acc, strings.Repeat("1", 64))
+
+	testCases := []struct {
+		name     string
+		txID     string
+		data     string
+		expected *certificationv1.VerifyResponse
+	}{
+		{"Matching Data", txID, "hello", &certificationv1.VerifyResponse{Found: true, Match: true, Status: "Executed", Data: "hello"}},
+		{"Different Data", txID, "goodbye", &certificationv1.VerifyResponse{Found: true, Status: "Executed", Data: "hello"}},
+		{"Not Found", strings.Repeat("cd", 32), "hello", &certificationv1.VerifyResponse{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := s.Verify(context.Background(), &certificationv1.VerifyRequest{TxId: tc.txID, Data: tc.data})
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if out.Found != tc.expected.Found || out.Match != tc.expected.Match || out.Status != tc.expected.Status || out.Data != tc.expected.Data {
+				t.Errorf("Expected %v, but got %v", tc.expected, out)
+			}
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{"Deadline", fmt.Errorf("poll: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"Canceled", context.Canceled, codes.Canceled},
+		{"Unknown Chain", fmt.Errorf("%w: test", cep.ErrUnknownChain), codes.InvalidArgument},
+		{"Payload Too Large", &cep.PayloadTooLargeError{Size: 2, Limit: 1}, codes.InvalidArgument},
+		{"Preflight", &cep.PreflightError{Err: cep.ErrNotRegistered}, codes.FailedPrecondition},
+		{"Dropped", fmt.Errorf("%w: tx", cep.ErrTransactionDropped), codes.NotFound},
+		{"Throttled", &cep.ThrottledError{}, codes.ResourceExhausted},
+		{"Result", &cep.ResultError{Endpoint: "GetWallet", Result: 108}, codes.Internal},
+		{"Network", fmt.Errorf("connection refused"), codes.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := status.Code(toStatus(tc.err)); got != tc.expected {
+				t.Errorf("Expected %v, but got %v", tc.expected, got)
+			}
+		})
+	}
+}
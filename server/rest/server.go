@@ -0,0 +1,307 @@
+// Package rest provides an embeddable HTTP server that certifies data on
+// behalf of a single CEPAccount. Legacy systems that can make HTTP calls but
+// cannot embed the SDK use it as a sidecar holding the account's private key.
+//
+// The server exposes three JSON endpoints:
+//
+//	POST /v1/certificates          submit {"data": "...", "chain": "..."}
+//	GET  /v1/transactions/{txID}   report the transaction's current status
+//	POST /v1/verify                check {"txId": "...", "data": "..."}
+//
+// Every request must carry one of the configured API keys, either in the
+// X-API-Key header or as a bearer token.
+package rest
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/circularutil"
+)
+
+// DefaultMaxBodySize bounds the size of request bodies accepted by the server.
+const DefaultMaxBodySize = 2 << 20
+
+// Server is an http.Handler serving the certification endpoints.
+type Server struct {
+	account     *cep.CEPAccount
+	privateKey  string
+	apiKeys     [][]byte
+	maxBodySize int64
+	mux         *http.ServeMux
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAPIKeys adds keys accepted by the server. A server without any keys
+// rejects every request.
+func WithAPIKeys(keys ...string) Option {
+	return func(s *Server) {
+		for _, key := range keys {
+			if key != "" {
+				s.apiKeys = append(s.apiKeys, []byte(key))
+			}
+		}
+	}
+}
+
+// WithMaxBodySize sets the largest request body the server accepts.
+func WithMaxBodySize(n int64) Option {
+	return func(s *Server) {
+		s.maxBodySize = n
+	}
+}
+
+// New returns a Server that signs certificates for account with privateKey.
+// The account must already be opened and have its network set.
+func New(account *cep.CEPAccount, privateKey string, opts ...Option) *Server {
+	s := &Server{
+		account:     account,
+		privateKey:  privateKey,
+		maxBodySize: DefaultMaxBodySize,
+		mux:         http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("POST /v1/certificates", s.handleSubmit)
+	s.mux.HandleFunc("GET /v1/transactions/{txID}", s.handleStatus)
+	s.mux.HandleFunc("POST /v1/verify", s.handleVerify)
+	return s
+}
+
+// ServeHTTP authenticates the request and dispatches it to its endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API key"))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether r carries one of the server's API keys. Every
+// key is compared so the time taken does not reveal which one matched.
+func (s *Server) authorized(r *http.Request) bool {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		key, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if key == "" {
+		return false
+	}
+	match := 0
+	for _, allowed := range s.apiKeys {
+		match |= subtle.ConstantTimeCompare([]byte(key), allowed)
+	}
+	return match == 1
+}
+
+// submitRequest is the body of POST /v1/certificates.
+type submitRequest struct {
+	Data  string `json:"data"`
+	Chain string `json:"chain,omitempty"`
+}
+
+// submitResponse is returned by POST /v1/certificates.
+type submitResponse struct {
+	TxID     string                 `json:"txId,omitempty"`
+	Result   int                    `json:"result"`
+	Response map[string]interface{} `json:"response"`
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req submitRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, bodyErrorStatus(err), err)
+		return
+	}
+	if req.Data == "" {
+		writeError(w, http.StatusBadRequest, errors.New("data is required"))
+		return
+	}
+	var opts []cep.SubmitOption
+	if req.Chain != "" {
+		opts = append(opts, cep.WithChain(req.Chain))
+	}
+
+	var txID string
+	opts = append(opts, cep.CaptureTxID(&txID))
+	response, err := s.account.SubmitCertificateContext(r.Context(), req.Data, s.privateKey, opts...)
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+
+	result, _ := response["Result"].(float64)
+	out := submitResponse{Result: int(result), Response: response}
+	if result != 200 {
+		writeJSON(w, http.StatusBadGateway, out)
+		return
+	}
+	out.TxID = txID
+	writeJSON(w, http.StatusAccepted, out)
+}
+
+// statusResponse is returned by GET /v1/transactions/{txID}.
+type statusResponse struct {
+	TxID        string                 `json:"txId"`
+	Found       bool                   `json:"found"`
+	Status      string                 `json:"status,omitempty"`
+	Transaction map[string]interface{} `json:"transaction,omitempty"`
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	txID := r.PathValue("txID")
+	if err := validateTxID(txID); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	transaction, found, err := s.lookup(r.Context(), txID)
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+	out := statusResponse{TxID: txID, Found: found, Transaction: transaction}
+	out.Status, _ = transaction["Status"].(string)
+	if !found {
+		writeJSON(w, http.StatusNotFound, out)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// verifyRequest is the body of POST /v1/verify.
+type verifyRequest struct {
+	TxID string `json:"txId"`
+	Data string `json:"data"`
+}
+
+// verifyResponse is returned by POST /v1/verify.
+type verifyResponse struct {
+	Found  bool   `json:"found"`
+	Match  bool   `json:"match"`
+	Status string `json:"status,omitempty"`
+	Data   string `json:"data,omitempty"`
+}
+
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	var req verifyRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, bodyErrorStatus(err), err)
+		return
+	}
+	if err := validateTxID(req.TxID); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	transaction, found, err := s.lookup(r.Context(), req.TxID)
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusOK, verifyResponse{})
+		return
+	}
+	certified, err := cep.NewTransaction(transaction).CertificateData()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("transaction %s: %w", req.TxID, err))
+		return
+	}
+	out := verifyResponse{Found: true, Match: certified == req.Data, Data: certified}
+	out.Status, _ = transaction["Status"].(string)
+	writeJSON(w, http.StatusOK, out)
+}
+
+// lookup fetches a transaction, reporting whether the gateway knows it.
+func (s *Server) lookup(ctx context.Context, txID string) (map[string]interface{}, bool, error) {
+	data, err := s.account.GetTransactionByIDContext(ctx, txID, "", "")
+	if err != nil {
+		return nil, false, err
+	}
+	if result, _ := data["Result"].(float64); result != 200 {
+		return nil, false, nil
+	}
+	transaction, _ := data["Response"].(map[string]interface{})
+	return transaction, true, nil
+}
+
+// validateTxID checks that id looks like a transaction ID: the hex encoding
+// of a SHA-256 digest.
+func validateTxID(id string) error {
+	id = circularutil.HexFix(id)
+	if len(id) != 64 {
+		return fmt.Errorf("invalid transaction ID %q: expected 64 hex characters", id)
+	}
+	if _, err := hex.DecodeString(id); err != nil {
+		return fmt.Errorf("invalid transaction ID %q: %w", id, err)
+	}
+	return nil
+}
+
+// decodeBody decodes a JSON request body into v, rejecting unknown fields and
+// trailing data.
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	if decoder.More() {
+		return errors.New("invalid request body: unexpected data after the JSON object")
+	}
+	return nil
+}
+
+// bodyErrorStatus returns the status for a request body that could not be
+// decoded.
+func bodyErrorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// statusCode maps an SDK error to the HTTP status closest to its cause.
+func statusCode(err error) int {
+	var preflightErr *cep.PreflightError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, cep.ErrUnknownChain):
+		return http.StatusBadRequest
+	case errors.Is(err, cep.ErrPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &preflightErr):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, cep.ErrThrottled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// writeJSON writes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
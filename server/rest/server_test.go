@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	"github.com/lessuselesss/CEP-Go-APIs/pkg/circularutil"
+)
+
+// newTestServer returns a sidecar whose account talks to a fake NAG that
+// accepts submissions and knows a single transaction certifying "hello".
+func newTestServer(t *testing.T) (*Server, string) {
+	txID := strings.Repeat("ab", 32)
+	nag := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusOK)
+		switch {
+		case strings.Contains(r.URL.Path, "GetTransactionbyID"):
+			if request["TxID"] != txID {
+				w.Write([]byte(`{"Result":118,"Response":"Transaction Not Found"}`))
+				return
+			}
+			payload := circularutil.StringToHex(`{"data":"hello"}`)
+			w.Write([]byte(`{"Result":200,"Response":{"Status":"Executed","Payload":"` + payload + `"}}`))
+		default:
+			w.Write([]byte(`{"Result":200,"Response":{"TxID":"abc"}}`))
+		}
+	}))
+	t.Cleanup(nag.Close)
+
+	acc := cep.NewCEPAccount(nag.URL, cep.DefaultChain, cep.LibVersion)
+	acc.Open("0x" + strings.Repeat("a", 64))
+	return New(acc, strings.Repeat("1", 64), WithAPIKeys("secret")), txID
+}
+
+func serve(s *Server, method, target, body, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	s, txID := newTestServer(t)
+
+	testCases := []struct {
+		name     string
+		header   string
+		value    string
+		expected int
+	}{
+		{"Missing Key", "", "", http.StatusUnauthorized},
+		{"Wrong Key", "X-API-Key", "wrong", http.StatusUnauthorized},
+		{"API Key Header", "X-API-Key", "secret", http.StatusOK},
+		{"Bearer Token", "Authorization", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/transactions/"+txID, nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			if rec.Code != tc.expected {
+				t.Errorf("Expected status %d, but got %d", tc.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"Valid", `{"data":"hello"}`, http.StatusAccepted},
+		{"Missing Data", `{}`, http.StatusBadRequest},
+		{"Unknown Field", `{"data":"hello","extra":1}`, http.StatusBadRequest},
+		{"Malformed", `{"data":`, http.StatusBadRequest},
+		{"Unknown Chain", `{"data":"hello","chain":"missing"}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serve(s, http.MethodPost, "/v1/certificates", tc.body, "secret")
+			if rec.Code != tc.expected {
+				t.Fatalf("Expected status %d, but got %d: %s", tc.expected, rec.Code, rec.Body)
+			}
+			if tc.expected != http.StatusAccepted {
+				return
+			}
+			var out submitResponse
+			json.NewDecoder(rec.Body).Decode(&out)
+			if len(out.TxID) != 64 || out.Result != 200 {
+				t.Errorf("Expected a transaction ID and result 200, but got %+v", out)
+			}
+		})
+	}
+}
+
+func TestStatusAndVerify(t *testing.T) {
+	s, txID := newTestServer(t)
+
+	t.Run("Status", func(t *testing.T) {
+		rec := serve(s, http.MethodGet, "/v1/transactions/"+txID, "", "secret")
+		var out statusResponse
+		json.NewDecoder(rec.Body).Decode(&out)
+		if rec.Code != http.StatusOK || !out.Found || out.Status != "Executed" {
+			t.Errorf("Expected an executed transaction, but got %d %+v", rec.Code, out)
+		}
+	})
+
+	t.Run("Status Not Found", func(t *testing.T) {
+		rec := serve(s, http.MethodGet, "/v1/transactions/"+strings.Repeat("cd", 32), "", "secret")
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, but got %d", rec.Code)
+		}
+	})
+
+	t.Run("Status Invalid ID", func(t *testing.T) {
+		rec := serve(s, http.MethodGet, "/v1/transactions/xyz", "", "secret")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, but got %d", rec.Code)
+		}
+	})
+
+	verifyCases := []struct {
+		name  string
+		data  string
+		match bool
+	}{
+		{"Matching Data", "hello", true},
+		{"Different Data", "goodbye", false},
+	}
+	for _, tc := range verifyCases {
+		t.Run("Verify "+tc.name, func(t *testing.T) {
+			rec := serve(s, http.MethodPost, "/v1/verify", `{"txId":"`+txID+`","data":"`+tc.data+`"}`, "secret")
+			var out verifyResponse
+			json.NewDecoder(rec.Body).Decode(&out)
+			if rec.Code != http.StatusOK || !out.Found || out.Match != tc.match || out.Data != "hello" {
+				t.Errorf("Expected found with match %v, but got %d %+v", tc.match, rec.Code, out)
+			}
+		})
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	s, _ := newTestServer(t)
+	WithMaxBodySize(16)(s)
+
+	rec := serve(s, http.MethodPost, "/v1/certificates", `{"data":"`+strings.Repeat("x", 64)+`"}`, "secret")
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413, but got %d", rec.Code)
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/lessuselesss/CEP-Go-APIs/store/bolt
+
+go 1.24.4
+
+require (
+	github.com/lessuselesss/CEP-Go-APIs v0.0.0
+	go.etcd.io/bbolt v1.4.0
+)
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+)
+
+replace github.com/lessuselesss/CEP-Go-APIs => ../..
@@ -0,0 +1,113 @@
+// Package boltstore implements cep.Store on a BoltDB file, giving the submit
+// queue and dead letters state that survives restarts. It lives in its own
+// module so the core SDK does not depend on BoltDB.
+//
+//	store, err := boltstore.Open("cep.db")
+//	defer store.Close()
+//	q, err := account.NewSubmitQueue(ctx, key, cep.WithQueueStore(store))
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultBucket is the bucket holding the store's keys.
+const DefaultBucket = "cep"
+
+// Store is a cep.Store keeping its keys in one bucket of a BoltDB database.
+type Store struct {
+	db     *bolt.DB
+	bucket []byte
+	owned  bool
+}
+
+// Open opens or creates the BoltDB file at path and returns a Store using
+// its DefaultBucket. Close closes the file.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	s, err := New(db, DefaultBucket)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s.owned = true
+	return s, nil
+}
+
+// New returns a Store using bucket of an already open database, creating the
+// bucket if needed. Close leaves the database open.
+func New(db *bolt.DB, bucket string) (*Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+	}
+	return &Store{db: db, bucket: []byte(bucket)}, nil
+}
+
+// Close closes the database if it was opened by Open.
+func (s *Store) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Get implements cep.Store.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		found := tx.Bucket(s.bucket).Get([]byte(key))
+		if found == nil {
+			return fmt.Errorf("%w: %s", cep.ErrKeyNotFound, key)
+		}
+		// Values are only valid for the life of the transaction.
+		value = append([]byte(nil), found...)
+		return nil
+	})
+	return value, err
+}
+
+// Put implements cep.Store.
+func (s *Store) Put(ctx context.Context, key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(key), value)
+	})
+}
+
+// Delete implements cep.Store.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(key))
+	})
+}
+
+// Iterate implements cep.Store. fn runs inside a read transaction, which is
+// why it must not modify the store.
+func (s *Store) Iterate(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(s.bucket).Cursor()
+		for k, v := cursor.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = cursor.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(string(k), append([]byte(nil), v...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+var _ cep.Store = (*Store)(nil)
@@ -0,0 +1,73 @@
+package boltstore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cep.db")
+	ctx := context.Background()
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	for _, key := range []string{"queue/2", "deadletter/a", "queue/1", "queuex"} {
+		if err := s.Put(ctx, key, []byte("value of "+key)); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	}
+	if err := s.Delete(ctx, "missing"); err != nil {
+		t.Errorf("Expected deleting a missing key to succeed, but got: %v", err)
+	}
+	s.Close()
+
+	// The values survive reopening the file.
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	defer s.Close()
+
+	value, err := s.Get(ctx, "deadletter/a")
+	if err != nil || string(value) != "value of deadletter/a" {
+		t.Errorf("Expected the stored value, but got %q (%v)", value, err)
+	}
+	var keys []string
+	err = s.Iterate(ctx, "queue/", func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil || strings.Join(keys, ",") != "queue/1,queue/2" {
+		t.Errorf("Expected the queue keys in order, but got %v (%v)", keys, err)
+	}
+
+	s.Delete(ctx, "deadletter/a")
+	if _, err := s.Get(ctx, "deadletter/a"); !errors.Is(err, cep.ErrKeyNotFound) {
+		t.Errorf("Expected cep.ErrKeyNotFound, but got %v", err)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "cep.db"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	letters := cep.NewStoreDeadLetterStore(s)
+	if err := letters.Put(ctx, cep.DeadLetter{ID: "a", Data: "hello", Attempts: 1}); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	list, err := letters.List(ctx)
+	if err != nil || len(list) != 1 || list[0].Data != "hello" {
+		t.Errorf("Expected the stored letter, but got %+v (%v)", list, err)
+	}
+}
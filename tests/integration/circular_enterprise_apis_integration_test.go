@@ -1,7 +1,6 @@
 package integration
 
 import (
-	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -9,7 +8,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/joho/godotenv"
 	cep "github.com/lessuselesss/CEP-Go-APIs/pkg"
 )
@@ -52,14 +50,7 @@ func TestCircularOperations(t *testing.T) {
 
 	acc := cep.NewCEPAccount(server.URL, "testnet", "1.0")
 
-	// Decode the private key and set it on the account
-	pkBytes, err := hex.DecodeString(privateKeyHex)
-	if err != nil {
-		t.Fatalf("Failed to decode private key: %v", err)
-	}
-	acc.PrivateKey = secp256k1.PrivKeyFromBytes(pkBytes)
-
-	err = acc.Open(address)
+	err := acc.Open(address)
 	if err != nil {
 		t.Fatalf("acc.Open() failed: %v", err)
 	}
@@ -71,8 +62,12 @@ func TestCircularOperations(t *testing.T) {
 
 	cert := cep.NewCertificate(acc.CodeVersion)
 	cert.SetData("test message")
+	certificate, err := cert.GetJSONCertificate()
+	if err != nil {
+		t.Fatalf("cert.GetJSONCertificate() failed: %v", err)
+	}
 
-	resp, err := acc.SubmitCertificate(cert)
+	resp, err := acc.SubmitCertificate(certificate, privateKeyHex)
 	if err != nil {
 		t.Fatalf("acc.SubmitCertificate() failed: %v", err)
 	}